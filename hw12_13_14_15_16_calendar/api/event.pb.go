@@ -1,17 +1,19 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.1
+// 	protoc-gen-go v1.36.9
 // 	protoc        v3.12.4
 // source: event.proto
 
 package api
 
 import (
-	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -22,19 +24,28 @@ const (
 )
 
 type Event struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Start       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Description string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	UserID      string                 `protobuf:"bytes,7,opt,name=userID,proto3" json:"userID,omitempty"`
+	// Deprecated: Marked as deprecated in event.proto.
+	Notification  *timestamppb.Timestamp   `protobuf:"bytes,8,opt,name=notification,proto3" json:"notification,omitempty"` // используйте reminders
+	Notified      bool                     `protobuf:"varint,9,opt,name=notified,proto3" json:"notified,omitempty"`
+	Rrule         string                   `protobuf:"bytes,10,opt,name=rrule,proto3" json:"rrule,omitempty"` // RFC 5545 RRULE, например "FREQ=WEEKLY;BYDAY=MO,WE"
+	ExDates       []*timestamppb.Timestamp `protobuf:"bytes,11,rep,name=exDates,proto3" json:"exDates,omitempty"`
+	Reminders     []*durationpb.Duration   `protobuf:"bytes,12,rep,name=reminders,proto3" json:"reminders,omitempty"` // за сколько до начала напомнить
+	Attendees     []*Attendee              `protobuf:"bytes,13,rep,name=attendees,proto3" json:"attendees,omitempty"`
+	Responses     *ResponseCounts          `protobuf:"bytes,14,opt,name=responses,proto3" json:"responses,omitempty"` // сколько приглашённых как ответили
+	CalendarID    string                   `protobuf:"bytes,15,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	Version       int64                    `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`    // растёт при каждом изменении события; по REST - также в заголовке ETag
+	DeletedAt     *timestamppb.Timestamp   `protobuf:"bytes,17,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"` // когда событие перенесено в корзину; только в GetTrash
+	TimeZone      string                   `protobuf:"bytes,18,opt,name=timeZone,proto3" json:"timeZone,omitempty"`   // IANA-зона, в которой разворачивается rrule
 	unknownFields protoimpl.UnknownFields
-
-	Id           string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title        string               `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	CreatedAt    *timestamp.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	Start        *timestamp.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
-	End          *timestamp.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
-	Description  string               `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
-	UserID       string               `protobuf:"bytes,7,opt,name=userID,proto3" json:"userID,omitempty"`
-	Notification *timestamp.Timestamp `protobuf:"bytes,8,opt,name=notification,proto3" json:"notification,omitempty"`
-	Notified     bool                 `protobuf:"varint,9,opt,name=notified,proto3" json:"notified,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Event) Reset() {
@@ -81,21 +92,21 @@ func (x *Event) GetTitle() string {
 	return ""
 }
 
-func (x *Event) GetCreatedAt() *timestamp.Timestamp {
+func (x *Event) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *Event) GetStart() *timestamp.Timestamp {
+func (x *Event) GetStart() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Start
 	}
 	return nil
 }
 
-func (x *Event) GetEnd() *timestamp.Timestamp {
+func (x *Event) GetEnd() *timestamppb.Timestamp {
 	if x != nil {
 		return x.End
 	}
@@ -116,7 +127,8 @@ func (x *Event) GetUserID() string {
 	return ""
 }
 
-func (x *Event) GetNotification() *timestamp.Timestamp {
+// Deprecated: Marked as deprecated in event.proto.
+func (x *Event) GetNotification() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Notification
 	}
@@ -130,22 +142,216 @@ func (x *Event) GetNotified() bool {
 	return false
 }
 
-type EventCreateDTO struct {
-	state         protoimpl.MessageState
+func (x *Event) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *Event) GetExDates() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.ExDates
+	}
+	return nil
+}
+
+func (x *Event) GetReminders() []*durationpb.Duration {
+	if x != nil {
+		return x.Reminders
+	}
+	return nil
+}
+
+func (x *Event) GetAttendees() []*Attendee {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+func (x *Event) GetResponses() *ResponseCounts {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *Event) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *Event) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Event) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Event) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// приглашённый пользователь; status - pending, accepted, declined или tentative
+type Attendee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attendee) Reset() {
+	*x = Attendee{}
+	mi := &file_event_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attendee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attendee) ProtoMessage() {}
+
+func (x *Attendee) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attendee.ProtoReflect.Descriptor instead.
+func (*Attendee) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Attendee) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *Attendee) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ResponseCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pending       int32                  `protobuf:"varint,1,opt,name=pending,proto3" json:"pending,omitempty"`
+	Accepted      int32                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Declined      int32                  `protobuf:"varint,3,opt,name=declined,proto3" json:"declined,omitempty"`
+	Tentative     int32                  `protobuf:"varint,4,opt,name=tentative,proto3" json:"tentative,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	Title        string               `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Start        *timestamp.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
-	End          *timestamp.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
-	Description  string               `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Notification *timestamp.Timestamp `protobuf:"bytes,5,opt,name=notification,proto3" json:"notification,omitempty"`
-	Notified     bool                 `protobuf:"varint,9,opt,name=notified,proto3" json:"notified,omitempty"`
+func (x *ResponseCounts) Reset() {
+	*x = ResponseCounts{}
+	mi := &file_event_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCounts) ProtoMessage() {}
+
+func (x *ResponseCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCounts.ProtoReflect.Descriptor instead.
+func (*ResponseCounts) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ResponseCounts) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *ResponseCounts) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *ResponseCounts) GetDeclined() int32 {
+	if x != nil {
+		return x.Declined
+	}
+	return 0
+}
+
+func (x *ResponseCounts) GetTentative() int32 {
+	if x != nil {
+		return x.Tentative
+	}
+	return 0
+}
+
+type EventCreateDTO struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Start       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// Deprecated: Marked as deprecated in event.proto.
+	Notification *timestamppb.Timestamp   `protobuf:"bytes,5,opt,name=notification,proto3" json:"notification,omitempty"` // используйте reminders
+	Notified     bool                     `protobuf:"varint,9,opt,name=notified,proto3" json:"notified,omitempty"`
+	Rrule        string                   `protobuf:"bytes,10,opt,name=rrule,proto3" json:"rrule,omitempty"` // RFC 5545 RRULE, например "FREQ=WEEKLY;BYDAY=MO,WE"
+	ExDates      []*timestamppb.Timestamp `protobuf:"bytes,11,rep,name=exDates,proto3" json:"exDates,omitempty"`
+	// Пустой список: при создании - напоминания по умолчанию, при обновлении - без изменений.
+	Reminders   []*durationpb.Duration `protobuf:"bytes,12,rep,name=reminders,proto3" json:"reminders,omitempty"`
+	NoReminders bool                   `protobuf:"varint,13,opt,name=noReminders,proto3" json:"noReminders,omitempty"` // без напоминаний; reminders игнорируется
+	// ID аккаунтов приглашённых. Пустой список при обновлении - без изменений.
+	Attendees   []string `protobuf:"bytes,14,rep,name=attendees,proto3" json:"attendees,omitempty"`
+	NoAttendees bool     `protobuf:"varint,15,opt,name=noAttendees,proto3" json:"noAttendees,omitempty"` // убрать всех приглашённых; attendees игнорируется
+	// IANA-зона правила повторения. Пустая: при создании - зона аккаунта, при обновлении - без изменений.
+	TimeZone      string `protobuf:"bytes,16,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *EventCreateDTO) Reset() {
 	*x = EventCreateDTO{}
-	mi := &file_event_proto_msgTypes[1]
+	mi := &file_event_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -157,7 +363,7 @@ func (x *EventCreateDTO) String() string {
 func (*EventCreateDTO) ProtoMessage() {}
 
 func (x *EventCreateDTO) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[1]
+	mi := &file_event_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,7 +376,7 @@ func (x *EventCreateDTO) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EventCreateDTO.ProtoReflect.Descriptor instead.
 func (*EventCreateDTO) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{1}
+	return file_event_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *EventCreateDTO) GetTitle() string {
@@ -180,14 +386,14 @@ func (x *EventCreateDTO) GetTitle() string {
 	return ""
 }
 
-func (x *EventCreateDTO) GetStart() *timestamp.Timestamp {
+func (x *EventCreateDTO) GetStart() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Start
 	}
 	return nil
 }
 
-func (x *EventCreateDTO) GetEnd() *timestamp.Timestamp {
+func (x *EventCreateDTO) GetEnd() *timestamppb.Timestamp {
 	if x != nil {
 		return x.End
 	}
@@ -201,7 +407,8 @@ func (x *EventCreateDTO) GetDescription() string {
 	return ""
 }
 
-func (x *EventCreateDTO) GetNotification() *timestamp.Timestamp {
+// Deprecated: Marked as deprecated in event.proto.
+func (x *EventCreateDTO) GetNotification() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Notification
 	}
@@ -215,85 +422,205 @@ func (x *EventCreateDTO) GetNotified() bool {
 	return false
 }
 
-var File_event_proto protoreflect.FileDescriptor
+func (x *EventCreateDTO) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *EventCreateDTO) GetExDates() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.ExDates
+	}
+	return nil
+}
+
+func (x *EventCreateDTO) GetReminders() []*durationpb.Duration {
+	if x != nil {
+		return x.Reminders
+	}
+	return nil
+}
+
+func (x *EventCreateDTO) GetNoReminders() bool {
+	if x != nil {
+		return x.NoReminders
+	}
+	return false
+}
+
+func (x *EventCreateDTO) GetAttendees() []string {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+func (x *EventCreateDTO) GetNoAttendees() bool {
+	if x != nil {
+		return x.NoAttendees
+	}
+	return false
+}
+
+func (x *EventCreateDTO) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type Interval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Interval) Reset() {
+	*x = Interval{}
+	mi := &file_event_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interval) ProtoMessage() {}
 
-var file_event_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdd, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
-	0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12,
-	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x3e, 0x0a, 0x0c, 0x6e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x6e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6e, 0x6f, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x64, 0x22, 0x84, 0x02, 0x0a, 0x0e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x30,
-	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x20,
-	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x3e, 0x0a, 0x0c, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x52, 0x0c, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x64, 0x42, 0x08, 0x5a, 0x06,
-	0x2e, 0x2f, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *Interval) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interval.ProtoReflect.Descriptor instead.
+func (*Interval) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{4}
 }
 
+func (x *Interval) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Interval) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+var File_event_proto protoreflect.FileDescriptor
+
+const file_event_proto_rawDesc = "" +
+	"\n" +
+	"\vevent.proto\x12\x05event\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xda\x05\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x128\n" +
+	"\tcreatedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06userID\x18\a \x01(\tR\x06userID\x12B\n" +
+	"\fnotification\x18\b \x01(\v2\x1a.google.protobuf.TimestampB\x02\x18\x01R\fnotification\x12\x1a\n" +
+	"\bnotified\x18\t \x01(\bR\bnotified\x12\x14\n" +
+	"\x05rrule\x18\n" +
+	" \x01(\tR\x05rrule\x124\n" +
+	"\aexDates\x18\v \x03(\v2\x1a.google.protobuf.TimestampR\aexDates\x127\n" +
+	"\treminders\x18\f \x03(\v2\x19.google.protobuf.DurationR\treminders\x12-\n" +
+	"\tattendees\x18\r \x03(\v2\x0f.event.AttendeeR\tattendees\x123\n" +
+	"\tresponses\x18\x0e \x01(\v2\x15.event.ResponseCountsR\tresponses\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x0f \x01(\tR\n" +
+	"calendarID\x12\x18\n" +
+	"\aversion\x18\x10 \x01(\x03R\aversion\x128\n" +
+	"\tdeletedAt\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x1a\n" +
+	"\btimeZone\x18\x12 \x01(\tR\btimeZone\":\n" +
+	"\bAttendee\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"\x80\x01\n" +
+	"\x0eResponseCounts\x12\x18\n" +
+	"\apending\x18\x01 \x01(\x05R\apending\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\bdeclined\x18\x03 \x01(\x05R\bdeclined\x12\x1c\n" +
+	"\ttentative\x18\x04 \x01(\x05R\ttentative\"\x8b\x04\n" +
+	"\x0eEventCreateDTO\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12B\n" +
+	"\fnotification\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x02\x18\x01R\fnotification\x12\x1a\n" +
+	"\bnotified\x18\t \x01(\bR\bnotified\x12\x14\n" +
+	"\x05rrule\x18\n" +
+	" \x01(\tR\x05rrule\x124\n" +
+	"\aexDates\x18\v \x03(\v2\x1a.google.protobuf.TimestampR\aexDates\x127\n" +
+	"\treminders\x18\f \x03(\v2\x19.google.protobuf.DurationR\treminders\x12 \n" +
+	"\vnoReminders\x18\r \x01(\bR\vnoReminders\x12\x1c\n" +
+	"\tattendees\x18\x0e \x03(\tR\tattendees\x12 \n" +
+	"\vnoAttendees\x18\x0f \x01(\bR\vnoAttendees\x12\x1a\n" +
+	"\btimeZone\x18\x10 \x01(\tR\btimeZone\"j\n" +
+	"\bInterval\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03endB\bZ\x06./;apib\x06proto3"
+
 var (
 	file_event_proto_rawDescOnce sync.Once
-	file_event_proto_rawDescData = file_event_proto_rawDesc
+	file_event_proto_rawDescData []byte
 )
 
 func file_event_proto_rawDescGZIP() []byte {
 	file_event_proto_rawDescOnce.Do(func() {
-		file_event_proto_rawDescData = protoimpl.X.CompressGZIP(file_event_proto_rawDescData)
+		file_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_event_proto_rawDesc), len(file_event_proto_rawDesc)))
 	})
 	return file_event_proto_rawDescData
 }
 
-var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_event_proto_goTypes = []any{
-	(*Event)(nil),               // 0: event.Event
-	(*EventCreateDTO)(nil),      // 1: event.EventCreateDTO
-	(*timestamp.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*Event)(nil),                 // 0: event.Event
+	(*Attendee)(nil),              // 1: event.Attendee
+	(*ResponseCounts)(nil),        // 2: event.ResponseCounts
+	(*EventCreateDTO)(nil),        // 3: event.EventCreateDTO
+	(*Interval)(nil),              // 4: event.Interval
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 6: google.protobuf.Duration
 }
 var file_event_proto_depIdxs = []int32{
-	2, // 0: event.Event.createdAt:type_name -> google.protobuf.Timestamp
-	2, // 1: event.Event.start:type_name -> google.protobuf.Timestamp
-	2, // 2: event.Event.end:type_name -> google.protobuf.Timestamp
-	2, // 3: event.Event.notification:type_name -> google.protobuf.Timestamp
-	2, // 4: event.EventCreateDTO.start:type_name -> google.protobuf.Timestamp
-	2, // 5: event.EventCreateDTO.end:type_name -> google.protobuf.Timestamp
-	2, // 6: event.EventCreateDTO.notification:type_name -> google.protobuf.Timestamp
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	5,  // 0: event.Event.createdAt:type_name -> google.protobuf.Timestamp
+	5,  // 1: event.Event.start:type_name -> google.protobuf.Timestamp
+	5,  // 2: event.Event.end:type_name -> google.protobuf.Timestamp
+	5,  // 3: event.Event.notification:type_name -> google.protobuf.Timestamp
+	5,  // 4: event.Event.exDates:type_name -> google.protobuf.Timestamp
+	6,  // 5: event.Event.reminders:type_name -> google.protobuf.Duration
+	1,  // 6: event.Event.attendees:type_name -> event.Attendee
+	2,  // 7: event.Event.responses:type_name -> event.ResponseCounts
+	5,  // 8: event.Event.deletedAt:type_name -> google.protobuf.Timestamp
+	5,  // 9: event.EventCreateDTO.start:type_name -> google.protobuf.Timestamp
+	5,  // 10: event.EventCreateDTO.end:type_name -> google.protobuf.Timestamp
+	5,  // 11: event.EventCreateDTO.notification:type_name -> google.protobuf.Timestamp
+	5,  // 12: event.EventCreateDTO.exDates:type_name -> google.protobuf.Timestamp
+	6,  // 13: event.EventCreateDTO.reminders:type_name -> google.protobuf.Duration
+	5,  // 14: event.Interval.start:type_name -> google.protobuf.Timestamp
+	5,  // 15: event.Interval.end:type_name -> google.protobuf.Timestamp
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_event_proto_init() }
@@ -305,9 +632,9 @@ func file_event_proto_init() {
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_event_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_proto_rawDesc), len(file_event_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -316,7 +643,6 @@ func file_event_proto_init() {
 		MessageInfos:      file_event_proto_msgTypes,
 	}.Build()
 	File_event_proto = out.File
-	file_event_proto_rawDesc = nil
 	file_event_proto_goTypes = nil
 	file_event_proto_depIdxs = nil
 }
@@ -1,17 +1,21 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.1
+// 	protoc-gen-go v1.36.9
 // 	protoc        v3.12.4
 // source: event_service.proto
 
 package api
 
 import (
-	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,6 +25,56 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Режим пакетной операции (не больше 1000 событий):
+// ATOMIC - всё или ничего: если хоть одно событие не прошло, пакет ничего не меняет, а ошибка
+// возвращается статусом с номером события в metadata["index"] ErrorInfo;
+// BEST_EFFORT - события обрабатываются по отдельности, ошибка каждого - в его результате.
+type BatchMode int32
+
+const (
+	BatchMode_ATOMIC      BatchMode = 0
+	BatchMode_BEST_EFFORT BatchMode = 1
+)
+
+// Enum value maps for BatchMode.
+var (
+	BatchMode_name = map[int32]string{
+		0: "ATOMIC",
+		1: "BEST_EFFORT",
+	}
+	BatchMode_value = map[string]int32{
+		"ATOMIC":      0,
+		"BEST_EFFORT": 1,
+	}
+)
+
+func (x BatchMode) Enum() *BatchMode {
+	p := new(BatchMode)
+	*p = x
+	return p
+}
+
+func (x BatchMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_event_service_proto_enumTypes[0].Descriptor()
+}
+
+func (BatchMode) Type() protoreflect.EnumType {
+	return &file_event_service_proto_enumTypes[0]
+}
+
+func (x BatchMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchMode.Descriptor instead.
+func (BatchMode) EnumDescriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{0}
+}
+
 type GetEventListingByUserIDRequest_Period int32
 
 const (
@@ -54,11 +108,11 @@ func (x GetEventListingByUserIDRequest_Period) String() string {
 }
 
 func (GetEventListingByUserIDRequest_Period) Descriptor() protoreflect.EnumDescriptor {
-	return file_event_service_proto_enumTypes[0].Descriptor()
+	return file_event_service_proto_enumTypes[1].Descriptor()
 }
 
 func (GetEventListingByUserIDRequest_Period) Type() protoreflect.EnumType {
-	return &file_event_service_proto_enumTypes[0]
+	return &file_event_service_proto_enumTypes[1]
 }
 
 func (x GetEventListingByUserIDRequest_Period) Number() protoreflect.EnumNumber {
@@ -71,12 +125,13 @@ func (GetEventListingByUserIDRequest_Period) EnumDescriptor() ([]byte, []int) {
 }
 
 type AddEventByIDRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	EventCreateDTO *EventCreateDTO `protobuf:"bytes,1,opt,name=eventCreateDTO,proto3" json:"eventCreateDTO,omitempty"`
-	UserID         string          `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EventCreateDTO *EventCreateDTO        `protobuf:"bytes,1,opt,name=eventCreateDTO,proto3" json:"eventCreateDTO,omitempty"`
+	UserID         string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	RejectOverlap  bool                   `protobuf:"varint,3,opt,name=rejectOverlap,proto3" json:"rejectOverlap,omitempty"` // отклонить событие, пересекающееся с другими
+	CalendarID     string                 `protobuf:"bytes,4,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *AddEventByIDRequest) Reset() {
@@ -123,13 +178,26 @@ func (x *AddEventByIDRequest) GetUserID() string {
 	return ""
 }
 
+func (x *AddEventByIDRequest) GetRejectOverlap() bool {
+	if x != nil {
+		return x.RejectOverlap
+	}
+	return false
+}
+
+func (x *AddEventByIDRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
 type AddEventByIDResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AddEventByIDResponse) Reset() {
@@ -177,13 +245,17 @@ func (x *AddEventByIDResponse) GetError() string {
 }
 
 type UpdateEventByIDRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id             string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	EventCreateDTO *EventCreateDTO `protobuf:"bytes,2,opt,name=eventCreateDTO,proto3" json:"eventCreateDTO,omitempty"`
-	UserID         string          `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventCreateDTO *EventCreateDTO        `protobuf:"bytes,2,opt,name=eventCreateDTO,proto3" json:"eventCreateDTO,omitempty"`
+	UserID         string                 `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	RejectOverlap  bool                   `protobuf:"varint,4,opt,name=rejectOverlap,proto3" json:"rejectOverlap,omitempty"` // отклонить изменение, если событие пересечётся с другими
+	CalendarID     string                 `protobuf:"bytes,5,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	// Версия события (Event.version), от которой сделано изменение; если событие уже изменили -
+	// Aborted (STALE_VERSION). 0 - без проверки; по REST вместо поля можно передать If-Match.
+	ExpectedVersion int64 `protobuf:"varint,6,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateEventByIDRequest) Reset() {
@@ -237,12 +309,32 @@ func (x *UpdateEventByIDRequest) GetUserID() string {
 	return ""
 }
 
+func (x *UpdateEventByIDRequest) GetRejectOverlap() bool {
+	if x != nil {
+		return x.RejectOverlap
+	}
+	return false
+}
+
+func (x *UpdateEventByIDRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *UpdateEventByIDRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type UpdateEventByIDResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateEventByIDResponse) Reset() {
@@ -282,12 +374,13 @@ func (x *UpdateEventByIDResponse) GetError() string {
 	return ""
 }
 
+// Событие переносится в корзину; вернуть его можно методом RestoreEvent.
 type DeleteEventByIDRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,2,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteEventByIDRequest) Reset() {
@@ -327,12 +420,18 @@ func (x *DeleteEventByIDRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteEventByIDRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
 type DeleteEventByIDResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteEventByIDResponse) Reset() {
@@ -373,13 +472,15 @@ func (x *DeleteEventByIDResponse) GetError() string {
 }
 
 type GetEventListingByUserIDRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state  protoimpl.MessageState                `protogen:"open.v1"`
 	UserID string                                `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
-	Date   *timestamp.Timestamp                  `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Date   *timestamppb.Timestamp                `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
 	Period GetEventListingByUserIDRequest_Period `protobuf:"varint,3,opt,name=period,proto3,enum=GetEventListingByUserIDRequest_Period" json:"period,omitempty"`
+	// Зона IANA, в которой считается период, например "Europe/Moscow"; по умолчанию - зона аккаунта.
+	TimeZone      string `protobuf:"bytes,4,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	CalendarID    string `protobuf:"bytes,5,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetEventListingByUserIDRequest) Reset() {
@@ -419,7 +520,7 @@ func (x *GetEventListingByUserIDRequest) GetUserID() string {
 	return ""
 }
 
-func (x *GetEventListingByUserIDRequest) GetDate() *timestamp.Timestamp {
+func (x *GetEventListingByUserIDRequest) GetDate() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Date
 	}
@@ -433,13 +534,26 @@ func (x *GetEventListingByUserIDRequest) GetPeriod() GetEventListingByUserIDRequ
 	return GetEventListingByUserIDRequest_day
 }
 
+func (x *GetEventListingByUserIDRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *GetEventListingByUserIDRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
 type GetEventListingByUserIDResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         []*Event               `protobuf:"bytes,1,rep,name=event,proto3" json:"event,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Event []*Event `protobuf:"bytes,1,rep,name=event,proto3" json:"event,omitempty"`
-	Error string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetEventListingByUserIDResponse) Reset() {
@@ -487,12 +601,12 @@ func (x *GetEventListingByUserIDResponse) GetError() string {
 }
 
 type GetEventByIDRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,3,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserID string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetEventByIDRequest) Reset() {
@@ -539,13 +653,19 @@ func (x *GetEventByIDRequest) GetUserID() string {
 	return ""
 }
 
+func (x *GetEventByIDRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
 type GetEventByIDResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Event *Event `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
-	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetEventByIDResponse) Reset() {
@@ -592,28 +712,38 @@ func (x *GetEventByIDResponse) GetError() string {
 	return ""
 }
 
-type NotifyRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// Пустые поля не ограничивают поиск. Повторяющиеся события не разворачиваются:
+// событие находится, если хотя бы одно его повторение начинается в [from, to).
+type SearchEventsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	From       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Query      string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"` // слова, каждое из которых есть в названии или описании
+	Notified   *bool                  `protobuf:"varint,4,opt,name=notified,proto3,oneof" json:"notified,omitempty"`
+	Sort       string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"` // start, created или title; с "-" - по убыванию
+	Limit      int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor     string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"` // nextCursor предыдущей страницы
+	CalendarID string                 `protobuf:"bytes,8,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	// Зона IANA, в которой разворачиваются события без своей зоны; по умолчанию - зона аккаунта.
+	TimeZone      string `protobuf:"bytes,9,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Day uint32 `protobuf:"varint,1,opt,name=day,proto3" json:"day,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NotifyRequest) Reset() {
-	*x = NotifyRequest{}
+func (x *SearchEventsRequest) Reset() {
+	*x = SearchEventsRequest{}
 	mi := &file_event_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NotifyRequest) String() string {
+func (x *SearchEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotifyRequest) ProtoMessage() {}
+func (*SearchEventsRequest) ProtoMessage() {}
 
-func (x *NotifyRequest) ProtoReflect() protoreflect.Message {
+func (x *SearchEventsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_event_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -625,41 +755,97 @@ func (x *NotifyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotifyRequest.ProtoReflect.Descriptor instead.
-func (*NotifyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchEventsRequest.ProtoReflect.Descriptor instead.
+func (*SearchEventsRequest) Descriptor() ([]byte, []int) {
 	return file_event_service_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *NotifyRequest) GetDay() uint32 {
+func (x *SearchEventsRequest) GetFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Day
+		return x.From
+	}
+	return nil
+}
+
+func (x *SearchEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *SearchEventsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchEventsRequest) GetNotified() bool {
+	if x != nil && x.Notified != nil {
+		return *x.Notified
+	}
+	return false
+}
+
+func (x *SearchEventsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *SearchEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-type NotifyResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *SearchEventsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
 
-	Msg   string `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
-	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+func (x *SearchEventsRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
 }
 
-func (x *NotifyResponse) Reset() {
-	*x = NotifyResponse{}
+func (x *SearchEventsRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type SearchEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"` // пустой - страниц больше нет
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchEventsResponse) Reset() {
+	*x = SearchEventsResponse{}
 	mi := &file_event_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NotifyResponse) String() string {
+func (x *SearchEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotifyResponse) ProtoMessage() {}
+func (*SearchEventsResponse) ProtoMessage() {}
 
-func (x *NotifyResponse) ProtoReflect() protoreflect.Message {
+func (x *SearchEventsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_event_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -671,179 +857,3380 @@ func (x *NotifyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotifyResponse.ProtoReflect.Descriptor instead.
-func (*NotifyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchEventsResponse.ProtoReflect.Descriptor instead.
+func (*SearchEventsResponse) Descriptor() ([]byte, []int) {
 	return file_event_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NotifyResponse) GetMsg() string {
+func (x *SearchEventsResponse) GetEvents() []*Event {
 	if x != nil {
-		return x.Msg
+		return x.Events
+	}
+	return nil
+}
+
+func (x *SearchEventsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
 	}
 	return ""
 }
 
-func (x *NotifyResponse) GetError() string {
+func (x *SearchEventsResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-var File_event_service_proto protoreflect.FileDescriptor
+// ответ приглашённого: accepted, declined или tentative
+type RespondToEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToEventRequest) Reset() {
+	*x = RespondToEventRequest{}
+	mi := &file_event_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToEventRequest) ProtoMessage() {}
+
+func (x *RespondToEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToEventRequest.ProtoReflect.Descriptor instead.
+func (*RespondToEventRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RespondToEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RespondToEventRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type RespondToEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToEventResponse) Reset() {
+	*x = RespondToEventResponse{}
+	mi := &file_event_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToEventResponse) ProtoMessage() {}
+
+func (x *RespondToEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToEventResponse.ProtoReflect.Descriptor instead.
+func (*RespondToEventResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RespondToEventResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
 
-var file_event_service_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x6c, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
-	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x0e, 0x65, 0x76,
-	0x65, 0x6e, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x52, 0x0e, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
-	0x44, 0x22, 0x3c, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49,
-	0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
-	0x7f, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79,
-	0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3d, 0x0a, 0x0e, 0x65, 0x76, 0x65,
-	0x6e, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x15, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x52, 0x0e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x54, 0x4f, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44,
-	0x22, 0x2f, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
-	0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x22, 0x28, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2f, 0x0a, 0x17, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xd0, 0x01, 0x0a,
-	0x1e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67,
-	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
-	0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52,
-	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x26, 0x0a, 0x06, 0x50, 0x65, 0x72, 0x69, 0x6f,
-	0x64, 0x12, 0x07, 0x0a, 0x03, 0x64, 0x61, 0x79, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x77, 0x65,
-	0x65, 0x6b, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x10, 0x02, 0x22,
-	0x5b, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x69,
-	0x6e, 0x67, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52,
-	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x3d, 0x0a, 0x13,
-	0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x50, 0x0a, 0x14, 0x47,
-	0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x21, 0x0a,
-	0x0d, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x64, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x64, 0x61, 0x79,
-	0x22, 0x38, 0x0a, 0x0e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6d, 0x73, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0x99, 0x03, 0x0a, 0x08, 0x53,
-	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x14, 0x2e, 0x41, 0x64, 0x64, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
-	0x41, 0x64, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x17, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x18, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79,
-	0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0f, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x17, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x5c, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74,
-	0x69, 0x6e, 0x67, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1f, 0x2e, 0x47, 0x65,
-	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x79, 0x55,
-	0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x47,
-	0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x79,
-	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b,
-	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x14,
-	0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42,
-	0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x4e,
-	0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0e, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x3b, 0x61, 0x70, 0x69,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+type NotifyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Day           uint32                 `protobuf:"varint,1,opt,name=day,proto3" json:"day,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotifyRequest) Reset() {
+	*x = NotifyRequest{}
+	mi := &file_event_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
+func (*NotifyRequest) ProtoMessage() {}
+
+func (x *NotifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyRequest.ProtoReflect.Descriptor instead.
+func (*NotifyRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *NotifyRequest) GetDay() uint32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+type NotifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotifyResponse) Reset() {
+	*x = NotifyResponse{}
+	mi := &file_event_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyResponse) ProtoMessage() {}
+
+func (x *NotifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyResponse.ProtoReflect.Descriptor instead.
+func (*NotifyResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NotifyResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *NotifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetFreeBusyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserID  string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	From    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	MinFree *durationpb.Duration   `protobuf:"bytes,4,opt,name=minFree,proto3" json:"minFree,omitempty"` // минимальная длина свободного промежутка
+	// Зона IANA интервалов ответа; по умолчанию - зона аккаунта.
+	TimeZone      string `protobuf:"bytes,5,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFreeBusyRequest) Reset() {
+	*x = GetFreeBusyRequest{}
+	mi := &file_event_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFreeBusyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeBusyRequest) ProtoMessage() {}
+
+func (x *GetFreeBusyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeBusyRequest.ProtoReflect.Descriptor instead.
+func (*GetFreeBusyRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetFreeBusyRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *GetFreeBusyRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetFreeBusyRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetFreeBusyRequest) GetMinFree() *durationpb.Duration {
+	if x != nil {
+		return x.MinFree
+	}
+	return nil
+}
+
+func (x *GetFreeBusyRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type GetFreeBusyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Busy          []*Interval            `protobuf:"bytes,1,rep,name=busy,proto3" json:"busy,omitempty"`
+	Free          []*Interval            `protobuf:"bytes,2,rep,name=free,proto3" json:"free,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFreeBusyResponse) Reset() {
+	*x = GetFreeBusyResponse{}
+	mi := &file_event_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFreeBusyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFreeBusyResponse) ProtoMessage() {}
+
+func (x *GetFreeBusyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFreeBusyResponse.ProtoReflect.Descriptor instead.
+func (*GetFreeBusyResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetFreeBusyResponse) GetBusy() []*Interval {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+func (x *GetFreeBusyResponse) GetFree() []*Interval {
+	if x != nil {
+		return x.Free
+	}
+	return nil
+}
+
+func (x *GetFreeBusyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_event_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LoginRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_event_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Login         string                 `protobuf:"bytes,2,opt,name=login,proto3" json:"login,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Channels      []*NotificationChannel `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	TimeZone      string                 `protobuf:"bytes,5,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_event_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Account) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Account) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *Account) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Account) GetChannels() []*NotificationChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *Account) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+// type: email, webhook или log; target - адрес письма или URL вебхука
+type NotificationChannel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationChannel) Reset() {
+	*x = NotificationChannel{}
+	mi := &file_event_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationChannel) ProtoMessage() {}
+
+func (x *NotificationChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationChannel.ProtoReflect.Descriptor instead.
+func (*NotificationChannel) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NotificationChannel) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NotificationChannel) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type RegisterAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAccountRequest) Reset() {
+	*x = RegisterAccountRequest{}
+	mi := &file_event_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAccountRequest) ProtoMessage() {}
+
+func (x *RegisterAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAccountRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAccountRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RegisterAccountRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *RegisterAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RegisterAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAccountResponse) Reset() {
+	*x = RegisterAccountResponse{}
+	mi := &file_event_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAccountResponse) ProtoMessage() {}
+
+func (x *RegisterAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAccountResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAccountResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RegisterAccountResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RegisterAccountResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	mi := &file_event_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{24}
+}
+
+type GetAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountResponse) Reset() {
+	*x = GetAccountResponse{}
+	mi := &file_event_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountResponse) ProtoMessage() {}
+
+func (x *GetAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetAccountResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetAccountResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ChangePasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=oldPassword,proto3" json:"oldPassword,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_event_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
+	if x != nil {
+		return x.OldPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_event_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChangePasswordResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// удаляет аккаунт из токена вместе со всеми событиями
+type DeleteAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_event_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{28}
+}
+
+type DeleteAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountResponse) Reset() {
+	*x = DeleteAccountResponse{}
+	mi := &file_event_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountResponse) ProtoMessage() {}
+
+func (x *DeleteAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAccountResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteAccountResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// пустой список возвращает каналы по умолчанию
+type SetNotificationChannelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channels      []*NotificationChannel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotificationChannelsRequest) Reset() {
+	*x = SetNotificationChannelsRequest{}
+	mi := &file_event_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotificationChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotificationChannelsRequest) ProtoMessage() {}
+
+func (x *SetNotificationChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotificationChannelsRequest.ProtoReflect.Descriptor instead.
+func (*SetNotificationChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetNotificationChannelsRequest) GetChannels() []*NotificationChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type SetNotificationChannelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotificationChannelsResponse) Reset() {
+	*x = SetNotificationChannelsResponse{}
+	mi := &file_event_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotificationChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotificationChannelsResponse) ProtoMessage() {}
+
+func (x *SetNotificationChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotificationChannelsResponse.ProtoReflect.Descriptor instead.
+func (*SetNotificationChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetNotificationChannelsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// зона IANA аккаунта, в которой считаются дни, недели и месяцы списка событий
+type SetTimeZoneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeZone      string                 `protobuf:"bytes,1,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTimeZoneRequest) Reset() {
+	*x = SetTimeZoneRequest{}
+	mi := &file_event_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTimeZoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTimeZoneRequest) ProtoMessage() {}
+
+func (x *SetTimeZoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTimeZoneRequest.ProtoReflect.Descriptor instead.
+func (*SetTimeZoneRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetTimeZoneRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type SetTimeZoneResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTimeZoneResponse) Reset() {
+	*x = SetTimeZoneResponse{}
+	mi := &file_event_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTimeZoneResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTimeZoneResponse) ProtoMessage() {}
+
+func (x *SetTimeZoneResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTimeZoneResponse.ProtoReflect.Descriptor instead.
+func (*SetTimeZoneResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetTimeZoneResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// role - права пользователя, запросившего календарь: owner, editor или viewer
+type Calendar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OwnerID       string                 `protobuf:"bytes,3,opt,name=ownerID,proto3" json:"ownerID,omitempty"`
+	Personal      bool                   `protobuf:"varint,4,opt,name=personal,proto3" json:"personal,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	Role          string                 `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Calendar) Reset() {
+	*x = Calendar{}
+	mi := &file_event_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Calendar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Calendar) ProtoMessage() {}
+
+func (x *Calendar) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Calendar.ProtoReflect.Descriptor instead.
+func (*Calendar) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Calendar) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Calendar) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Calendar) GetOwnerID() string {
+	if x != nil {
+		return x.OwnerID
+	}
+	return ""
+}
+
+func (x *Calendar) GetPersonal() bool {
+	if x != nil {
+		return x.Personal
+	}
+	return false
+}
+
+func (x *Calendar) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Calendar) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CalendarACLEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarACLEntry) Reset() {
+	*x = CalendarACLEntry{}
+	mi := &file_event_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarACLEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarACLEntry) ProtoMessage() {}
+
+func (x *CalendarACLEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarACLEntry.ProtoReflect.Descriptor instead.
+func (*CalendarACLEntry) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CalendarACLEntry) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *CalendarACLEntry) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CreateCalendarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCalendarRequest) Reset() {
+	*x = CreateCalendarRequest{}
+	mi := &file_event_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCalendarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCalendarRequest) ProtoMessage() {}
+
+func (x *CreateCalendarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCalendarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCalendarRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CreateCalendarRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateCalendarResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCalendarResponse) Reset() {
+	*x = CreateCalendarResponse{}
+	mi := &file_event_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCalendarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCalendarResponse) ProtoMessage() {}
+
+func (x *CreateCalendarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCalendarResponse.ProtoReflect.Descriptor instead.
+func (*CreateCalendarResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CreateCalendarResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateCalendarResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListCalendarsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalendarsRequest) Reset() {
+	*x = ListCalendarsRequest{}
+	mi := &file_event_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalendarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalendarsRequest) ProtoMessage() {}
+
+func (x *ListCalendarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalendarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCalendarsRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{38}
+}
+
+type ListCalendarsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Calendars     []*Calendar            `protobuf:"bytes,1,rep,name=calendars,proto3" json:"calendars,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalendarsResponse) Reset() {
+	*x = ListCalendarsResponse{}
+	mi := &file_event_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalendarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalendarsResponse) ProtoMessage() {}
+
+func (x *ListCalendarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalendarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCalendarsResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListCalendarsResponse) GetCalendars() []*Calendar {
+	if x != nil {
+		return x.Calendars
+	}
+	return nil
+}
+
+func (x *ListCalendarsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// удаляет календарь вместе с событиями; личный календарь удалить нельзя
+type DeleteCalendarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarID    string                 `protobuf:"bytes,1,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCalendarRequest) Reset() {
+	*x = DeleteCalendarRequest{}
+	mi := &file_event_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCalendarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCalendarRequest) ProtoMessage() {}
+
+func (x *DeleteCalendarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCalendarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCalendarRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteCalendarRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+type DeleteCalendarResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCalendarResponse) Reset() {
+	*x = DeleteCalendarResponse{}
+	mi := &file_event_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCalendarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCalendarResponse) ProtoMessage() {}
+
+func (x *DeleteCalendarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCalendarResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCalendarResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeleteCalendarResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetCalendarACLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarID    string                 `protobuf:"bytes,1,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCalendarACLRequest) Reset() {
+	*x = GetCalendarACLRequest{}
+	mi := &file_event_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCalendarACLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCalendarACLRequest) ProtoMessage() {}
+
+func (x *GetCalendarACLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCalendarACLRequest.ProtoReflect.Descriptor instead.
+func (*GetCalendarACLRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetCalendarACLRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+type GetCalendarACLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*CalendarACLEntry    `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCalendarACLResponse) Reset() {
+	*x = GetCalendarACLResponse{}
+	mi := &file_event_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCalendarACLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCalendarACLResponse) ProtoMessage() {}
+
+func (x *GetCalendarACLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCalendarACLResponse.ProtoReflect.Descriptor instead.
+func (*GetCalendarACLResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetCalendarACLResponse) GetEntries() []*CalendarACLEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetCalendarACLResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// выдаёт права editor или viewer; доступно только владельцу календаря
+type SetCalendarACLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarID    string                 `protobuf:"bytes,1,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	Entry         *CalendarACLEntry      `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCalendarACLRequest) Reset() {
+	*x = SetCalendarACLRequest{}
+	mi := &file_event_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCalendarACLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCalendarACLRequest) ProtoMessage() {}
+
+func (x *SetCalendarACLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCalendarACLRequest.ProtoReflect.Descriptor instead.
+func (*SetCalendarACLRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SetCalendarACLRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *SetCalendarACLRequest) GetEntry() *CalendarACLEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type SetCalendarACLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCalendarACLResponse) Reset() {
+	*x = SetCalendarACLResponse{}
+	mi := &file_event_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCalendarACLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCalendarACLResponse) ProtoMessage() {}
+
+func (x *SetCalendarACLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCalendarACLResponse.ProtoReflect.Descriptor instead.
+func (*SetCalendarACLResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SetCalendarACLResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RevokeCalendarAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarID    string                 `protobuf:"bytes,1,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCalendarAccessRequest) Reset() {
+	*x = RevokeCalendarAccessRequest{}
+	mi := &file_event_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCalendarAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCalendarAccessRequest) ProtoMessage() {}
+
+func (x *RevokeCalendarAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCalendarAccessRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCalendarAccessRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RevokeCalendarAccessRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *RevokeCalendarAccessRequest) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+type RevokeCalendarAccessResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCalendarAccessResponse) Reset() {
+	*x = RevokeCalendarAccessResponse{}
+	mi := &file_event_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCalendarAccessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCalendarAccessResponse) ProtoMessage() {}
+
+func (x *RevokeCalendarAccessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCalendarAccessResponse.ProtoReflect.Descriptor instead.
+func (*RevokeCalendarAccessResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RevokeCalendarAccessResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// resumeToken - токен из последнего полученного ответа; без него лента начинается с момента подключения
+// Если изменения после resumeToken уже удалены по сроку хранения, лента завершается ошибкой CONFLICT:
+// нужно заново прочитать события и подключиться без токена.
+type WatchChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesRequest) Reset() {
+	*x = WatchChangesRequest{}
+	mi := &file_event_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesRequest) ProtoMessage() {}
+
+func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WatchChangesRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// Первый ответ и ответы без изменений (раз в 15 секунд, пока изменений нет) только сообщают токен.
+type WatchChangesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*EventChange         `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"` // токен для продолжения ленты после этого ответа
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesResponse) Reset() {
+	*x = WatchChangesResponse{}
+	mi := &file_event_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesResponse) ProtoMessage() {}
+
+func (x *WatchChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesResponse.ProtoReflect.Descriptor instead.
+func (*WatchChangesResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WatchChangesResponse) GetChanges() []*EventChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *WatchChangesResponse) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// kind: created, updated, deleted или notified (отправлено напоминание)
+type EventChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	EventID       string                 `protobuf:"bytes,2,opt,name=eventID,proto3" json:"eventID,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,3,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=changedAt,proto3" json:"changedAt,omitempty"`
+	Event         *Event                 `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`             // текущее состояние события; нет, если событие удалено
+	ResumeToken   string                 `protobuf:"bytes,6,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"` // токен для продолжения ленты после этого изменения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventChange) Reset() {
+	*x = EventChange{}
+	mi := &file_event_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventChange) ProtoMessage() {}
+
+func (x *EventChange) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventChange.ProtoReflect.Descriptor instead.
+func (*EventChange) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *EventChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *EventChange) GetEventID() string {
+	if x != nil {
+		return x.EventID
+	}
+	return ""
+}
+
+func (x *EventChange) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *EventChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+func (x *EventChange) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *EventChange) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type BatchAddEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*EventCreateDTO      `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	Mode          BatchMode              `protobuf:"varint,2,opt,name=mode,proto3,enum=BatchMode" json:"mode,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,3,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	RejectOverlap bool                   `protobuf:"varint,4,opt,name=rejectOverlap,proto3" json:"rejectOverlap,omitempty"` // отклонять события, пересекающиеся с другими
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchAddEventsRequest) Reset() {
+	*x = BatchAddEventsRequest{}
+	mi := &file_event_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchAddEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchAddEventsRequest) ProtoMessage() {}
+
+func (x *BatchAddEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchAddEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchAddEventsRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BatchAddEventsRequest) GetEvents() []*EventCreateDTO {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *BatchAddEventsRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_ATOMIC
+}
+
+func (x *BatchAddEventsRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *BatchAddEventsRequest) GetRejectOverlap() bool {
+	if x != nil {
+		return x.RejectOverlap
+	}
+	return false
+}
+
+type EventUpdate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventCreateDTO  *EventCreateDTO        `protobuf:"bytes,2,opt,name=eventCreateDTO,proto3" json:"eventCreateDTO,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"` // как в UpdateEventByIDRequest
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EventUpdate) Reset() {
+	*x = EventUpdate{}
+	mi := &file_event_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventUpdate) ProtoMessage() {}
+
+func (x *EventUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventUpdate.ProtoReflect.Descriptor instead.
+func (*EventUpdate) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *EventUpdate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EventUpdate) GetEventCreateDTO() *EventCreateDTO {
+	if x != nil {
+		return x.EventCreateDTO
+	}
+	return nil
+}
+
+func (x *EventUpdate) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type BatchUpdateEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updates       []*EventUpdate         `protobuf:"bytes,1,rep,name=updates,proto3" json:"updates,omitempty"`
+	Mode          BatchMode              `protobuf:"varint,2,opt,name=mode,proto3,enum=BatchMode" json:"mode,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,3,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	RejectOverlap bool                   `protobuf:"varint,4,opt,name=rejectOverlap,proto3" json:"rejectOverlap,omitempty"` // отклонять изменения, после которых событие пересечётся с другими
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchUpdateEventsRequest) Reset() {
+	*x = BatchUpdateEventsRequest{}
+	mi := &file_event_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchUpdateEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchUpdateEventsRequest) ProtoMessage() {}
+
+func (x *BatchUpdateEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchUpdateEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchUpdateEventsRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *BatchUpdateEventsRequest) GetUpdates() []*EventUpdate {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+func (x *BatchUpdateEventsRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_ATOMIC
+}
+
+func (x *BatchUpdateEventsRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+func (x *BatchUpdateEventsRequest) GetRejectOverlap() bool {
+	if x != nil {
+		return x.RejectOverlap
+	}
+	return false
+}
+
+type BatchDeleteEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Mode          BatchMode              `protobuf:"varint,2,opt,name=mode,proto3,enum=BatchMode" json:"mode,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,3,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteEventsRequest) Reset() {
+	*x = BatchDeleteEventsRequest{}
+	mi := &file_event_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteEventsRequest) ProtoMessage() {}
+
+func (x *BatchDeleteEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteEventsRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *BatchDeleteEventsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchDeleteEventsRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_ATOMIC
+}
+
+func (x *BatchDeleteEventsRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+// Результат одного события пакета в порядке запроса. code - код статуса gRPC (0 - событие обработано),
+// reason - причина ErrorInfo, error - текст ошибки.
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code          int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_event_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BatchResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BatchResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchResult         `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_event_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *BatchResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Удалённые события лежат в корзине, пока планировщик не удалит их окончательно
+// (trashRetention в его конфигурации).
+type GetTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarID    string                 `protobuf:"bytes,1,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrashRequest) Reset() {
+	*x = GetTrashRequest{}
+	mi := &file_event_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrashRequest) ProtoMessage() {}
+
+func (x *GetTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrashRequest.ProtoReflect.Descriptor instead.
+func (*GetTrashRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetTrashRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+// события корзины, последние удалённые - первыми
+type GetTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrashResponse) Reset() {
+	*x = GetTrashResponse{}
+	mi := &file_event_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrashResponse) ProtoMessage() {}
+
+func (x *GetTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrashResponse.ProtoReflect.Descriptor instead.
+func (*GetTrashResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetTrashResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type RestoreEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,2,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreEventRequest) Reset() {
+	*x = RestoreEventRequest{}
+	mi := &file_event_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreEventRequest) ProtoMessage() {}
+
+func (x *RestoreEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreEventRequest.ProtoReflect.Descriptor instead.
+func (*RestoreEventRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *RestoreEventRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RestoreEventRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+type RestoreEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreEventResponse) Reset() {
+	*x = RestoreEventResponse{}
+	mi := &file_event_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreEventResponse) ProtoMessage() {}
+
+func (x *RestoreEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreEventResponse.ProtoReflect.Descriptor instead.
+func (*RestoreEventResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{60}
+}
+
+// История доступна и для события в корзине.
+type GetEventHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CalendarID    string                 `protobuf:"bytes,2,opt,name=calendarID,proto3" json:"calendarID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventHistoryRequest) Reset() {
+	*x = GetEventHistoryRequest{}
+	mi := &file_event_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventHistoryRequest) ProtoMessage() {}
+
+func (x *GetEventHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetEventHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetEventHistoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetEventHistoryRequest) GetCalendarID() string {
+	if x != nil {
+		return x.CalendarID
+	}
+	return ""
+}
+
+// записи журнала аудита события, старые - первыми
+type GetEventHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventHistoryResponse) Reset() {
+	*x = GetEventHistoryResponse{}
+	mi := &file_event_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventHistoryResponse) ProtoMessage() {}
+
+func (x *GetEventHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetEventHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetEventHistoryResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Запись журнала аудита: кто, когда и в каком запросе изменил событие.
+type AuditEntry struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventID string                 `protobuf:"bytes,2,opt,name=eventID,proto3" json:"eventID,omitempty"`
+	ActorID string                 `protobuf:"bytes,3,opt,name=actorID,proto3" json:"actorID,omitempty"`
+	// create, update, delete (перенос в корзину) или restore
+	Operation string `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	// событие до операции; нет при создании
+	Before *Event `protobuf:"bytes,5,opt,name=before,proto3" json:"before,omitempty"`
+	After  *Event `protobuf:"bytes,6,opt,name=after,proto3" json:"after,omitempty"`
+	// ID запроса из заголовка X-Request-ID или метаданных x-request-id
+	RequestID     string                 `protobuf:"bytes,7,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_event_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *AuditEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetEventID() string {
+	if x != nil {
+		return x.EventID
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetActorID() string {
+	if x != nil {
+		return x.ActorID
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetBefore() *Event {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetAfter() *Event {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Ошибка REST-шлюза в формате application/problem+json (RFC 7807);
+// type - urn:calendar:problem:not-found, forbidden, validation или conflict.
+type Problem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Status        int32                  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Detail        string                 `protobuf:"bytes,4,opt,name=detail,proto3" json:"detail,omitempty"`
+	Instance      string                 `protobuf:"bytes,5,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Problem) Reset() {
+	*x = Problem{}
+	mi := &file_event_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Problem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Problem) ProtoMessage() {}
+
+func (x *Problem) ProtoReflect() protoreflect.Message {
+	mi := &file_event_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Problem.ProtoReflect.Descriptor instead.
+func (*Problem) Descriptor() ([]byte, []int) {
+	return file_event_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *Problem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Problem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Problem) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Problem) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *Problem) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+var File_event_service_proto protoreflect.FileDescriptor
+
+const file_event_service_proto_rawDesc = "" +
+	"\n" +
+	"\x13event_service.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\vevent.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xb2\x01\n" +
+	"\x13AddEventByIDRequest\x12=\n" +
+	"\x0eeventCreateDTO\x18\x01 \x01(\v2\x15.event.EventCreateDTOR\x0eeventCreateDTO\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12$\n" +
+	"\rrejectOverlap\x18\x03 \x01(\bR\rrejectOverlap\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x04 \x01(\tR\n" +
+	"calendarID\"<\n" +
+	"\x14AddEventByIDResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xef\x01\n" +
+	"\x16UpdateEventByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12=\n" +
+	"\x0eeventCreateDTO\x18\x02 \x01(\v2\x15.event.EventCreateDTOR\x0eeventCreateDTO\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\tR\x06userID\x12$\n" +
+	"\rrejectOverlap\x18\x04 \x01(\bR\rrejectOverlap\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x05 \x01(\tR\n" +
+	"calendarID\x12(\n" +
+	"\x0fexpectedVersion\x18\x06 \x01(\x03R\x0fexpectedVersion\"/\n" +
+	"\x17UpdateEventByIDResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"H\n" +
+	"\x16DeleteEventByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x02 \x01(\tR\n" +
+	"calendarID\"/\n" +
+	"\x17DeleteEventByIDResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"\x8c\x02\n" +
+	"\x1eGetEventListingByUserIDRequest\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12.\n" +
+	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12>\n" +
+	"\x06period\x18\x03 \x01(\x0e2&.GetEventListingByUserIDRequest.PeriodR\x06period\x12\x1a\n" +
+	"\btimeZone\x18\x04 \x01(\tR\btimeZone\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x05 \x01(\tR\n" +
+	"calendarID\"&\n" +
+	"\x06Period\x12\a\n" +
+	"\x03day\x10\x00\x12\b\n" +
+	"\x04week\x10\x01\x12\t\n" +
+	"\x05month\x10\x02\"[\n" +
+	"\x1fGetEventListingByUserIDResponse\x12\"\n" +
+	"\x05event\x18\x01 \x03(\v2\f.event.EventR\x05event\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"]\n" +
+	"\x13GetEventByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x03 \x01(\tR\n" +
+	"calendarID\"P\n" +
+	"\x14GetEventByIDResponse\x12\"\n" +
+	"\x05event\x18\x01 \x01(\v2\f.event.EventR\x05event\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xb3\x02\n" +
+	"\x13SearchEventsRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12\x1f\n" +
+	"\bnotified\x18\x04 \x01(\bH\x00R\bnotified\x88\x01\x01\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\b \x01(\tR\n" +
+	"calendarID\x12\x1a\n" +
+	"\btimeZone\x18\t \x01(\tR\btimeZoneB\v\n" +
+	"\t_notified\"r\n" +
+	"\x14SearchEventsResponse\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"?\n" +
+	"\x15RespondToEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\".\n" +
+	"\x16RespondToEventResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"!\n" +
+	"\rNotifyRequest\x12\x10\n" +
+	"\x03day\x18\x01 \x01(\rR\x03day\"8\n" +
+	"\x0eNotifyResponse\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\xd9\x01\n" +
+	"\x12GetFreeBusyRequest\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x123\n" +
+	"\aminFree\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\aminFree\x12\x1a\n" +
+	"\btimeZone\x18\x05 \x01(\tR\btimeZone\"u\n" +
+	"\x13GetFreeBusyResponse\x12#\n" +
+	"\x04busy\x18\x01 \x03(\v2\x0f.event.IntervalR\x04busy\x12#\n" +
+	"\x04free\x18\x02 \x03(\v2\x0f.event.IntervalR\x04free\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"u\n" +
+	"\rLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x128\n" +
+	"\texpiresAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xb7\x01\n" +
+	"\aAccount\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05login\x18\x02 \x01(\tR\x05login\x128\n" +
+	"\tcreatedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x120\n" +
+	"\bchannels\x18\x04 \x03(\v2\x14.NotificationChannelR\bchannels\x12\x1a\n" +
+	"\btimeZone\x18\x05 \x01(\tR\btimeZone\"A\n" +
+	"\x13NotificationChannel\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\"J\n" +
+	"\x16RegisterAccountRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"?\n" +
+	"\x17RegisterAccountResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x13\n" +
+	"\x11GetAccountRequest\"N\n" +
+	"\x12GetAccountResponse\x12\"\n" +
+	"\aaccount\x18\x01 \x01(\v2\b.AccountR\aaccount\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"[\n" +
+	"\x15ChangePasswordRequest\x12 \n" +
+	"\voldPassword\x18\x01 \x01(\tR\voldPassword\x12 \n" +
+	"\vnewPassword\x18\x02 \x01(\tR\vnewPassword\".\n" +
+	"\x16ChangePasswordResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"\x16\n" +
+	"\x14DeleteAccountRequest\"-\n" +
+	"\x15DeleteAccountResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"R\n" +
+	"\x1eSetNotificationChannelsRequest\x120\n" +
+	"\bchannels\x18\x01 \x03(\v2\x14.NotificationChannelR\bchannels\"7\n" +
+	"\x1fSetNotificationChannelsResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"0\n" +
+	"\x12SetTimeZoneRequest\x12\x1a\n" +
+	"\btimeZone\x18\x01 \x01(\tR\btimeZone\"+\n" +
+	"\x13SetTimeZoneResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"\xb2\x01\n" +
+	"\bCalendar\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aownerID\x18\x03 \x01(\tR\aownerID\x12\x1a\n" +
+	"\bpersonal\x18\x04 \x01(\bR\bpersonal\x128\n" +
+	"\tcreatedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x12\n" +
+	"\x04role\x18\x06 \x01(\tR\x04role\">\n" +
+	"\x10CalendarACLEntry\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"+\n" +
+	"\x15CreateCalendarRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\">\n" +
+	"\x16CreateCalendarResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x16\n" +
+	"\x14ListCalendarsRequest\"V\n" +
+	"\x15ListCalendarsResponse\x12'\n" +
+	"\tcalendars\x18\x01 \x03(\v2\t.CalendarR\tcalendars\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"7\n" +
+	"\x15DeleteCalendarRequest\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x01 \x01(\tR\n" +
+	"calendarID\".\n" +
+	"\x16DeleteCalendarResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"7\n" +
+	"\x15GetCalendarACLRequest\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x01 \x01(\tR\n" +
+	"calendarID\"[\n" +
+	"\x16GetCalendarACLResponse\x12+\n" +
+	"\aentries\x18\x01 \x03(\v2\x11.CalendarACLEntryR\aentries\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"`\n" +
+	"\x15SetCalendarACLRequest\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x01 \x01(\tR\n" +
+	"calendarID\x12'\n" +
+	"\x05entry\x18\x02 \x01(\v2\x11.CalendarACLEntryR\x05entry\".\n" +
+	"\x16SetCalendarACLResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"U\n" +
+	"\x1bRevokeCalendarAccessRequest\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x01 \x01(\tR\n" +
+	"calendarID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\"4\n" +
+	"\x1cRevokeCalendarAccessResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"7\n" +
+	"\x13WatchChangesRequest\x12 \n" +
+	"\vresumeToken\x18\x01 \x01(\tR\vresumeToken\"`\n" +
+	"\x14WatchChangesResponse\x12&\n" +
+	"\achanges\x18\x01 \x03(\v2\f.EventChangeR\achanges\x12 \n" +
+	"\vresumeToken\x18\x02 \x01(\tR\vresumeToken\"\xdb\x01\n" +
+	"\vEventChange\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x18\n" +
+	"\aeventID\x18\x02 \x01(\tR\aeventID\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x03 \x01(\tR\n" +
+	"calendarID\x128\n" +
+	"\tchangedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\x12\"\n" +
+	"\x05event\x18\x05 \x01(\v2\f.event.EventR\x05event\x12 \n" +
+	"\vresumeToken\x18\x06 \x01(\tR\vresumeToken\"\xac\x01\n" +
+	"\x15BatchAddEventsRequest\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.event.EventCreateDTOR\x06events\x12\x1e\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\n" +
+	".BatchModeR\x04mode\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x03 \x01(\tR\n" +
+	"calendarID\x12$\n" +
+	"\rrejectOverlap\x18\x04 \x01(\bR\rrejectOverlap\"\x86\x01\n" +
+	"\vEventUpdate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12=\n" +
+	"\x0eeventCreateDTO\x18\x02 \x01(\v2\x15.event.EventCreateDTOR\x0eeventCreateDTO\x12(\n" +
+	"\x0fexpectedVersion\x18\x03 \x01(\x03R\x0fexpectedVersion\"\xa8\x01\n" +
+	"\x18BatchUpdateEventsRequest\x12&\n" +
+	"\aupdates\x18\x01 \x03(\v2\f.EventUpdateR\aupdates\x12\x1e\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\n" +
+	".BatchModeR\x04mode\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x03 \x01(\tR\n" +
+	"calendarID\x12$\n" +
+	"\rrejectOverlap\x18\x04 \x01(\bR\rrejectOverlap\"l\n" +
+	"\x18BatchDeleteEventsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x1e\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\n" +
+	".BatchModeR\x04mode\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x03 \x01(\tR\n" +
+	"calendarID\"_\n" +
+	"\vBatchResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"7\n" +
+	"\rBatchResponse\x12&\n" +
+	"\aresults\x18\x01 \x03(\v2\f.BatchResultR\aresults\"1\n" +
+	"\x0fGetTrashRequest\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x01 \x01(\tR\n" +
+	"calendarID\"8\n" +
+	"\x10GetTrashResponse\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\"E\n" +
+	"\x13RestoreEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x02 \x01(\tR\n" +
+	"calendarID\"\x16\n" +
+	"\x14RestoreEventResponse\"H\n" +
+	"\x16GetEventHistoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"calendarID\x18\x02 \x01(\tR\n" +
+	"calendarID\"@\n" +
+	"\x17GetEventHistoryResponse\x12%\n" +
+	"\aentries\x18\x01 \x03(\v2\v.AuditEntryR\aentries\"\x90\x02\n" +
+	"\n" +
+	"AuditEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aeventID\x18\x02 \x01(\tR\aeventID\x12\x18\n" +
+	"\aactorID\x18\x03 \x01(\tR\aactorID\x12\x1c\n" +
+	"\toperation\x18\x04 \x01(\tR\toperation\x12$\n" +
+	"\x06before\x18\x05 \x01(\v2\f.event.EventR\x06before\x12\"\n" +
+	"\x05after\x18\x06 \x01(\v2\f.event.EventR\x05after\x12\x1c\n" +
+	"\trequestID\x18\a \x01(\tR\trequestID\x128\n" +
+	"\tcreatedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x7f\n" +
+	"\aProblem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06detail\x18\x04 \x01(\tR\x06detail\x12\x1a\n" +
+	"\binstance\x18\x05 \x01(\tR\binstance*(\n" +
+	"\tBatchMode\x12\n" +
+	"\n" +
+	"\x06ATOMIC\x10\x00\x12\x0f\n" +
+	"\vBEST_EFFORT\x10\x012\xc1\x1c\n" +
+	"\bStorager\x12z\n" +
+	"\fAddEventByID\x12\x14.AddEventByIDRequest\x1a\x15.AddEventByIDResponse\"=\x82\xd3\xe4\x93\x027:\x01*Z&:\x01*\"!/v1/calendars/{calendarID}/events\"\n" +
+	"/v1/events\x12\xd7\x02\n" +
+	"\x0fUpdateEventByID\x12\x17.UpdateEventByIDRequest\x1a\x18.UpdateEventByIDResponse\"\x90\x02\x92A\xc5\x01J]\n" +
+	"\x03412\x12V\n" +
+	"FСобытие изменили после версии из If-Match.\x12\f\n" +
+	"\n" +
+	"\x1a\b.Problemrd\n" +
+	"b\n" +
+	"\bIf-Match\x12TETag события из GET; если событие уже изменили - 412.\x18\x01\x82\xd3\xe4\x93\x02A:\x01*Z+:\x01*\x1a&/v1/calendars/{calendarID}/events/{id}\x1a\x0f/v1/events/{id}\x12\x87\x01\n" +
+	"\x0fDeleteEventByID\x12\x17.DeleteEventByIDRequest\x1a\x18.DeleteEventByIDResponse\"A\x82\xd3\xe4\x93\x02;Z(*&/v1/calendars/{calendarID}/events/{id}*\x0f/v1/events/{id}\x12\x95\x01\n" +
+	"\x17GetEventListingByUserID\x12\x1f.GetEventListingByUserIDRequest\x1a .GetEventListingByUserIDResponse\"7\x82\xd3\xe4\x93\x021Z#\x12!/v1/calendars/{calendarID}/events\x12\n" +
+	"/v1/events\x12\x94\x02\n" +
+	"\fGetEventByID\x12\x14.GetEventByIDRequest\x1a\x15.GetEventByIDResponse\"\xd6\x01\x92A\x91\x01J\x8e\x01\n" +
+	"\x03200\x12\x86\x01\n" +
+	"\x0fСобытие.\x12\x19\n" +
+	"\x17\x1a\x15.GetEventByIDResponse\x1aX\n" +
+	"\x04ETag\x12P\n" +
+	"FВерсия события для If-Match при изменении.\x12\x06string\x82\xd3\xe4\x93\x02;Z(\x12&/v1/calendars/{calendarID}/events/{id}\x12\x0f/v1/events/{id}\x12\x82\x01\n" +
+	"\fSearchEvents\x12\x14.SearchEventsRequest\x1a\x15.SearchEventsResponse\"E\x82\xd3\xe4\x93\x02?Z*\x12(/v1/calendars/{calendarID}/events:search\x12\x11/v1/events:search\x12b\n" +
+	"\x0eRespondToEvent\x12\x16.RespondToEventRequest\x1a\x17.RespondToEventResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\x1a\x14/v1/events/{id}/rsvp\x12@\n" +
+	"\x06Notify\x12\x0e.NotifyRequest\x1a\x0f.NotifyResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/notify\x12N\n" +
+	"\vGetFreeBusy\x12\x13.GetFreeBusyRequest\x1a\x14.GetFreeBusyResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/freebusy\x12A\n" +
+	"\x05Login\x12\r.LoginRequest\x1a\x0e.LoginResponse\"\x19\x92A\x02b\x00\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/login\x12b\n" +
+	"\x0fRegisterAccount\x12\x17.RegisterAccountRequest\x1a\x18.RegisterAccountResponse\"\x1c\x92A\x02b\x00\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/accounts\x12J\n" +
+	"\n" +
+	"GetAccount\x12\x12.GetAccountRequest\x1a\x13.GetAccountResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/account\x12b\n" +
+	"\x0eChangePassword\x12\x16.ChangePasswordRequest\x1a\x17.ChangePasswordResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/account/password\x12S\n" +
+	"\rDeleteAccount\x12\x15.DeleteAccountRequest\x1a\x16.DeleteAccountResponse\"\x13\x82\xd3\xe4\x93\x02\r*\v/v1/account\x12}\n" +
+	"\x17SetNotificationChannels\x12\x1f.SetNotificationChannelsRequest\x1a .SetNotificationChannelsResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\x1a\x14/v1/account/channels\x12Y\n" +
+	"\vSetTimeZone\x12\x13.SetTimeZoneRequest\x1a\x14.SetTimeZoneResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\x1a\x14/v1/account/timezone\x12[\n" +
+	"\x0eCreateCalendar\x12\x16.CreateCalendarRequest\x1a\x17.CreateCalendarResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/calendars\x12U\n" +
+	"\rListCalendars\x12\x15.ListCalendarsRequest\x1a\x16.ListCalendarsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/calendars\x12e\n" +
+	"\x0eDeleteCalendar\x12\x16.DeleteCalendarRequest\x1a\x17.DeleteCalendarResponse\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/v1/calendars/{calendarID}\x12i\n" +
+	"\x0eGetCalendarACL\x12\x16.GetCalendarACLRequest\x1a\x17.GetCalendarACLResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/calendars/{calendarID}/acl\x12p\n" +
+	"\x0eSetCalendarACL\x12\x16.SetCalendarACLRequest\x1a\x17.SetCalendarACLResponse\"-\x82\xd3\xe4\x93\x02':\x05entry\x1a\x1e/v1/calendars/{calendarID}/acl\x12\x84\x01\n" +
+	"\x14RevokeCalendarAccess\x12\x1c.RevokeCalendarAccessRequest\x1a\x1d.RevokeCalendarAccessResponse\"/\x82\xd3\xe4\x93\x02)*'/v1/calendars/{calendarID}/acl/{userID}\x12=\n" +
+	"\fWatchChanges\x12\x14.WatchChangesRequest\x1a\x15.WatchChangesResponse0\x01\x12\x8f\x01\n" +
+	"\x0eBatchAddEvents\x12\x16.BatchAddEventsRequest\x1a\x0e.BatchResponse\"U\x82\xd3\xe4\x93\x02O:\x01*Z2:\x01*\"-/v1/calendars/{calendarID}/events:batchCreate\"\x16/v1/events:batchCreate\x12\x95\x01\n" +
+	"\x11BatchUpdateEvents\x12\x19.BatchUpdateEventsRequest\x1a\x0e.BatchResponse\"U\x82\xd3\xe4\x93\x02O:\x01*Z2:\x01*\"-/v1/calendars/{calendarID}/events:batchUpdate\"\x16/v1/events:batchUpdate\x12\x95\x01\n" +
+	"\x11BatchDeleteEvents\x12\x19.BatchDeleteEventsRequest\x1a\x0e.BatchResponse\"U\x82\xd3\xe4\x93\x02O:\x01*Z2:\x01*\"-/v1/calendars/{calendarID}/events:batchDelete\"\x16/v1/events:batchDelete\x12t\n" +
+	"\bGetTrash\x12\x10.GetTrashRequest\x1a\x11.GetTrashResponse\"C\x82\xd3\xe4\x93\x02=Z)\x12'/v1/calendars/{calendarID}/events:trash\x12\x10/v1/events:trash\x12\x94\x01\n" +
+	"\fRestoreEvent\x12\x14.RestoreEventRequest\x1a\x15.RestoreEventResponse\"W\x82\xd3\xe4\x93\x02Q:\x01*Z3:\x01*\"./v1/calendars/{calendarID}/events/{id}:restore\"\x17/v1/events/{id}:restore\x12\x97\x01\n" +
+	"\x0fGetEventHistory\x12\x17.GetEventHistoryRequest\x1a\x18.GetEventHistoryResponse\"Q\x82\xd3\xe4\x93\x02KZ0\x12./v1/calendars/{calendarID}/events/{id}/history\x12\x17/v1/events/{id}/historyB\xc0\x01\x92A\xb4\x01\x12\x13\n" +
+	"\fCalendar API2\x031.0R^\n" +
+	"\adefault\x12S\n" +
+	"CОшибка в формате application/problem+json (RFC 7807).\x12\f\n" +
+	"\n" +
+	"\x1a\b.ProblemZ/\n" +
+	"-\n" +
+	"\x06bearer\x12#\b\x02\x12\x0eBearer <token>\x1a\rAuthorization \x02b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06bearer\x12\x00Z\x06./;apib\x06proto3"
+
 var (
 	file_event_service_proto_rawDescOnce sync.Once
-	file_event_service_proto_rawDescData = file_event_service_proto_rawDesc
+	file_event_service_proto_rawDescData []byte
 )
 
 func file_event_service_proto_rawDescGZIP() []byte {
 	file_event_service_proto_rawDescOnce.Do(func() {
-		file_event_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_event_service_proto_rawDescData)
+		file_event_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_event_service_proto_rawDesc), len(file_event_service_proto_rawDesc)))
 	})
 	return file_event_service_proto_rawDescData
 }
 
-var file_event_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_event_service_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_event_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_event_service_proto_msgTypes = make([]protoimpl.MessageInfo, 65)
 var file_event_service_proto_goTypes = []any{
-	(GetEventListingByUserIDRequest_Period)(0), // 0: GetEventListingByUserIDRequest.Period
-	(*AddEventByIDRequest)(nil),                // 1: AddEventByIDRequest
-	(*AddEventByIDResponse)(nil),               // 2: AddEventByIDResponse
-	(*UpdateEventByIDRequest)(nil),             // 3: UpdateEventByIDRequest
-	(*UpdateEventByIDResponse)(nil),            // 4: UpdateEventByIDResponse
-	(*DeleteEventByIDRequest)(nil),             // 5: DeleteEventByIDRequest
-	(*DeleteEventByIDResponse)(nil),            // 6: DeleteEventByIDResponse
-	(*GetEventListingByUserIDRequest)(nil),     // 7: GetEventListingByUserIDRequest
-	(*GetEventListingByUserIDResponse)(nil),    // 8: GetEventListingByUserIDResponse
-	(*GetEventByIDRequest)(nil),                // 9: GetEventByIDRequest
-	(*GetEventByIDResponse)(nil),               // 10: GetEventByIDResponse
-	(*NotifyRequest)(nil),                      // 11: NotifyRequest
-	(*NotifyResponse)(nil),                     // 12: NotifyResponse
-	(*EventCreateDTO)(nil),                     // 13: event.EventCreateDTO
-	(*timestamp.Timestamp)(nil),                // 14: google.protobuf.Timestamp
-	(*Event)(nil),                              // 15: event.Event
+	(BatchMode)(0), // 0: BatchMode
+	(GetEventListingByUserIDRequest_Period)(0), // 1: GetEventListingByUserIDRequest.Period
+	(*AddEventByIDRequest)(nil),                // 2: AddEventByIDRequest
+	(*AddEventByIDResponse)(nil),               // 3: AddEventByIDResponse
+	(*UpdateEventByIDRequest)(nil),             // 4: UpdateEventByIDRequest
+	(*UpdateEventByIDResponse)(nil),            // 5: UpdateEventByIDResponse
+	(*DeleteEventByIDRequest)(nil),             // 6: DeleteEventByIDRequest
+	(*DeleteEventByIDResponse)(nil),            // 7: DeleteEventByIDResponse
+	(*GetEventListingByUserIDRequest)(nil),     // 8: GetEventListingByUserIDRequest
+	(*GetEventListingByUserIDResponse)(nil),    // 9: GetEventListingByUserIDResponse
+	(*GetEventByIDRequest)(nil),                // 10: GetEventByIDRequest
+	(*GetEventByIDResponse)(nil),               // 11: GetEventByIDResponse
+	(*SearchEventsRequest)(nil),                // 12: SearchEventsRequest
+	(*SearchEventsResponse)(nil),               // 13: SearchEventsResponse
+	(*RespondToEventRequest)(nil),              // 14: RespondToEventRequest
+	(*RespondToEventResponse)(nil),             // 15: RespondToEventResponse
+	(*NotifyRequest)(nil),                      // 16: NotifyRequest
+	(*NotifyResponse)(nil),                     // 17: NotifyResponse
+	(*GetFreeBusyRequest)(nil),                 // 18: GetFreeBusyRequest
+	(*GetFreeBusyResponse)(nil),                // 19: GetFreeBusyResponse
+	(*LoginRequest)(nil),                       // 20: LoginRequest
+	(*LoginResponse)(nil),                      // 21: LoginResponse
+	(*Account)(nil),                            // 22: Account
+	(*NotificationChannel)(nil),                // 23: NotificationChannel
+	(*RegisterAccountRequest)(nil),             // 24: RegisterAccountRequest
+	(*RegisterAccountResponse)(nil),            // 25: RegisterAccountResponse
+	(*GetAccountRequest)(nil),                  // 26: GetAccountRequest
+	(*GetAccountResponse)(nil),                 // 27: GetAccountResponse
+	(*ChangePasswordRequest)(nil),              // 28: ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),             // 29: ChangePasswordResponse
+	(*DeleteAccountRequest)(nil),               // 30: DeleteAccountRequest
+	(*DeleteAccountResponse)(nil),              // 31: DeleteAccountResponse
+	(*SetNotificationChannelsRequest)(nil),     // 32: SetNotificationChannelsRequest
+	(*SetNotificationChannelsResponse)(nil),    // 33: SetNotificationChannelsResponse
+	(*SetTimeZoneRequest)(nil),                 // 34: SetTimeZoneRequest
+	(*SetTimeZoneResponse)(nil),                // 35: SetTimeZoneResponse
+	(*Calendar)(nil),                           // 36: Calendar
+	(*CalendarACLEntry)(nil),                   // 37: CalendarACLEntry
+	(*CreateCalendarRequest)(nil),              // 38: CreateCalendarRequest
+	(*CreateCalendarResponse)(nil),             // 39: CreateCalendarResponse
+	(*ListCalendarsRequest)(nil),               // 40: ListCalendarsRequest
+	(*ListCalendarsResponse)(nil),              // 41: ListCalendarsResponse
+	(*DeleteCalendarRequest)(nil),              // 42: DeleteCalendarRequest
+	(*DeleteCalendarResponse)(nil),             // 43: DeleteCalendarResponse
+	(*GetCalendarACLRequest)(nil),              // 44: GetCalendarACLRequest
+	(*GetCalendarACLResponse)(nil),             // 45: GetCalendarACLResponse
+	(*SetCalendarACLRequest)(nil),              // 46: SetCalendarACLRequest
+	(*SetCalendarACLResponse)(nil),             // 47: SetCalendarACLResponse
+	(*RevokeCalendarAccessRequest)(nil),        // 48: RevokeCalendarAccessRequest
+	(*RevokeCalendarAccessResponse)(nil),       // 49: RevokeCalendarAccessResponse
+	(*WatchChangesRequest)(nil),                // 50: WatchChangesRequest
+	(*WatchChangesResponse)(nil),               // 51: WatchChangesResponse
+	(*EventChange)(nil),                        // 52: EventChange
+	(*BatchAddEventsRequest)(nil),              // 53: BatchAddEventsRequest
+	(*EventUpdate)(nil),                        // 54: EventUpdate
+	(*BatchUpdateEventsRequest)(nil),           // 55: BatchUpdateEventsRequest
+	(*BatchDeleteEventsRequest)(nil),           // 56: BatchDeleteEventsRequest
+	(*BatchResult)(nil),                        // 57: BatchResult
+	(*BatchResponse)(nil),                      // 58: BatchResponse
+	(*GetTrashRequest)(nil),                    // 59: GetTrashRequest
+	(*GetTrashResponse)(nil),                   // 60: GetTrashResponse
+	(*RestoreEventRequest)(nil),                // 61: RestoreEventRequest
+	(*RestoreEventResponse)(nil),               // 62: RestoreEventResponse
+	(*GetEventHistoryRequest)(nil),             // 63: GetEventHistoryRequest
+	(*GetEventHistoryResponse)(nil),            // 64: GetEventHistoryResponse
+	(*AuditEntry)(nil),                         // 65: AuditEntry
+	(*Problem)(nil),                            // 66: Problem
+	(*EventCreateDTO)(nil),                     // 67: event.EventCreateDTO
+	(*timestamppb.Timestamp)(nil),              // 68: google.protobuf.Timestamp
+	(*Event)(nil),                              // 69: event.Event
+	(*durationpb.Duration)(nil),                // 70: google.protobuf.Duration
+	(*Interval)(nil),                           // 71: event.Interval
 }
 var file_event_service_proto_depIdxs = []int32{
-	13, // 0: AddEventByIDRequest.eventCreateDTO:type_name -> event.EventCreateDTO
-	13, // 1: UpdateEventByIDRequest.eventCreateDTO:type_name -> event.EventCreateDTO
-	14, // 2: GetEventListingByUserIDRequest.date:type_name -> google.protobuf.Timestamp
-	0,  // 3: GetEventListingByUserIDRequest.period:type_name -> GetEventListingByUserIDRequest.Period
-	15, // 4: GetEventListingByUserIDResponse.event:type_name -> event.Event
-	15, // 5: GetEventByIDResponse.event:type_name -> event.Event
-	1,  // 6: Storager.AddEventByID:input_type -> AddEventByIDRequest
-	3,  // 7: Storager.UpdateEventByID:input_type -> UpdateEventByIDRequest
-	5,  // 8: Storager.DeleteEventByID:input_type -> DeleteEventByIDRequest
-	7,  // 9: Storager.GetEventListingByUserID:input_type -> GetEventListingByUserIDRequest
-	9,  // 10: Storager.GetEventByID:input_type -> GetEventByIDRequest
-	11, // 11: Storager.Notify:input_type -> NotifyRequest
-	2,  // 12: Storager.AddEventByID:output_type -> AddEventByIDResponse
-	4,  // 13: Storager.UpdateEventByID:output_type -> UpdateEventByIDResponse
-	6,  // 14: Storager.DeleteEventByID:output_type -> DeleteEventByIDResponse
-	8,  // 15: Storager.GetEventListingByUserID:output_type -> GetEventListingByUserIDResponse
-	10, // 16: Storager.GetEventByID:output_type -> GetEventByIDResponse
-	12, // 17: Storager.Notify:output_type -> NotifyResponse
-	12, // [12:18] is the sub-list for method output_type
-	6,  // [6:12] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	67, // 0: AddEventByIDRequest.eventCreateDTO:type_name -> event.EventCreateDTO
+	67, // 1: UpdateEventByIDRequest.eventCreateDTO:type_name -> event.EventCreateDTO
+	68, // 2: GetEventListingByUserIDRequest.date:type_name -> google.protobuf.Timestamp
+	1,  // 3: GetEventListingByUserIDRequest.period:type_name -> GetEventListingByUserIDRequest.Period
+	69, // 4: GetEventListingByUserIDResponse.event:type_name -> event.Event
+	69, // 5: GetEventByIDResponse.event:type_name -> event.Event
+	68, // 6: SearchEventsRequest.from:type_name -> google.protobuf.Timestamp
+	68, // 7: SearchEventsRequest.to:type_name -> google.protobuf.Timestamp
+	69, // 8: SearchEventsResponse.events:type_name -> event.Event
+	68, // 9: GetFreeBusyRequest.from:type_name -> google.protobuf.Timestamp
+	68, // 10: GetFreeBusyRequest.to:type_name -> google.protobuf.Timestamp
+	70, // 11: GetFreeBusyRequest.minFree:type_name -> google.protobuf.Duration
+	71, // 12: GetFreeBusyResponse.busy:type_name -> event.Interval
+	71, // 13: GetFreeBusyResponse.free:type_name -> event.Interval
+	68, // 14: LoginResponse.expiresAt:type_name -> google.protobuf.Timestamp
+	68, // 15: Account.createdAt:type_name -> google.protobuf.Timestamp
+	23, // 16: Account.channels:type_name -> NotificationChannel
+	22, // 17: GetAccountResponse.account:type_name -> Account
+	23, // 18: SetNotificationChannelsRequest.channels:type_name -> NotificationChannel
+	68, // 19: Calendar.createdAt:type_name -> google.protobuf.Timestamp
+	36, // 20: ListCalendarsResponse.calendars:type_name -> Calendar
+	37, // 21: GetCalendarACLResponse.entries:type_name -> CalendarACLEntry
+	37, // 22: SetCalendarACLRequest.entry:type_name -> CalendarACLEntry
+	52, // 23: WatchChangesResponse.changes:type_name -> EventChange
+	68, // 24: EventChange.changedAt:type_name -> google.protobuf.Timestamp
+	69, // 25: EventChange.event:type_name -> event.Event
+	67, // 26: BatchAddEventsRequest.events:type_name -> event.EventCreateDTO
+	0,  // 27: BatchAddEventsRequest.mode:type_name -> BatchMode
+	67, // 28: EventUpdate.eventCreateDTO:type_name -> event.EventCreateDTO
+	54, // 29: BatchUpdateEventsRequest.updates:type_name -> EventUpdate
+	0,  // 30: BatchUpdateEventsRequest.mode:type_name -> BatchMode
+	0,  // 31: BatchDeleteEventsRequest.mode:type_name -> BatchMode
+	57, // 32: BatchResponse.results:type_name -> BatchResult
+	69, // 33: GetTrashResponse.events:type_name -> event.Event
+	65, // 34: GetEventHistoryResponse.entries:type_name -> AuditEntry
+	69, // 35: AuditEntry.before:type_name -> event.Event
+	69, // 36: AuditEntry.after:type_name -> event.Event
+	68, // 37: AuditEntry.createdAt:type_name -> google.protobuf.Timestamp
+	2,  // 38: Storager.AddEventByID:input_type -> AddEventByIDRequest
+	4,  // 39: Storager.UpdateEventByID:input_type -> UpdateEventByIDRequest
+	6,  // 40: Storager.DeleteEventByID:input_type -> DeleteEventByIDRequest
+	8,  // 41: Storager.GetEventListingByUserID:input_type -> GetEventListingByUserIDRequest
+	10, // 42: Storager.GetEventByID:input_type -> GetEventByIDRequest
+	12, // 43: Storager.SearchEvents:input_type -> SearchEventsRequest
+	14, // 44: Storager.RespondToEvent:input_type -> RespondToEventRequest
+	16, // 45: Storager.Notify:input_type -> NotifyRequest
+	18, // 46: Storager.GetFreeBusy:input_type -> GetFreeBusyRequest
+	20, // 47: Storager.Login:input_type -> LoginRequest
+	24, // 48: Storager.RegisterAccount:input_type -> RegisterAccountRequest
+	26, // 49: Storager.GetAccount:input_type -> GetAccountRequest
+	28, // 50: Storager.ChangePassword:input_type -> ChangePasswordRequest
+	30, // 51: Storager.DeleteAccount:input_type -> DeleteAccountRequest
+	32, // 52: Storager.SetNotificationChannels:input_type -> SetNotificationChannelsRequest
+	34, // 53: Storager.SetTimeZone:input_type -> SetTimeZoneRequest
+	38, // 54: Storager.CreateCalendar:input_type -> CreateCalendarRequest
+	40, // 55: Storager.ListCalendars:input_type -> ListCalendarsRequest
+	42, // 56: Storager.DeleteCalendar:input_type -> DeleteCalendarRequest
+	44, // 57: Storager.GetCalendarACL:input_type -> GetCalendarACLRequest
+	46, // 58: Storager.SetCalendarACL:input_type -> SetCalendarACLRequest
+	48, // 59: Storager.RevokeCalendarAccess:input_type -> RevokeCalendarAccessRequest
+	50, // 60: Storager.WatchChanges:input_type -> WatchChangesRequest
+	53, // 61: Storager.BatchAddEvents:input_type -> BatchAddEventsRequest
+	55, // 62: Storager.BatchUpdateEvents:input_type -> BatchUpdateEventsRequest
+	56, // 63: Storager.BatchDeleteEvents:input_type -> BatchDeleteEventsRequest
+	59, // 64: Storager.GetTrash:input_type -> GetTrashRequest
+	61, // 65: Storager.RestoreEvent:input_type -> RestoreEventRequest
+	63, // 66: Storager.GetEventHistory:input_type -> GetEventHistoryRequest
+	3,  // 67: Storager.AddEventByID:output_type -> AddEventByIDResponse
+	5,  // 68: Storager.UpdateEventByID:output_type -> UpdateEventByIDResponse
+	7,  // 69: Storager.DeleteEventByID:output_type -> DeleteEventByIDResponse
+	9,  // 70: Storager.GetEventListingByUserID:output_type -> GetEventListingByUserIDResponse
+	11, // 71: Storager.GetEventByID:output_type -> GetEventByIDResponse
+	13, // 72: Storager.SearchEvents:output_type -> SearchEventsResponse
+	15, // 73: Storager.RespondToEvent:output_type -> RespondToEventResponse
+	17, // 74: Storager.Notify:output_type -> NotifyResponse
+	19, // 75: Storager.GetFreeBusy:output_type -> GetFreeBusyResponse
+	21, // 76: Storager.Login:output_type -> LoginResponse
+	25, // 77: Storager.RegisterAccount:output_type -> RegisterAccountResponse
+	27, // 78: Storager.GetAccount:output_type -> GetAccountResponse
+	29, // 79: Storager.ChangePassword:output_type -> ChangePasswordResponse
+	31, // 80: Storager.DeleteAccount:output_type -> DeleteAccountResponse
+	33, // 81: Storager.SetNotificationChannels:output_type -> SetNotificationChannelsResponse
+	35, // 82: Storager.SetTimeZone:output_type -> SetTimeZoneResponse
+	39, // 83: Storager.CreateCalendar:output_type -> CreateCalendarResponse
+	41, // 84: Storager.ListCalendars:output_type -> ListCalendarsResponse
+	43, // 85: Storager.DeleteCalendar:output_type -> DeleteCalendarResponse
+	45, // 86: Storager.GetCalendarACL:output_type -> GetCalendarACLResponse
+	47, // 87: Storager.SetCalendarACL:output_type -> SetCalendarACLResponse
+	49, // 88: Storager.RevokeCalendarAccess:output_type -> RevokeCalendarAccessResponse
+	51, // 89: Storager.WatchChanges:output_type -> WatchChangesResponse
+	58, // 90: Storager.BatchAddEvents:output_type -> BatchResponse
+	58, // 91: Storager.BatchUpdateEvents:output_type -> BatchResponse
+	58, // 92: Storager.BatchDeleteEvents:output_type -> BatchResponse
+	60, // 93: Storager.GetTrash:output_type -> GetTrashResponse
+	62, // 94: Storager.RestoreEvent:output_type -> RestoreEventResponse
+	64, // 95: Storager.GetEventHistory:output_type -> GetEventHistoryResponse
+	67, // [67:96] is the sub-list for method output_type
+	38, // [38:67] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_event_service_proto_init() }
@@ -852,13 +4239,14 @@ func file_event_service_proto_init() {
 		return
 	}
 	file_event_proto_init()
+	file_event_service_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_event_service_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   12,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_service_proto_rawDesc), len(file_event_service_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   65,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -868,7 +4256,6 @@ func file_event_service_proto_init() {
 		MessageInfos:      file_event_service_proto_msgTypes,
 	}.Build()
 	File_event_service_proto = out.File
-	file_event_service_proto_rawDesc = nil
 	file_event_service_proto_goTypes = nil
 	file_event_service_proto_depIdxs = nil
 }
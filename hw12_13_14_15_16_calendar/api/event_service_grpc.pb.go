@@ -24,7 +24,30 @@ const (
 	Storager_DeleteEventByID_FullMethodName         = "/Storager/DeleteEventByID"
 	Storager_GetEventListingByUserID_FullMethodName = "/Storager/GetEventListingByUserID"
 	Storager_GetEventByID_FullMethodName            = "/Storager/GetEventByID"
+	Storager_SearchEvents_FullMethodName            = "/Storager/SearchEvents"
+	Storager_RespondToEvent_FullMethodName          = "/Storager/RespondToEvent"
 	Storager_Notify_FullMethodName                  = "/Storager/Notify"
+	Storager_GetFreeBusy_FullMethodName             = "/Storager/GetFreeBusy"
+	Storager_Login_FullMethodName                   = "/Storager/Login"
+	Storager_RegisterAccount_FullMethodName         = "/Storager/RegisterAccount"
+	Storager_GetAccount_FullMethodName              = "/Storager/GetAccount"
+	Storager_ChangePassword_FullMethodName          = "/Storager/ChangePassword"
+	Storager_DeleteAccount_FullMethodName           = "/Storager/DeleteAccount"
+	Storager_SetNotificationChannels_FullMethodName = "/Storager/SetNotificationChannels"
+	Storager_SetTimeZone_FullMethodName             = "/Storager/SetTimeZone"
+	Storager_CreateCalendar_FullMethodName          = "/Storager/CreateCalendar"
+	Storager_ListCalendars_FullMethodName           = "/Storager/ListCalendars"
+	Storager_DeleteCalendar_FullMethodName          = "/Storager/DeleteCalendar"
+	Storager_GetCalendarACL_FullMethodName          = "/Storager/GetCalendarACL"
+	Storager_SetCalendarACL_FullMethodName          = "/Storager/SetCalendarACL"
+	Storager_RevokeCalendarAccess_FullMethodName    = "/Storager/RevokeCalendarAccess"
+	Storager_WatchChanges_FullMethodName            = "/Storager/WatchChanges"
+	Storager_BatchAddEvents_FullMethodName          = "/Storager/BatchAddEvents"
+	Storager_BatchUpdateEvents_FullMethodName       = "/Storager/BatchUpdateEvents"
+	Storager_BatchDeleteEvents_FullMethodName       = "/Storager/BatchDeleteEvents"
+	Storager_GetTrash_FullMethodName                = "/Storager/GetTrash"
+	Storager_RestoreEvent_FullMethodName            = "/Storager/RestoreEvent"
+	Storager_GetEventHistory_FullMethodName         = "/Storager/GetEventHistory"
 )
 
 // StoragerClient is the client API for Storager service.
@@ -36,7 +59,31 @@ type StoragerClient interface {
 	DeleteEventByID(ctx context.Context, in *DeleteEventByIDRequest, opts ...grpc.CallOption) (*DeleteEventByIDResponse, error)
 	GetEventListingByUserID(ctx context.Context, in *GetEventListingByUserIDRequest, opts ...grpc.CallOption) (*GetEventListingByUserIDResponse, error)
 	GetEventByID(ctx context.Context, in *GetEventByIDRequest, opts ...grpc.CallOption) (*GetEventByIDResponse, error)
+	SearchEvents(ctx context.Context, in *SearchEventsRequest, opts ...grpc.CallOption) (*SearchEventsResponse, error)
+	RespondToEvent(ctx context.Context, in *RespondToEventRequest, opts ...grpc.CallOption) (*RespondToEventResponse, error)
 	Notify(ctx context.Context, in *NotifyRequest, opts ...grpc.CallOption) (*NotifyResponse, error)
+	GetFreeBusy(ctx context.Context, in *GetFreeBusyRequest, opts ...grpc.CallOption) (*GetFreeBusyResponse, error)
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	RegisterAccount(ctx context.Context, in *RegisterAccountRequest, opts ...grpc.CallOption) (*RegisterAccountResponse, error)
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*GetAccountResponse, error)
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
+	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	SetNotificationChannels(ctx context.Context, in *SetNotificationChannelsRequest, opts ...grpc.CallOption) (*SetNotificationChannelsResponse, error)
+	SetTimeZone(ctx context.Context, in *SetTimeZoneRequest, opts ...grpc.CallOption) (*SetTimeZoneResponse, error)
+	CreateCalendar(ctx context.Context, in *CreateCalendarRequest, opts ...grpc.CallOption) (*CreateCalendarResponse, error)
+	ListCalendars(ctx context.Context, in *ListCalendarsRequest, opts ...grpc.CallOption) (*ListCalendarsResponse, error)
+	DeleteCalendar(ctx context.Context, in *DeleteCalendarRequest, opts ...grpc.CallOption) (*DeleteCalendarResponse, error)
+	GetCalendarACL(ctx context.Context, in *GetCalendarACLRequest, opts ...grpc.CallOption) (*GetCalendarACLResponse, error)
+	SetCalendarACL(ctx context.Context, in *SetCalendarACLRequest, opts ...grpc.CallOption) (*SetCalendarACLResponse, error)
+	RevokeCalendarAccess(ctx context.Context, in *RevokeCalendarAccessRequest, opts ...grpc.CallOption) (*RevokeCalendarAccessResponse, error)
+	// по HTTP лента отдаётся как Server-Sent Events: GET /v1/events/changes
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchChangesResponse], error)
+	BatchAddEvents(ctx context.Context, in *BatchAddEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	BatchUpdateEvents(ctx context.Context, in *BatchUpdateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	GetTrash(ctx context.Context, in *GetTrashRequest, opts ...grpc.CallOption) (*GetTrashResponse, error)
+	RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*RestoreEventResponse, error)
+	GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*GetEventHistoryResponse, error)
 }
 
 type storagerClient struct {
@@ -97,6 +144,26 @@ func (c *storagerClient) GetEventByID(ctx context.Context, in *GetEventByIDReque
 	return out, nil
 }
 
+func (c *storagerClient) SearchEvents(ctx context.Context, in *SearchEventsRequest, opts ...grpc.CallOption) (*SearchEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchEventsResponse)
+	err := c.cc.Invoke(ctx, Storager_SearchEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) RespondToEvent(ctx context.Context, in *RespondToEventRequest, opts ...grpc.CallOption) (*RespondToEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RespondToEventResponse)
+	err := c.cc.Invoke(ctx, Storager_RespondToEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *storagerClient) Notify(ctx context.Context, in *NotifyRequest, opts ...grpc.CallOption) (*NotifyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(NotifyResponse)
@@ -107,6 +174,225 @@ func (c *storagerClient) Notify(ctx context.Context, in *NotifyRequest, opts ...
 	return out, nil
 }
 
+func (c *storagerClient) GetFreeBusy(ctx context.Context, in *GetFreeBusyRequest, opts ...grpc.CallOption) (*GetFreeBusyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFreeBusyResponse)
+	err := c.cc.Invoke(ctx, Storager_GetFreeBusy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Storager_Login_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) RegisterAccount(ctx context.Context, in *RegisterAccountRequest, opts ...grpc.CallOption) (*RegisterAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterAccountResponse)
+	err := c.cc.Invoke(ctx, Storager_RegisterAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*GetAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountResponse)
+	err := c.cc.Invoke(ctx, Storager_GetAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePasswordResponse)
+	err := c.cc.Invoke(ctx, Storager_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, Storager_DeleteAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) SetNotificationChannels(ctx context.Context, in *SetNotificationChannelsRequest, opts ...grpc.CallOption) (*SetNotificationChannelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNotificationChannelsResponse)
+	err := c.cc.Invoke(ctx, Storager_SetNotificationChannels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) SetTimeZone(ctx context.Context, in *SetTimeZoneRequest, opts ...grpc.CallOption) (*SetTimeZoneResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTimeZoneResponse)
+	err := c.cc.Invoke(ctx, Storager_SetTimeZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) CreateCalendar(ctx context.Context, in *CreateCalendarRequest, opts ...grpc.CallOption) (*CreateCalendarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCalendarResponse)
+	err := c.cc.Invoke(ctx, Storager_CreateCalendar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) ListCalendars(ctx context.Context, in *ListCalendarsRequest, opts ...grpc.CallOption) (*ListCalendarsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCalendarsResponse)
+	err := c.cc.Invoke(ctx, Storager_ListCalendars_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) DeleteCalendar(ctx context.Context, in *DeleteCalendarRequest, opts ...grpc.CallOption) (*DeleteCalendarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCalendarResponse)
+	err := c.cc.Invoke(ctx, Storager_DeleteCalendar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) GetCalendarACL(ctx context.Context, in *GetCalendarACLRequest, opts ...grpc.CallOption) (*GetCalendarACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCalendarACLResponse)
+	err := c.cc.Invoke(ctx, Storager_GetCalendarACL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) SetCalendarACL(ctx context.Context, in *SetCalendarACLRequest, opts ...grpc.CallOption) (*SetCalendarACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCalendarACLResponse)
+	err := c.cc.Invoke(ctx, Storager_SetCalendarACL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) RevokeCalendarAccess(ctx context.Context, in *RevokeCalendarAccessRequest, opts ...grpc.CallOption) (*RevokeCalendarAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeCalendarAccessResponse)
+	err := c.cc.Invoke(ctx, Storager_RevokeCalendarAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchChangesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storager_ServiceDesc.Streams[0], Storager_WatchChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChangesRequest, WatchChangesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storager_WatchChangesClient = grpc.ServerStreamingClient[WatchChangesResponse]
+
+func (c *storagerClient) BatchAddEvents(ctx context.Context, in *BatchAddEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, Storager_BatchAddEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) BatchUpdateEvents(ctx context.Context, in *BatchUpdateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, Storager_BatchUpdateEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, Storager_BatchDeleteEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) GetTrash(ctx context.Context, in *GetTrashRequest, opts ...grpc.CallOption) (*GetTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTrashResponse)
+	err := c.cc.Invoke(ctx, Storager_GetTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*RestoreEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreEventResponse)
+	err := c.cc.Invoke(ctx, Storager_RestoreEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storagerClient) GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*GetEventHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventHistoryResponse)
+	err := c.cc.Invoke(ctx, Storager_GetEventHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoragerServer is the server API for Storager service.
 // All implementations must embed UnimplementedStoragerServer
 // for forward compatibility.
@@ -116,7 +402,31 @@ type StoragerServer interface {
 	DeleteEventByID(context.Context, *DeleteEventByIDRequest) (*DeleteEventByIDResponse, error)
 	GetEventListingByUserID(context.Context, *GetEventListingByUserIDRequest) (*GetEventListingByUserIDResponse, error)
 	GetEventByID(context.Context, *GetEventByIDRequest) (*GetEventByIDResponse, error)
+	SearchEvents(context.Context, *SearchEventsRequest) (*SearchEventsResponse, error)
+	RespondToEvent(context.Context, *RespondToEventRequest) (*RespondToEventResponse, error)
 	Notify(context.Context, *NotifyRequest) (*NotifyResponse, error)
+	GetFreeBusy(context.Context, *GetFreeBusyRequest) (*GetFreeBusyResponse, error)
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	RegisterAccount(context.Context, *RegisterAccountRequest) (*RegisterAccountResponse, error)
+	GetAccount(context.Context, *GetAccountRequest) (*GetAccountResponse, error)
+	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	SetNotificationChannels(context.Context, *SetNotificationChannelsRequest) (*SetNotificationChannelsResponse, error)
+	SetTimeZone(context.Context, *SetTimeZoneRequest) (*SetTimeZoneResponse, error)
+	CreateCalendar(context.Context, *CreateCalendarRequest) (*CreateCalendarResponse, error)
+	ListCalendars(context.Context, *ListCalendarsRequest) (*ListCalendarsResponse, error)
+	DeleteCalendar(context.Context, *DeleteCalendarRequest) (*DeleteCalendarResponse, error)
+	GetCalendarACL(context.Context, *GetCalendarACLRequest) (*GetCalendarACLResponse, error)
+	SetCalendarACL(context.Context, *SetCalendarACLRequest) (*SetCalendarACLResponse, error)
+	RevokeCalendarAccess(context.Context, *RevokeCalendarAccessRequest) (*RevokeCalendarAccessResponse, error)
+	// по HTTP лента отдаётся как Server-Sent Events: GET /v1/events/changes
+	WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[WatchChangesResponse]) error
+	BatchAddEvents(context.Context, *BatchAddEventsRequest) (*BatchResponse, error)
+	BatchUpdateEvents(context.Context, *BatchUpdateEventsRequest) (*BatchResponse, error)
+	BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error)
+	GetTrash(context.Context, *GetTrashRequest) (*GetTrashResponse, error)
+	RestoreEvent(context.Context, *RestoreEventRequest) (*RestoreEventResponse, error)
+	GetEventHistory(context.Context, *GetEventHistoryRequest) (*GetEventHistoryResponse, error)
 	mustEmbedUnimplementedStoragerServer()
 }
 
@@ -142,9 +452,78 @@ func (UnimplementedStoragerServer) GetEventListingByUserID(context.Context, *Get
 func (UnimplementedStoragerServer) GetEventByID(context.Context, *GetEventByIDRequest) (*GetEventByIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventByID not implemented")
 }
+func (UnimplementedStoragerServer) SearchEvents(context.Context, *SearchEventsRequest) (*SearchEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchEvents not implemented")
+}
+func (UnimplementedStoragerServer) RespondToEvent(context.Context, *RespondToEventRequest) (*RespondToEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToEvent not implemented")
+}
 func (UnimplementedStoragerServer) Notify(context.Context, *NotifyRequest) (*NotifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Notify not implemented")
 }
+func (UnimplementedStoragerServer) GetFreeBusy(context.Context, *GetFreeBusyRequest) (*GetFreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeBusy not implemented")
+}
+func (UnimplementedStoragerServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedStoragerServer) RegisterAccount(context.Context, *RegisterAccountRequest) (*RegisterAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterAccount not implemented")
+}
+func (UnimplementedStoragerServer) GetAccount(context.Context, *GetAccountRequest) (*GetAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
+}
+func (UnimplementedStoragerServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedStoragerServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
+}
+func (UnimplementedStoragerServer) SetNotificationChannels(context.Context, *SetNotificationChannelsRequest) (*SetNotificationChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNotificationChannels not implemented")
+}
+func (UnimplementedStoragerServer) SetTimeZone(context.Context, *SetTimeZoneRequest) (*SetTimeZoneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTimeZone not implemented")
+}
+func (UnimplementedStoragerServer) CreateCalendar(context.Context, *CreateCalendarRequest) (*CreateCalendarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCalendar not implemented")
+}
+func (UnimplementedStoragerServer) ListCalendars(context.Context, *ListCalendarsRequest) (*ListCalendarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCalendars not implemented")
+}
+func (UnimplementedStoragerServer) DeleteCalendar(context.Context, *DeleteCalendarRequest) (*DeleteCalendarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCalendar not implemented")
+}
+func (UnimplementedStoragerServer) GetCalendarACL(context.Context, *GetCalendarACLRequest) (*GetCalendarACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCalendarACL not implemented")
+}
+func (UnimplementedStoragerServer) SetCalendarACL(context.Context, *SetCalendarACLRequest) (*SetCalendarACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCalendarACL not implemented")
+}
+func (UnimplementedStoragerServer) RevokeCalendarAccess(context.Context, *RevokeCalendarAccessRequest) (*RevokeCalendarAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeCalendarAccess not implemented")
+}
+func (UnimplementedStoragerServer) WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[WatchChangesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedStoragerServer) BatchAddEvents(context.Context, *BatchAddEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchAddEvents not implemented")
+}
+func (UnimplementedStoragerServer) BatchUpdateEvents(context.Context, *BatchUpdateEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateEvents not implemented")
+}
+func (UnimplementedStoragerServer) BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEvents not implemented")
+}
+func (UnimplementedStoragerServer) GetTrash(context.Context, *GetTrashRequest) (*GetTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrash not implemented")
+}
+func (UnimplementedStoragerServer) RestoreEvent(context.Context, *RestoreEventRequest) (*RestoreEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEvent not implemented")
+}
+func (UnimplementedStoragerServer) GetEventHistory(context.Context, *GetEventHistoryRequest) (*GetEventHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHistory not implemented")
+}
 func (UnimplementedStoragerServer) mustEmbedUnimplementedStoragerServer() {}
 func (UnimplementedStoragerServer) testEmbeddedByValue()                  {}
 
@@ -256,6 +635,42 @@ func _Storager_GetEventByID_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Storager_SearchEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).SearchEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_SearchEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).SearchEvents(ctx, req.(*SearchEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_RespondToEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).RespondToEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_RespondToEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).RespondToEvent(ctx, req.(*RespondToEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Storager_Notify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NotifyRequest)
 	if err := dec(in); err != nil {
@@ -274,38 +689,503 @@ func _Storager_Notify_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
-// Storager_ServiceDesc is the grpc.ServiceDesc for Storager service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Storager_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "Storager",
-	HandlerType: (*StoragerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "AddEventByID",
-			Handler:    _Storager_AddEventByID_Handler,
-		},
-		{
-			MethodName: "UpdateEventByID",
-			Handler:    _Storager_UpdateEventByID_Handler,
-		},
-		{
-			MethodName: "DeleteEventByID",
-			Handler:    _Storager_DeleteEventByID_Handler,
-		},
-		{
-			MethodName: "GetEventListingByUserID",
-			Handler:    _Storager_GetEventListingByUserID_Handler,
-		},
-		{
-			MethodName: "GetEventByID",
-			Handler:    _Storager_GetEventByID_Handler,
-		},
-		{
-			MethodName: "Notify",
-			Handler:    _Storager_Notify_Handler,
+func _Storager_GetFreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).GetFreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_GetFreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).GetFreeBusy(ctx, req.(*GetFreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_RegisterAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).RegisterAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_RegisterAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).RegisterAccount(ctx, req.(*RegisterAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_GetAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).DeleteAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_DeleteAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).DeleteAccount(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_SetNotificationChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNotificationChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).SetNotificationChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_SetNotificationChannels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).SetNotificationChannels(ctx, req.(*SetNotificationChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_SetTimeZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTimeZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).SetTimeZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_SetTimeZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).SetTimeZone(ctx, req.(*SetTimeZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_CreateCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).CreateCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_CreateCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).CreateCalendar(ctx, req.(*CreateCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_ListCalendars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCalendarsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).ListCalendars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_ListCalendars_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).ListCalendars(ctx, req.(*ListCalendarsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_DeleteCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).DeleteCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_DeleteCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).DeleteCalendar(ctx, req.(*DeleteCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_GetCalendarACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCalendarACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).GetCalendarACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_GetCalendarACL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).GetCalendarACL(ctx, req.(*GetCalendarACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_SetCalendarACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCalendarACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).SetCalendarACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_SetCalendarACL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).SetCalendarACL(ctx, req.(*SetCalendarACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_RevokeCalendarAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCalendarAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).RevokeCalendarAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_RevokeCalendarAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).RevokeCalendarAccess(ctx, req.(*RevokeCalendarAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StoragerServer).WatchChanges(m, &grpc.GenericServerStream[WatchChangesRequest, WatchChangesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storager_WatchChangesServer = grpc.ServerStreamingServer[WatchChangesResponse]
+
+func _Storager_BatchAddEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchAddEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).BatchAddEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_BatchAddEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).BatchAddEvents(ctx, req.(*BatchAddEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_BatchUpdateEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).BatchUpdateEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_BatchUpdateEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).BatchUpdateEvents(ctx, req.(*BatchUpdateEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_BatchDeleteEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).BatchDeleteEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_BatchDeleteEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).BatchDeleteEvents(ctx, req.(*BatchDeleteEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_GetTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).GetTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_GetTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).GetTrash(ctx, req.(*GetTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_RestoreEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).RestoreEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_RestoreEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).RestoreEvent(ctx, req.(*RestoreEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storager_GetEventHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoragerServer).GetEventHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storager_GetEventHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoragerServer).GetEventHistory(ctx, req.(*GetEventHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Storager_ServiceDesc is the grpc.ServiceDesc for Storager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Storager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Storager",
+	HandlerType: (*StoragerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddEventByID",
+			Handler:    _Storager_AddEventByID_Handler,
+		},
+		{
+			MethodName: "UpdateEventByID",
+			Handler:    _Storager_UpdateEventByID_Handler,
+		},
+		{
+			MethodName: "DeleteEventByID",
+			Handler:    _Storager_DeleteEventByID_Handler,
+		},
+		{
+			MethodName: "GetEventListingByUserID",
+			Handler:    _Storager_GetEventListingByUserID_Handler,
+		},
+		{
+			MethodName: "GetEventByID",
+			Handler:    _Storager_GetEventByID_Handler,
+		},
+		{
+			MethodName: "SearchEvents",
+			Handler:    _Storager_SearchEvents_Handler,
+		},
+		{
+			MethodName: "RespondToEvent",
+			Handler:    _Storager_RespondToEvent_Handler,
+		},
+		{
+			MethodName: "Notify",
+			Handler:    _Storager_Notify_Handler,
+		},
+		{
+			MethodName: "GetFreeBusy",
+			Handler:    _Storager_GetFreeBusy_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Storager_Login_Handler,
+		},
+		{
+			MethodName: "RegisterAccount",
+			Handler:    _Storager_RegisterAccount_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _Storager_GetAccount_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _Storager_ChangePassword_Handler,
+		},
+		{
+			MethodName: "DeleteAccount",
+			Handler:    _Storager_DeleteAccount_Handler,
+		},
+		{
+			MethodName: "SetNotificationChannels",
+			Handler:    _Storager_SetNotificationChannels_Handler,
+		},
+		{
+			MethodName: "SetTimeZone",
+			Handler:    _Storager_SetTimeZone_Handler,
+		},
+		{
+			MethodName: "CreateCalendar",
+			Handler:    _Storager_CreateCalendar_Handler,
+		},
+		{
+			MethodName: "ListCalendars",
+			Handler:    _Storager_ListCalendars_Handler,
+		},
+		{
+			MethodName: "DeleteCalendar",
+			Handler:    _Storager_DeleteCalendar_Handler,
+		},
+		{
+			MethodName: "GetCalendarACL",
+			Handler:    _Storager_GetCalendarACL_Handler,
+		},
+		{
+			MethodName: "SetCalendarACL",
+			Handler:    _Storager_SetCalendarACL_Handler,
+		},
+		{
+			MethodName: "RevokeCalendarAccess",
+			Handler:    _Storager_RevokeCalendarAccess_Handler,
+		},
+		{
+			MethodName: "BatchAddEvents",
+			Handler:    _Storager_BatchAddEvents_Handler,
+		},
+		{
+			MethodName: "BatchUpdateEvents",
+			Handler:    _Storager_BatchUpdateEvents_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEvents",
+			Handler:    _Storager_BatchDeleteEvents_Handler,
+		},
+		{
+			MethodName: "GetTrash",
+			Handler:    _Storager_GetTrash_Handler,
+		},
+		{
+			MethodName: "RestoreEvent",
+			Handler:    _Storager_RestoreEvent_Handler,
+		},
+		{
+			MethodName: "GetEventHistory",
+			Handler:    _Storager_GetEventHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _Storager_WatchChanges_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "event_service.proto",
 }
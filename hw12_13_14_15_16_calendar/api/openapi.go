@@ -0,0 +1,8 @@
+package api
+
+import _ "embed"
+
+// OpenAPISpec - спецификация REST-шлюза, сгенерированная protoc-gen-openapiv2 из event_service.proto.
+//
+//go:embed openapi/calendar.swagger.json
+var OpenAPISpec []byte
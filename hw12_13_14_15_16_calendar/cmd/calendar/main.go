@@ -2,20 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // зоны IANA аккаунтов; в образе alpine своих нет
 
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/configs" //nolint:depguard
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/app"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/migrator"
 	internalgrpc "github.com/adettelle/hw/hw12_13_14_15_calendar/internal/server/grpc"
 	internalhttp "github.com/adettelle/hw/hw12_13_14_15_calendar/internal/server/http"
 	memorystorage "github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage/memory"
 	sqlstorage "github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage/sql"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/database"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -61,6 +66,15 @@ func initialize() error {
 	logg.Info("Hello!")
 	logg.Info(getVersion())
 
+	shutdownTracing, err := tracing.Setup(startCtx, tracing.Config{
+		Service:  "calendar",
+		Exporter: config.TraceExporter,
+		Endpoint: config.TraceEndpoint,
+	})
+	if err != nil {
+		return err
+	}
+
 	storager, err := initStorager(config, logg)
 	if err != nil {
 		return err
@@ -70,8 +84,16 @@ func initialize() error {
 
 	var wg sync.WaitGroup
 
-	server := internalhttp.NewServer(config, logg, calendar, storager)
-	serverGRPC := internalgrpc.NewGRPCServer(config, logg, storager)
+	tokens, err := initTokenManager(config, logg)
+	if err != nil {
+		return err
+	}
+
+	server, err := internalhttp.NewServer(config, logg, calendar, storager, tokens)
+	if err != nil {
+		return err
+	}
+	serverGRPC := internalgrpc.NewGRPCServer(config, logg, storager, tokens)
 
 	go func() {
 		s := <-ctx.Done()
@@ -89,6 +111,11 @@ func initialize() error {
 			logg.Error("failed to stop grpc server", zap.Error(err))
 		}
 
+		// ошибка выгрузки спанов не мешает штатной остановке
+		if err := shutdownTracing(stopCtx); err != nil {
+			logg.Error("failed to flush traces", zap.Error(err))
+		}
+
 		if err != nil {
 			os.Exit(1)
 		}
@@ -119,6 +146,21 @@ func initialize() error {
 	return nil
 }
 
+// initTokenManager creates the access token manager. Without a configured secret
+// a random one is generated, so tokens do not survive a restart.
+func initTokenManager(cfg *configs.Config, logg *zap.Logger) (*auth.TokenManager, error) {
+	secret := cfg.AuthSecret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+		logg.Warn("auth secret is not configured, using a random one")
+	}
+	return auth.NewTokenManager(secret, cfg.TokenLifetime()), nil
+}
+
 // initStorager not only constructs, but also starts related processes
 // depending on which storager we choose.
 func initStorager(cfg *configs.Config, logg *zap.Logger) (app.Storager, error) {
@@ -140,7 +182,9 @@ func initStorager(cfg *configs.Config, logg *zap.Logger) (app.Storager, error) {
 
 		migrator.MustApplyMigrations(connStr, logg)
 	} else {
-		storager = memorystorage.New()
+		store := memorystorage.New()
+		store.Logg = logg
+		storager = store
 	}
-	return storager, nil
+	return app.WithMetrics(storager), nil
 }
@@ -0,0 +1,128 @@
+// calendar_admin - служебные команды для очереди напоминаний.
+//
+//	calendar_admin dlq list [-limit N]              показать недоставленные напоминания
+//	calendar_admin dlq replay [-key KEY] [-limit N] вернуть их в основную очередь
+//
+// Брокер, подключение и имя очереди берутся из конфига отправителя (-config) и его переменных окружения.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	configs "github.com/adettelle/hw/hw12_13_14_15_calendar/configs/sender"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/database"
+	"go.uber.org/zap"
+)
+
+var errUsage = errors.New("usage: calendar_admin [-config path] dlq list|replay [flags]")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("calendar_admin", flag.ContinueOnError)
+	cfgPath := fs.String("config", "./configs/sender/sender_cfg.json", "path to sender config")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) < 2 || args[0] != "dlq" {
+		return errUsage
+	}
+
+	cmd := flag.NewFlagSet("dlq "+args[1], flag.ContinueOnError)
+	limit := cmd.Int("limit", 100, "max messages to process")
+	key := cmd.String("key", "", "replay only the message with this idempotency key")
+	if err := cmd.Parse(args[2:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	config, err := configs.New(&ctx, *cfgPath)
+	if err != nil {
+		return err
+	}
+	logg := zap.NewNop()
+
+	dead, err := newDeadLetters(config, logg)
+	if err != nil {
+		return err
+	}
+	defer dead.Close()
+
+	switch args[1] {
+	case "list":
+		return list(ctx, dead, *limit, out)
+	case "replay":
+		return replay(ctx, dead, *key, *limit, out)
+	default:
+		return errUsage
+	}
+}
+
+// newDeadLetters подключается к брокеру из конфига отправителя. Повторы здесь не нужны,
+// поэтому очереди повторов не объявляются.
+func newDeadLetters(config *configs.Config, logg *zap.Logger) (broker.Broker, error) {
+	cfg := broker.Config{Kind: config.Broker, Queue: config.Queue, AMQPURL: config.RabbitURL}
+	if cfg.Kind == broker.KindPostgres {
+		db, err := database.Connect(config.DBConnStr())
+		if err != nil {
+			return nil, err
+		}
+		cfg.DB = db
+	}
+	return broker.New(cfg, logg)
+}
+
+// deadLetter - то, что показывается о недоставленном напоминании.
+type deadLetter struct {
+	broker.Message
+	Body json.RawMessage `json:"body"`
+}
+
+func list(ctx context.Context, dead broker.DeadLetters, limit int, out io.Writer) error {
+	msgs, err := dead.ListDead(ctx, limit)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		fmt.Fprintln(out, "dead letter queue is empty")
+		return nil
+	}
+
+	enc := json.NewEncoder(out)
+	for _, m := range msgs {
+		dl := deadLetter{Message: m, Body: m.Body}
+		if !json.Valid(m.Body) {
+			dl.Body, _ = json.Marshal(string(m.Body))
+		}
+		if err := enc.Encode(dl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replay(ctx context.Context, dead broker.DeadLetters, key string, limit int, out io.Writer) error {
+	keys, err := dead.ReplayDead(ctx, key, limit)
+	for _, k := range keys {
+		fmt.Fprintf(out, "replayed %s\n", k)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d message(s) replayed\n", len(keys))
+	return nil
+}
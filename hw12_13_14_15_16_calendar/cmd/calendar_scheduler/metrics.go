@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// metricsPlanner - Planner, который записывает время операций хранилища в метрики.
+type metricsPlanner struct {
+	Planner
+}
+
+func (p metricsPlanner) CollectEventsToNotify(ctx context.Context) ([]storage.EventToNotify, error) {
+	return metrics.ObserveStorage("CollectEventsToNotify", func() ([]storage.EventToNotify, error) {
+		return p.Planner.CollectEventsToNotify(ctx)
+	})
+}
+
+func (p metricsPlanner) EnqueueNotifications(ctx context.Context, events []storage.EventToNotify) ([]string, error) {
+	return metrics.ObserveStorage("EnqueueNotifications", func() ([]string, error) {
+		return p.Planner.EnqueueNotifications(ctx, events)
+	})
+}
+
+func (p metricsPlanner) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	return metrics.ObserveStorage("PendingOutbox", func() ([]storage.OutboxMessage, error) {
+		return p.Planner.PendingOutbox(ctx, limit)
+	})
+}
+
+func (p metricsPlanner) MarkOutboxDelivered(ctx context.Context, ids []string) error {
+	return metrics.ObserveStorageErr("MarkOutboxDelivered", func() error {
+		return p.Planner.MarkOutboxDelivered(ctx, ids)
+	})
+}
+
+func (p metricsPlanner) DeleteEvents(ctx context.Context, retention time.Duration, trashRetention time.Duration) error {
+	return metrics.ObserveStorageErr("DeleteEvents", func() error {
+		return p.Planner.DeleteEvents(ctx, retention, trashRetention)
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startReminderSpans начинает по спану на каждое напоминание в трейсе создания его события
+// и записывает в напоминание контекст этого спана: с ним напоминание попадает в outbox,
+// а публикация и доставка продолжают тот же трейс. Спаны связаны со спаном прохода
+// планировщика из ctx. Возвращённая функция завершает спаны с ошибкой постановки в очередь.
+func startReminderSpans(ctx context.Context, events []storage.EventToNotify) func(error) {
+	link := trace.LinkFromContext(ctx)
+
+	spans := make([]trace.Span, 0, len(events))
+	for i, e := range events {
+		eventCtx, span := tracing.Tracer().Start(tracing.WithTraceParent(ctx, e.TraceParent), "schedule reminder",
+			trace.WithLinks(link),
+			trace.WithAttributes(
+				attribute.String("event.id", e.ID),
+				attribute.String("event.recipient", e.UserID),
+			))
+		events[i].TraceParent = tracing.TraceParent(eventCtx)
+		spans = append(spans, span)
+	}
+
+	return func(err error) {
+		for _, span := range spans {
+			tracing.End(span, err)
+		}
+	}
+}
@@ -2,17 +2,33 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
+	"net"
+	"net/http"
+	"net/smtp"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	configs "github.com/adettelle/hw/hw12_13_14_15_calendar/configs/sender"
-	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/migrator"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/notifier"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/database"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// dedupTTL - сколько помнить ключи обработанных напоминаний.
+const dedupTTL = 24 * time.Hour
+
 func main() {
 	startCtx := context.Background()
 
@@ -66,51 +82,126 @@ func main() {
 	logg.Info("LEVELS", zap.String("cfgLevel", config.Logger.Level), zap.String("actualLevel", logg.Level().String()))
 	defer logg.Sync()
 
-	conn, err := amqp.Dial(config.RabbitURL)
+	shutdownTracing, err := tracing.Setup(startCtx, tracing.Config{
+		Service:  "calendar_sender",
+		Exporter: config.TraceExporter,
+		Endpoint: config.TraceEndpoint,
+	})
 	if err != nil {
-		logg.Fatal("Failed to connect to RabbitMQ", zap.Error(err))
+		logg.Fatal("failed to set up tracing", zap.Error(err))
 	}
-	defer conn.Close()
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(startCtx, 3*time.Second)
+		defer cancel()
+		if err := shutdownTracing(stopCtx); err != nil {
+			logg.Error("failed to flush traces", zap.Error(err))
+		}
+	}()
 
-	ch, err := conn.Channel()
+	retry, err := config.RetryBackoff()
 	if err != nil {
-		logg.Fatal("Failed to open a channel", zap.Error(err))
+		logg.Fatal("failed to parse retries", zap.Error(err))
 	}
-	defer ch.Close()
+	backoff := notifier.Backoff{Initial: retry.Initial, Max: retry.Max, MaxAttempts: retry.Attempts}
 
-	q, err := ch.QueueDeclare(
-		"hello", // name
-		false,   // durable
-		false,   // delete when unused
-		false,   // exclusive
-		false,   // no-wait
-		nil,     // arguments
-	)
+	b, err := newBroker(config, backoff.Delays(), logg)
 	if err != nil {
-		logg.Fatal("Failed to declare a queue", zap.Error(err))
+		logg.Fatal("Failed to connect to broker", zap.String("broker", config.Broker), zap.Error(err))
 	}
+	defer b.Close()
 
-	msg, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+	dispatcher, closeSinks, err := newDispatcher(config, logg)
+	if err != nil {
+		logg.Fatal("Failed to configure notifiers", zap.Error(err))
+	}
+	defer closeSinks()
+
+	ctx, cancel := signal.NotifyContext(startCtx, syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	go metrics.Serve(ctx, config.MetricsAddress, logg)
+
+	deliveries, err := b.Consume(ctx)
 	if err != nil {
 		logg.Fatal("Failed to register a consumer", zap.Error(err))
 	}
 
+	// канал закрывается по отмене ctx; необработанное брокер вернёт в очередь
+	notifier.NewConsumer(dispatcher, notifier.NewDedup(dedupTTL), b.Retries(), logg).Run(ctx, deliveries)
+	logg.Info("Stopping sender")
+}
+
+// newBroker подключается к брокеру из конфига. Для postgres применяются миграции,
+// чтобы таблица очереди существовала независимо от порядка запуска сервисов.
+func newBroker(config *configs.Config, delays []time.Duration, logg *zap.Logger) (broker.Broker, error) {
+	cfg := broker.Config{
+		Kind:        config.Broker,
+		Queue:       config.Queue,
+		RetryDelays: delays,
+		AMQPURL:     config.RabbitURL,
+	}
+
+	var err error
+	if cfg.Prefetch, err = strconv.Atoi(config.Prefetch); err != nil {
+		return nil, err
+	}
 	t, err := strconv.Atoi(config.WorkTicker)
 	if err != nil {
-		logg.Error("failed to parsecworkTicker", zap.Error(err))
+		return nil, err
 	}
-	ticker := time.NewTicker(time.Duration(t) * time.Second)
-	defer ticker.Stop()
+	cfg.PollInterval = time.Duration(t) * time.Second
 
-	for d := range msg {
-		logg.Info("Received a message", zap.ByteString("message", d.Body))
+	if cfg.Kind == broker.KindPostgres {
+		connStr := config.DBConnStr()
+		if cfg.DB, err = database.Connect(connStr); err != nil {
+			return nil, err
+		}
+		migrator.MustApplyMigrations(connStr, logg)
 	}
+
+	return broker.New(cfg, logg)
+}
+
+// newDispatcher собирает отправителей по конфигу. Email доступен, только если задан SMTP-сервер.
+func newDispatcher(config *configs.Config, logg *zap.Logger) (*notifier.Dispatcher, func(), error) {
+	webhookTimeout, err := time.ParseDuration(config.WebhookTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeSinks := func() {}
+	sink := io.Writer(os.Stdout)
+	if config.NotifyFile != "" {
+		f, err := os.OpenFile(config.NotifyFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink = f
+		closeSinks = func() { f.Close() }
+	}
+
+	// получатель вебхука может продолжить трейс по заголовку traceparent
+	webhookClient := &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: otelhttp.NewTransport(notifier.NewWebhookTransport(config.WebhookAllowedHosts())),
+	}
+	notifiers := map[string]notifier.Notifier{
+		storage.ChannelLog:     notifier.NewWriterNotifier(sink),
+		storage.ChannelWebhook: notifier.NewWebhookNotifier(webhookClient),
+	}
+	if config.SMTPAddr != "" {
+		var auth smtp.Auth
+		if config.SMTPUser != "" {
+			host, _, err := net.SplitHostPort(config.SMTPAddr)
+			if err != nil {
+				return nil, nil, err
+			}
+			auth = smtp.PlainAuth("", config.SMTPUser, config.SMTPPassword, host)
+		}
+		notifiers[storage.ChannelEmail] = notifier.NewSMTPNotifier(config.SMTPAddr, config.SMTPFrom, auth)
+	} else {
+		logg.Warn("smtp is not configured, email notifications are disabled")
+	}
+
+	return notifier.NewDispatcher(notifiers, logg), closeSinks, nil
 }
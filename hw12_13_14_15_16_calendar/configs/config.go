@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"time"
 )
 
 // флаг только -config
@@ -22,6 +23,7 @@ const (
 	defaultDBUser      = "postgres"
 	defaultDBPassword  = "123456"
 	defaultDBName      = "test_db" // calendar
+	defaultTokenTTL    = "24h"
 )
 
 // Организация конфига в main принуждает нас сужать API компонентов, использовать
@@ -37,6 +39,13 @@ type Config struct {
 	DBUser      string `json:"dbuser"`
 	DBPassword  string `json:"dbpassword"`
 	DBName      string `json:"dbname"`
+	AuthSecret  string `json:"authsecret"` // ключ подписи токенов; если пуст, генерируется при старте
+	TokenTTL    string `json:"tokenttl"`   // время жизни токена в формате time.Duration
+	// адрес gRPC-сервера, к которому подключается REST-шлюз; пусто - localhost и порт GRPCAddress
+	GatewayUpstream string `json:"gatewayupstream"`
+	// Экспорт трассировки: otlp, file или пусто - спаны не экспортируются.
+	TraceExporter string `json:"traceexporter"`
+	TraceEndpoint string `json:"traceendpoint"` // адрес OTLP-коллектора или путь к файлу спанов
 }
 
 type LoggerConf struct {
@@ -66,6 +75,8 @@ func New(ctx *context.Context) (*Config, error) {
 		ensureAddrFLagIsCorrect(cfgFromJSON.GRPCAddress)
 		ensureHostFlagIsCorrect(*cfgFromJSON.Context, cfgFromJSON.DBHost)
 		ensurePortFlagIsCorrect(cfgFromJSON.DBPort)
+		ensureDurationIsCorrect(cfgFromJSON.TokenTTL)
+		ensureAddrFLagIsCorrect(cfgFromJSON.GatewayUpstreamAddress())
 		return cfgFromJSON, nil
 	}
 	cfg = &Config{
@@ -76,11 +87,19 @@ func New(ctx *context.Context) (*Config, error) {
 		GRPCAddress: getEnvOrDefault("GRPCADDR", defaultGRPCAddress),
 		Config:      "", // *flagConfig,
 
+		GatewayUpstream: os.Getenv("GATEWAYUPSTREAM"),
+
 		DBHost:     getEnvOrDefault("DBHOST", defaultDBHost),
 		DBPort:     getEnvOrDefault("DBPORT", defaultDBPort),
 		DBUser:     getEnvOrDefault("DBUSER", defaultDBUser),
 		DBPassword: getEnvOrDefault("DBPASSWORD", defaultDBPassword),
 		DBName:     getEnvOrDefault("DBNAME", defaultDBName),
+
+		AuthSecret: os.Getenv("AUTHSECRET"),
+		TokenTTL:   getEnvOrDefault("TOKENTTL", defaultTokenTTL),
+
+		TraceExporter: os.Getenv("TRACE_EXPORTER"),
+		TraceEndpoint: os.Getenv("TRACE_ENDPOINT"),
 	}
 
 	cfg.Context = ctx
@@ -89,6 +108,8 @@ func New(ctx *context.Context) (*Config, error) {
 	ensureAddrFLagIsCorrect(cfg.GRPCAddress)
 	ensureHostFlagIsCorrect(*cfg.Context, cfg.DBHost)
 	ensurePortFlagIsCorrect(cfg.DBPort)
+	ensureDurationIsCorrect(cfg.TokenTTL)
+	ensureAddrFLagIsCorrect(cfg.GatewayUpstreamAddress())
 
 	return cfg, nil
 }
@@ -118,6 +139,9 @@ func (cfg *Config) applyDefauls() {
 	if cfg.DBPassword == "" {
 		cfg.DBPassword = defaultDBPassword
 	}
+	if cfg.TokenTTL == "" {
+		cfg.TokenTTL = defaultTokenTTL
+	}
 }
 
 func getEnvOrDefault(envName string, defaultVal string) string {
@@ -157,6 +181,32 @@ func ensurePortFlagIsCorrect(port string) {
 	}
 }
 
+func ensureDurationIsCorrect(d string) {
+	v, err := time.ParseDuration(d)
+	if err != nil || v <= 0 {
+		log.Fatal(fmt.Errorf("invalid duration: '%s'", d))
+	}
+}
+
+// TokenLifetime returns the access token lifetime.
+func (cfg *Config) TokenLifetime() time.Duration {
+	d, _ := time.ParseDuration(cfg.TokenTTL)
+	return d
+}
+
+// GatewayUpstreamAddress returns the gRPC server address the REST gateway dials. By default
+// the gateway runs next to the gRPC server, which listens on all interfaces, so it dials localhost.
+func (cfg *Config) GatewayUpstreamAddress() string {
+	if cfg.GatewayUpstream != "" {
+		return cfg.GatewayUpstream
+	}
+	_, port, err := net.SplitHostPort(cfg.GRPCAddress)
+	if err != nil {
+		return cfg.GRPCAddress
+	}
+	return net.JoinHostPort("localhost", port)
+}
+
 // defaultDBParams = "host=localhost port=9999 user=postgres password=123456 dbname=calendar sslmode=disable"
 // DBConnStr constructs and returns the PostgreSQL database connection string.
 func (cfg *Config) DBConnStr() string {
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/c2fo/testify/require"
 )
@@ -21,6 +22,16 @@ func TestLoadConfig(t *testing.T) {
 	require.Equal(t, "postgres", cfg.DBUser)
 	require.Equal(t, "123456", cfg.DBPassword)
 	require.Equal(t, "test_db", cfg.DBName)
+	require.Equal(t, 24*time.Hour, cfg.TokenLifetime())
+	require.Equal(t, "localhost:8082", cfg.GatewayUpstreamAddress())
+}
+
+func TestGatewayUpstreamAddress(t *testing.T) {
+	cfg := Config{GRPCAddress: "0.0.0.0:9090"}
+	require.Equal(t, "localhost:9090", cfg.GatewayUpstreamAddress())
+
+	cfg.GatewayUpstream = "calendar-grpc:9090"
+	require.Equal(t, "calendar-grpc:9090", cfg.GatewayUpstreamAddress())
 }
 
 func TestFromFlagsConfig(t *testing.T) {
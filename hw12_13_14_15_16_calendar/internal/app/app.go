@@ -16,11 +16,64 @@ type Logger interface { // TODO
 type Storager interface { // TODO
 	AddEventByID(ctx context.Context, event storage.EventCreateDTO, userID string) (string, error)
 	UpdateEventByID(ctx context.Context, id string, event storage.EventUpdateDTO, userID string) error
-	DeleteEventByID(ctx context.Context, id string) error
+	// перенести событие в корзину; оттуда его можно восстановить, пока планировщик его не удалил;
+	DeleteEventByID(ctx context.Context, id string, userID string) error
 	// получить список событий на день/неделю/месяц;
 	GetEventListingByUserID(userID string, date time.Time, period string) ([]storage.Event, error)
 	GetEventByID(id string, userID string) (storage.Event, error)
+	// ответить на приглашение на событие;
+	RespondToEvent(ctx context.Context, eventID string, userID string, status storage.AttendeeStatus) error
+	// найти события по периоду, тексту и признаку отправки напоминания, постранично;
+	SearchEvents(ctx context.Context, userID string, q storage.SearchQuery) (storage.SearchResult, error)
+	// получить все события пользователя (повторяющиеся - без разворачивания);
+	GetEventsByUserID(ctx context.Context, userID string) ([]storage.Event, error)
+	// получить занятые интервалы и свободные промежутки не короче minFree;
+	GetFreeBusy(ctx context.Context, userID string, from, to time.Time, minFree time.Duration) (storage.FreeBusy, error)
 	Notify(day uint) (string, error)
+	// получить аккаунт по логину для проверки пароля;
+	GetAccountByLogin(ctx context.Context, login string) (storage.Account, error)
+	// зарегистрировать аккаунт, пароль передаётся уже захэшированным;
+	CreateAccount(ctx context.Context, login string, passwordHash string) (string, error)
+	GetAccountByID(ctx context.Context, id string) (storage.Account, error)
+	UpdateAccountPassword(ctx context.Context, id string, passwordHash string) error
+	// задать каналы доставки напоминаний аккаунта;
+	SetAccountChannels(ctx context.Context, id string, channels []storage.NotificationChannel) error
+	// задать зону IANA, в которой считаются периоды списка событий;
+	SetAccountTimeZone(ctx context.Context, id string, timeZone string) error
+	// удалить аккаунт вместе с его календарями и событиями;
+	DeleteAccountByID(ctx context.Context, id string) error
+	// создать общий календарь, пользователь становится его владельцем;
+	CreateCalendar(ctx context.Context, name string, userID string) (string, error)
+	// получить календари, к которым у пользователя есть доступ;
+	GetCalendars(ctx context.Context, userID string) ([]storage.Calendar, error)
+	DeleteCalendar(ctx context.Context, calendarID string, userID string) error
+	// права на календарь: посмотреть, выдать editor/viewer, отозвать;
+	GetCalendarACL(ctx context.Context, calendarID string, userID string) ([]storage.ACLEntry, error)
+	SetCalendarACL(ctx context.Context, calendarID string, entry storage.ACLEntry, userID string) error
+	RevokeCalendarAccess(ctx context.Context, calendarID string, granteeID string, userID string) error
+	// события календаря; методы выше без calendarID работают с личным календарём пользователя;
+	AddCalendarEvent(ctx context.Context, calendarID string, event storage.EventCreateDTO, userID string) (string, error)
+	UpdateCalendarEvent(ctx context.Context, calendarID string, id string, event storage.EventUpdateDTO,
+		userID string) error
+	DeleteCalendarEvent(ctx context.Context, calendarID string, id string, userID string) error
+	GetCalendarEvent(ctx context.Context, calendarID string, id string, userID string) (storage.Event, error)
+	GetCalendarEventListing(ctx context.Context, calendarID string, date time.Time, period string,
+		userID string) ([]storage.Event, error)
+	// пакетные операции с событиями календаря (пустой calendarID - личный календарь):
+	// всё или ничего (BatchAtomic) или по отдельности с результатом каждого события (BatchBestEffort);
+	AddEvents(ctx context.Context, calendarID string, events []storage.EventCreateDTO, mode storage.BatchMode,
+		userID string) ([]storage.BatchResult, error)
+	UpdateEvents(ctx context.Context, calendarID string, updates []storage.EventBatchUpdate, mode storage.BatchMode,
+		userID string) ([]storage.BatchResult, error)
+	DeleteEventsByID(ctx context.Context, calendarID string, ids []string, mode storage.BatchMode,
+		userID string) ([]storage.BatchResult, error)
+	// корзина календаря (пустой calendarID - личный календарь): удалённые события и их восстановление;
+	GetTrash(ctx context.Context, calendarID string, userID string) ([]storage.Event, error)
+	RestoreEvent(ctx context.Context, calendarID string, id string, userID string) error
+	// журнал аудита события календаря (пустой calendarID - личный календарь), старые записи - первыми;
+	GetEventHistory(ctx context.Context, calendarID string, id string, userID string) ([]storage.AuditEntry, error)
+	// лента изменений событий: создание, изменение, удаление и отправка напоминаний;
+	storage.ChangeFeed
 }
 
 func New(_ Logger, _ Storager) *App {
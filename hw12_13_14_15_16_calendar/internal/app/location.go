@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// UserLocation возвращает зону, в которой считаются периоды списка событий:
+// override из запроса (имя IANA), если задан, иначе зону аккаунта.
+func UserLocation(ctx context.Context, s Storager, userID string, override string) (*time.Location, error) {
+	if override != "" {
+		return storage.LoadLocation(override)
+	}
+
+	account, err := s.GetAccountByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return account.Location()
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// metricsStorager - Storager, который записывает время каждой операции в метрики (см. WithMetrics).
+type metricsStorager struct {
+	Storager
+}
+
+// WithMetrics оборачивает хранилище: время операций попадает в metrics.StorageDuration.
+// Notify и WaitForChanges не замеряются.
+func WithMetrics(s Storager) Storager {
+	return metricsStorager{s}
+}
+
+func (s metricsStorager) AddEventByID(ctx context.Context, event storage.EventCreateDTO, userID string,
+) (string, error) {
+	return metrics.ObserveStorage("AddEventByID", func() (string, error) {
+		return s.Storager.AddEventByID(ctx, event, userID)
+	})
+}
+
+func (s metricsStorager) UpdateEventByID(ctx context.Context, id string, event storage.EventUpdateDTO,
+	userID string,
+) error {
+	return metrics.ObserveStorageErr("UpdateEventByID", func() error {
+		return s.Storager.UpdateEventByID(ctx, id, event, userID)
+	})
+}
+
+func (s metricsStorager) DeleteEventByID(ctx context.Context, id string, userID string) error {
+	return metrics.ObserveStorageErr("DeleteEventByID", func() error {
+		return s.Storager.DeleteEventByID(ctx, id, userID)
+	})
+}
+
+func (s metricsStorager) GetEventListingByUserID(userID string, date time.Time, period string,
+) ([]storage.Event, error) {
+	return metrics.ObserveStorage("GetEventListingByUserID", func() ([]storage.Event, error) {
+		return s.Storager.GetEventListingByUserID(userID, date, period)
+	})
+}
+
+func (s metricsStorager) GetEventByID(id string, userID string) (storage.Event, error) {
+	return metrics.ObserveStorage("GetEventByID", func() (storage.Event, error) {
+		return s.Storager.GetEventByID(id, userID)
+	})
+}
+
+func (s metricsStorager) RespondToEvent(ctx context.Context, eventID string, userID string,
+	status storage.AttendeeStatus,
+) error {
+	return metrics.ObserveStorageErr("RespondToEvent", func() error {
+		return s.Storager.RespondToEvent(ctx, eventID, userID, status)
+	})
+}
+
+func (s metricsStorager) SearchEvents(ctx context.Context, userID string, q storage.SearchQuery,
+) (storage.SearchResult, error) {
+	return metrics.ObserveStorage("SearchEvents", func() (storage.SearchResult, error) {
+		return s.Storager.SearchEvents(ctx, userID, q)
+	})
+}
+
+func (s metricsStorager) GetEventsByUserID(ctx context.Context, userID string) ([]storage.Event, error) {
+	return metrics.ObserveStorage("GetEventsByUserID", func() ([]storage.Event, error) {
+		return s.Storager.GetEventsByUserID(ctx, userID)
+	})
+}
+
+func (s metricsStorager) GetFreeBusy(ctx context.Context, userID string, from, to time.Time,
+	minFree time.Duration,
+) (storage.FreeBusy, error) {
+	return metrics.ObserveStorage("GetFreeBusy", func() (storage.FreeBusy, error) {
+		return s.Storager.GetFreeBusy(ctx, userID, from, to, minFree)
+	})
+}
+
+func (s metricsStorager) GetAccountByLogin(ctx context.Context, login string) (storage.Account, error) {
+	return metrics.ObserveStorage("GetAccountByLogin", func() (storage.Account, error) {
+		return s.Storager.GetAccountByLogin(ctx, login)
+	})
+}
+
+func (s metricsStorager) CreateAccount(ctx context.Context, login string, passwordHash string) (string, error) {
+	return metrics.ObserveStorage("CreateAccount", func() (string, error) {
+		return s.Storager.CreateAccount(ctx, login, passwordHash)
+	})
+}
+
+func (s metricsStorager) GetAccountByID(ctx context.Context, id string) (storage.Account, error) {
+	return metrics.ObserveStorage("GetAccountByID", func() (storage.Account, error) {
+		return s.Storager.GetAccountByID(ctx, id)
+	})
+}
+
+func (s metricsStorager) UpdateAccountPassword(ctx context.Context, id string, passwordHash string) error {
+	return metrics.ObserveStorageErr("UpdateAccountPassword", func() error {
+		return s.Storager.UpdateAccountPassword(ctx, id, passwordHash)
+	})
+}
+
+func (s metricsStorager) SetAccountChannels(ctx context.Context, id string,
+	channels []storage.NotificationChannel,
+) error {
+	return metrics.ObserveStorageErr("SetAccountChannels", func() error {
+		return s.Storager.SetAccountChannels(ctx, id, channels)
+	})
+}
+
+func (s metricsStorager) SetAccountTimeZone(ctx context.Context, id string, timeZone string) error {
+	return metrics.ObserveStorageErr("SetAccountTimeZone", func() error {
+		return s.Storager.SetAccountTimeZone(ctx, id, timeZone)
+	})
+}
+
+func (s metricsStorager) DeleteAccountByID(ctx context.Context, id string) error {
+	return metrics.ObserveStorageErr("DeleteAccountByID", func() error {
+		return s.Storager.DeleteAccountByID(ctx, id)
+	})
+}
+
+func (s metricsStorager) CreateCalendar(ctx context.Context, name string, userID string) (string, error) {
+	return metrics.ObserveStorage("CreateCalendar", func() (string, error) {
+		return s.Storager.CreateCalendar(ctx, name, userID)
+	})
+}
+
+func (s metricsStorager) GetCalendars(ctx context.Context, userID string) ([]storage.Calendar, error) {
+	return metrics.ObserveStorage("GetCalendars", func() ([]storage.Calendar, error) {
+		return s.Storager.GetCalendars(ctx, userID)
+	})
+}
+
+func (s metricsStorager) DeleteCalendar(ctx context.Context, calendarID string, userID string) error {
+	return metrics.ObserveStorageErr("DeleteCalendar", func() error {
+		return s.Storager.DeleteCalendar(ctx, calendarID, userID)
+	})
+}
+
+func (s metricsStorager) GetCalendarACL(ctx context.Context, calendarID string, userID string,
+) ([]storage.ACLEntry, error) {
+	return metrics.ObserveStorage("GetCalendarACL", func() ([]storage.ACLEntry, error) {
+		return s.Storager.GetCalendarACL(ctx, calendarID, userID)
+	})
+}
+
+func (s metricsStorager) SetCalendarACL(ctx context.Context, calendarID string, entry storage.ACLEntry,
+	userID string,
+) error {
+	return metrics.ObserveStorageErr("SetCalendarACL", func() error {
+		return s.Storager.SetCalendarACL(ctx, calendarID, entry, userID)
+	})
+}
+
+func (s metricsStorager) RevokeCalendarAccess(ctx context.Context, calendarID string, granteeID string,
+	userID string,
+) error {
+	return metrics.ObserveStorageErr("RevokeCalendarAccess", func() error {
+		return s.Storager.RevokeCalendarAccess(ctx, calendarID, granteeID, userID)
+	})
+}
+
+func (s metricsStorager) AddCalendarEvent(ctx context.Context, calendarID string, event storage.EventCreateDTO,
+	userID string,
+) (string, error) {
+	return metrics.ObserveStorage("AddCalendarEvent", func() (string, error) {
+		return s.Storager.AddCalendarEvent(ctx, calendarID, event, userID)
+	})
+}
+
+func (s metricsStorager) UpdateCalendarEvent(ctx context.Context, calendarID string, id string,
+	event storage.EventUpdateDTO, userID string,
+) error {
+	return metrics.ObserveStorageErr("UpdateCalendarEvent", func() error {
+		return s.Storager.UpdateCalendarEvent(ctx, calendarID, id, event, userID)
+	})
+}
+
+func (s metricsStorager) DeleteCalendarEvent(ctx context.Context, calendarID string, id string, userID string,
+) error {
+	return metrics.ObserveStorageErr("DeleteCalendarEvent", func() error {
+		return s.Storager.DeleteCalendarEvent(ctx, calendarID, id, userID)
+	})
+}
+
+func (s metricsStorager) GetCalendarEvent(ctx context.Context, calendarID string, id string, userID string,
+) (storage.Event, error) {
+	return metrics.ObserveStorage("GetCalendarEvent", func() (storage.Event, error) {
+		return s.Storager.GetCalendarEvent(ctx, calendarID, id, userID)
+	})
+}
+
+func (s metricsStorager) GetCalendarEventListing(ctx context.Context, calendarID string, date time.Time,
+	period string, userID string,
+) ([]storage.Event, error) {
+	return metrics.ObserveStorage("GetCalendarEventListing", func() ([]storage.Event, error) {
+		return s.Storager.GetCalendarEventListing(ctx, calendarID, date, period, userID)
+	})
+}
+
+func (s metricsStorager) AddEvents(ctx context.Context, calendarID string, events []storage.EventCreateDTO,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	return metrics.ObserveStorage("AddEvents", func() ([]storage.BatchResult, error) {
+		return s.Storager.AddEvents(ctx, calendarID, events, mode, userID)
+	})
+}
+
+func (s metricsStorager) UpdateEvents(ctx context.Context, calendarID string,
+	updates []storage.EventBatchUpdate, mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	return metrics.ObserveStorage("UpdateEvents", func() ([]storage.BatchResult, error) {
+		return s.Storager.UpdateEvents(ctx, calendarID, updates, mode, userID)
+	})
+}
+
+func (s metricsStorager) DeleteEventsByID(ctx context.Context, calendarID string, ids []string,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	return metrics.ObserveStorage("DeleteEventsByID", func() ([]storage.BatchResult, error) {
+		return s.Storager.DeleteEventsByID(ctx, calendarID, ids, mode, userID)
+	})
+}
+
+func (s metricsStorager) GetTrash(ctx context.Context, calendarID string, userID string,
+) ([]storage.Event, error) {
+	return metrics.ObserveStorage("GetTrash", func() ([]storage.Event, error) {
+		return s.Storager.GetTrash(ctx, calendarID, userID)
+	})
+}
+
+func (s metricsStorager) RestoreEvent(ctx context.Context, calendarID string, id string, userID string) error {
+	return metrics.ObserveStorageErr("RestoreEvent", func() error {
+		return s.Storager.RestoreEvent(ctx, calendarID, id, userID)
+	})
+}
+
+func (s metricsStorager) GetEventHistory(ctx context.Context, calendarID string, id string, userID string,
+) ([]storage.AuditEntry, error) {
+	return metrics.ObserveStorage("GetEventHistory", func() ([]storage.AuditEntry, error) {
+		return s.Storager.GetEventHistory(ctx, calendarID, id, userID)
+	})
+}
+
+func (s metricsStorager) ChangeToken(ctx context.Context) (string, error) {
+	return metrics.ObserveStorage("ChangeToken", func() (string, error) {
+		return s.Storager.ChangeToken(ctx)
+	})
+}
+
+func (s metricsStorager) GetChanges(ctx context.Context, userID string, after string, limit int,
+) ([]storage.Change, error) {
+	return metrics.ObserveStorage("GetChanges", func() ([]storage.Change, error) {
+		return s.Storager.GetChanges(ctx, userID, after, limit)
+	})
+}
@@ -0,0 +1,149 @@
+// Package auth выпускает и проверяет токены доступа и хэширует пароли аккаунтов.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidCredentials = errors.New("invalid login or password")
+)
+
+type ctxKey struct{}
+
+// WithUserID кладёт ID аутентифицированного пользователя в контекст.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserIDFromContext достаёт ID пользователя, положенный middleware или интерсептором.
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(ctxKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// TokenManager выпускает JWT, подписанные HMAC-SHA256.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: ttl}
+}
+
+// claims - содержимое токена: кроме sub и exp в нём версия токенов аккаунта на момент выпуска.
+type claims struct {
+	jwt.RegisteredClaims
+	Version int64 `json:"ver"`
+}
+
+// Issue выпускает токен для пользователя с версией токенов его аккаунта
+// (storage.Account.TokenVersion) и возвращает время его истечения.
+func (tm *TokenManager) Issue(userID string, version int64) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(tm.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Version: version,
+	})
+
+	signed, err := token.SignedString(tm.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Parse проверяет подпись и срок действия токена и возвращает ID пользователя
+// и версию токенов, с которой он выпущен.
+func (tm *TokenManager) Parse(token string) (string, int64, error) {
+	var c claims
+
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (any, error) {
+		return tm.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if c.Subject == "" {
+		return "", 0, fmt.Errorf("%w: empty subject", ErrInvalidToken)
+	}
+	return c.Subject, c.Version, nil
+}
+
+// AccountGetter - часть хранилища, по которой проверяется, не отозван ли токен.
+type AccountGetter interface {
+	GetAccountByID(ctx context.Context, id string) (storage.Account, error)
+}
+
+// Verifier проверяет токены и отклоняет отозванные: выпущенные для удалённого аккаунта
+// или до смены пароля (версия токенов аккаунта с тех пор выросла).
+type Verifier struct {
+	tokens   *TokenManager
+	accounts AccountGetter
+}
+
+func NewVerifier(tokens *TokenManager, accounts AccountGetter) *Verifier {
+	return &Verifier{tokens: tokens, accounts: accounts}
+}
+
+// Verify возвращает ID пользователя действующего токена. Для недействительного или отозванного
+// токена ошибка оборачивает ErrInvalidToken; остальные ошибки - сбои хранилища.
+func (v *Verifier) Verify(ctx context.Context, token string) (string, error) {
+	userID, version, err := v.tokens.Parse(token)
+	if err != nil {
+		return "", err
+	}
+
+	account, err := v.accounts.GetAccountByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			return "", fmt.Errorf("%w: account is deleted", ErrInvalidToken)
+		}
+		return "", err
+	}
+	if account.TokenVersion != version {
+		return "", fmt.Errorf("%w: token is revoked", ErrInvalidToken)
+	}
+	return userID, nil
+}
+
+// BearerToken извлекает токен из значения заголовка Authorization.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword сравнивает пароль с bcrypt-хэшем (в том числе с хэшами pgcrypto crypt/gen_salt('bf')).
+func CheckPassword(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
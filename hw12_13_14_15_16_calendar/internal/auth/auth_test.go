@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	tm := NewTokenManager("secret", time.Hour)
+
+	token, expiresAt, err := tm.Issue("42", 3)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	userID, version, err := tm.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, "42", userID)
+	require.Equal(t, int64(3), version)
+
+	_, _, err = NewTokenManager("other", time.Hour).Parse(token)
+	require.True(t, errors.Is(err, ErrInvalidToken))
+
+	_, _, err = tm.Parse(token + "x")
+	require.True(t, errors.Is(err, ErrInvalidToken))
+}
+
+func TestParseExpired(t *testing.T) {
+	tm := NewTokenManager("secret", -time.Minute)
+
+	token, _, err := tm.Issue("42", 0)
+	require.NoError(t, err)
+
+	_, _, err = tm.Parse(token)
+	require.True(t, errors.Is(err, ErrInvalidToken))
+}
+
+// accounts - хранилище аккаунтов для проверки отзыва токенов.
+type accounts map[string]storage.Account
+
+func (a accounts) GetAccountByID(_ context.Context, id string) (storage.Account, error) {
+	if id == "broken" {
+		return storage.Account{}, errors.New("db is down")
+	}
+	account, ok := a[id]
+	if !ok {
+		return storage.Account{}, storage.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func TestVerify(t *testing.T) {
+	tm := NewTokenManager("secret", time.Hour)
+	v := NewVerifier(tm, accounts{"42": {ID: "42", TokenVersion: 1}})
+	ctx := context.Background()
+
+	token, _, err := tm.Issue("42", 1)
+	require.NoError(t, err)
+	userID, err := v.Verify(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, "42", userID)
+
+	// токен, выпущенный до смены пароля, отозван
+	stale, _, err := tm.Issue("42", 0)
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, stale)
+	require.True(t, errors.Is(err, ErrInvalidToken))
+
+	// токен удалённого аккаунта
+	deleted, _, err := tm.Issue("7", 0)
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, deleted)
+	require.True(t, errors.Is(err, ErrInvalidToken))
+
+	// сбой хранилища - не ошибка токена
+	broken, _, err := tm.Issue("broken", 0)
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, broken)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrInvalidToken))
+}
+
+func TestBearerToken(t *testing.T) {
+	token, ok := BearerToken("Bearer abc")
+	require.True(t, ok)
+	require.Equal(t, "abc", token)
+
+	token, ok = BearerToken("bearer  abc ")
+	require.True(t, ok)
+	require.Equal(t, "abc", token)
+
+	for _, bad := range []string{"", "Bearer", "Bearer ", "Basic abc", "abc"} {
+		_, ok := BearerToken(bad)
+		require.False(t, ok, bad)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("user1")
+	require.NoError(t, err)
+	require.NotEqual(t, "user1", hash)
+
+	require.NoError(t, CheckPassword(hash, "user1"))
+	require.True(t, errors.Is(CheckPassword(hash, "user2"), ErrInvalidCredentials))
+	require.True(t, errors.Is(CheckPassword("user1", "user1"), ErrInvalidCredentials))
+}
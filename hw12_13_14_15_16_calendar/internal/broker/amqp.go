@@ -0,0 +1,248 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/queue"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// publishTimeout ограничивает ожидание подтверждения брокера, если у ctx нет своего срока.
+const publishTimeout = 5 * time.Second
+
+// AMQP - RabbitMQ. Публикации ждут подтверждения брокера,
+// получение идёт с ручным подтверждением.
+type AMQP struct {
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	topo     queue.Topology
+	prefetch int
+}
+
+// NewAMQP подключается к RabbitMQ и объявляет топологию очереди name.
+func NewAMQP(url, name string, retryDelays []time.Duration, prefetch int) (*AMQP, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("connect to rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("open a channel: %w", err)
+	}
+
+	b := &AMQP{conn: conn, ch: ch, topo: queue.Topology{Name: name, RetryDelays: retryDelays}, prefetch: prefetch}
+	if err := b.setup(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *AMQP) setup() error {
+	if _, err := b.topo.Declare(b.ch); err != nil {
+		return err
+	}
+	if b.prefetch > 0 {
+		if err := b.ch.Qos(b.prefetch, 0, false); err != nil {
+			return fmt.Errorf("set qos: %w", err)
+		}
+	}
+	// без подтверждений нельзя считать сообщение принятым брокером
+	if err := b.ch.Confirm(false); err != nil {
+		return fmt.Errorf("put channel into confirm mode: %w", err)
+	}
+	return nil
+}
+
+func (b *AMQP) Close() error {
+	b.ch.Close()
+	return b.conn.Close()
+}
+
+func (b *AMQP) publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, publishTimeout)
+		defer cancel()
+	}
+	return queue.Publish(ctx, b.ch, exchange, key, msg)
+}
+
+func (b *AMQP) Publish(ctx context.Context, m Message) (err error) {
+	ctx, span := startPublish(ctx, semconv.MessagingSystemRabbitMQ, b.topo.Name, m)
+	defer func() { tracing.End(span, err) }()
+
+	var headers amqp.Table
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		headers = amqp.Table{queue.TraceParentHeader: traceParent}
+	}
+	return b.publish(ctx, "", b.topo.Name, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    m.Key,
+		Timestamp:    time.Now(),
+		Body:         m.Body,
+	})
+}
+
+func (b *AMQP) Retries() int {
+	return len(b.topo.RetryDelays)
+}
+
+func (b *AMQP) Consume(ctx context.Context) (<-chan Delivery, error) {
+	msgs, err := b.ch.ConsumeWithContext(ctx,
+		b.topo.Name, // queue
+		"",          // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
+	)
+	if err != nil {
+		return nil, fmt.Errorf("register a consumer: %w", err)
+	}
+
+	out := make(chan Delivery)
+	go func() {
+		defer close(out)
+		for d := range msgs {
+			select {
+			case out <- &amqpDelivery{b: b, d: d}:
+			case <-ctx.Done():
+				// неподтверждённое брокер вернёт в очередь при закрытии канала
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// ListDead читает сообщения без подтверждения и возвращает их в очередь недоставленных.
+func (b *AMQP) ListDead(_ context.Context, limit int) ([]Message, error) {
+	var res []Message
+	var last uint64
+	for range limit {
+		d, ok, err := b.ch.Get(b.topo.DeadLetterQueue(), false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		last = d.DeliveryTag
+		res = append(res, toMessage(d))
+	}
+
+	if last != 0 {
+		if err := b.ch.Nack(last, true, true); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ReplayDead переиздаёт сообщения в основную очередь. Из очереди недоставленных
+// сообщение удаляется только после подтверждения брокера.
+func (b *AMQP) ReplayDead(ctx context.Context, key string, limit int) ([]string, error) {
+	var skipped []uint64
+	defer func() {
+		// несовпавшие по ключу остаются в очереди недоставленных
+		for _, tag := range skipped {
+			_ = b.ch.Nack(tag, false, true)
+		}
+	}()
+
+	var replayed []string
+	for len(replayed) < limit {
+		d, ok, err := b.ch.Get(b.topo.DeadLetterQueue(), false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+		if key != "" && d.MessageId != key {
+			skipped = append(skipped, d.DeliveryTag)
+			continue
+		}
+
+		if err := b.publish(ctx, "", b.topo.Name, queue.Republish(d, d.Body, nil)); err != nil {
+			skipped = append(skipped, d.DeliveryTag)
+			return replayed, fmt.Errorf("replay %s: %w", d.MessageId, err)
+		}
+		if err := b.ch.Ack(d.DeliveryTag, false); err != nil {
+			return replayed, err
+		}
+		replayed = append(replayed, d.MessageId)
+	}
+	return replayed, nil
+}
+
+func toMessage(d amqp.Delivery) Message {
+	m := Message{
+		Key:       d.MessageId,
+		Body:      d.Body,
+		Retry:     queue.RetryCount(d),
+		Timestamp: d.Timestamp,
+	}
+	if s, ok := d.Headers[queue.LastErrorHeader].(string); ok {
+		m.LastError = s
+	}
+	m.TraceParent, _ = d.Headers[queue.TraceParentHeader].(string)
+	// отклонённое брокером (nack без возврата) несёт причину в x-death
+	if deaths, ok := d.Headers["x-death"].([]any); ok && len(deaths) > 0 && m.LastError == "" {
+		if death, ok := deaths[0].(amqp.Table); ok {
+			m.LastError, _ = death["reason"].(string)
+		}
+	}
+	return m
+}
+
+type amqpDelivery struct {
+	b *AMQP
+	d amqp.Delivery
+}
+
+func (d *amqpDelivery) Message() Message {
+	return toMessage(d.d)
+}
+
+func (d *amqpDelivery) Ack() error {
+	return d.d.Ack(false)
+}
+
+func (d *amqpDelivery) Retry(ctx context.Context, body []byte) error {
+	retry := queue.RetryCount(d.d) + 1
+	if retry > len(d.b.topo.RetryDelays) {
+		return fmt.Errorf("retry %d exceeds %d configured retries", retry, len(d.b.topo.RetryDelays))
+	}
+
+	msg := queue.Republish(d.d, body, amqp.Table{queue.RetryCountHeader: int32(retry)})
+	if err := d.b.publish(ctx, d.b.topo.RetryExchange(), d.b.topo.RetryKey(retry), msg); err != nil {
+		return err
+	}
+	return d.d.Ack(false)
+}
+
+func (d *amqpDelivery) DeadLetter(ctx context.Context, body []byte, reason string) error {
+	msg := queue.Republish(d.d, body, amqp.Table{
+		queue.RetryCountHeader: int32(queue.RetryCount(d.d)),
+		queue.LastErrorHeader:  reason,
+	})
+	if err := d.b.publish(ctx, "", d.b.topo.DeadLetterQueue(), msg); err != nil {
+		return err
+	}
+	return d.d.Ack(false)
+}
+
+func (d *amqpDelivery) Requeue() error {
+	return d.d.Nack(false, true)
+}
@@ -0,0 +1,139 @@
+// Package broker отделяет планировщик и отправителя от конкретной очереди сообщений.
+//
+// Реализации:
+//   - amqp - RabbitMQ с очередями повторов и недоставленных (см. пакет queue);
+//   - postgres - таблица broker_message, которую отправитель опрашивает;
+//     позволяет запустить планировщик и отправителя без RabbitMQ;
+//   - memory - очередь внутри процесса, только для тестов, в которых весь поток идёт в одном процессе
+//     (NewMemory). Планировщик и отправитель - разные процессы, поэтому New её не создаёт.
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	KindAMQP     = "amqp"
+	KindPostgres = "postgres"
+	KindMemory   = "memory"
+)
+
+var (
+	ErrUnknownKind  = errors.New("unknown broker")
+	ErrProcessLocal = errors.New("memory broker is process-local and cannot connect scheduler and sender")
+)
+
+// Message - напоминание в очереди.
+type Message struct {
+	Key       string    `json:"key"` // ключ идемпотентности
+	Body      []byte    `json:"-"`
+	Retry     int       `json:"retry"`               // номер повтора; 0 у первой доставки
+	LastError string    `json:"lastError,omitempty"` // причина попадания в очередь недоставленных
+	Timestamp time.Time `json:"timestamp"`
+	// Контекст трассировки публикации (traceparent); заполняется при получении.
+	TraceParent string `json:"traceParent,omitempty"`
+}
+
+// Publisher публикует сообщения. Publish возвращается после того,
+// как брокер надёжно принял сообщение. Вместе с сообщением уходит контекст трассировки из ctx.
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
+
+// Delivery - полученное сообщение. Ровно один из методов Ack, Retry, DeadLetter
+// или Requeue должен быть вызван; до этого сообщение считается необработанным
+// и при падении получателя будет доставлено повторно.
+type Delivery interface {
+	Message() Message
+	// Ack подтверждает обработку.
+	Ack() error
+	// Retry заменяет тело сообщения и доставляет его снова после задержки повтора Message().Retry+1.
+	Retry(ctx context.Context, body []byte) error
+	// DeadLetter заменяет тело сообщения и переносит его в очередь недоставленных.
+	DeadLetter(ctx context.Context, body []byte, reason string) error
+	// Requeue возвращает сообщение в очередь без изменений.
+	Requeue() error
+}
+
+// Consumer выдаёт сообщения очереди. Канал закрывается, когда ctx отменён.
+type Consumer interface {
+	Consume(ctx context.Context) (<-chan Delivery, error)
+	// Retries возвращает число повторов, после которых сообщение стоит отправить в DeadLetter.
+	Retries() int
+}
+
+// DeadLetters даёт доступ к очереди недоставленных.
+type DeadLetters interface {
+	ListDead(ctx context.Context, limit int) ([]Message, error)
+	// ReplayDead возвращает в основную очередь до limit сообщений со сброшенным
+	// счётчиком повторов (только с ключом key, если он задан) и возвращает их ключи.
+	ReplayDead(ctx context.Context, key string, limit int) ([]string, error)
+}
+
+type Broker interface {
+	Publisher
+	Consumer
+	DeadLetters
+	Close() error
+}
+
+// Config - параметры для New. Используются только поля выбранной реализации.
+type Config struct {
+	Kind        string
+	Queue       string
+	RetryDelays []time.Duration // задержка перед i+1-м повтором
+
+	AMQPURL  string
+	Prefetch int
+
+	DB           *sql.DB
+	PollInterval time.Duration
+}
+
+// New создаёт брокер выбранного вида.
+func New(cfg Config, logg *zap.Logger) (Broker, error) {
+	switch cfg.Kind {
+	case KindAMQP, "":
+		return NewAMQP(cfg.AMQPURL, cfg.Queue, cfg.RetryDelays, cfg.Prefetch)
+	case KindPostgres:
+		if cfg.DB == nil {
+			return nil, errors.New("postgres broker requires a database connection")
+		}
+		return NewPostgres(cfg.DB, cfg.Queue, cfg.RetryDelays, cfg.PollInterval, logg), nil
+	case KindMemory:
+		return nil, ErrProcessLocal
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, cfg.Kind)
+	}
+}
+
+// startPublish начинает спан публикации m в очередь queue брокера system.
+// Контекст этого спана публикуется вместе с сообщением, и получатель продолжает трейс.
+func startPublish(ctx context.Context, system attribute.KeyValue, queue string, m Message,
+) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "send "+queue, trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			system,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(queue),
+			semconv.MessagingMessageID(m.Key),
+		))
+}
+
+// retryDelay возвращает задержку перед повтором номер retry (с 1).
+func retryDelay(delays []time.Duration, retry int) time.Duration {
+	if len(delays) == 0 {
+		return 0
+	}
+	return delays[min(retry, len(delays))-1]
+}
@@ -0,0 +1,190 @@
+package broker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// memorySystem - значение messaging.system в спанах публикации.
+var memorySystem = semconv.MessagingSystemKey.String("memory")
+
+// Memory - очередь внутри процесса для тестов. Рассчитана на одного потребителя.
+type Memory struct {
+	mu     sync.Mutex
+	queue  []Message
+	dead   []Message
+	timers []*time.Timer
+	delays []time.Duration
+	wake   chan struct{}
+}
+
+func NewMemory(retryDelays []time.Duration) *Memory {
+	return &Memory{delays: retryDelays, wake: make(chan struct{}, 1)}
+}
+
+func (b *Memory) Publish(ctx context.Context, m Message) error {
+	ctx, span := startPublish(ctx, memorySystem, "memory", m)
+	defer span.End()
+
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	m.TraceParent = tracing.TraceParent(ctx)
+	b.push(m)
+	return nil
+}
+
+func (b *Memory) push(m Message) {
+	b.mu.Lock()
+	b.queue = append(b.queue, m)
+	b.mu.Unlock()
+
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *Memory) pop() (Message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.queue) == 0 {
+		return Message{}, false
+	}
+	m := b.queue[0]
+	b.queue = b.queue[1:]
+	return m, true
+}
+
+func (b *Memory) Retries() int {
+	return len(b.delays)
+}
+
+func (b *Memory) Consume(ctx context.Context) (<-chan Delivery, error) {
+	out := make(chan Delivery)
+
+	go func() {
+		defer close(out)
+		for {
+			m, ok := b.pop()
+			if !ok {
+				select {
+				case <-b.wake:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case out <- &memoryDelivery{b: b, m: m}:
+			case <-ctx.Done():
+				// не выданное потребителю возвращаем в начало очереди
+				b.mu.Lock()
+				b.queue = slices.Insert(b.queue, 0, m)
+				b.mu.Unlock()
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (b *Memory) ListDead(_ context.Context, limit int) ([]Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return slices.Clone(b.dead[:min(limit, len(b.dead))]), nil
+}
+
+func (b *Memory) ReplayDead(_ context.Context, key string, limit int) ([]string, error) {
+	b.mu.Lock()
+	var replay []Message
+	rest := b.dead[:0]
+	for _, m := range b.dead {
+		if len(replay) < limit && (key == "" || m.Key == key) {
+			replay = append(replay, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	b.dead = rest
+	b.mu.Unlock()
+
+	keys := make([]string, 0, len(replay))
+	for _, m := range replay {
+		m.Retry, m.LastError = 0, ""
+		b.push(m)
+		keys = append(keys, m.Key)
+	}
+	return keys, nil
+}
+
+// Close останавливает отложенные повторы; они теряются вместе с процессом.
+func (b *Memory) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, t := range b.timers {
+		t.Stop()
+	}
+	b.timers = nil
+	return nil
+}
+
+type memoryDelivery struct {
+	b *Memory
+	m Message
+}
+
+func (d *memoryDelivery) Message() Message {
+	return d.m
+}
+
+func (d *memoryDelivery) Ack() error {
+	return nil
+}
+
+func (d *memoryDelivery) Retry(_ context.Context, body []byte) error {
+	m := d.m
+	m.Body = body
+	m.Retry++
+
+	b := d.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// таймер снимает себя со списка под тем же мьютексом, под которым был добавлен
+	var t *time.Timer
+	t = time.AfterFunc(retryDelay(b.delays, m.Retry), func() {
+		b.mu.Lock()
+		b.timers = slices.DeleteFunc(b.timers, func(x *time.Timer) bool { return x == t })
+		b.mu.Unlock()
+		b.push(m)
+	})
+	b.timers = append(b.timers, t)
+	return nil
+}
+
+func (d *memoryDelivery) DeadLetter(_ context.Context, body []byte, reason string) error {
+	m := d.m
+	m.Body = body
+	m.LastError = reason
+
+	d.b.mu.Lock()
+	defer d.b.mu.Unlock()
+	d.b.dead = append(d.b.dead, m)
+	return nil
+}
+
+func (d *memoryDelivery) Requeue() error {
+	d.b.push(d.m)
+	return nil
+}
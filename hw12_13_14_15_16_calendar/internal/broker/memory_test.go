@@ -0,0 +1,90 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func receive(t *testing.T, deliveries <-chan Delivery) Delivery {
+	t.Helper()
+
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(time.Second):
+		t.Fatal("no delivery")
+		return nil
+	}
+}
+
+func TestMemoryRetryAndDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewMemory([]time.Duration{10 * time.Millisecond})
+	defer b.Close()
+	require.Equal(t, 1, b.Retries())
+
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish(ctx, Message{Key: "event-1-100", Body: []byte(`{"id":"1"}`)}))
+
+	d := receive(t, deliveries)
+	require.Equal(t, "event-1-100", d.Message().Key)
+	require.Equal(t, 0, d.Message().Retry)
+	require.NoError(t, d.Retry(ctx, []byte(`{"id":"1","retry":true}`)))
+
+	d = receive(t, deliveries)
+	require.Equal(t, 1, d.Message().Retry)
+	require.Equal(t, []byte(`{"id":"1","retry":true}`), d.Message().Body)
+	require.NoError(t, d.DeadLetter(ctx, d.Message().Body, "webhook: 500"))
+
+	dead, err := b.ListDead(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, "webhook: 500", dead[0].LastError)
+
+	keys, err := b.ReplayDead(ctx, "other", 10)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	keys, err = b.ReplayDead(ctx, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"event-1-100"}, keys)
+
+	d = receive(t, deliveries)
+	require.Equal(t, 0, d.Message().Retry)
+	require.Empty(t, d.Message().LastError)
+	require.NoError(t, d.Ack())
+
+	dead, err = b.ListDead(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, dead)
+}
+
+func TestMemoryRequeue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := NewMemory(nil)
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish(ctx, Message{Key: "k"}))
+	require.NoError(t, receive(t, deliveries).Requeue())
+	require.Equal(t, "k", receive(t, deliveries).Message().Key)
+}
+
+func TestNewUnknownKind(t *testing.T) {
+	_, err := New(Config{Kind: "kafka"}, nil)
+	require.True(t, errors.Is(err, ErrUnknownKind))
+
+	// сообщения memory не дошли бы из планировщика в отправителя
+	_, err = New(Config{Kind: KindMemory}, nil)
+	require.True(t, errors.Is(err, ErrProcessLocal))
+}
@@ -0,0 +1,237 @@
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = time.Second
+	// pollBatch - сколько сообщений забирается за один опрос.
+	pollBatch = 10
+	// lockTimeout - на сколько сообщение скрывается от других получателей.
+	// Если получатель упал, не подтвердив сообщение, оно вернётся в очередь по истечении срока.
+	lockTimeout = 5 * time.Minute
+)
+
+// Postgres - очередь в таблице broker_message, которую получатель опрашивает.
+// Сообщения разбираются через "for update skip locked", поэтому получателей может быть несколько.
+type Postgres struct {
+	db           *sql.DB
+	queue        string
+	delays       []time.Duration
+	pollInterval time.Duration
+	logg         *zap.Logger
+}
+
+func NewPostgres(db *sql.DB, queue string, retryDelays []time.Duration, pollInterval time.Duration,
+	logg *zap.Logger,
+) *Postgres {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &Postgres{db: db, queue: queue, delays: retryDelays, pollInterval: pollInterval, logg: logg}
+}
+
+// Close ничего не делает: соединением с базой владеет вызывающий.
+func (b *Postgres) Close() error {
+	return nil
+}
+
+func (b *Postgres) Publish(ctx context.Context, m Message) (err error) {
+	ctx, span := startPublish(ctx, semconv.DBSystemNamePostgreSQL, b.queue, m)
+	defer func() { tracing.End(span, err) }()
+
+	sqlSt := `insert into broker_message (queue, idempotency_key, payload, trace_parent) values ($1, $2, $3, $4);`
+
+	_, err = b.db.ExecContext(ctx, sqlSt, b.queue, m.Key, m.Body, tracing.TraceParent(ctx))
+	return err
+}
+
+func (b *Postgres) Retries() int {
+	return len(b.delays)
+}
+
+func (b *Postgres) Consume(ctx context.Context) (<-chan Delivery, error) {
+	out := make(chan Delivery)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(b.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			msgs, err := b.claim(ctx)
+			if err != nil && ctx.Err() == nil {
+				b.logg.Error("failed to poll broker messages", zap.Error(err))
+			}
+			for i, d := range msgs {
+				select {
+				case out <- d:
+				case <-ctx.Done():
+					// не выданные потребителю сразу возвращаем в очередь
+					for _, rest := range msgs[i:] {
+						_ = rest.Requeue()
+					}
+					return
+				}
+			}
+			// полная пачка - вероятно, есть ещё, опрашиваем сразу
+			if len(msgs) == pollBatch {
+				continue
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// claim забирает готовые к доставке сообщения, скрывая их на lockTimeout.
+func (b *Postgres) claim(ctx context.Context) ([]*postgresDelivery, error) {
+	sqlSt := `update broker_message set locked_until = now() + $3 * interval '1 millisecond'
+		where id in (
+			select id from broker_message
+			where queue = $1 and not dead and available_at <= now()
+				and (locked_until is null or locked_until < now())
+			order by id
+			limit $2
+			for update skip locked)
+		returning id, idempotency_key, payload, retry, created_at, trace_parent;`
+
+	rows, err := b.db.QueryContext(ctx, sqlSt, b.queue, pollBatch, lockTimeout.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*postgresDelivery
+	for rows.Next() {
+		var id int64
+		var m Message
+		if err := rows.Scan(&id, &m.Key, &m.Body, &m.Retry, &m.Timestamp, &m.TraceParent); err != nil {
+			return nil, err
+		}
+		res = append(res, &postgresDelivery{b: b, id: id, m: m})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// returning не гарантирует порядок
+	slices.SortFunc(res, func(x, y *postgresDelivery) int { return int(x.id - y.id) })
+	return res, nil
+}
+
+func (b *Postgres) ListDead(ctx context.Context, limit int) ([]Message, error) {
+	sqlSt := `select idempotency_key, payload, retry, last_error, created_at, trace_parent from broker_message
+		where queue = $1 and dead order by id limit $2;`
+
+	rows, err := b.db.QueryContext(ctx, sqlSt, b.queue, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Key, &m.Body, &m.Retry, &m.LastError, &m.Timestamp, &m.TraceParent); err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+func (b *Postgres) ReplayDead(ctx context.Context, key string, limit int) ([]string, error) {
+	sqlSt := `update broker_message
+		set dead = false, retry = 0, last_error = '', available_at = now(), locked_until = null
+		where id in (
+			select id from broker_message
+			where queue = $1 and dead and ($2 = '' or idempotency_key = $2)
+			order by id
+			limit $3
+			for update skip locked)
+		returning idempotency_key;`
+
+	rows, err := b.db.QueryContext(ctx, sqlSt, b.queue, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+type postgresDelivery struct {
+	b  *Postgres
+	id int64
+	m  Message
+}
+
+func (d *postgresDelivery) Message() Message {
+	return d.m
+}
+
+func (d *postgresDelivery) exec(ctx context.Context, sqlSt string, args ...any) error {
+	res, err := d.b.db.ExecContext(ctx, sqlSt, append([]any{d.id}, args...)...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("broker message %d not found", d.id)
+	}
+	return nil
+}
+
+func (d *postgresDelivery) Ack() error {
+	return d.exec(context.Background(), `delete from broker_message where id = $1;`)
+}
+
+func (d *postgresDelivery) Retry(ctx context.Context, body []byte) error {
+	retry := d.m.Retry + 1
+	if retry > len(d.b.delays) {
+		return fmt.Errorf("retry %d exceeds %d configured retries", retry, len(d.b.delays))
+	}
+
+	sqlSt := `update broker_message
+		set payload = $2, retry = $3, available_at = now() + $4 * interval '1 millisecond', locked_until = null
+		where id = $1;`
+	return d.exec(ctx, sqlSt, body, retry, retryDelay(d.b.delays, retry).Milliseconds())
+}
+
+func (d *postgresDelivery) DeadLetter(ctx context.Context, body []byte, reason string) error {
+	sqlSt := `update broker_message set payload = $2, dead = true, last_error = $3, locked_until = null
+		where id = $1;`
+	return d.exec(ctx, sqlSt, body, reason)
+}
+
+func (d *postgresDelivery) Requeue() error {
+	return d.exec(context.Background(), `update broker_message set locked_until = null where id = $1;`)
+}
@@ -0,0 +1,653 @@
+// Package ical конвертирует события календаря в формат iCalendar (RFC 5545) и обратно.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+	prodID      = "-//adettelle//calendar//RU"
+
+	utcLayout      = "20060102T150405Z"
+	floatingLayout = "20060102T150405"
+	dateLayout     = "20060102"
+
+	maxLineOctets = 75
+)
+
+var ErrNoCalendar = errors.New("no VCALENDAR component")
+
+// Item - результат разбора одного VEVENT.
+// Err заполнен, если событие импортировать нельзя; Warnings - свойства, которые были пропущены.
+type Item struct {
+	UID      string
+	Event    storage.EventCreateDTO
+	Warnings []string
+	Err      error
+}
+
+// Свойства VEVENT, которые не переносятся в событие, но и не считаются неподдерживаемыми.
+var ignoredProps = map[string]bool{
+	"UID":           true,
+	"DTSTAMP":       true,
+	"CREATED":       true,
+	"LAST-MODIFIED": true,
+	"SEQUENCE":      true,
+}
+
+// Encode записывает события пользователя в формате VCALENDAR. host - домен UID событий,
+// созданных не импортом (см. EventUID).
+// Повторяющееся событие выгружается в своей зоне (DTSTART;TZID= и VTIMEZONE), чтобы клиент
+// разворачивал правило по тем же местным часам; остальное время - в UTC.
+func Encode(w io.Writer, host string, events []storage.Event) error {
+	bw := bufio.NewWriter(w)
+	lw := lineWriter{w: bw}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+
+	now := time.Now()
+	zones := map[string]bool{}
+	for _, e := range events {
+		if loc := eventZone(e); loc != nil && !zones[loc.String()] {
+			zones[loc.String()] = true
+			writeTimezone(&lw, loc, now)
+		}
+	}
+
+	stamp := now.UTC().Format(utcLayout)
+	for _, e := range events {
+		loc := eventZone(e)
+
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escapeText(EventUID(e, host)))
+		lw.line("DTSTAMP:" + stamp)
+		if !e.CreatedAt.IsZero() {
+			lw.line("CREATED:" + e.CreatedAt.UTC().Format(utcLayout))
+		}
+		lw.line(dateTimeProp("DTSTART", loc, e.Start))
+		lw.line(dateTimeProp("DTEND", loc, e.End))
+		lw.line("SUMMARY:" + escapeText(e.Title))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(e.Description))
+		}
+		if e.RRule != "" {
+			lw.line("RRULE:" + e.RRule)
+		}
+		if len(e.ExDates) > 0 {
+			lw.line(dateTimeProp("EXDATE", loc, e.ExDates...))
+		}
+		for _, trigger := range alarmTriggers(e) {
+			lw.line("BEGIN:VALARM")
+			lw.line("ACTION:DISPLAY")
+			lw.line("DESCRIPTION:" + escapeText(e.Title))
+			lw.line(trigger)
+			lw.line("END:VALARM")
+		}
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// EventUID возвращает UID события в iCalendar: UID, с которым оно импортировано,
+// а для остальных - ID@host, чтобы UID были уникальны не только внутри сервиса.
+func EventUID(e storage.Event, host string) string {
+	if e.UID != "" {
+		return e.UID
+	}
+	return e.ID + "@" + host
+}
+
+// LocalEventID возвращает ID события, если uid - UID, выданный EventUID для host.
+func LocalEventID(uid, host string) (string, bool) {
+	id, ok := strings.CutSuffix(uid, "@"+host)
+	return id, ok && id != ""
+}
+
+// eventZone возвращает зону, в которой выгружается время события, или nil для UTC.
+// Зона нужна только повторяющимся событиям: их правило считается по местному времени.
+func eventZone(e storage.Event) *time.Location {
+	loc := e.Location(time.UTC)
+	if e.RRule == "" || loc == time.UTC {
+		return nil
+	}
+	return loc
+}
+
+// dateTimeProp записывает свойство со временем: в UTC или, если задана зона, с TZID.
+func dateTimeProp(name string, loc *time.Location, times ...time.Time) string {
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		if loc == nil {
+			values = append(values, t.UTC().Format(utcLayout))
+		} else {
+			values = append(values, t.In(loc).Format(floatingLayout))
+		}
+	}
+	if loc != nil {
+		name += ";TZID=" + loc.String()
+	}
+	return name + ":" + strings.Join(values, ",")
+}
+
+// alarmTriggers возвращает TRIGGER для каждого напоминания события. Событие без
+// напоминаний (nil), созданное до их появления, выгружается с абсолютным временем notification.
+func alarmTriggers(e storage.Event) []string {
+	if e.Reminders == nil {
+		if !e.Notification.IsZero() && e.Notification.Before(e.Start) {
+			return []string{"TRIGGER;VALUE=DATE-TIME:" + e.Notification.UTC().Format(utcLayout)}
+		}
+		return nil
+	}
+
+	triggers := make([]string, 0, len(e.Reminders))
+	for _, r := range e.Reminders {
+		triggers = append(triggers, "TRIGGER:"+formatOffset(r))
+	}
+	return triggers
+}
+
+// formatOffset записывает смещение напоминания как отрицательную длительность RFC 5545: -P1DT2H.
+func formatOffset(o storage.Offset) string {
+	d := time.Duration(o)
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("-P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&sb, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 {
+		sb.WriteString("T")
+		for _, unit := range []struct {
+			suffix string
+			size   time.Duration
+		}{{"H", time.Hour}, {"M", time.Minute}, {"S", time.Second}} {
+			if n := d / unit.size; n > 0 {
+				fmt.Fprintf(&sb, "%d%s", n, unit.suffix)
+				d -= n * unit.size
+			}
+		}
+	}
+	return sb.String()
+}
+
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line пишет строку контента, сворачивая её по 75 октетов (RFC 5545, 3.1).
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	for len(s) > maxLineOctets {
+		cut := maxLineOctets
+		// не разрезаем многобайтовый символ UTF-8
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, lw.err = lw.w.WriteString(s[:cut] + "\r\n "); lw.err != nil {
+			return
+		}
+		s = s[cut:]
+	}
+	_, lw.err = lw.w.WriteString(s + "\r\n")
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// Decode разбирает VCALENDAR. Ошибка возвращается, только если сам календарь некорректен;
+// проблемы отдельных событий попадают в Item.Err и Item.Warnings.
+func Decode(r io.Reader) ([]Item, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		items      []Item
+		inCalendar bool
+		seen       bool
+		event      *eventBuilder
+		skip       string // имя пропускаемого компонента
+		skipDepth  int
+	)
+
+	for n, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		p, err := parseProperty(raw)
+		if err != nil {
+			if event != nil {
+				event.fail(fmt.Errorf("line %d: %w", n+1, err))
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		switch {
+		case skip != "":
+			if p.name == "BEGIN" && p.value == skip {
+				skipDepth++
+			}
+			if p.name == "END" && p.value == skip {
+				skipDepth--
+				if skipDepth == 0 {
+					skip = ""
+				}
+			}
+		case p.name == "BEGIN" && p.value == "VCALENDAR":
+			inCalendar, seen = true, true
+		case !inCalendar:
+			return nil, ErrNoCalendar
+		case p.name == "END" && p.value == "VCALENDAR":
+			inCalendar = false
+		case event == nil && p.name == "BEGIN" && p.value == "VEVENT":
+			event = &eventBuilder{}
+		case event == nil && p.name == "BEGIN":
+			// VTIMEZONE разбирать не нужно: TZID сопоставляется с базой IANA
+			if p.value != "VTIMEZONE" {
+				items = append(items, Item{Err: fmt.Errorf("unsupported component %s", p.value)})
+			}
+			skip, skipDepth = p.value, 1
+		case event == nil:
+			// свойства самого календаря (VERSION, PRODID, ...) не используются
+		case p.name == "END" && p.value == "VEVENT":
+			items = append(items, event.build())
+			event = nil
+		default:
+			event.add(p)
+		}
+	}
+
+	if !seen {
+		return nil, ErrNoCalendar
+	}
+	return items, nil
+}
+
+// unfold читает строки контента, склеивая свёрнутые продолжения.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseProperty разбирает строку вида NAME;PARAM=VALUE;PARAM="a:b":value.
+func parseProperty(line string) (property, error) {
+	p := property{params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		}
+		if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := line[:colon]
+	p.value = line[colon+1:]
+
+	parts := strings.Split(head, ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	if p.name == "BEGIN" || p.name == "END" {
+		p.value = strings.ToUpper(p.value)
+	}
+	return p, nil
+}
+
+type eventBuilder struct {
+	uid      string
+	event    storage.EventCreateDTO
+	hasStart bool
+	allDay   bool // DTSTART - дата без времени
+	hasEnd   bool
+	duration time.Duration
+	warnings []string
+	err      error
+
+	skip       string // вложенный неподдерживаемый компонент
+	inAlarm    bool
+	trigger    *time.Duration
+	triggerAbs time.Time
+	alarms     []alarm
+}
+
+// alarm - TRIGGER одного VALARM: относительный или абсолютный.
+type alarm struct {
+	trigger    *time.Duration
+	triggerAbs time.Time
+}
+
+func (b *eventBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *eventBuilder) warn(format string, args ...any) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+func (b *eventBuilder) add(p property) {
+	if b.skip != "" {
+		if p.name == "END" && p.value == b.skip {
+			b.skip = ""
+		}
+		return
+	}
+	if b.inAlarm {
+		b.addAlarm(p)
+		return
+	}
+
+	var err error
+	switch p.name {
+	case "BEGIN":
+		if p.value != "VALARM" {
+			b.warn("unsupported component %s", p.value)
+			b.skip = p.value
+			return
+		}
+		b.inAlarm = true
+		b.trigger, b.triggerAbs = nil, time.Time{}
+	case "UID":
+		b.uid = unescapeText(p.value)
+	case "SUMMARY":
+		b.event.Title = unescapeText(p.value)
+	case "DESCRIPTION":
+		b.event.Description = unescapeText(p.value)
+	case "DTSTART":
+		b.event.Start, err = parseDateTime(p)
+		b.hasStart = err == nil
+		b.allDay = isDate(p)
+		// повторяющееся событие разворачивается в зоне DTSTART
+		if _, zerr := storage.LoadLocation(p.params["TZID"]); zerr == nil && err == nil {
+			b.event.TimeZone = p.params["TZID"]
+		}
+	case "DTEND":
+		b.event.End, err = parseDateTime(p)
+		b.hasEnd = err == nil
+	case "DURATION":
+		b.duration, err = parseDuration(p.value)
+	case "RRULE":
+		var rule storage.RRule
+		rule, err = storage.ParseRRule(p.value)
+		if err != nil {
+			b.warn("RRULE: %v", err)
+			return
+		}
+		b.event.RRule = rule.String()
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			d, err := parseDateTime(property{name: p.name, params: p.params, value: v})
+			if err != nil {
+				b.warn("EXDATE: %v", err)
+				continue
+			}
+			b.event.ExDates = append(b.event.ExDates, d)
+		}
+	default:
+		if !ignoredProps[p.name] {
+			b.warn("unsupported property %s", p.name)
+		}
+	}
+	if err != nil {
+		b.fail(fmt.Errorf("%s: %w", p.name, err))
+	}
+}
+
+func (b *eventBuilder) addAlarm(p property) {
+	switch p.name {
+	case "END":
+		if p.value == "VALARM" {
+			b.inAlarm = false
+			if b.trigger != nil || !b.triggerAbs.IsZero() {
+				b.alarms = append(b.alarms, alarm{trigger: b.trigger, triggerAbs: b.triggerAbs})
+			}
+		}
+	case "TRIGGER":
+		if p.params["VALUE"] == "DATE-TIME" {
+			t, err := parseDateTime(property{name: p.name, value: p.value})
+			if err != nil {
+				b.warn("TRIGGER: %v", err)
+				return
+			}
+			b.triggerAbs = t
+			return
+		}
+		if p.params["RELATED"] == "END" {
+			b.warn("TRIGGER related to END is not supported")
+			return
+		}
+		d, err := parseDuration(p.value)
+		if err != nil {
+			b.warn("TRIGGER: %v", err)
+			return
+		}
+		b.trigger = &d
+	}
+}
+
+func (b *eventBuilder) build() Item {
+	item := Item{UID: b.uid, Warnings: b.warnings, Err: b.err}
+	b.event.UID = b.uid
+	if item.Err != nil {
+		return item
+	}
+
+	switch {
+	case !b.hasStart:
+		item.Err = errors.New("DTSTART is required")
+		return item
+	case b.hasEnd:
+	case b.duration != 0:
+		b.event.End = b.event.Start.Add(b.duration)
+	case b.allDay:
+		// без DTEND и DURATION событие-дата длится день, а событие со временем - ноль (RFC 5545, 3.6.1)
+		b.event.End = b.event.Start.AddDate(0, 0, 1)
+	default:
+		b.event.End = b.event.Start
+	}
+	if (b.hasEnd || b.duration != 0) && !b.event.End.After(b.event.Start) {
+		item.Err = errors.New("event end must be after its start")
+		return item
+	}
+	if b.event.Title == "" {
+		item.Err = errors.New("SUMMARY is required")
+		return item
+	}
+
+	b.buildReminders()
+
+	item.Event = b.event
+	return item
+}
+
+// buildReminders превращает VALARM в напоминания события. Notification по-прежнему
+// заполняется временем первого из них. Событие без VALARM получит напоминания по умолчанию.
+func (b *eventBuilder) buildReminders() {
+	for i, a := range b.alarms {
+		at := a.triggerAbs
+		if a.trigger != nil {
+			at = b.event.Start.Add(*a.trigger)
+		}
+		if i == 0 {
+			b.event.Notification = at
+		}
+
+		offset := storage.Offset(b.event.Start.Sub(at))
+		switch {
+		case offset < 0 || offset > storage.MaxReminderOffset:
+			b.warn("VALARM: trigger %s before start is not supported", offset)
+		case slices.Contains(b.event.Reminders, offset):
+		case len(b.event.Reminders) == storage.MaxReminders:
+			b.warn("only the first %d VALARM are imported", storage.MaxReminders)
+		default:
+			b.event.Reminders = append(b.event.Reminders, offset)
+		}
+	}
+	if len(b.alarms) > 0 && b.event.Reminders == nil {
+		b.event.Reminders = []storage.Offset{}
+	}
+}
+
+// isDate сообщает, задано ли в свойстве значение DATE (без времени).
+func isDate(p property) bool {
+	return p.params["VALUE"] == "DATE" || len(strings.TrimSpace(p.value)) == len(dateLayout)
+}
+
+// parseDateTime понимает DATE-TIME в UTC, с TZID, "плавающее" время и DATE.
+// Плавающее время и даты трактуются в зоне сервера.
+func parseDateTime(p property) (time.Time, error) {
+	loc := time.Local
+	if tzid, ok := p.params["TZID"]; ok {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+
+	v := strings.TrimSpace(p.value)
+	switch {
+	case isDate(p):
+		return time.ParseInLocation(dateLayout, v, loc)
+	case strings.HasSuffix(v, "Z"):
+		return time.Parse(utcLayout, v)
+	default:
+		return time.ParseInLocation(floatingLayout, v, loc)
+	}
+}
+
+// parseDuration разбирает длительность RFC 5545, например "PT1H30M", "-P1D", "P2W".
+func parseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("bad duration %q", orig)
+	}
+	s = s[1:]
+
+	var (
+		total  time.Duration
+		num    int
+		digits bool
+		inTime bool
+	)
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num = num*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, fmt.Errorf("bad duration %q", orig)
+		}
+		switch {
+		case c == 'W' && !inTime:
+			total += time.Duration(num) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			total += time.Duration(num) * 24 * time.Hour
+		case c == 'H' && inTime:
+			total += time.Duration(num) * time.Hour
+		case c == 'M' && inTime:
+			total += time.Duration(num) * time.Minute
+		case c == 'S' && inTime:
+			total += time.Duration(num) * time.Second
+		default:
+			return 0, fmt.Errorf("bad duration %q", orig)
+		}
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("bad duration %q", orig)
+	}
+	return sign * total, nil
+}
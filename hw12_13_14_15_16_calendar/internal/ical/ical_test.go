@@ -0,0 +1,238 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+	events := []storage.Event{
+		{
+			ID:           "1",
+			Title:        "stand-up; daily, short",
+			CreatedAt:    start.AddDate(0, 0, -1),
+			Start:        start,
+			End:          start.Add(15 * time.Minute),
+			Description:  strings.Repeat("длинное описание ", 10) + "\nвторая строка",
+			Notification: start.Add(-10 * time.Minute),
+			RRule:        "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			ExDates:      []time.Time{start.AddDate(0, 0, 2)},
+			TimeZone:     "Europe/Berlin",
+		},
+		{
+			ID:    "2",
+			Title: "retro",
+			Start: start.Add(5 * time.Hour),
+			End:   start.Add(6 * time.Hour),
+		},
+		{
+			ID:           "3",
+			Title:        "review",
+			Start:        start.Add(7 * time.Hour),
+			End:          start.Add(8 * time.Hour),
+			Notification: start.Add(7*time.Hour - 10*time.Minute),
+			Reminders:    []storage.Offset{storage.Offset(10 * time.Minute), storage.Offset(26*time.Hour + 30*time.Second)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, "calendar.example.com", events))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		require.True(t, len(line) <= maxLineOctets+1, line)
+	}
+	// повторяющееся событие - в своей зоне, с VTIMEZONE для неё
+	require.Contains(t, buf.String(), "DTSTART;TZID=Europe/Berlin:20250901T120000\r\n")
+	require.Contains(t, buf.String(), "EXDATE;TZID=Europe/Berlin:20250903T120000\r\n")
+	require.Contains(t, buf.String(), "BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n")
+	require.Contains(t, buf.String(), "DTSTART:20250901T150000Z\r\n")
+
+	items, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	require.Equal(t, []storage.Offset{storage.Offset(10 * time.Minute)}, items[0].Event.Reminders)
+	require.Nil(t, items[1].Event.Reminders)
+	require.Equal(t, events[2].Reminders, items[2].Event.Reminders)
+
+	for i, item := range items {
+		require.NoError(t, item.Err)
+		require.Empty(t, item.Warnings)
+		require.Equal(t, events[i].ID+"@calendar.example.com", item.UID)
+		require.Equal(t, item.UID, item.Event.UID)
+		require.Equal(t, events[i].TimeZone, item.Event.TimeZone)
+		require.Equal(t, events[i].Title, item.Event.Title)
+		require.Equal(t, events[i].Description, item.Event.Description)
+		require.True(t, events[i].Start.Equal(item.Event.Start))
+		require.True(t, events[i].End.Equal(item.Event.End))
+		require.True(t, events[i].Notification.Equal(item.Event.Notification))
+		require.Equal(t, events[i].RRule, item.Event.RRule)
+		require.Equal(t, len(events[i].ExDates), len(item.Event.ExDates))
+	}
+}
+
+func TestDecodeReportsPerEvent(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Moscow",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:ok@example.com",
+		"SUMMARY:Planning",
+		"DTSTART;TZID=Europe/Moscow:20250902T100000",
+		"DURATION:PT1H30M",
+		"ATTENDEE:mailto:someone@example.com",
+		"RRULE:FREQ=YEARLY",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"TRIGGER;RELATED=START:-P1D",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"SUMMARY:No start",
+		"DTEND:20250902T100000Z",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"SUMMARY:todo",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := Decode(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	start := time.Date(2025, time.September, 2, 10, 0, 0, 0, moscow)
+
+	ok := items[0]
+	require.NoError(t, ok.Err)
+	require.Equal(t, "ok@example.com", ok.UID)
+	require.Equal(t, "Europe/Moscow", ok.Event.TimeZone)
+	require.True(t, start.Equal(ok.Event.Start))
+	require.True(t, start.Add(90*time.Minute).Equal(ok.Event.End))
+	require.True(t, start.Add(-15*time.Minute).Equal(ok.Event.Notification))
+	require.Equal(t, []storage.Offset{storage.Offset(15 * time.Minute), storage.Offset(24 * time.Hour)}, ok.Event.Reminders)
+	require.Equal(t, "", ok.Event.RRule)
+	require.Len(t, ok.Warnings, 2)
+
+	require.Equal(t, "broken@example.com", items[1].UID)
+	require.Error(t, items[1].Err)
+
+	require.Error(t, items[2].Err)
+}
+
+func TestDecodeWithoutEnd(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Holiday",
+		"DTSTART;VALUE=DATE:20251104",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Deadline",
+		"DTSTART:20251104T150000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Backwards",
+		"DTSTART:20251104T150000Z",
+		"DURATION:-PT1H",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := Decode(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	// дата без DTEND - весь день, время без DTEND - событие нулевой длительности
+	require.NoError(t, items[0].Err)
+	require.True(t, items[0].Event.Start.AddDate(0, 0, 1).Equal(items[0].Event.End))
+	require.NoError(t, items[1].Err)
+	require.True(t, items[1].Event.Start.Equal(items[1].Event.End))
+	require.Error(t, items[2].Err)
+}
+
+func TestEncodeTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	lw := lineWriter{w: bufio.NewWriter(&buf)}
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	writeTimezone(&lw, berlin, now)
+	writeTimezone(&lw, tokyo, now)
+	require.NoError(t, lw.w.Flush())
+
+	require.Equal(t, strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:19700329T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:19701025T030000",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VTIMEZONE",
+		"TZID:Asia/Tokyo",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0900",
+		"TZOFFSETTO:+0900",
+		"TZNAME:JST",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"",
+	}, "\r\n"), buf.String())
+}
+
+func TestDecodeWithoutCalendar(t *testing.T) {
+	_, err := Decode(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n"))
+	require.True(t, errors.Is(err, ErrNoCalendar))
+
+	_, err = Decode(strings.NewReader(""))
+	require.True(t, errors.Is(err, ErrNoCalendar))
+}
+
+func TestParseDuration(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"PT15M":     15 * time.Minute,
+		"-PT1H":     -time.Hour,
+		"P1D":       24 * time.Hour,
+		"P1W":       7 * 24 * time.Hour,
+		"P1DT2H30S": 26*time.Hour + 30*time.Second,
+	} {
+		got, err := parseDuration(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got, in)
+	}
+
+	for _, bad := range []string{"", "P", "PT", "1H", "PT1D", "P1H", "PT1"} {
+		_, err := parseDuration(bad)
+		require.Error(t, err, bad)
+	}
+}
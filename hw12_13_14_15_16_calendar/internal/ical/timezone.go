@@ -0,0 +1,118 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// transition - переход зоны на другое смещение.
+type transition struct {
+	at       time.Time // первый момент с новым смещением
+	from, to int       // смещения до и после, в секундах
+	name     string    // сокращение после перехода, например CEST
+	dst      bool
+}
+
+// yearTransitions находит переходы зоны loc в году year.
+func yearTransitions(loc *time.Location, year int) []transition {
+	var res []transition
+
+	day := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(1, 0, 0)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		_, from := day.In(loc).Zone()
+		if _, to := next.In(loc).Zone(); to == from {
+			continue
+		}
+		// ищем первую секунду суток с новым смещением
+		lo, hi := day.Unix(), next.Unix()
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			if _, off := time.Unix(mid, 0).In(loc).Zone(); off == from {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		at := time.Unix(hi, 0).In(loc)
+		name, to := at.Zone()
+		res = append(res, transition{at: at, from: from, to: to, name: name, dst: at.IsDST()})
+	}
+	return res
+}
+
+// writeTimezone записывает VTIMEZONE для зоны loc. Переходы текущего года записываются
+// ежегодными правилами (например, последнее воскресенье марта); зона без переходов -
+// одним STANDARD с её смещением.
+func writeTimezone(lw *lineWriter, loc *time.Location, now time.Time) {
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + loc.String())
+
+	transitions := yearTransitions(loc, now.Year())
+	if len(transitions) == 0 {
+		name, offset := now.In(loc).Zone()
+		lw.line("BEGIN:STANDARD")
+		lw.line("DTSTART:19700101T000000")
+		lw.line("TZOFFSETFROM:" + formatUTCOffset(offset))
+		lw.line("TZOFFSETTO:" + formatUTCOffset(offset))
+		lw.line("TZNAME:" + escapeText(name))
+		lw.line("END:STANDARD")
+	}
+
+	for _, t := range transitions {
+		kind := "STANDARD"
+		if t.dst {
+			kind = "DAYLIGHT"
+		}
+		// правило и DTSTART - в местном времени до перехода
+		wall := t.at.In(time.FixedZone("", t.from))
+		n := (wall.Day()-1)/7 + 1
+		if wall.Day()+7 > daysIn(wall.Year(), wall.Month()) {
+			n = -1
+		}
+		first := nthWeekday(1970, wall.Month(), wall.Weekday(), n)
+		h, m, s := wall.Clock()
+		dtstart := time.Date(1970, wall.Month(), first, h, m, s, 0, time.UTC)
+		day := strings.ToUpper(wall.Weekday().String()[:2])
+
+		lw.line("BEGIN:" + kind)
+		lw.line("DTSTART:" + dtstart.Format(floatingLayout))
+		lw.line(fmt.Sprintf("RRULE:FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(wall.Month()), n, day))
+		lw.line("TZOFFSETFROM:" + formatUTCOffset(t.from))
+		lw.line("TZOFFSETTO:" + formatUTCOffset(t.to))
+		lw.line("TZNAME:" + escapeText(t.name))
+		lw.line("END:" + kind)
+	}
+
+	lw.line("END:VTIMEZONE")
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nthWeekday возвращает число месяца n-го дня недели wd; n = -1 - последнего.
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) int {
+	if n < 0 {
+		last := daysIn(year, month)
+		shift := (int(time.Date(year, month, last, 0, 0, 0, 0, time.UTC).Weekday()) - int(wd) + 7) % 7
+		return last - shift
+	}
+	shift := (int(wd) - int(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()) + 7) % 7
+	return 1 + shift + (n-1)*7
+}
+
+// formatUTCOffset записывает смещение в виде UTC-OFFSET RFC 5545: +0100, -0330, +053730.
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	res := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if s := seconds % 60; s != 0 {
+		res += fmt.Sprintf("%02d", s)
+	}
+	return res
+}
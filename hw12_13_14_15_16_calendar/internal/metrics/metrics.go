@@ -0,0 +1,161 @@
+// Package metrics - метрики Prometheus календаря, планировщика и отправителя.
+// Метрики регистрируются в реестре по умолчанию; каждый сервис отдаёт их по /metrics (см. Handler).
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "calendar"
+
+// Значения метки result.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// Запросы к серверу календаря.
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	GRPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "gRPC requests by method and status code.",
+	}, []string{"method", "code"})
+	GRPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request latency by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// StorageDuration - время операций хранилища (см. app.WithMetrics).
+var StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "storage",
+	Name:      "operation_duration_seconds",
+	Help:      "Storage operation latency by operation and result.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"operation", "result"})
+
+// Планировщик: собранные напоминания, поставленные в outbox и опубликованные в брокер.
+var (
+	SchedulerCollected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "collected_total",
+		Help:      "Reminders collected for notification.",
+	})
+	SchedulerNotified = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "notified_total",
+		Help:      "Reminders marked as sent and written to the outbox.",
+	})
+	SchedulerPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "published_total",
+		Help:      "Outbox messages published to the broker by result.",
+	}, []string{"result"})
+)
+
+// Отправитель: полученные сообщения, доставки по каналам и задержка в очереди.
+var (
+	SenderConsumed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "consumed_total",
+		Help:      "Messages received from the reminder queue.",
+	})
+	SenderDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "delivered_total",
+		Help:      "Notifications delivered by channel.",
+	}, []string{"channel"})
+	SenderFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "failed_total",
+		Help:      "Failed notification deliveries by channel.",
+	}, []string{"channel"})
+	SenderQueueLag = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "queue_lag_seconds",
+		Help:      "Time a message spent in the queue before it was received.",
+		// от 50 мс до ~3,6 часа: повторы ждут в очереди минуты
+		Buckets: prometheus.ExponentialBuckets(0.05, 4, 10),
+	})
+)
+
+// Result возвращает значение метки result для ошибки операции.
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOK
+}
+
+// Handler отдаёт метрики в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve отдаёт /metrics на addr, пока ctx не отменён; для сервисов без своего HTTP-сервера.
+func Serve(ctx context.Context, addr string, logg *zap.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(stopCtx)
+	}()
+
+	logg.Info("metrics server is running", zap.String("addr", addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logg.Error("failed to serve metrics", zap.Error(err))
+	}
+}
+
+// ObserveStorage выполняет операцию хранилища op и записывает её время в StorageDuration.
+func ObserveStorage[T any](op string, f func() (T, error)) (T, error) {
+	start := time.Now()
+	v, err := f()
+	StorageDuration.WithLabelValues(op, Result(err)).Observe(time.Since(start).Seconds())
+	return v, err
+}
+
+// ObserveStorageErr - ObserveStorage для операций без результата.
+func ObserveStorageErr(op string, f func() error) error {
+	_, err := ObserveStorage(op, func() (struct{}, error) { return struct{}{}, f() })
+	return err
+}
@@ -0,0 +1,3 @@
+alter table event
+drop column exdates,
+drop column rrule;
\ No newline at end of file
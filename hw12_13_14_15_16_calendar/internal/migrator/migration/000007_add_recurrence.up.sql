@@ -0,0 +1,3 @@
+alter table event
+add column rrule text not null default '',
+add column exdates timestamptz[] not null default '{}';
\ No newline at end of file
@@ -0,0 +1 @@
+-- исходные пароли нельзя восстановить из хэшей, откатывать нечего
\ No newline at end of file
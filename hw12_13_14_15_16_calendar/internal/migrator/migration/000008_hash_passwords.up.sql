@@ -0,0 +1,5 @@
+create extension if not exists pgcrypto;
+
+-- пароли, ещё не являющиеся bcrypt-хэшем, хэшируются на месте
+update account set password = crypt(password, gen_salt('bf', 10))
+where password not like '$2_$%';
\ No newline at end of file
@@ -0,0 +1,3 @@
+alter table event drop constraint event_account_id_fkey;
+alter table event add constraint event_account_id_fkey
+    foreign key (account_id) references account (id);
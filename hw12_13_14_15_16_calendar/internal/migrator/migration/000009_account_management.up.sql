@@ -0,0 +1,6 @@
+-- аккаунт из 000006 вставлен с явным id, сдвигаем последовательность
+select setval('account_id_seq', coalesce((select max(id) from account), 1));
+
+alter table event drop constraint event_account_id_fkey;
+alter table event add constraint event_account_id_fkey
+    foreign key (account_id) references account (id) on delete cascade;
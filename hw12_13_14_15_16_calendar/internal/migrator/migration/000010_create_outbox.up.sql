@@ -0,0 +1,9 @@
+create table outbox (
+    id bigserial primary key,
+    idempotency_key varchar(255) not null,
+    payload jsonb not null,
+    created_at timestamptz not null default now(),
+    delivered_at timestamptz,
+    unique(idempotency_key));
+
+create index outbox_pending_idx on outbox (id) where delivered_at is null;
@@ -0,0 +1 @@
+alter table account drop column notify_channels;
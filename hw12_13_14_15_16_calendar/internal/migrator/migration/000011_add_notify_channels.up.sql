@@ -0,0 +1 @@
+alter table account add column notify_channels jsonb not null default '[]';
@@ -0,0 +1 @@
+drop table broker_message;
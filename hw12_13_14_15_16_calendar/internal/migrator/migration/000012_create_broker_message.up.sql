@@ -0,0 +1,14 @@
+create table broker_message (
+    id bigserial primary key,
+    queue varchar(100) not null,
+    idempotency_key varchar(255) not null default '',
+    payload bytea not null,
+    retry int not null default 0,
+    last_error text not null default '',
+    dead boolean not null default false,
+    available_at timestamptz not null default now(),
+    locked_until timestamptz,
+    created_at timestamptz not null default now());
+
+create index broker_message_ready_idx on broker_message (queue, available_at) where not dead;
+create index broker_message_dead_idx on broker_message (queue, id) where dead;
@@ -0,0 +1,10 @@
+create table event_reminder (
+    event_id integer not null references event (id) on delete cascade,
+    offset_seconds bigint not null check (offset_seconds >= 0),
+    -- начало последнего повторения, о котором уже напомнили
+    fired_for timestamptz,
+    primary key (event_id, offset_seconds));
+
+-- раньше о каждом событии напоминали за час; уже отправленные не повторяем
+insert into event_reminder (event_id, offset_seconds, fired_for)
+select id, 3600, case when notified then date_start end from event;
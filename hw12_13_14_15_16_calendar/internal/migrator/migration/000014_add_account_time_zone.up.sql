@@ -0,0 +1,2 @@
+-- зона IANA, в которой считаются дни, недели и месяцы списка событий
+alter table account add column time_zone text not null default 'UTC';
@@ -0,0 +1,5 @@
+drop index event_account_title_idx;
+drop index event_account_created_idx;
+drop index event_account_start_idx;
+drop index event_search_idx;
+alter table event drop column search;
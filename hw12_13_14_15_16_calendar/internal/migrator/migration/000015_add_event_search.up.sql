@@ -0,0 +1,10 @@
+-- полнотекстовый поиск по названию и описанию; 'simple' - без стемминга,
+-- чтобы одинаково искать русские и английские слова
+alter table event add column search tsvector
+    generated always as (to_tsvector('simple', title || ' ' || coalesce(description, ''))) stored;
+create index event_search_idx on event using gin (search);
+
+-- keyset-пагинация поиска: (столбец сортировки, id) в пределах аккаунта
+create index event_account_start_idx on event (account_id, date_start, id);
+create index event_account_created_idx on event (account_id, created_at, id);
+create index event_account_title_idx on event (account_id, (title collate "C"), id);
@@ -0,0 +1,9 @@
+create table event_attendee (
+    event_id integer not null references event (id) on delete cascade,
+    account_id integer not null references account (id) on delete cascade,
+    status varchar(20) not null default 'pending'
+        check (status in ('pending', 'accepted', 'declined', 'tentative')),
+    primary key (event_id, account_id));
+
+-- события, на которые пригласили пользователя, для его списков
+create index event_attendee_account_idx on event_attendee (account_id);
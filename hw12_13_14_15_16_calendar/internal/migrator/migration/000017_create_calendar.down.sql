@@ -0,0 +1,3 @@
+alter table event drop column calendar_id;
+drop table calendar_acl;
+drop table calendar;
@@ -0,0 +1,24 @@
+create table calendar (
+    id serial primary key,
+    name varchar(255) not null,
+    owner_id integer not null references account (id) on delete cascade,
+    -- личный календарь владельца: в нём события из адресов /user/{userid}/...
+    personal boolean not null default false,
+    created_at timestamptz not null default now());
+create unique index calendar_personal_idx on calendar (owner_id) where personal;
+
+create table calendar_acl (
+    calendar_id integer not null references calendar (id) on delete cascade,
+    account_id integer not null references account (id) on delete cascade,
+    role varchar(20) not null check (role in ('owner', 'editor', 'viewer')),
+    primary key (calendar_id, account_id));
+create index calendar_acl_account_idx on calendar_acl (account_id);
+
+-- у каждого аккаунта появляется личный календарь, в который переходят его события
+insert into calendar (name, owner_id, personal) select 'Personal', id, true from account order by id;
+insert into calendar_acl (calendar_id, account_id, role) select id, owner_id, 'owner' from calendar;
+
+alter table event add column calendar_id integer references calendar (id) on delete cascade;
+update event e set calendar_id = c.id from calendar c where c.owner_id = e.account_id and c.personal;
+alter table event alter column calendar_id set not null;
+create index event_calendar_start_idx on event (calendar_id, date_start, id);
@@ -0,0 +1,3 @@
+drop trigger event_change_notify on event_change;
+drop function notify_event_change();
+drop table event_change;
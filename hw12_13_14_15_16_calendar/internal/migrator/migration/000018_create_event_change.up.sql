@@ -0,0 +1,22 @@
+-- лента изменений событий; event_id без внешнего ключа: запись остаётся после удаления события
+create table event_change (
+    id bigserial primary key,
+    event_id integer not null,
+    calendar_id integer not null,
+    kind varchar(20) not null check (kind in ('created', 'updated', 'deleted', 'notified')),
+    -- пользователи, которые видели событие в момент изменения
+    recipients bigint[] not null,
+    changed_at timestamptz not null default now());
+create index event_change_recipients_idx on event_change using gin (recipients);
+create index event_change_changed_at_idx on event_change (changed_at);
+
+-- подписчики ленты ждут уведомления на канале event_change
+create function notify_event_change() returns trigger as $$
+begin
+    perform pg_notify('event_change', '');
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger event_change_notify after insert on event_change
+    for each statement execute function notify_event_change();
@@ -0,0 +1,2 @@
+-- версия события для оптимистичной блокировки: растёт при каждом изменении
+alter table event add column version integer not null default 1;
@@ -0,0 +1,2 @@
+delete from event where deleted_at is not null;
+alter table event drop column deleted_at;
@@ -0,0 +1,3 @@
+-- корзина: удалённое событие помечается и окончательно удаляется планировщиком позже
+alter table event add column deleted_at timestamptz;
+create index event_deleted_at_idx on event (calendar_id, deleted_at) where deleted_at is not null;
@@ -0,0 +1,3 @@
+drop trigger event_audit_append_only on event_audit;
+drop function reject_event_audit_change();
+drop table event_audit;
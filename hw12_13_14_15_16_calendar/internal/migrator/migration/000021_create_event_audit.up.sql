@@ -0,0 +1,22 @@
+-- журнал аудита изменений событий; без внешних ключей: записи остаются после удаления
+-- события и аккаунта
+create table event_audit (
+    id bigserial primary key,
+    event_id integer not null,
+    actor_id integer not null,
+    operation varchar(20) not null check (operation in ('create', 'update', 'delete', 'restore')),
+    before jsonb,
+    after jsonb not null,
+    request_id text not null default '',
+    created_at timestamptz not null default now());
+create index event_audit_event_id_idx on event_audit (event_id, id);
+
+-- журнал только дополняется
+create function reject_event_audit_change() returns trigger as $$
+begin
+    raise exception 'event_audit is append-only';
+end;
+$$ language plpgsql;
+
+create trigger event_audit_append_only before update or delete or truncate on event_audit
+    for each statement execute function reject_event_audit_change();
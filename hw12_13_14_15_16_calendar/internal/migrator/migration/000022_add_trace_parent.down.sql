@@ -0,0 +1,3 @@
+alter table broker_message drop column trace_parent;
+alter table outbox drop column trace_parent;
+alter table event drop column trace_parent;
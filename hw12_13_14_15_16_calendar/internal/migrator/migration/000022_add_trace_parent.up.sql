@@ -0,0 +1,4 @@
+-- контекст трассировки (W3C traceparent), чтобы напоминание попало в трейс создания события
+alter table event add column trace_parent text not null default '';
+alter table outbox add column trace_parent text not null default '';
+alter table broker_message add column trace_parent text not null default '';
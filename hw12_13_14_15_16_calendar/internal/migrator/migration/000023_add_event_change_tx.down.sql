@@ -0,0 +1,3 @@
+drop table event_change_horizon;
+drop index event_change_tx_id_idx;
+alter table event_change drop column tx;
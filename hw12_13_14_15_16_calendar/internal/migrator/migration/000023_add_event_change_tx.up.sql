@@ -0,0 +1,13 @@
+-- транзакция, записавшая изменение: лента читается по (tx, id) только из завершившихся транзакций,
+-- иначе изменения транзакции, получившей id раньше, но завершившейся позже, пропускались.
+-- У записей до миграции tx = 0, поэтому старые токены (просто id) продолжают работать.
+alter table event_change add column tx bigint not null default 0;
+alter table event_change alter column tx set default pg_current_xact_id()::text::bigint;
+create index event_change_tx_id_idx on event_change (tx, id);
+
+-- последнее по (tx, id) изменение, удалённое из ленты по сроку хранения:
+-- продолжить ленту с токена раньше него нельзя
+create table event_change_horizon (
+    tx bigint not null,
+    id bigint not null);
+insert into event_change_horizon (tx, id) values (0, 0);
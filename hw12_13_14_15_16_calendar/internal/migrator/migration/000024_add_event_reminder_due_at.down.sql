@@ -0,0 +1,2 @@
+drop index event_reminder_due_at_idx;
+alter table event_reminder drop column due_at;
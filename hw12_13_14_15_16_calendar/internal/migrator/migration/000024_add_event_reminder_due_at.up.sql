@@ -0,0 +1,4 @@
+-- для повторяющихся событий: раньше этого времени напоминание не наступит (storage.NextReminder),
+-- и планировщик не разворачивает серию; null - ещё не вычислено
+alter table event_reminder add column due_at timestamptz;
+create index event_reminder_due_at_idx on event_reminder (due_at);
@@ -0,0 +1 @@
+alter table event drop column time_zone;
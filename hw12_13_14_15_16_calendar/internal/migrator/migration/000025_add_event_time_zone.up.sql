@@ -0,0 +1,4 @@
+-- зона, в которой разворачивается правило повторения (storage.Event.Location);
+-- существующим событиям достаётся зона аккаунта автора
+alter table event add column time_zone text not null default 'UTC';
+update event e set time_zone = a.time_zone from account a where a.id = e.account_id;
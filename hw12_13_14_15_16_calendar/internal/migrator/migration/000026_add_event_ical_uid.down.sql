@@ -0,0 +1,2 @@
+drop index event_calendar_ical_uid_idx;
+alter table event drop column ical_uid;
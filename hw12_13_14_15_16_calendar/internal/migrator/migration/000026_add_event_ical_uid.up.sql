@@ -0,0 +1,3 @@
+-- UID iCalendar импортированного события: повторный импорт того же файла не создаёт дубликаты
+alter table event add column ical_uid text not null default '';
+create unique index event_calendar_ical_uid_idx on event (calendar_id, ical_uid) where ical_uid <> '';
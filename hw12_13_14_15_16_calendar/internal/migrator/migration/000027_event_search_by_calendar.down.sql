@@ -0,0 +1,7 @@
+drop index event_account_idx;
+create index event_account_start_idx on event (account_id, date_start, id);
+create index event_account_created_idx on event (account_id, created_at, id);
+create index event_account_title_idx on event (account_id, (title collate "C"), id);
+
+drop index event_calendar_title_idx;
+drop index event_calendar_created_idx;
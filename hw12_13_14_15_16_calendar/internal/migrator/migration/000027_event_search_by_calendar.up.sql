@@ -0,0 +1,10 @@
+-- поиск идёт по календарю, а не по аккаунту: keyset-пагинация по (столбец сортировки, id)
+-- в пределах календаря; для date_start есть event_calendar_start_idx (миграция 000017)
+create index event_calendar_created_idx on event (calendar_id, created_at, id);
+create index event_calendar_title_idx on event (calendar_id, (title collate "C"), id);
+
+drop index event_account_start_idx;
+drop index event_account_created_idx;
+drop index event_account_title_idx;
+-- автор события нужен только при удалении аккаунта (события передаются владельцу календаря)
+create index event_account_idx on event (account_id);
@@ -0,0 +1 @@
+drop index outbox_delivered_idx;
@@ -0,0 +1,2 @@
+-- доставленные сообщения удаляются по сроку хранения (DeleteEvents)
+create index outbox_delivered_idx on outbox (delivered_at) where delivered_at is not null;
@@ -0,0 +1 @@
+alter table account drop column token_version;
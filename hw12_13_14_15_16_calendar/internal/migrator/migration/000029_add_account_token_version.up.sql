@@ -0,0 +1,2 @@
+-- версия токенов доступа; растёт при смене пароля, выпущенные раньше токены отклоняются
+alter table account add column token_version bigint not null default 0;
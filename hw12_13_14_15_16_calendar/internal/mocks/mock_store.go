@@ -36,6 +36,21 @@ func (m *MockStorager) EXPECT() *MockStoragerMockRecorder {
 	return m.recorder
 }
 
+// AddCalendarEvent mocks base method.
+func (m *MockStorager) AddCalendarEvent(arg0 context.Context, arg1 string, arg2 storage.EventCreateDTO, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCalendarEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCalendarEvent indicates an expected call of AddCalendarEvent.
+func (mr *MockStoragerMockRecorder) AddCalendarEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCalendarEvent", reflect.TypeOf((*MockStorager)(nil).AddCalendarEvent), arg0, arg1, arg2, arg3)
+}
+
 // AddEventByID mocks base method.
 func (m *MockStorager) AddEventByID(arg0 context.Context, arg1 storage.EventCreateDTO, arg2 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -51,18 +66,240 @@ func (mr *MockStoragerMockRecorder) AddEventByID(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEventByID", reflect.TypeOf((*MockStorager)(nil).AddEventByID), arg0, arg1, arg2)
 }
 
+// AddEvents mocks base method.
+func (m *MockStorager) AddEvents(arg0 context.Context, arg1 string, arg2 []storage.EventCreateDTO, arg3 storage.BatchMode, arg4 string) ([]storage.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvents", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]storage.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEvents indicates an expected call of AddEvents.
+func (mr *MockStoragerMockRecorder) AddEvents(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvents", reflect.TypeOf((*MockStorager)(nil).AddEvents), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ChangeToken mocks base method.
+func (m *MockStorager) ChangeToken(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeToken indicates an expected call of ChangeToken.
+func (mr *MockStoragerMockRecorder) ChangeToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeToken", reflect.TypeOf((*MockStorager)(nil).ChangeToken), arg0)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStorager) CreateAccount(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoragerMockRecorder) CreateAccount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStorager)(nil).CreateAccount), arg0, arg1, arg2)
+}
+
+// CreateCalendar mocks base method.
+func (m *MockStorager) CreateCalendar(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCalendar", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCalendar indicates an expected call of CreateCalendar.
+func (mr *MockStoragerMockRecorder) CreateCalendar(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCalendar", reflect.TypeOf((*MockStorager)(nil).CreateCalendar), arg0, arg1, arg2)
+}
+
+// DeleteAccountByID mocks base method.
+func (m *MockStorager) DeleteAccountByID(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountByID indicates an expected call of DeleteAccountByID.
+func (mr *MockStoragerMockRecorder) DeleteAccountByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountByID", reflect.TypeOf((*MockStorager)(nil).DeleteAccountByID), arg0, arg1)
+}
+
+// DeleteCalendar mocks base method.
+func (m *MockStorager) DeleteCalendar(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendar", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendar indicates an expected call of DeleteCalendar.
+func (mr *MockStoragerMockRecorder) DeleteCalendar(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendar", reflect.TypeOf((*MockStorager)(nil).DeleteCalendar), arg0, arg1, arg2)
+}
+
+// DeleteCalendarEvent mocks base method.
+func (m *MockStorager) DeleteCalendarEvent(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCalendarEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCalendarEvent indicates an expected call of DeleteCalendarEvent.
+func (mr *MockStoragerMockRecorder) DeleteCalendarEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendarEvent", reflect.TypeOf((*MockStorager)(nil).DeleteCalendarEvent), arg0, arg1, arg2, arg3)
+}
+
 // DeleteEventByID mocks base method.
-func (m *MockStorager) DeleteEventByID(arg0 context.Context, arg1 string) error {
+func (m *MockStorager) DeleteEventByID(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEventByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteEventByID", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteEventByID indicates an expected call of DeleteEventByID.
-func (mr *MockStoragerMockRecorder) DeleteEventByID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStoragerMockRecorder) DeleteEventByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventByID", reflect.TypeOf((*MockStorager)(nil).DeleteEventByID), arg0, arg1, arg2)
+}
+
+// DeleteEventsByID mocks base method.
+func (m *MockStorager) DeleteEventsByID(arg0 context.Context, arg1 string, arg2 []string, arg3 storage.BatchMode, arg4 string) ([]storage.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventsByID", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]storage.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventsByID indicates an expected call of DeleteEventsByID.
+func (mr *MockStoragerMockRecorder) DeleteEventsByID(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsByID", reflect.TypeOf((*MockStorager)(nil).DeleteEventsByID), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetAccountByID mocks base method.
+func (m *MockStorager) GetAccountByID(arg0 context.Context, arg1 string) (storage.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByID", arg0, arg1)
+	ret0, _ := ret[0].(storage.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByID indicates an expected call of GetAccountByID.
+func (mr *MockStoragerMockRecorder) GetAccountByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockStorager)(nil).GetAccountByID), arg0, arg1)
+}
+
+// GetAccountByLogin mocks base method.
+func (m *MockStorager) GetAccountByLogin(arg0 context.Context, arg1 string) (storage.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByLogin", arg0, arg1)
+	ret0, _ := ret[0].(storage.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByLogin indicates an expected call of GetAccountByLogin.
+func (mr *MockStoragerMockRecorder) GetAccountByLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByLogin", reflect.TypeOf((*MockStorager)(nil).GetAccountByLogin), arg0, arg1)
+}
+
+// GetCalendarACL mocks base method.
+func (m *MockStorager) GetCalendarACL(arg0 context.Context, arg1, arg2 string) ([]storage.ACLEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarACL", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.ACLEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarACL indicates an expected call of GetCalendarACL.
+func (mr *MockStoragerMockRecorder) GetCalendarACL(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarACL", reflect.TypeOf((*MockStorager)(nil).GetCalendarACL), arg0, arg1, arg2)
+}
+
+// GetCalendarEvent mocks base method.
+func (m *MockStorager) GetCalendarEvent(arg0 context.Context, arg1, arg2, arg3 string) (storage.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(storage.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarEvent indicates an expected call of GetCalendarEvent.
+func (mr *MockStoragerMockRecorder) GetCalendarEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarEvent", reflect.TypeOf((*MockStorager)(nil).GetCalendarEvent), arg0, arg1, arg2, arg3)
+}
+
+// GetCalendarEventListing mocks base method.
+func (m *MockStorager) GetCalendarEventListing(arg0 context.Context, arg1 string, arg2 time.Time, arg3, arg4 string) ([]storage.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarEventListing", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]storage.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarEventListing indicates an expected call of GetCalendarEventListing.
+func (mr *MockStoragerMockRecorder) GetCalendarEventListing(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarEventListing", reflect.TypeOf((*MockStorager)(nil).GetCalendarEventListing), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetCalendars mocks base method.
+func (m *MockStorager) GetCalendars(arg0 context.Context, arg1 string) ([]storage.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockStoragerMockRecorder) GetCalendars(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventByID", reflect.TypeOf((*MockStorager)(nil).DeleteEventByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockStorager)(nil).GetCalendars), arg0, arg1)
+}
+
+// GetChanges mocks base method.
+func (m *MockStorager) GetChanges(arg0 context.Context, arg1, arg2 string, arg3 int) ([]storage.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockStoragerMockRecorder) GetChanges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockStorager)(nil).GetChanges), arg0, arg1, arg2, arg3)
 }
 
 // GetEventByID mocks base method.
@@ -80,6 +317,21 @@ func (mr *MockStoragerMockRecorder) GetEventByID(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockStorager)(nil).GetEventByID), arg0, arg1)
 }
 
+// GetEventHistory mocks base method.
+func (m *MockStorager) GetEventHistory(arg0 context.Context, arg1, arg2, arg3 string) ([]storage.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]storage.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventHistory indicates an expected call of GetEventHistory.
+func (mr *MockStoragerMockRecorder) GetEventHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventHistory", reflect.TypeOf((*MockStorager)(nil).GetEventHistory), arg0, arg1, arg2, arg3)
+}
+
 // GetEventListingByUserID mocks base method.
 func (m *MockStorager) GetEventListingByUserID(arg0 string, arg1 time.Time, arg2 string) ([]storage.Event, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +347,51 @@ func (mr *MockStoragerMockRecorder) GetEventListingByUserID(arg0, arg1, arg2 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventListingByUserID", reflect.TypeOf((*MockStorager)(nil).GetEventListingByUserID), arg0, arg1, arg2)
 }
 
+// GetEventsByUserID mocks base method.
+func (m *MockStorager) GetEventsByUserID(arg0 context.Context, arg1 string) ([]storage.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]storage.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsByUserID indicates an expected call of GetEventsByUserID.
+func (mr *MockStoragerMockRecorder) GetEventsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsByUserID", reflect.TypeOf((*MockStorager)(nil).GetEventsByUserID), arg0, arg1)
+}
+
+// GetFreeBusy mocks base method.
+func (m *MockStorager) GetFreeBusy(arg0 context.Context, arg1 string, arg2, arg3 time.Time, arg4 time.Duration) (storage.FreeBusy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeBusy", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(storage.FreeBusy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeBusy indicates an expected call of GetFreeBusy.
+func (mr *MockStoragerMockRecorder) GetFreeBusy(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeBusy", reflect.TypeOf((*MockStorager)(nil).GetFreeBusy), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetTrash mocks base method.
+func (m *MockStorager) GetTrash(arg0 context.Context, arg1, arg2 string) ([]storage.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]storage.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockStoragerMockRecorder) GetTrash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockStorager)(nil).GetTrash), arg0, arg1, arg2)
+}
+
 // Notify mocks base method.
 func (m *MockStorager) Notify(arg0 uint) (string, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +407,133 @@ func (mr *MockStoragerMockRecorder) Notify(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockStorager)(nil).Notify), arg0)
 }
 
+// RespondToEvent mocks base method.
+func (m *MockStorager) RespondToEvent(arg0 context.Context, arg1, arg2 string, arg3 storage.AttendeeStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondToEvent indicates an expected call of RespondToEvent.
+func (mr *MockStoragerMockRecorder) RespondToEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockStorager)(nil).RespondToEvent), arg0, arg1, arg2, arg3)
+}
+
+// RestoreEvent mocks base method.
+func (m *MockStorager) RestoreEvent(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEvent", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEvent indicates an expected call of RestoreEvent.
+func (mr *MockStoragerMockRecorder) RestoreEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEvent", reflect.TypeOf((*MockStorager)(nil).RestoreEvent), arg0, arg1, arg2, arg3)
+}
+
+// RevokeCalendarAccess mocks base method.
+func (m *MockStorager) RevokeCalendarAccess(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeCalendarAccess", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeCalendarAccess indicates an expected call of RevokeCalendarAccess.
+func (mr *MockStoragerMockRecorder) RevokeCalendarAccess(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCalendarAccess", reflect.TypeOf((*MockStorager)(nil).RevokeCalendarAccess), arg0, arg1, arg2, arg3)
+}
+
+// SearchEvents mocks base method.
+func (m *MockStorager) SearchEvents(arg0 context.Context, arg1 string, arg2 storage.SearchQuery) (storage.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(storage.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEvents indicates an expected call of SearchEvents.
+func (mr *MockStoragerMockRecorder) SearchEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockStorager)(nil).SearchEvents), arg0, arg1, arg2)
+}
+
+// SetAccountChannels mocks base method.
+func (m *MockStorager) SetAccountChannels(arg0 context.Context, arg1 string, arg2 []storage.NotificationChannel) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountChannels", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountChannels indicates an expected call of SetAccountChannels.
+func (mr *MockStoragerMockRecorder) SetAccountChannels(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountChannels", reflect.TypeOf((*MockStorager)(nil).SetAccountChannels), arg0, arg1, arg2)
+}
+
+// SetAccountTimeZone mocks base method.
+func (m *MockStorager) SetAccountTimeZone(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountTimeZone", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountTimeZone indicates an expected call of SetAccountTimeZone.
+func (mr *MockStoragerMockRecorder) SetAccountTimeZone(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountTimeZone", reflect.TypeOf((*MockStorager)(nil).SetAccountTimeZone), arg0, arg1, arg2)
+}
+
+// SetCalendarACL mocks base method.
+func (m *MockStorager) SetCalendarACL(arg0 context.Context, arg1 string, arg2 storage.ACLEntry, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCalendarACL", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCalendarACL indicates an expected call of SetCalendarACL.
+func (mr *MockStoragerMockRecorder) SetCalendarACL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCalendarACL", reflect.TypeOf((*MockStorager)(nil).SetCalendarACL), arg0, arg1, arg2, arg3)
+}
+
+// UpdateAccountPassword mocks base method.
+func (m *MockStorager) UpdateAccountPassword(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccountPassword indicates an expected call of UpdateAccountPassword.
+func (mr *MockStoragerMockRecorder) UpdateAccountPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountPassword", reflect.TypeOf((*MockStorager)(nil).UpdateAccountPassword), arg0, arg1, arg2)
+}
+
+// UpdateCalendarEvent mocks base method.
+func (m *MockStorager) UpdateCalendarEvent(arg0 context.Context, arg1, arg2 string, arg3 storage.EventUpdateDTO, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCalendarEvent", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCalendarEvent indicates an expected call of UpdateCalendarEvent.
+func (mr *MockStoragerMockRecorder) UpdateCalendarEvent(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCalendarEvent", reflect.TypeOf((*MockStorager)(nil).UpdateCalendarEvent), arg0, arg1, arg2, arg3, arg4)
+}
+
 // UpdateEventByID mocks base method.
 func (m *MockStorager) UpdateEventByID(arg0 context.Context, arg1 string, arg2 storage.EventUpdateDTO, arg3 string) error {
 	m.ctrl.T.Helper()
@@ -123,3 +547,32 @@ func (mr *MockStoragerMockRecorder) UpdateEventByID(arg0, arg1, arg2, arg3 inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventByID", reflect.TypeOf((*MockStorager)(nil).UpdateEventByID), arg0, arg1, arg2, arg3)
 }
+
+// UpdateEvents mocks base method.
+func (m *MockStorager) UpdateEvents(arg0 context.Context, arg1 string, arg2 []storage.EventBatchUpdate, arg3 storage.BatchMode, arg4 string) ([]storage.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvents", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]storage.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEvents indicates an expected call of UpdateEvents.
+func (mr *MockStoragerMockRecorder) UpdateEvents(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvents", reflect.TypeOf((*MockStorager)(nil).UpdateEvents), arg0, arg1, arg2, arg3, arg4)
+}
+
+// WaitForChanges mocks base method.
+func (m *MockStorager) WaitForChanges() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForChanges")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// WaitForChanges indicates an expected call of WaitForChanges.
+func (mr *MockStoragerMockRecorder) WaitForChanges() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForChanges", reflect.TypeOf((*MockStorager)(nil).WaitForChanges))
+}
@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Consumer обрабатывает напоминания из очереди. Сообщение подтверждается только
+// после доставки или после того, как брокер принял его копию для повтора или
+// в очередь недоставленных, поэтому падение отправителя не теряет напоминание.
+type Consumer struct {
+	dispatcher *Dispatcher
+	dedup      *Dedup
+	retries    int
+	logg       *zap.Logger
+}
+
+// NewConsumer создаёт обработчик; после retries повторов напоминание уходит в очередь недоставленных.
+func NewConsumer(dispatcher *Dispatcher, dedup *Dedup, retries int, logg *zap.Logger) *Consumer {
+	return &Consumer{dispatcher: dispatcher, dedup: dedup, retries: retries, logg: logg}
+}
+
+// Run обрабатывает сообщения, пока канал не закроется.
+func (c *Consumer) Run(ctx context.Context, deliveries <-chan broker.Delivery) {
+	for d := range deliveries {
+		c.Handle(ctx, d)
+	}
+}
+
+// Handle обрабатывает одно сообщение в спане, продолжающем трейс его публикации.
+func (c *Consumer) Handle(ctx context.Context, d broker.Delivery) {
+	m := d.Message()
+	ctx, span := tracing.Tracer().Start(tracing.WithTraceParent(ctx, m.TraceParent), "process reminder",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingMessageID(m.Key),
+			attribute.Int("messaging.retry", m.Retry),
+		))
+	var handleErr error
+	defer func() { tracing.End(span, handleErr) }()
+
+	logg := c.logg.With(zap.String("key", m.Key), zap.Int("retry", m.Retry))
+	metrics.SenderConsumed.Inc()
+	if !m.Timestamp.IsZero() {
+		metrics.SenderQueueLag.Observe(time.Since(m.Timestamp).Seconds())
+	}
+
+	// планировщик доставляет напоминания at-least-once, дубликаты отбрасываем по ключу;
+	// повторы несут тот же ключ, их не проверяем
+	if m.Retry == 0 && c.dedup.Seen(m.Key, time.Now()) {
+		logg.Info("Skipped a duplicate message")
+		c.ack(d, logg)
+		return
+	}
+	logg.Info("Received a message", zap.ByteString("message", m.Body))
+
+	var n storage.EventToNotify
+	if err := json.Unmarshal(m.Body, &n); err != nil {
+		// повтор не поможет: сразу в очередь недоставленных
+		handleErr = err
+		logg.Error("Failed to decode a message", zap.Error(err))
+		if err := d.DeadLetter(ctx, m.Body, err.Error()); err != nil {
+			logg.Error("Failed to dead-letter a message", zap.Error(err))
+			c.requeue(d, m, logg)
+		}
+		return
+	}
+
+	failed, deliverErr := c.dispatcher.Deliver(ctx, n)
+	handleErr = deliverErr
+	if len(failed) == 0 {
+		c.ack(d, logg)
+		return
+	}
+
+	// переиздаём напоминание только для неудачных каналов
+	n.Channels = failed
+	body, err := json.Marshal(n)
+	if err != nil {
+		logg.Error("Failed to encode a message", zap.Error(err))
+		c.requeue(d, m, logg)
+		return
+	}
+
+	if m.Retry < c.retries {
+		logg.Warn("Notification delivery failed, will retry", zap.Error(deliverErr))
+		err = d.Retry(ctx, body)
+	} else {
+		logg.Error("Notification delivery failed, giving up", zap.Error(deliverErr))
+		err = d.DeadLetter(ctx, body, deliverErr.Error())
+	}
+	if err != nil {
+		logg.Error("Failed to republish a message", zap.Error(err))
+		c.requeue(d, m, logg)
+	}
+}
+
+func (c *Consumer) ack(d broker.Delivery, logg *zap.Logger) {
+	if err := d.Ack(); err != nil {
+		logg.Error("Failed to ack a message", zap.Error(err))
+	}
+}
+
+// requeue возвращает сообщение в очередь целиком; уже доставленные
+// каналы получат напоминание повторно, но оно не потеряется.
+func (c *Consumer) requeue(d broker.Delivery, m broker.Message, logg *zap.Logger) {
+	c.dedup.Forget(m.Key)
+	if err := d.Requeue(); err != nil {
+		logg.Error("Failed to requeue a message", zap.Error(err))
+	}
+}
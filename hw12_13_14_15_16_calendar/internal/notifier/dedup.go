@@ -0,0 +1,58 @@
+// Package notifier доставляет напоминания, полученные из очереди, пользователям.
+package notifier
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery - через сколько новых ключей удаляются просроченные.
+const sweepEvery = 1000
+
+// Dedup помнит ключи идемпотентности обработанных сообщений в течение ttl,
+// чтобы повторная доставка из outbox не приводила к повторному напоминанию.
+type Dedup struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	seen  map[string]time.Time
+	added int
+}
+
+func NewDedup(ttl time.Duration) *Dedup {
+	return &Dedup{ttl: ttl, seen: map[string]time.Time{}}
+}
+
+// Seen сообщает, встречался ли key за последние ttl, и запоминает его.
+// Пустой ключ (сообщения без идемпотентности) дубликатом не считается.
+func (d *Dedup) Seen(key string, now time.Time) bool {
+	if key == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if at, ok := d.seen[key]; ok && now.Sub(at) < d.ttl {
+		return true
+	}
+
+	d.seen[key] = now
+	d.added++
+	if d.added >= sweepEvery {
+		d.added = 0
+		for k, at := range d.seen {
+			if now.Sub(at) >= d.ttl {
+				delete(d.seen, k)
+			}
+		}
+	}
+	return false
+}
+
+// Forget удаляет ключ, например если обработка сообщения не удалась и его нужно принять повторно.
+func (d *Dedup) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, key)
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestDedup(t *testing.T) {
+	d := NewDedup(time.Hour)
+	now := time.Now()
+
+	require.False(t, d.Seen("event-1-100", now))
+	require.True(t, d.Seen("event-1-100", now.Add(time.Minute)))
+	require.False(t, d.Seen("event-2-100", now))
+
+	// после ttl ключ снова считается новым
+	require.False(t, d.Seen("event-1-100", now.Add(2*time.Hour)))
+
+	d.Forget("event-2-100")
+	require.False(t, d.Seen("event-2-100", now))
+
+	require.False(t, d.Seen("", now))
+	require.False(t, d.Seen("", now))
+}
+
+func TestDedupSweepsExpired(t *testing.T) {
+	d := NewDedup(time.Minute)
+	now := time.Now()
+
+	for i := range sweepEvery {
+		d.Seen(strconv.Itoa(i), now)
+	}
+	later := now.Add(time.Hour)
+	for i := range sweepEvery {
+		d.Seen("new-"+strconv.Itoa(i), later)
+	}
+	require.Len(t, d.seen, sweepEvery)
+}
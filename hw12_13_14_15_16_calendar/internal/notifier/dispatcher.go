@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Backoff - экспоненциальная задержка между повторами доставки.
+type Backoff struct {
+	Initial     time.Duration
+	Max         time.Duration
+	MaxAttempts int // всего попыток, включая первую
+}
+
+// Delays возвращает задержки перед каждым повтором: их MaxAttempts-1.
+func (b Backoff) Delays() []time.Duration {
+	res := make([]time.Duration, 0, max(b.MaxAttempts-1, 0))
+	for attempt := 1; attempt < b.MaxAttempts; attempt++ {
+		res = append(res, b.Delay(attempt))
+	}
+	return res
+}
+
+// Delay возвращает задержку перед попыткой attempt+1 после неудачной попытки attempt (с 1).
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := b.Initial
+	for i := 1; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	return min(d, b.Max)
+}
+
+// Dispatcher рассылает напоминание по каналам аккаунта.
+// Сам он не повторяет доставку: неудачные каналы возвращаются вызывающему,
+// который переиздаёт напоминание только для них, так что успешно доставленное не дублируется.
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	logg      *zap.Logger
+}
+
+func NewDispatcher(notifiers map[string]Notifier, logg *zap.Logger) *Dispatcher {
+	return &Dispatcher{notifiers: notifiers, logg: logg}
+}
+
+// Deliver делает по одной попытке доставки в каждый канал напоминания
+// (или в каналы по умолчанию, если у аккаунта их нет) и возвращает каналы,
+// доставку в которые стоит повторить, вместе с их ошибками.
+// Канал без настроенного отправителя повторять бессмысленно, он только логируется.
+func (d *Dispatcher) Deliver(ctx context.Context, n storage.EventToNotify) ([]storage.NotificationChannel, error) {
+	channels := n.Channels
+	if len(channels) == 0 {
+		channels = storage.DefaultChannels
+	}
+
+	var failed []storage.NotificationChannel
+	var errs []error
+	for _, c := range channels {
+		sendCtx, span := tracing.Tracer().Start(ctx, "deliver "+c.Type,
+			trace.WithAttributes(attribute.String("notification.channel", c.Type)))
+		err := d.send(sendCtx, c, n)
+		tracing.End(span, err)
+		switch {
+		case err == nil:
+			metrics.SenderDelivered.WithLabelValues(c.Type).Inc()
+			d.logg.Info("notification delivered",
+				zap.String("key", n.IdempotencyKey), zap.String("channel", c.Type))
+		case errors.Is(err, storage.ErrInvalidChannel):
+			metrics.SenderFailed.WithLabelValues(c.Type).Inc()
+			d.logg.Error("notification channel is not available, skipping",
+				zap.String("key", n.IdempotencyKey), zap.String("channel", c.Type), zap.Error(err))
+		default:
+			metrics.SenderFailed.WithLabelValues(c.Type).Inc()
+			failed = append(failed, c)
+			errs = append(errs, fmt.Errorf("%s: %w", c.Type, err))
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+func (d *Dispatcher) send(ctx context.Context, c storage.NotificationChannel, n storage.EventToNotify) error {
+	notifier, ok := d.notifiers[c.Type]
+	if !ok {
+		return fmt.Errorf("%w: %q is not configured", storage.ErrInvalidChannel, c.Type)
+	}
+	return notifier.Notify(ctx, c.Target, n)
+}
@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// Notifier доставляет напоминание по одному каналу. target - адрес из настроек аккаунта.
+type Notifier interface {
+	Notify(ctx context.Context, target string, n storage.EventToNotify) error
+}
+
+// WriterNotifier пишет напоминания JSON-строками в файл или stdout.
+type WriterNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+func (wn *WriterNotifier) Notify(_ context.Context, _ string, n storage.EventToNotify) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	wn.mu.Lock()
+	defer wn.mu.Unlock()
+
+	_, err = wn.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,324 @@
+package notifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+var reminder = storage.EventToNotify{
+	ID:             "1",
+	Title:          "Планёрка",
+	Start:          time.Date(2025, time.September, 19, 10, 0, 0, 0, time.UTC),
+	UserID:         "1",
+	IdempotencyKey: "event-1-1758276000",
+}
+
+// fakeSMTP - минимальный SMTP-сервер: принимает одно письмо и отдаёт его в канал.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	mails := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		reply("220 fake ESMTP")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					mails <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return l.Addr().String(), mails
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, mails := fakeSMTP(t)
+
+	n := NewSMTPNotifier(addr, "calendar@example.com", nil)
+	require.NoError(t, n.Notify(context.Background(), "user1@gmail.com", reminder))
+
+	mail := <-mails
+	require.Contains(t, mail, "To: user1@gmail.com")
+	require.Contains(t, mail, "Message-ID: <event-1-1758276000@calendar>")
+	require.Contains(t, mail, "Subject: =?utf-8?q?")
+	require.Contains(t, mail, "Планёрка начнётся")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got storage.EventToNotify
+	var key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil || got.ID == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.Client())
+	require.NoError(t, n.Notify(context.Background(), srv.URL, reminder))
+	require.Equal(t, reminder.IdempotencyKey, key)
+	require.Equal(t, reminder.Title, got.Title)
+
+	failing := reminder
+	failing.ID = "fail"
+	require.Error(t, n.Notify(context.Background(), srv.URL, failing))
+}
+
+func TestWebhookTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	// httptest слушает loopback: соединение запрещено, даже если хост разрешён
+	n := NewWebhookNotifier(&http.Client{Transport: NewWebhookTransport(nil)})
+	err := n.Notify(context.Background(), srv.URL, reminder)
+	require.True(t, errors.Is(err, ErrWebhookForbidden), err)
+
+	n = NewWebhookNotifier(&http.Client{Transport: NewWebhookTransport([]string{"hooks.example.com"})})
+	err = n.Notify(context.Background(), srv.URL, reminder)
+	require.True(t, errors.Is(err, ErrWebhookForbidden), err)
+	require.Contains(t, err.Error(), `host "127.0.0.1"`)
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	for address, forbidden := range map[string]bool{
+		"127.0.0.1:80":             true,
+		"10.1.2.3:443":             true,
+		"192.168.0.10:443":         true,
+		"169.254.169.254:80":       true,
+		"0.0.0.0:80":               true,
+		"[::1]:80":                 true,
+		"[fe80::1]:80":             true,
+		"[fd00::1]:80":             true,
+		"[::ffff:127.0.0.1]:80":    true,
+		"93.184.216.34:443":        false,
+		"[2606:2800:220:1::1]:443": false,
+	} {
+		err := checkWebhookAddress("tcp", address, nil)
+		require.Equal(t, forbidden, errors.Is(err, ErrWebhookForbidden), address)
+	}
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewWriterNotifier(&buf)
+
+	require.NoError(t, n.Notify(context.Background(), "", reminder))
+	require.NoError(t, n.Notify(context.Background(), "", reminder))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var got storage.EventToNotify
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	require.Equal(t, reminder.IdempotencyKey, got.IdempotencyKey)
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 10 * time.Second, MaxAttempts: 5}
+
+	require.Equal(t, time.Second, b.Delay(1))
+	require.Equal(t, 2*time.Second, b.Delay(2))
+	require.Equal(t, 8*time.Second, b.Delay(4))
+	require.Equal(t, 10*time.Second, b.Delay(5))
+	require.Equal(t, 10*time.Second, b.Delay(50))
+}
+
+// flaky отказывает failures раз, затем доставляет.
+type flaky struct {
+	failures int
+	calls    int
+}
+
+func (f *flaky) Notify(context.Context, string, storage.EventToNotify) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("temporary failure")
+	}
+	return nil
+}
+
+func TestBackoffDelays(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 3 * time.Second, MaxAttempts: 4}
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, b.Delays())
+
+	require.Empty(t, Backoff{Initial: time.Second, Max: time.Second, MaxAttempts: 1}.Delays())
+}
+
+func TestDispatcherReturnsFailedChannels(t *testing.T) {
+	webhook := &flaky{failures: 1}
+	log := &flaky{}
+	d := NewDispatcher(map[string]Notifier{
+		storage.ChannelWebhook: webhook,
+		storage.ChannelLog:     log,
+	}, zap.NewNop())
+
+	n := reminder
+	n.Channels = []storage.NotificationChannel{
+		{Type: storage.ChannelWebhook, Target: "http://example.com"},
+		{Type: storage.ChannelLog},
+		{Type: storage.ChannelEmail, Target: "user1@gmail.com"}, // не настроен - не повторяется
+	}
+
+	failed, err := d.Deliver(context.Background(), n)
+	require.Error(t, err)
+	require.Equal(t, []storage.NotificationChannel{{Type: storage.ChannelWebhook, Target: "http://example.com"}}, failed)
+	require.Equal(t, 1, webhook.calls)
+	require.Equal(t, 1, log.calls)
+
+	// повтор только по неудачным каналам
+	n.Channels = failed
+	failed, err = d.Deliver(context.Background(), n)
+	require.NoError(t, err)
+	require.Empty(t, failed)
+	require.Equal(t, 2, webhook.calls)
+	require.Equal(t, 1, log.calls)
+}
+
+func TestDispatcherMetrics(t *testing.T) {
+	d := NewDispatcher(map[string]Notifier{
+		storage.ChannelWebhook: &flaky{failures: 1},
+		storage.ChannelLog:     &flaky{},
+	}, zap.NewNop())
+	delivered := testutil.ToFloat64(metrics.SenderDelivered.WithLabelValues(storage.ChannelLog))
+	failed := testutil.ToFloat64(metrics.SenderFailed.WithLabelValues(storage.ChannelWebhook))
+
+	n := reminder
+	n.Channels = []storage.NotificationChannel{{Type: storage.ChannelWebhook}, {Type: storage.ChannelLog}}
+	_, err := d.Deliver(context.Background(), n)
+	require.Error(t, err)
+
+	require.Equal(t, delivered+1, testutil.ToFloat64(metrics.SenderDelivered.WithLabelValues(storage.ChannelLog)))
+	require.Equal(t, failed+1, testutil.ToFloat64(metrics.SenderFailed.WithLabelValues(storage.ChannelWebhook)))
+}
+
+func TestDispatcherDefaultChannels(t *testing.T) {
+	log := &flaky{}
+	d := NewDispatcher(map[string]Notifier{storage.ChannelLog: log}, zap.NewNop())
+
+	failed, err := d.Deliver(context.Background(), reminder)
+	require.NoError(t, err)
+	require.Empty(t, failed)
+	require.Equal(t, 1, log.calls)
+}
+
+func TestConsumerRetriesThenDeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	webhook := &flaky{failures: 100}
+	log := &flaky{}
+	d := NewDispatcher(map[string]Notifier{
+		storage.ChannelWebhook: webhook,
+		storage.ChannelLog:     log,
+	}, zap.NewNop())
+
+	b := broker.NewMemory([]time.Duration{time.Millisecond, time.Millisecond})
+	c := NewConsumer(d, NewDedup(time.Hour), b.Retries(), zap.NewNop())
+
+	n := reminder
+	n.Channels = []storage.NotificationChannel{
+		{Type: storage.ChannelWebhook, Target: "http://example.com"},
+		{Type: storage.ChannelLog},
+	}
+	body, err := json.Marshal(n)
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(ctx, broker.Message{Key: n.IdempotencyKey, Body: body}))
+	// дубликат из outbox отбрасывается
+	require.NoError(t, b.Publish(ctx, broker.Message{Key: n.IdempotencyKey, Body: body}))
+
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+	for range 4 {
+		select {
+		case m := <-deliveries:
+			c.Handle(ctx, m)
+		case <-time.After(time.Second):
+			t.Fatal("no delivery")
+		}
+	}
+
+	// первая попытка и два повтора, успешный канал не повторяется
+	require.Equal(t, 3, webhook.calls)
+	require.Equal(t, 1, log.calls)
+
+	dead, err := b.ListDead(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Contains(t, dead[0].LastError, "temporary failure")
+
+	var got storage.EventToNotify
+	require.NoError(t, json.Unmarshal(dead[0].Body, &got))
+	require.Equal(t, []storage.NotificationChannel{{Type: storage.ChannelWebhook, Target: "http://example.com"}}, got.Channels)
+}
+
+func TestConsumerDeadLettersUndecodable(t *testing.T) {
+	ctx := context.Background()
+
+	b := broker.NewMemory(nil)
+	c := NewConsumer(NewDispatcher(nil, zap.NewNop()), NewDedup(time.Hour), b.Retries(), zap.NewNop())
+
+	require.NoError(t, b.Publish(ctx, broker.Message{Key: "k", Body: []byte("not json")}))
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+	c.Handle(ctx, <-deliveries)
+
+	dead, err := b.ListDead(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, []byte("not json"), dead[0].Body)
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// SMTPNotifier отправляет напоминание письмом.
+type SMTPNotifier struct {
+	addr string // host:port SMTP-сервера
+	from string
+	auth smtp.Auth // nil - без аутентификации
+}
+
+func NewSMTPNotifier(addr, from string, auth smtp.Auth) *SMTPNotifier {
+	return &SMTPNotifier{addr: addr, from: from, auth: auth}
+}
+
+func (sn *SMTPNotifier) Notify(_ context.Context, target string, n storage.EventToNotify) error {
+	subject := mime.QEncoding.Encode("utf-8", "Напоминание: "+n.Title)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", sn.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	if n.IdempotencyKey != "" {
+		fmt.Fprintf(&msg, "Message-ID: <%s@calendar>\r\n", n.IdempotencyKey)
+	}
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	fmt.Fprintf(&msg, "%s начнётся %s.\r\n", n.Title, n.Start.Format(time.RFC1123Z))
+
+	return smtp.SendMail(sn.addr, sn.auth, sn.from, []string{target}, msg.Bytes())
+}
@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// ErrWebhookForbidden - адрес вебхука во внутренней сети или хост не из списка разрешённых.
+var ErrWebhookForbidden = errors.New("webhook target is not allowed")
+
+// NewWebhookTransport возвращает транспорт вебхуков, который не соединяется с loopback, частными,
+// link-local и неуказанными адресами: адрес вебхука задаёт пользователь, и без проверки через него
+// можно обращаться к внутренним сервисам. Адрес проверяется при соединении, поэтому проверка
+// учитывает и результат DNS, и перенаправления. Если allowedHosts не пуст, запросы допускаются
+// только к этим хостам.
+func NewWebhookTransport(allowedHosts []string) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkWebhookAddress}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil // через прокси проверялся бы адрес прокси, а не получателя
+	t.DialContext = dialer.DialContext
+	return &webhookTransport{base: t, allowedHosts: allowedHosts}
+}
+
+type webhookTransport struct {
+	base         http.RoundTripper
+	allowedHosts []string
+}
+
+func (t *webhookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if len(t.allowedHosts) > 0 && !slices.ContainsFunc(t.allowedHosts, func(h string) bool {
+		return strings.EqualFold(h, host)
+	}) {
+		return nil, fmt.Errorf("%w: host %q", ErrWebhookForbidden, host)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// checkWebhookAddress - net.Dialer.Control: запрещает соединение с адресами внутренней сети.
+func checkWebhookAddress(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebhookForbidden, err)
+	}
+	ip := addrPort.Addr().Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() {
+		return fmt.Errorf("%w: address %s", ErrWebhookForbidden, ip)
+	}
+	return nil
+}
+
+// WebhookNotifier отправляет напоминание POST-запросом с JSON телом.
+// Ключ идемпотентности передаётся в заголовке Idempotency-Key.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+func (wn *WebhookNotifier) Notify(ctx context.Context, target string, n storage.EventToNotify) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", n.IdempotencyKey)
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
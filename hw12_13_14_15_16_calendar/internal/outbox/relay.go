@@ -0,0 +1,86 @@
+// Package outbox публикует в брокер напоминания, записанные планировщиком в таблицу outbox.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"go.uber.org/zap"
+)
+
+// relayBatch - сколько сообщений outbox публикуется за один проход релея.
+const relayBatch = 100
+
+type Store interface {
+	PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error)
+	MarkOutboxDelivered(ctx context.Context, ids []string) error
+}
+
+// Relay публикует недоставленные сообщения outbox и помечает их доставленными.
+// Сообщение помечается только после того, как брокер его принял, поэтому при сбое между
+// публикацией и пометкой оно уйдёт повторно с тем же ключом (доставка at-least-once).
+type Relay struct {
+	store     Store
+	publisher broker.Publisher
+	logg      *zap.Logger
+}
+
+func NewRelay(store Store, publisher broker.Publisher, logg *zap.Logger) *Relay {
+	return &Relay{store: store, publisher: publisher, logg: logg}
+}
+
+// Run вызывает Once каждые interval, пока ctx не отменён.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctxRelay, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err := r.Once(ctxRelay)
+			cancel()
+			if err != nil {
+				r.logg.Error("failed to relay outbox", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Relay) Once(ctx context.Context) error {
+	msgs, err := r.store.PendingOutbox(ctx, relayBatch)
+	if err != nil {
+		return err
+	}
+
+	var delivered []string
+	var publishErr error
+
+	for _, m := range msgs {
+		// публикация продолжает трейс напоминания, записанный в outbox
+		msgCtx := tracing.WithTraceParent(ctx, m.TraceParent)
+		err := r.publisher.Publish(msgCtx, broker.Message{Key: m.IdempotencyKey, Body: m.Payload})
+		metrics.SchedulerPublished.WithLabelValues(metrics.Result(err)).Inc()
+		if err != nil {
+			// порядок сохраняем: остальное опубликуем в следующий проход
+			publishErr = err
+			break
+		}
+		delivered = append(delivered, m.ID)
+		r.logg.Info("sent notification", zap.String("key", m.IdempotencyKey))
+	}
+
+	if len(delivered) > 0 {
+		if err := r.store.MarkOutboxDelivered(ctx, delivered); err != nil {
+			return errors.Join(publishErr, err)
+		}
+	}
+	return publishErr
+}
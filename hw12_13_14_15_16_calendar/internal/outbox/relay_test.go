@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/broker"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/notifier"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/c2fo/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+// TestReminderFlow проходит весь путь напоминания без внешних сервисов:
+// событие -> outbox -> релей -> брокер -> отправитель -> канал log.
+func TestReminderFlow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := memorystorage.New()
+	start := time.Now().Add(30 * time.Minute)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "Планёрка", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	_, err = store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+
+	b := broker.NewMemory(nil)
+	relay := NewRelay(store, b, zap.NewNop())
+	require.NoError(t, relay.Once(ctx))
+	// повторный проход ничего не публикует: outbox помечен доставленным
+	require.NoError(t, relay.Once(ctx))
+
+	pending, err := store.PendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	var sink bytes.Buffer
+	dispatcher := notifier.NewDispatcher(map[string]notifier.Notifier{
+		storage.ChannelLog: notifier.NewWriterNotifier(&sink),
+	}, zap.NewNop())
+	consumer := notifier.NewConsumer(dispatcher, notifier.NewDedup(time.Hour), b.Retries(), zap.NewNop())
+
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+	select {
+	case d := <-deliveries:
+		consumer.Handle(ctx, d)
+	case <-time.After(time.Second):
+		t.Fatal("reminder was not published")
+	}
+
+	var got storage.EventToNotify
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &got))
+	require.Equal(t, id, got.ID)
+	require.Equal(t, storage.NotificationKey(events[0]), got.IdempotencyKey)
+}
+
+// TestReminderFlowTrace проверяет, что публикация, получение и доставка напоминания
+// попадают в трейс запроса, создавшего событие.
+func TestReminderFlowTrace(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := memorystorage.New()
+	start := time.Now().Add(30 * time.Minute)
+	createCtx, createSpan := tracing.Tracer().Start(ctx, "create event")
+	_, err := store.AddEventByID(createCtx,
+		storage.EventCreateDTO{Title: "Планёрка", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+	createSpan.End()
+
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	_, err = store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+
+	b := broker.NewMemory(nil)
+	require.NoError(t, NewRelay(store, b, zap.NewNop()).Once(ctx))
+
+	dispatcher := notifier.NewDispatcher(map[string]notifier.Notifier{
+		storage.ChannelLog: notifier.NewWriterNotifier(&bytes.Buffer{}),
+	}, zap.NewNop())
+	consumer := notifier.NewConsumer(dispatcher, notifier.NewDedup(time.Hour), b.Retries(), zap.NewNop())
+
+	deliveries, err := b.Consume(ctx)
+	require.NoError(t, err)
+	select {
+	case d := <-deliveries:
+		consumer.Handle(ctx, d)
+	case <-time.After(time.Second):
+		t.Fatal("reminder was not published")
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	traceID := createSpan.SpanContext().TraceID()
+	for _, name := range []string{"send memory", "process reminder", "deliver log"} {
+		span, ok := spans[name]
+		require.True(t, ok, name)
+		require.Equal(t, traceID, span.SpanContext().TraceID(), name)
+	}
+	require.Equal(t, spans["send memory"].SpanContext().SpanID(), spans["process reminder"].Parent().SpanID())
+	require.Equal(t, spans["process reminder"].SpanContext().SpanID(), spans["deliver log"].Parent().SpanID())
+}
@@ -0,0 +1,172 @@
+// Package queue описывает топологию RabbitMQ для доставки напоминаний.
+//
+// Планировщик публикует напоминания в основную очередь Name. Отправитель читает её
+// с ручным подтверждением. Неудачная доставка переиздаётся в обменник повторов
+// Name+".retry" с ключом маршрутизации, равным задержке попытки (например, "30s").
+// Очередь повторов Name+".retry.<задержка>" держит сообщение TTL, равный задержке,
+// и по его истечении возвращает сообщение в основную очередь. Сообщения, для которых
+// попытки исчерпаны или которые не удалось разобрать, попадают в Name+".dlq".
+//
+// Задержка входит в имя очереди и ключ, поэтому при смене задержек объявляются новые
+// очереди, а не меняется TTL существующих (RabbitMQ отклонил бы это с PRECONDITION_FAILED).
+// Очереди прежних задержек больше не получают сообщений: после обновления отправителей
+// нужно дождаться, пока они опустеют (не дольше их самой длинной задержки), и удалить их,
+// например rabbitmqctl delete_queue reminders.retry.1m0s. Так же удаляются очереди
+// Name+".retry.<n>" с номером попытки в имени, которые объявляли прежние версии.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// RetryCountHeader - номер повтора, с которым сообщение вернулось в основную очередь.
+	RetryCountHeader = "x-retry-count"
+	// LastErrorHeader - последняя ошибка доставки у сообщения в очереди недоставленных.
+	LastErrorHeader = "x-last-error"
+	// TraceParentHeader - контекст трассировки публикации (W3C traceparent).
+	TraceParentHeader = "traceparent"
+)
+
+// ErrNotConfirmed - брокер не подтвердил публикацию.
+var ErrNotConfirmed = errors.New("publishing is not confirmed by broker")
+
+// Topology - имена очередей и задержки повторов.
+type Topology struct {
+	Name        string
+	RetryDelays []time.Duration // задержка перед i+1-м повтором; len - число повторов
+}
+
+func (t Topology) RetryExchange() string {
+	return t.Name + ".retry"
+}
+
+// RetryKey возвращает ключ маршрутизации повтора номер attempt (с 1): его задержку.
+func (t Topology) RetryKey(attempt int) string {
+	return t.RetryDelays[attempt-1].String()
+}
+
+// RetryQueue возвращает очередь повторов с задержкой delay.
+func (t Topology) RetryQueue(delay time.Duration) string {
+	return t.Name + ".retry." + delay.String()
+}
+
+func (t Topology) DeadLetterQueue() string {
+	return t.Name + ".dlq"
+}
+
+// DeclareMain объявляет основную очередь. Её аргументы должны совпадать у всех,
+// кто её объявляет, поэтому и планировщик, и отправитель используют эту функцию.
+// Отклонённые без возврата (nack, requeue=false) сообщения уходят в очередь недоставленных.
+func (t Topology) DeclareMain(ch *amqp.Channel) (amqp.Queue, error) {
+	if _, err := ch.QueueDeclare(t.DeadLetterQueue(), true, false, false, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("declare %s: %w", t.DeadLetterQueue(), err)
+	}
+
+	q, err := ch.QueueDeclare(
+		t.Name, // name
+		true,   // durable
+		false,  // delete when unused
+		false,  // exclusive
+		false,  // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": t.DeadLetterQueue(),
+		},
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("declare %s: %w", t.Name, err)
+	}
+	return q, nil
+}
+
+// Declare объявляет всю топологию: основную очередь, очередь недоставленных,
+// обменник и очереди повторов.
+func (t Topology) Declare(ch *amqp.Channel) (amqp.Queue, error) {
+	q, err := t.DeclareMain(ch)
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+
+	if err := ch.ExchangeDeclare(t.RetryExchange(), amqp.ExchangeDirect, true, false, false, false, nil); err != nil {
+		return amqp.Queue{}, fmt.Errorf("declare %s: %w", t.RetryExchange(), err)
+	}
+
+	// у повторов с одинаковой задержкой (например, после достижения максимальной) одна очередь
+	for _, delay := range slices.Compact(slices.Sorted(slices.Values(t.RetryDelays))) {
+		name := t.RetryQueue(delay)
+		// у очереди нет потребителей: по истечении TTL сообщение возвращается в основную
+		_, err := ch.QueueDeclare(name, true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": t.Name,
+		})
+		if err != nil {
+			return amqp.Queue{}, fmt.Errorf("declare %s: %w", name, err)
+		}
+		if err := ch.QueueBind(name, delay.String(), t.RetryExchange(), false, nil); err != nil {
+			return amqp.Queue{}, fmt.Errorf("bind %s: %w", name, err)
+		}
+	}
+
+	return q, nil
+}
+
+// RetryCount возвращает номер повтора сообщения; у первой доставки он 0.
+func RetryCount(d amqp.Delivery) int {
+	switch v := d.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// Republish готовит копию доставленного сообщения с новым телом и заголовками.
+// MessageId сохраняется, чтобы получатель мог распознать повтор, а traceparent -
+// чтобы повтор остался в трейсе исходной публикации.
+func Republish(d amqp.Delivery, body []byte, headers amqp.Table) amqp.Publishing {
+	if traceParent, ok := d.Headers[TraceParentHeader]; ok {
+		headers = maps.Clone(headers)
+		if headers == nil {
+			headers = amqp.Table{}
+		}
+		headers[TraceParentHeader] = traceParent
+	}
+	return amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    time.Now(),
+		Body:         body,
+	}
+}
+
+// Publish публикует сообщение и ждёт подтверждения брокера.
+// Канал должен быть переведён в режим подтверждений (ch.Confirm).
+func Publish(ctx context.Context, ch *amqp.Channel, exchange, key string, msg amqp.Publishing) error {
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, key, false, false, msg)
+	if err != nil {
+		return err
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return ErrNotConfirmed
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestTopologyNames(t *testing.T) {
+	topo := Topology{Name: "reminders", RetryDelays: []time.Duration{time.Second, 2 * time.Second}}
+
+	require.Equal(t, "reminders.retry", topo.RetryExchange())
+	require.Equal(t, "reminders.retry.2s", topo.RetryQueue(2*time.Second))
+	require.Equal(t, "2s", topo.RetryKey(2))
+	require.Equal(t, topo.RetryQueue(2*time.Second), "reminders.retry."+topo.RetryKey(2))
+	require.Equal(t, "reminders.dlq", topo.DeadLetterQueue())
+}
+
+func TestRetryCount(t *testing.T) {
+	require.Equal(t, 0, RetryCount(amqp.Delivery{}))
+	// после брокера целые числа в заголовках приходят как int32 или int64
+	require.Equal(t, 2, RetryCount(amqp.Delivery{Headers: amqp.Table{RetryCountHeader: int32(2)}}))
+	require.Equal(t, 3, RetryCount(amqp.Delivery{Headers: amqp.Table{RetryCountHeader: int64(3)}}))
+	require.Equal(t, 0, RetryCount(amqp.Delivery{Headers: amqp.Table{RetryCountHeader: "x"}}))
+}
+
+func TestRepublishKeepsMessageID(t *testing.T) {
+	d := amqp.Delivery{MessageId: "event-1-100", ContentType: "application/json", Body: []byte("old")}
+
+	p := Republish(d, []byte("new"), amqp.Table{RetryCountHeader: int32(1)})
+	require.Equal(t, "event-1-100", p.MessageId)
+	require.Equal(t, "application/json", p.ContentType)
+	require.Equal(t, amqp.Persistent, p.DeliveryMode)
+	require.Equal(t, []byte("new"), p.Body)
+	require.Equal(t, int32(1), p.Headers[RetryCountHeader])
+}
+
+func TestRepublishKeepsTraceParent(t *testing.T) {
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	d := amqp.Delivery{MessageId: "event-1-100", Headers: amqp.Table{TraceParentHeader: traceParent}}
+
+	p := Republish(d, []byte("new"), nil)
+	require.Equal(t, traceParent, p.Headers[TraceParentHeader])
+
+	headers := amqp.Table{LastErrorHeader: "boom"}
+	p = Republish(d, []byte("new"), headers)
+	require.Equal(t, traceParent, p.Headers[TraceParentHeader])
+	require.Equal(t, "boom", p.Headers[LastErrorHeader])
+	require.Equal(t, 1, len(headers))
+}
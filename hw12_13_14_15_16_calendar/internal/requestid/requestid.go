@@ -0,0 +1,44 @@
+// Package requestid передаёт ID запроса от HTTP и gRPC до хранилища, которое пишет его в журнал аудита.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header - заголовок HTTP с ID запроса; сервер возвращает его и в ответе.
+	Header = "X-Request-ID"
+	// MetadataKey - ключ метаданных gRPC с ID запроса.
+	MetadataKey = "x-request-id"
+	// maxLen ограничивает длину ID, пришедшего от клиента.
+	maxLen = 128
+)
+
+type ctxKey struct{}
+
+// Ensure возвращает ID запроса от клиента, если он не пустой, не длиннее maxLen
+// и состоит из печатных ASCII-символов, иначе - новый.
+func Ensure(id string) string {
+	if id == "" || len(id) > maxLen {
+		return uuid.New().String()
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return uuid.New().String()
+		}
+	}
+	return id
+}
+
+// WithID кладёт ID запроса в контекст.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext достаёт ID запроса; вне запроса (планировщик, тесты) - пустая строка.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,24 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestEnsure(t *testing.T) {
+	require.Equal(t, "req-1", Ensure("req-1"))
+
+	for _, id := range []string{"", "with space", "перевод", strings.Repeat("a", maxLen+1)} {
+		generated := Ensure(id)
+		require.NotEqual(t, id, generated)
+		require.Len(t, generated, 36)
+	}
+}
+
+func TestContext(t *testing.T) {
+	require.Equal(t, "", FromContext(context.Background()))
+	require.Equal(t, "req-1", FromContext(WithID(context.Background(), "req-1")))
+}
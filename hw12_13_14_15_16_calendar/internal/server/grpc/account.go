@@ -0,0 +1,185 @@
+package internalgrpc
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *GRPCServer) RegisterAccount(ctx context.Context,
+	in *pb.RegisterAccountRequest,
+) (*pb.RegisterAccountResponse, error) {
+	var response pb.RegisterAccountResponse
+
+	account := storage.AccountCreateDTO{Login: in.Login, Password: in.Password}
+	if err := validator.New().Struct(account); err != nil {
+		err = fmt.Errorf("%w: %w", storage.ErrValidation, err)
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	hash, err := auth.HashPassword(account.Password)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	id, err := s.Storager.CreateAccount(ctx, account.Login, hash)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Id = id
+
+	return &response, nil
+}
+
+func (s *GRPCServer) GetAccount(ctx context.Context, _ *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	var response pb.GetAccountResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	a, err := s.Storager.GetAccountByID(ctx, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	channels := make([]*pb.NotificationChannel, 0, len(a.Channels))
+	for _, c := range a.Channels {
+		channels = append(channels, &pb.NotificationChannel{Type: c.Type, Target: c.Target})
+	}
+
+	response.Account = &pb.Account{
+		Id:        a.ID,
+		Login:     a.Login,
+		CreatedAt: timestamppb.New(a.CreatedAt),
+		Channels:  channels,
+		TimeZone:  a.TimeZone,
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) ChangePassword(ctx context.Context,
+	in *pb.ChangePasswordRequest,
+) (*pb.ChangePasswordResponse, error) {
+	var response pb.ChangePasswordResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	change := storage.PasswordChangeDTO{OldPassword: in.OldPassword, NewPassword: in.NewPassword}
+	if err := validator.New().Struct(change); err != nil {
+		err = fmt.Errorf("%w: %w", storage.ErrValidation, err)
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	a, err := s.Storager.GetAccountByID(ctx, userID)
+	if err == nil && auth.CheckPassword(a.PasswordHash, change.OldPassword) != nil {
+		err = fmt.Errorf("%w: wrong current password", storage.ErrForbidden)
+	}
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	hash, err := auth.HashPassword(change.NewPassword)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.UpdateAccountPassword(ctx, userID, hash)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) DeleteAccount(ctx context.Context, _ *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	var response pb.DeleteAccountResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.DeleteAccountByID(ctx, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) SetNotificationChannels(ctx context.Context,
+	in *pb.SetNotificationChannelsRequest,
+) (*pb.SetNotificationChannelsResponse, error) {
+	var response pb.SetNotificationChannelsResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	channels := make([]storage.NotificationChannel, 0, len(in.Channels))
+	for _, c := range in.Channels {
+		channels = append(channels, storage.NotificationChannel{Type: c.Type, Target: c.Target})
+	}
+
+	if err := storage.ValidateChannels(channels); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.SetAccountChannels(ctx, userID, channels)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) SetTimeZone(ctx context.Context, in *pb.SetTimeZoneRequest) (*pb.SetTimeZoneResponse, error) {
+	var response pb.SetTimeZoneResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if _, err := storage.LoadLocation(in.TimeZone); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.SetAccountTimeZone(ctx, userID, in.TimeZone)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,46 @@
+package internalgrpc
+
+import (
+	"context"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *GRPCServer) GetEventHistory(ctx context.Context,
+	in *pb.GetEventHistoryRequest,
+) (*pb.GetEventHistoryResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.Storager.GetEventHistory(ctx, in.CalendarID, in.Id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetEventHistoryResponse{Entries: toPBAuditEntries(entries)}, nil
+}
+
+func toPBAuditEntries(entries []storage.AuditEntry) []*pb.AuditEntry {
+	res := make([]*pb.AuditEntry, 0, len(entries))
+	for _, a := range entries {
+		entry := &pb.AuditEntry{
+			Id:        a.ID,
+			EventID:   a.EventID,
+			ActorID:   a.ActorID,
+			Operation: string(a.Operation),
+			RequestID: a.RequestID,
+			CreatedAt: timestamppb.New(a.CreatedAt),
+		}
+		if a.Before != nil {
+			entry.Before = toPBEvent(*a.Before)
+		}
+		if a.After != nil {
+			entry.After = toPBEvent(*a.After)
+		}
+		res = append(res, entry)
+	}
+	return res
+}
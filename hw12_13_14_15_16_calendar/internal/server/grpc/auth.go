@@ -0,0 +1,126 @@
+package internalgrpc
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// методы, доступные без токена.
+var publicMethods = map[string]bool{
+	pb.Storager_Login_FullMethodName:           true,
+	pb.Storager_RegisterAccount_FullMethodName: true,
+}
+
+// authInterceptor проверяет токен из метаданных authorization и кладёт ID пользователя в контекст.
+// Токены удалённых аккаунтов и выпущенные до смены пароля отклоняются.
+func authInterceptor(tokens *auth.Verifier, logg *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, tokens, logg)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor - authInterceptor для потоковых методов.
+func authStreamInterceptor(tokens *auth.Verifier, logg *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), tokens, logg)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authStream - поток с контекстом, в который положен ID пользователя.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate проверяет токен из метаданных authorization и возвращает контекст с ID пользователя.
+func authenticate(ctx context.Context, tokens *auth.Verifier, logg *zap.Logger) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing access token")
+	}
+	token, ok := auth.BearerToken(values[0])
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "malformed authorization metadata")
+	}
+
+	userID, err := tokens.Verify(ctx, token)
+	if err != nil && !errors.Is(err, auth.ErrInvalidToken) {
+		logg.Error("error in verifying access token", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	if err != nil {
+		logg.Info("invalid access token", zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+	return auth.WithUserID(ctx, userID), nil
+}
+
+// requestUserID возвращает пользователя из токена. userID из запроса оставлен для
+// совместимости и должен либо отсутствовать, либо совпадать с пользователем токена.
+func requestUserID(ctx context.Context, userID string) (string, error) {
+	tokenUserID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing access token")
+	}
+	if userID != "" && userID != tokenUserID {
+		return "", status.Error(codes.PermissionDenied, "access to another user's events is denied")
+	}
+	return tokenUserID, nil
+}
+
+func (s *GRPCServer) Login(ctx context.Context, in *pb.LoginRequest) (*pb.LoginResponse, error) {
+	var response pb.LoginResponse
+
+	account, err := s.Storager.GetAccountByLogin(ctx, in.Login)
+	if err == nil {
+		err = auth.CheckPassword(account.PasswordHash, in.Password)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			// не сообщаем, что именно неверно - логин или пароль
+			err = auth.ErrInvalidCredentials
+		}
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	token, expiresAt, err := s.tokens.Issue(account.ID, account.TokenVersion)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Token = token
+	response.ExpiresAt = timestamppb.New(expiresAt)
+
+	return &response, nil
+}
@@ -0,0 +1,106 @@
+package internalgrpc
+
+import (
+	"context"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// batchModes - режим пакетной операции хранилища для режима из запроса.
+var batchModes = map[pb.BatchMode]storage.BatchMode{
+	pb.BatchMode_ATOMIC:      storage.BatchAtomic,
+	pb.BatchMode_BEST_EFFORT: storage.BatchBestEffort,
+}
+
+func (s *GRPCServer) BatchAddEvents(ctx context.Context, in *pb.BatchAddEventsRequest) (*pb.BatchResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]storage.EventCreateDTO, 0, len(in.Events))
+	for _, e := range in.Events {
+		if e == nil {
+			return nil, storage.Invalid("empty event in batch")
+		}
+		events = append(events, toEventCreateDTO(e, in.RejectOverlap))
+	}
+
+	results, err := s.Storager.AddEvents(ctx, in.CalendarID, events, batchModes[in.Mode], userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BatchResponse{
+		Results: toPBBatchResults(s.logg, pb.Storager_BatchAddEvents_FullMethodName, results),
+	}, nil
+}
+
+func (s *GRPCServer) BatchUpdateEvents(ctx context.Context,
+	in *pb.BatchUpdateEventsRequest,
+) (*pb.BatchResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]storage.EventBatchUpdate, 0, len(in.Updates))
+	for _, u := range in.Updates {
+		if u.GetEventCreateDTO() == nil {
+			return nil, storage.Invalid("empty event %s in batch", u.GetId())
+		}
+		updates = append(updates, storage.EventBatchUpdate{
+			ID:    u.Id,
+			Event: toEventUpdateDTO(u.EventCreateDTO, in.RejectOverlap, u.ExpectedVersion),
+		})
+	}
+
+	results, err := s.Storager.UpdateEvents(ctx, in.CalendarID, updates, batchModes[in.Mode], userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BatchResponse{
+		Results: toPBBatchResults(s.logg, pb.Storager_BatchUpdateEvents_FullMethodName, results),
+	}, nil
+}
+
+func (s *GRPCServer) BatchDeleteEvents(ctx context.Context,
+	in *pb.BatchDeleteEventsRequest,
+) (*pb.BatchResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.Storager.DeleteEventsByID(ctx, in.CalendarID, in.Ids, batchModes[in.Mode], userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BatchResponse{
+		Results: toPBBatchResults(s.logg, pb.Storager_BatchDeleteEvents_FullMethodName, results),
+	}, nil
+}
+
+// toPBBatchResults переводит ошибки событий пакета в коды и причины, как у статусов методов
+// (см. toStatus); внутренние ошибки пишутся в лог.
+func toPBBatchResults(logg *zap.Logger, method string, results []storage.BatchResult) []*pb.BatchResult {
+	res := make([]*pb.BatchResult, 0, len(results))
+	for _, r := range results {
+		item := &pb.BatchResult{Id: r.ID}
+		if r.Err != nil {
+			st := status.Convert(logStatus(logg, method, r.Err))
+			item.Code = int32(st.Code())
+			item.Error = st.Message()
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok {
+					item.Reason = info.Reason
+				}
+			}
+		}
+		res = append(res, item)
+	}
+	return res
+}
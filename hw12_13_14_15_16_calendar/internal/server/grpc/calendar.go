@@ -0,0 +1,160 @@
+package internalgrpc
+
+import (
+	"context"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *GRPCServer) CreateCalendar(ctx context.Context,
+	in *pb.CreateCalendarRequest,
+) (*pb.CreateCalendarResponse, error) {
+	var response pb.CreateCalendarResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateCalendarName(in.Name); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	id, err := s.Storager.CreateCalendar(ctx, in.Name, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Id = id
+
+	return &response, nil
+}
+
+func (s *GRPCServer) ListCalendars(ctx context.Context,
+	_ *pb.ListCalendarsRequest,
+) (*pb.ListCalendarsResponse, error) {
+	var response pb.ListCalendarsResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	calendars, err := s.Storager.GetCalendars(ctx, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	for _, c := range calendars {
+		response.Calendars = append(response.Calendars, &pb.Calendar{
+			Id:        c.ID,
+			Name:      c.Name,
+			OwnerID:   c.OwnerID,
+			Personal:  c.Personal,
+			CreatedAt: timestamppb.New(c.CreatedAt),
+			Role:      string(c.Role),
+		})
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) DeleteCalendar(ctx context.Context,
+	in *pb.DeleteCalendarRequest,
+) (*pb.DeleteCalendarResponse, error) {
+	var response pb.DeleteCalendarResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.DeleteCalendar(ctx, in.CalendarID, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) GetCalendarACL(ctx context.Context,
+	in *pb.GetCalendarACLRequest,
+) (*pb.GetCalendarACLResponse, error) {
+	var response pb.GetCalendarACLResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	acl, err := s.Storager.GetCalendarACL(ctx, in.CalendarID, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	for _, e := range acl {
+		response.Entries = append(response.Entries, &pb.CalendarACLEntry{UserID: e.UserID, Role: string(e.Role)})
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) SetCalendarACL(ctx context.Context,
+	in *pb.SetCalendarACLRequest,
+) (*pb.SetCalendarACLResponse, error) {
+	var response pb.SetCalendarACLResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	entry := storage.ACLEntry{
+		UserID: in.GetEntry().GetUserID(),
+		Role:   storage.Role(in.GetEntry().GetRole()),
+	}
+	if err := storage.ValidateGrant(entry); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.SetCalendarACL(ctx, in.CalendarID, entry, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
+
+func (s *GRPCServer) RevokeCalendarAccess(ctx context.Context,
+	in *pb.RevokeCalendarAccessRequest,
+) (*pb.RevokeCalendarAccessResponse, error) {
+	var response pb.RevokeCalendarAccessResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.RevokeCalendarAccess(ctx, in.CalendarID, in.UserID, userID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,46 @@
+package internalgrpc
+
+import (
+	"time"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// changesIdle - как часто поток ленты без изменений присылает токен.
+const changesIdle = 15 * time.Second
+
+// WatchChanges передаёт изменения событий пользователя из токена, пока клиент не отключится.
+func (s *GRPCServer) WatchChanges(in *pb.WatchChangesRequest,
+	stream grpc.ServerStreamingServer[pb.WatchChangesResponse],
+) error {
+	userID, err := requestUserID(stream.Context(), "")
+	if err != nil {
+		return err
+	}
+
+	return storage.FollowChanges(stream.Context(), s.Storager, userID, in.ResumeToken, changesIdle,
+		func(changes []storage.Change, token string) error {
+			return stream.Send(&pb.WatchChangesResponse{Changes: toPBChanges(changes), ResumeToken: token})
+		})
+}
+
+func toPBChanges(changes []storage.Change) []*pb.EventChange {
+	res := make([]*pb.EventChange, 0, len(changes))
+	for _, c := range changes {
+		change := &pb.EventChange{
+			ResumeToken: c.ID,
+			Kind:        string(c.Kind),
+			EventID:     c.EventID,
+			CalendarID:  c.CalendarID,
+			ChangedAt:   timestamppb.New(c.ChangedAt),
+		}
+		if c.Event != nil {
+			change.Event = toPBEvent(*c.Event)
+		}
+		res = append(res, change)
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package internalgrpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain - домен ErrorInfo в деталях статусов.
+const errorDomain = "calendar"
+
+// kindStatuses - код gRPC и причина ErrorInfo для каждого вида ошибки.
+var kindStatuses = map[error]struct {
+	code   codes.Code
+	reason string
+}{
+	storage.ErrNotFound:   {codes.NotFound, "NOT_FOUND"},
+	storage.ErrForbidden:  {codes.PermissionDenied, "FORBIDDEN"},
+	storage.ErrValidation: {codes.InvalidArgument, "VALIDATION"},
+	storage.ErrConflict:   {codes.AlreadyExists, "CONFLICT"},
+}
+
+// toStatus переводит ошибку в статус gRPC по её виду (storage.Kind) с деталями ErrorInfo.
+// Готовые статусы возвращаются как есть, текст внутренних ошибок клиенту не отдаётся.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	ks, ok := kindStatuses[storage.Kind(err)]
+	switch {
+	case errors.Is(err, storage.ErrStaleVersion):
+		// клиенту нужно перечитать событие и повторить изменение
+		ks.code, ks.reason = codes.Aborted, "STALE_VERSION"
+	case ok:
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		ks.code, ks.reason = codes.Unauthenticated, "UNAUTHENTICATED"
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	info := &errdetails.ErrorInfo{Reason: ks.reason, Domain: errorDomain}
+	// ошибка пакета в режиме ATOMIC сообщает, на каком событии пакет не прошёл
+	var itemErr *storage.BatchItemError
+	if errors.As(err, &itemErr) {
+		info.Metadata = map[string]string{"index": strconv.Itoa(itemErr.Index)}
+	}
+
+	st := status.New(ks.code, err.Error())
+	if detailed, derr := st.WithDetails(info); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// errorInterceptor отдаёт ошибки обработчиков статусами gRPC (см. toStatus),
+// а внутренние ошибки пишет в лог.
+func errorInterceptor(logg *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, logStatus(logg, info.FullMethod, err)
+	}
+}
+
+// errorStreamInterceptor - errorInterceptor для потоковых методов.
+func errorStreamInterceptor(logg *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		return logStatus(logg, info.FullMethod, err)
+	}
+}
+
+// logStatus переводит ошибку метода в статус и пишет внутренние ошибки в лог.
+func logStatus(logg *zap.Logger, method string, err error) error {
+	st := toStatus(err)
+	if status.Code(st) == codes.Internal {
+		logg.Error("error in handling request", zap.String("method", method), zap.Error(err))
+	}
+	return st
+}
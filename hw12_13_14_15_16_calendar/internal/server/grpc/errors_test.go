@@ -0,0 +1,84 @@
+package internalgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{fmt.Errorf("%w: 7", storage.ErrEventNotFound), codes.NotFound, "NOT_FOUND"},
+		{storage.ErrAccountNotFound, codes.NotFound, "NOT_FOUND"},
+		{fmt.Errorf("%w: owner role is required", storage.ErrForbidden), codes.PermissionDenied, "FORBIDDEN"},
+		{storage.ErrInvalidRRule, codes.InvalidArgument, "VALIDATION"},
+		{storage.Invalid("invalid interval"), codes.InvalidArgument, "VALIDATION"},
+		{storage.ErrOverlap, codes.AlreadyExists, "CONFLICT"},
+		{storage.ErrLoginTaken, codes.AlreadyExists, "CONFLICT"},
+		{storage.CheckVersion("7", 3, 2), codes.Aborted, "STALE_VERSION"},
+		{auth.ErrInvalidCredentials, codes.Unauthenticated, "UNAUTHENTICATED"},
+	} {
+		st := status.Convert(toStatus(tc.err))
+		require.Equal(t, tc.code, st.Code(), tc.err)
+		require.Equal(t, tc.err.Error(), st.Message())
+
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		require.Equal(t, tc.reason, info.Reason)
+		require.Equal(t, errorDomain, info.Domain)
+	}
+}
+
+func TestToStatusBatchItem(t *testing.T) {
+	st := status.Convert(toStatus(&storage.BatchItemError{Index: 3, Err: storage.ErrOverlap}))
+	require.Equal(t, codes.AlreadyExists, st.Code())
+
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, "CONFLICT", info.Reason)
+	require.Equal(t, map[string]string{"index": "3"}, info.Metadata)
+}
+
+func TestToStatusInternal(t *testing.T) {
+	st := status.Convert(toStatus(errors.New("pq: connection refused")))
+	require.Equal(t, codes.Internal, st.Code())
+	require.Equal(t, "internal error", st.Message())
+
+	require.Equal(t, codes.Canceled, status.Code(toStatus(fmt.Errorf("query: %w", context.Canceled))))
+
+	// готовые статусы не меняются
+	denied := status.Error(codes.PermissionDenied, "access to another user's events is denied")
+	require.Equal(t, denied, toStatus(denied))
+}
+
+func TestErrorInterceptor(t *testing.T) {
+	interceptor := errorInterceptor(zap.NewNop())
+	info := &grpc.UnaryServerInfo{FullMethod: "/Storager/GetEventByID"}
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, storage.ErrCalendarNotFound
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+}
@@ -6,40 +6,87 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"time"
 
 	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/configs"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/app"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// closeTimeout - сколько Close ждёт завершения вызовов.
+const closeTimeout = 2 * time.Second
+
 type GRPCServer struct {
 	pb.UnimplementedStoragerServer
 	grpcServer *grpc.Server
 	cfg        *configs.Config
 	logg       *zap.Logger
 	Storager   app.Storager
+	tokens     *auth.TokenManager
 }
 
-func NewGRPCServer(cfg *configs.Config, logg *zap.Logger, storager app.Storager) *GRPCServer {
-	server := grpc.NewServer()
-	return &GRPCServer{cfg: cfg, logg: logg, Storager: storager, grpcServer: server}
+func NewGRPCServer(cfg *configs.Config, logg *zap.Logger, storager app.Storager,
+	tokens *auth.TokenManager,
+) *GRPCServer {
+	verifier := auth.NewVerifier(tokens, storager)
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(requestIDInterceptor(), metricsInterceptor(), errorInterceptor(logg),
+			authInterceptor(verifier, logg)),
+		grpc.ChainStreamInterceptor(metricsStreamInterceptor(), errorStreamInterceptor(logg),
+			authStreamInterceptor(verifier, logg)),
+	)
+	return &GRPCServer{cfg: cfg, logg: logg, Storager: storager, grpcServer: server, tokens: tokens}
 }
 
 func (s *GRPCServer) AddEventByID(ctx context.Context, in *pb.AddEventByIDRequest) (*pb.AddEventByIDResponse, error) {
 	var response pb.AddEventByIDResponse
-	event := storage.EventCreateDTO{
-		Title:        in.EventCreateDTO.Title,
-		Start:        in.EventCreateDTO.Start.AsTime(),
-		End:          in.EventCreateDTO.End.AsTime(),
-		Description:  in.EventCreateDTO.Description,
-		Notification: in.EventCreateDTO.Notification.AsTime(),
+
+	userID, err := requestUserID(ctx, in.UserID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+	event := toEventCreateDTO(in.EventCreateDTO, in.RejectOverlap)
+
+	if err := storage.ValidateEventTime(event.Start, event.End); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateRRule(event.RRule); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateTimeZone(event.TimeZone); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateReminders(event.Reminders); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateAttendees(userID, event.Attendees); err != nil {
+		response.Error = err.Error()
+		return &response, err
 	}
 
-	res, err := s.Storager.AddEventByID(ctx, event, in.UserID)
+	var res string
+	if in.CalendarID != "" {
+		res, err = s.Storager.AddCalendarEvent(ctx, in.CalendarID, event, userID)
+	} else {
+		res, err = s.Storager.AddEventByID(ctx, event, userID)
+	}
 	if err != nil {
 		response.Error = err.Error()
 		return &response, err
@@ -54,19 +101,51 @@ func (s *GRPCServer) UpdateEventByID(ctx context.Context,
 	in *pb.UpdateEventByIDRequest,
 ) (*pb.UpdateEventByIDResponse, error) {
 	var response pb.UpdateEventByIDResponse
-	start := in.EventCreateDTO.Start.AsTime()
-	end := in.EventCreateDTO.End.AsTime()
-	notification := in.EventCreateDTO.Notification.AsTime()
 
-	event := storage.EventUpdateDTO{
-		Title:        &in.EventCreateDTO.Title,
-		Start:        &start,
-		End:          &end,
-		Description:  &in.EventCreateDTO.Description,
-		Notification: &notification,
+	userID, err := requestUserID(ctx, in.UserID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	event := toEventUpdateDTO(in.EventCreateDTO, in.RejectOverlap, in.ExpectedVersion)
+
+	if err := storage.ValidateEventTime(*event.Start, *event.End); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if err := storage.ValidateRRule(*event.RRule); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if event.TimeZone != nil {
+		if err := storage.ValidateTimeZone(*event.TimeZone); err != nil {
+			response.Error = err.Error()
+			return &response, err
+		}
+	}
+
+	if event.Reminders != nil {
+		if err := storage.ValidateReminders(*event.Reminders); err != nil {
+			response.Error = err.Error()
+			return &response, err
+		}
+	}
+
+	if event.Attendees != nil {
+		if err := storage.ValidateAttendees(userID, *event.Attendees); err != nil {
+			response.Error = err.Error()
+			return &response, err
+		}
 	}
 
-	err := s.Storager.UpdateEventByID(ctx, in.Id, event, in.UserID)
+	if in.CalendarID != "" {
+		err = s.Storager.UpdateCalendarEvent(ctx, in.CalendarID, in.Id, event, userID)
+	} else {
+		err = s.Storager.UpdateEventByID(ctx, in.Id, event, userID)
+	}
 	if err != nil {
 		response.Error = err.Error()
 		return &response, err
@@ -80,7 +159,17 @@ func (s *GRPCServer) DeleteEventByID(ctx context.Context,
 ) (*pb.DeleteEventByIDResponse, error) {
 	var response pb.DeleteEventByIDResponse
 
-	err := s.Storager.DeleteEventByID(ctx, in.Id)
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	if in.CalendarID != "" {
+		err = s.Storager.DeleteCalendarEvent(ctx, in.CalendarID, in.Id, userID)
+	} else {
+		err = s.Storager.DeleteEventByID(ctx, in.Id, userID)
+	}
 	if err != nil {
 		response.Error = err.Error()
 		return &response, err
@@ -89,50 +178,125 @@ func (s *GRPCServer) DeleteEventByID(ctx context.Context,
 	return &response, nil
 }
 
-func (s *GRPCServer) GetEventListingByUserID(_ context.Context,
+func (s *GRPCServer) GetEventListingByUserID(ctx context.Context,
 	in *pb.GetEventListingByUserIDRequest,
 ) (*pb.GetEventListingByUserIDResponse, error) {
 	var response pb.GetEventListingByUserIDResponse
 
-	events, err := s.Storager.GetEventListingByUserID(in.UserID, in.Date.AsTime(), in.Period.String())
+	userID, err := requestUserID(ctx, in.UserID)
 	if err != nil {
 		response.Error = err.Error()
 		return &response, err
 	}
 
-	resEvents := make([]*pb.Event, 0, len(events))
-	for _, e := range events {
-		resEvents = append(resEvents, &pb.Event{
-			Id:           e.ID,
-			Title:        e.Title,
-			Description:  e.Description,
-			CreatedAt:    timestamppb.New(e.CreatedAt),
-			Start:        timestamppb.New(e.Start),
-			End:          timestamppb.New(e.End),
-			Notification: timestamppb.New(e.Notification),
-		})
+	loc, err := app.UserLocation(ctx, s.Storager, userID, in.TimeZone)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
 	}
 
-	response.Event = resEvents
+	var events []storage.Event
+	if in.CalendarID != "" {
+		events, err = s.Storager.GetCalendarEventListing(ctx, in.CalendarID, in.Date.AsTime().In(loc),
+			in.Period.String(), userID)
+	} else {
+		events, err = s.Storager.GetEventListingByUserID(userID, in.Date.AsTime().In(loc), in.Period.String())
+	}
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Event = toPBEvents(events)
 
 	return &response, nil
 }
 
-func (s *GRPCServer) GetEventByID(_ context.Context, in *pb.GetEventByIDRequest) (*pb.GetEventByIDResponse, error) {
+func (s *GRPCServer) GetEventByID(ctx context.Context, in *pb.GetEventByIDRequest) (*pb.GetEventByIDResponse, error) {
 	var response pb.GetEventByIDResponse
 
-	e, err := s.Storager.GetEventByID(in.Id, in.UserID)
+	userID, err := requestUserID(ctx, in.UserID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	var e storage.Event
+	if in.CalendarID != "" {
+		e, err = s.Storager.GetCalendarEvent(ctx, in.CalendarID, in.Id, userID)
+	} else {
+		e, err = s.Storager.GetEventByID(in.Id, userID)
+	}
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+	response.Event = toPBEvent(e)
+
+	return &response, nil
+}
+
+func (s *GRPCServer) SearchEvents(ctx context.Context, in *pb.SearchEventsRequest) (*pb.SearchEventsResponse, error) {
+	var response pb.SearchEventsResponse
+
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	q := storage.SearchQuery{
+		CalendarID: in.CalendarID,
+		Text:       in.Query,
+		Notified:   in.Notified,
+		Sort:       in.Sort,
+		Limit:      int(in.Limit),
+		Cursor:     in.Cursor,
+	}
+	if in.From != nil {
+		q.From = in.From.AsTime()
+	}
+	if in.To != nil {
+		q.To = in.To.AsTime()
+	}
+	if q.Location, err = app.UserLocation(ctx, s.Storager, userID, in.TimeZone); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	res, err := s.Storager.SearchEvents(ctx, userID, q)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Events = toPBEvents(res.Events)
+	response.NextCursor = res.NextCursor
+
+	return &response, nil
+}
+
+func (s *GRPCServer) RespondToEvent(ctx context.Context,
+	in *pb.RespondToEventRequest,
+) (*pb.RespondToEventResponse, error) {
+	var response pb.RespondToEventResponse
+
+	userID, err := requestUserID(ctx, "")
 	if err != nil {
 		response.Error = err.Error()
 		return &response, err
 	}
-	response.Event = &pb.Event{
-		Id:          e.ID,
-		Title:       e.Title,
-		Description: e.Description,
-		Start:       timestamppb.New(e.Start),
-		End:         timestamppb.New(e.End),
-		CreatedAt:   timestamppb.New(e.CreatedAt),
+
+	rsvp := storage.AttendeeStatus(in.Status)
+	if err := storage.ValidateRSVP(rsvp); err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	err = s.Storager.RespondToEvent(ctx, in.Id, userID, rsvp)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
 	}
 
 	return &response, nil
@@ -144,6 +308,194 @@ func (s *GRPCServer) Notify(_ context.Context, _ *pb.NotifyRequest) (*pb.NotifyR
 	return response, nil
 }
 
+func (s *GRPCServer) GetFreeBusy(ctx context.Context, in *pb.GetFreeBusyRequest) (*pb.GetFreeBusyResponse, error) {
+	var response pb.GetFreeBusyResponse
+
+	userID, err := requestUserID(ctx, in.UserID)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	from, to := in.From.AsTime(), in.To.AsTime()
+	if !to.After(from) {
+		err := storage.Invalid("invalid interval: %s - %s", from, to)
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	loc, err := app.UserLocation(ctx, s.Storager, userID, in.TimeZone)
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	fb, err := s.Storager.GetFreeBusy(ctx, userID, from.In(loc), to.In(loc), in.MinFree.AsDuration())
+	if err != nil {
+		response.Error = err.Error()
+		return &response, err
+	}
+
+	response.Busy = toIntervals(fb.Busy)
+	response.Free = toIntervals(fb.Free)
+
+	return &response, nil
+}
+
+func toIntervals(intervals []storage.Interval) []*pb.Interval {
+	res := make([]*pb.Interval, 0, len(intervals))
+	for _, i := range intervals {
+		res = append(res, &pb.Interval{Start: timestamppb.New(i.Start), End: timestamppb.New(i.End)})
+	}
+	return res
+}
+
+func toEventCreateDTO(dto *pb.EventCreateDTO, rejectOverlap bool) storage.EventCreateDTO {
+	return storage.EventCreateDTO{
+		Title:         dto.Title,
+		Start:         dto.Start.AsTime(),
+		End:           dto.End.AsTime(),
+		Description:   dto.Description,
+		Notification:  dto.Notification.AsTime(),
+		RRule:         dto.Rrule,
+		ExDates:       toTimes(dto.ExDates),
+		Reminders:     toReminders(dto),
+		Attendees:     toAttendeeIDs(dto),
+		TimeZone:      dto.TimeZone,
+		RejectOverlap: rejectOverlap,
+	}
+}
+
+// toEventUpdateDTO - изменение события по EventCreateDTO: время, тексты, правило повторения
+// и исключения заменяются всегда, напоминания и приглашённые - если заданы.
+func toEventUpdateDTO(dto *pb.EventCreateDTO, rejectOverlap bool, version int64) storage.EventUpdateDTO {
+	start := dto.Start.AsTime()
+	end := dto.End.AsTime()
+	notification := dto.Notification.AsTime()
+	exDates := toTimes(dto.ExDates)
+
+	event := storage.EventUpdateDTO{
+		Title:         &dto.Title,
+		Start:         &start,
+		End:           &end,
+		Description:   &dto.Description,
+		Notification:  &notification,
+		RRule:         &dto.Rrule,
+		ExDates:       &exDates,
+		RejectOverlap: rejectOverlap,
+		Version:       version,
+	}
+	if reminders := toReminders(dto); reminders != nil {
+		event.Reminders = &reminders
+	}
+	if attendees := toAttendeeIDs(dto); attendees != nil {
+		event.Attendees = &attendees
+	}
+	if dto.TimeZone != "" {
+		event.TimeZone = &dto.TimeZone
+	}
+	return event
+}
+
+func toPBEvent(e storage.Event) *pb.Event {
+	event := &pb.Event{
+		Id:           e.ID,
+		Title:        e.Title,
+		Description:  e.Description,
+		UserID:       e.UserID,
+		CalendarID:   e.CalendarID,
+		CreatedAt:    timestamppb.New(e.CreatedAt),
+		Start:        timestamppb.New(e.Start),
+		End:          timestamppb.New(e.End),
+		Notification: timestamppb.New(e.Notification),
+		Notified:     e.Notified,
+		Rrule:        e.RRule,
+		TimeZone:     e.TimeZone,
+		ExDates:      toTimestamps(e.ExDates),
+		Reminders:    toDurations(e.Reminders),
+		Attendees:    toPBAttendees(e.Attendees),
+		Responses: &pb.ResponseCounts{
+			Pending:   int32(e.Responses.Pending),
+			Accepted:  int32(e.Responses.Accepted),
+			Declined:  int32(e.Responses.Declined),
+			Tentative: int32(e.Responses.Tentative),
+		},
+		Version: e.Version,
+	}
+	if e.DeletedAt != nil {
+		event.DeletedAt = timestamppb.New(*e.DeletedAt)
+	}
+	return event
+}
+
+func toPBAttendees(attendees []storage.Attendee) []*pb.Attendee {
+	res := make([]*pb.Attendee, 0, len(attendees))
+	for _, a := range attendees {
+		res = append(res, &pb.Attendee{UserID: a.UserID, Status: string(a.Status)})
+	}
+	return res
+}
+
+// toAttendeeIDs возвращает приглашённых из запроса: nil, если они не заданы,
+// и пустой срез, если всех приглашённых нужно убрать.
+func toAttendeeIDs(dto *pb.EventCreateDTO) []string {
+	if dto.NoAttendees {
+		return []string{}
+	}
+	if len(dto.Attendees) == 0 {
+		return nil
+	}
+	return dto.Attendees
+}
+
+func toPBEvents(events []storage.Event) []*pb.Event {
+	res := make([]*pb.Event, 0, len(events))
+	for _, e := range events {
+		res = append(res, toPBEvent(e))
+	}
+	return res
+}
+
+func toTimes(ts []*timestamppb.Timestamp) []time.Time {
+	res := make([]time.Time, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, t.AsTime())
+	}
+	return res
+}
+
+func toTimestamps(ts []time.Time) []*timestamppb.Timestamp {
+	res := make([]*timestamppb.Timestamp, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, timestamppb.New(t))
+	}
+	return res
+}
+
+// toReminders возвращает напоминания из запроса: nil, если они не заданы,
+// и пустой срез, если напоминания отключены.
+func toReminders(dto *pb.EventCreateDTO) []storage.Offset {
+	if dto.NoReminders {
+		return []storage.Offset{}
+	}
+	if len(dto.Reminders) == 0 {
+		return nil
+	}
+	res := make([]storage.Offset, 0, len(dto.Reminders))
+	for _, d := range dto.Reminders {
+		res = append(res, storage.Offset(d.AsDuration()))
+	}
+	return res
+}
+
+func toDurations(reminders []storage.Offset) []*durationpb.Duration {
+	res := make([]*durationpb.Duration, 0, len(reminders))
+	for _, r := range reminders {
+		res = append(res, durationpb.New(time.Duration(r)))
+	}
+	return res
+}
+
 func (s *GRPCServer) Start(ctx context.Context, logg *zap.Logger) error { // port string storager app.Storager,
 	// определяем порт для сервера
 	_, port, err := net.SplitHostPort(s.cfg.GRPCAddress)
@@ -162,7 +514,7 @@ func (s *GRPCServer) Start(ctx context.Context, logg *zap.Logger) error { // por
 	}
 
 	// регистрируем сервис
-	pb.RegisterStoragerServer(s.grpcServer, &GRPCServer{Storager: s.Storager})
+	pb.RegisterStoragerServer(s.grpcServer, s)
 	logg.Info("start grpc server success ", zap.Any("endpoint", grpcListen.Addr()))
 
 	// получаем запрос gRPC
@@ -174,7 +526,19 @@ func (s *GRPCServer) Start(ctx context.Context, logg *zap.Logger) error { // por
 	return nil
 }
 
+// Close дожидается завершения вызовов не дольше closeTimeout: потоки ленты изменений
+// сами не заканчиваются, поэтому после этого соединения закрываются принудительно.
 func (s *GRPCServer) Close() error {
-	s.grpcServer.GracefulStop()
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(closeTimeout):
+		s.grpcServer.Stop()
+	}
 	return nil
 }
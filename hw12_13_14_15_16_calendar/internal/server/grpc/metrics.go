@@ -0,0 +1,40 @@
+package internalgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsInterceptor считает запросы и их время по методу и коду ответа. Стоит перед
+// errorInterceptor, поэтому видит уже переведённые в статусы ошибки.
+func metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// metricsStreamInterceptor - metricsInterceptor для потоковых методов: время - от открытия
+// до закрытия потока.
+func metricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observe(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	metrics.GRPCRequests.WithLabelValues(method, code).Inc()
+	metrics.GRPCDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,29 @@
+package internalgrpc
+
+import (
+	"context"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDInterceptor берёт ID запроса из метаданных x-request-id (или создаёт новый),
+// возвращает его в заголовках ответа и кладёт в контекст для журнала аудита.
+// Потоковые методы только читают события, поэтому ID запроса им не нужен.
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		id = requestid.Ensure(id)
+		// заголовок не отправится, только если клиент уже отключился
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+		return handler(requestid.WithID(ctx, id), req)
+	}
+}
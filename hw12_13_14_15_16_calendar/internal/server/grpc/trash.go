@@ -0,0 +1,32 @@
+package internalgrpc
+
+import (
+	"context"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+)
+
+func (s *GRPCServer) GetTrash(ctx context.Context, in *pb.GetTrashRequest) (*pb.GetTrashResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.Storager.GetTrash(ctx, in.CalendarID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTrashResponse{Events: toPBEvents(events)}, nil
+}
+
+func (s *GRPCServer) RestoreEvent(ctx context.Context, in *pb.RestoreEventRequest) (*pb.RestoreEventResponse, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Storager.RestoreEvent(ctx, in.CalendarID, in.Id, userID); err != nil {
+		return nil, err
+	}
+	return &pb.RestoreEventResponse{}, nil
+}
@@ -0,0 +1,224 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// Register создаёт аккаунт. Доступен без токена.
+func (eh *EventHandlers) Register(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var account storage.AccountCreateDTO
+	if err := json.NewDecoder(r.Body).Decode(&account); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := validator.New().Struct(account); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	hash, err := auth.HashPassword(account.Password)
+	if err != nil {
+		eh.Logg.Error("error in hashing password:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	createdID, err := eh.Storager.CreateAccount(r.Context(), account.Login, hash)
+	if err != nil {
+		eh.Logg.Error("error in creating account:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{ID: createdID})
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// GetAccount отдаёт профиль аутентифицированного пользователя.
+func (eh *EventHandlers) GetAccount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	account, err := eh.Storager.GetAccountByID(r.Context(), userID)
+	if err != nil {
+		eh.Logg.Error("error in getting account:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(account)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// ChangePassword меняет пароль; требует текущий пароль, даже при валидном токене.
+// Все выпущенные раньше токены, включая текущий, отзываются - нужно войти заново.
+func (eh *EventHandlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var change storage.PasswordChangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := validator.New().Struct(change); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	account, err := eh.Storager.GetAccountByID(r.Context(), userID)
+	if err != nil {
+		eh.Logg.Error("error in getting account:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	if err := auth.CheckPassword(account.PasswordHash, change.OldPassword); err != nil {
+		eh.writeError(w, r, fmt.Errorf("%w: wrong current password", storage.ErrForbidden))
+		return
+	}
+
+	hash, err := auth.HashPassword(change.NewPassword)
+	if err != nil {
+		eh.Logg.Error("error in hashing password:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	err = eh.Storager.UpdateAccountPassword(r.Context(), userID, hash)
+	if err != nil {
+		eh.Logg.Error("error in updating password:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteAccount удаляет аккаунт пользователя вместе со всеми его событиями.
+func (eh *EventHandlers) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	err := eh.Storager.DeleteAccountByID(r.Context(), userID)
+	if err != nil {
+		eh.Logg.Error("error in deleting account:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetChannels задаёт каналы доставки напоминаний: JSON-массив [{"type": "email", "target": "..."}].
+// Пустой массив возвращает каналы по умолчанию.
+func (eh *EventHandlers) SetChannels(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var channels []storage.NotificationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channels); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := storage.ValidateChannels(channels); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	err := eh.Storager.SetAccountChannels(r.Context(), userID, channels)
+	if err != nil {
+		eh.Logg.Error("error in setting channels:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetTimeZone задаёт зону аккаунта: {"timeZone": "Europe/Moscow"}.
+// В ней считаются дни, недели и месяцы списка событий.
+func (eh *EventHandlers) SetTimeZone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var dto storage.TimeZoneDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if _, err := storage.LoadLocation(dto.TimeZone); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	err := eh.Storager.SetAccountTimeZone(r.Context(), userID, dto.TimeZone)
+	if err != nil {
+		eh.Logg.Error("error in setting time zone:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
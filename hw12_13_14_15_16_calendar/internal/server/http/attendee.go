@@ -0,0 +1,43 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// RespondToEvent записывает ответ приглашённого на приглашение: {"status": "accepted"}.
+// Допустимы accepted, declined и tentative.
+func (eh *EventHandlers) RespondToEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+	eventID := r.PathValue("id")
+
+	var dto storage.RSVPDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := storage.ValidateRSVP(dto.Status); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	err := eh.Storager.RespondToEvent(r.Context(), eventID, userID, dto.Status)
+	if err != nil {
+		eh.Logg.Error("error in responding to invitation:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,192 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// Адреса /calendars/{calendarid}/... обслуживаются теми же обработчиками событий, что и
+// /user/{userid}/...: с calendarid они работают с этим календарём (по правам из ACL),
+// без него - с личным календарём пользователя.
+
+// GetCalendars отдаёт календари, к которым у пользователя есть доступ, с его ролью в каждом.
+func (eh *EventHandlers) GetCalendars(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	calendars, err := eh.Storager.GetCalendars(r.Context(), userID)
+	if err != nil {
+		eh.Logg.Error("error in getting calendars:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(calendars)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// CreateCalendar создаёт общий календарь: {"name": "on-call"}. Создатель - его владелец.
+func (eh *EventHandlers) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var dto storage.CalendarCreateDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := storage.ValidateCalendarName(dto.Name); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	createdID, err := eh.Storager.CreateCalendar(r.Context(), dto.Name, userID)
+	if err != nil {
+		eh.Logg.Error("error in creating calendar:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(struct {
+		ID string `json:"id"`
+	}{ID: createdID})
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// DeleteCalendar удаляет календарь вместе с событиями; доступно только владельцу.
+func (eh *EventHandlers) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	err := eh.Storager.DeleteCalendar(r.Context(), r.PathValue("calendarid"), userID)
+	if err != nil {
+		eh.Logg.Error("error in deleting calendar:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCalendarACL отдаёт права на календарь всем, у кого есть к нему доступ.
+func (eh *EventHandlers) GetCalendarACL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	acl, err := eh.Storager.GetCalendarACL(r.Context(), r.PathValue("calendarid"), userID)
+	if err != nil {
+		eh.Logg.Error("error in getting calendar acl:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(acl)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// SetCalendarACL выдаёт или меняет права аккаунта: {"userId": "2", "role": "editor"}.
+// Роли - editor и viewer; несуществующий аккаунт - 404. Доступно только владельцу.
+func (eh *EventHandlers) SetCalendarACL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var entry storage.ACLEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	if err := storage.ValidateGrant(entry); err != nil {
+		eh.Logg.Error("error in validating:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	err := eh.Storager.SetCalendarACL(r.Context(), r.PathValue("calendarid"), entry, userID)
+	if err != nil {
+		eh.Logg.Error("error in setting calendar acl:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeCalendarAccess отзывает права аккаунта granteeid. Доступно только владельцу.
+func (eh *EventHandlers) RevokeCalendarAccess(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	err := eh.Storager.RevokeCalendarAccess(r.Context(), r.PathValue("calendarid"), r.PathValue("granteeid"), userID)
+	if err != nil {
+		eh.Logg.Error("error in revoking calendar access:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
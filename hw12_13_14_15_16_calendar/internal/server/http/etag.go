@@ -0,0 +1,82 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// errPreconditionFailed - событие изменили после версии из заголовка If-Match.
+var errPreconditionFailed = errors.New("precondition failed")
+
+// eventETag - ETag события: его версия в кавычках.
+func eventETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch - разобранный заголовок If-Match: "*" или список ETag.
+type ifMatch struct {
+	header string
+	any    bool    // заголовка нет или "*" (событие есть в любой версии): версия не проверяется
+	tags   []int64 // версии из сильных ETag
+}
+
+// parseIfMatch разбирает заголовок If-Match (RFC 9110, 13.1.1): "*" или ETag через запятую.
+// ETag сравниваются сильно: слабые W/"..." и ETag, которые не являются версией события,
+// не совпадают ни с какой версией.
+func parseIfMatch(header string) (ifMatch, error) {
+	header = strings.TrimSpace(header)
+	m := ifMatch{header: header}
+	if header == "" || header == "*" {
+		m.any = true
+		return m, nil
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		unquoted, ok := strings.CutPrefix(strings.TrimPrefix(tag, "W/"), `"`)
+		if ok {
+			unquoted, ok = strings.CutSuffix(unquoted, `"`)
+		}
+		if !ok || strings.Contains(unquoted, `"`) {
+			return ifMatch{}, storage.Invalid("invalid If-Match %s: expected ETag of the event", header)
+		}
+		if version, err := strconv.ParseInt(unquoted, 10, 64); err == nil && version > 0 && !weak {
+			m.tags = append(m.tags, version)
+		}
+	}
+	return m, nil
+}
+
+// expectedVersion возвращает версию, с которой должно совпасть событие при изменении:
+// 0 - без проверки. Если в списке несколько версий, выбирается текущая версия события
+// из current. Если ни одна версия не подходит, возвращает errPreconditionFailed.
+func (m ifMatch) expectedVersion(current func() (int64, error)) (int64, error) {
+	switch {
+	case m.any:
+		return 0, nil
+	case len(m.tags) == 1:
+		return m.tags[0], nil
+	case len(m.tags) > 1:
+		version, err := current()
+		if err != nil {
+			return 0, err
+		}
+		if slices.Contains(m.tags, version) {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: If-Match %s does not match the event", errPreconditionFailed, m.header)
+}
+
+// preconditionFailed отдаёт устаревшую версию из If-Match как 412, а не конфликт.
+func preconditionFailed(err error) error {
+	if errors.Is(err, storage.ErrStaleVersion) {
+		return fmt.Errorf("%w: %w", errPreconditionFailed, err)
+	}
+	return err
+}
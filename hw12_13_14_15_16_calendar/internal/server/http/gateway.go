@@ -0,0 +1,258 @@
+package internalhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	httpmetrics "github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/metrics"
+	httptracing "github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/tracing"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/problem"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// codeKinds - вид ошибки для кодов gRPC, которыми сервис отвечает на ошибки (см. internalgrpc.toStatus).
+var codeKinds = map[codes.Code]error{
+	codes.NotFound:         storage.ErrNotFound,
+	codes.PermissionDenied: storage.ErrForbidden,
+	codes.InvalidArgument:  storage.ErrValidation,
+	codes.AlreadyExists:    storage.ErrConflict,
+	codes.Aborted:          storage.ErrConflict,
+}
+
+// dialGRPC открывает соединение шлюза с gRPC-сервером календаря по адресу target
+// (configs.Config.GatewayUpstreamAddress). Контекст трассировки запроса передаётся серверу в метаданных.
+func dialGRPC(target string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+}
+
+// NewGateway возвращает REST-шлюз /v1/..., который переводит запросы в вызовы gRPC-сервиса по conn.
+// Заголовок Authorization передаётся в метаданных, поэтому аутентификацию выполняет gRPC-сервер.
+// Версия события отдаётся заголовком ETag и проверяется по If-Match.
+// ID запроса из заголовка X-Request-ID передаётся в метаданных x-request-id.
+// Лента изменений отдаётся как Server-Sent Events; при отмене ctx её потоки закрываются.
+func NewGateway(ctx context.Context, conn *grpc.ClientConn, logg *zap.Logger) (http.Handler, error) {
+	client := gatewayClient{pb.NewStoragerClient(conn)}
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(gatewayErrorHandler(logg)),
+		runtime.WithForwardResponseOption(setETag),
+		runtime.WithMetadata(forwardRequestID),
+		runtime.WithMiddlewares(routeLabel),
+	)
+	if err := pb.RegisterStoragerHandlerClient(ctx, mux, client); err != nil {
+		return nil, err
+	}
+	// добавленный позже маршрут проверяется раньше /v1/events/{id}
+	err := mux.HandlePath(http.MethodGet, "/v1/events/changes", watchChanges(ctx, client, logg))
+	if err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// gatewayClient - клиент gRPC-сервиса для шлюза: переносит версию из заголовка If-Match
+// в поле expectedVersion запроса UpdateEventByID.
+type gatewayClient struct {
+	pb.StoragerClient
+}
+
+func (c gatewayClient) UpdateEventByID(ctx context.Context, in *pb.UpdateEventByIDRequest,
+	opts ...grpc.CallOption,
+) (*pb.UpdateEventByIDResponse, error) {
+	// шлюз передаёт If-Match в метаданных grpcgateway-if-match
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(runtime.MetadataPrefix + "if-match"); len(values) > 0 {
+		match, err := parseIfMatch(strings.Join(values, ","))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		version, err := match.expectedVersion(func() (int64, error) {
+			resp, err := c.StoragerClient.GetEventByID(ctx, &pb.GetEventByIDRequest{
+				Id: in.GetId(), UserID: in.GetUserID(), CalendarID: in.GetCalendarID(),
+			}, opts...)
+			return resp.GetEvent().GetVersion(), err
+		})
+		// несовпадение If-Match gatewayErrorHandler отдаёт как 412
+		if errors.Is(err, errPreconditionFailed) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if version != 0 {
+			in.ExpectedVersion = version
+		}
+	}
+	return c.StoragerClient.UpdateEventByID(ctx, in, opts...)
+}
+
+// forwardRequestID передаёт gRPC-серверу ID запроса, чтобы он попал в журнал аудита.
+func forwardRequestID(_ context.Context, r *http.Request) metadata.MD {
+	if id := r.Header.Get(requestid.Header); id != "" {
+		return metadata.Pairs(requestid.MetadataKey, id)
+	}
+	return nil
+}
+
+// routeLabel отдаёт шаблон пути шлюза, например /v1/events/{id=*}, в метку route метрик HTTP
+// и в имя спана запроса.
+func routeLabel(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			httpmetrics.SetRoute(r.Context(), pattern.String())
+			httptracing.SetRoute(r.Context(), pattern.String())
+		}
+		next(w, r, pathParams)
+	}
+}
+
+// setETag отдаёт версию прочитанного события заголовком ETag.
+func setETag(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if r, ok := resp.(*pb.GetEventByIDResponse); ok && r.Event != nil {
+		w.Header().Set("ETag", eventETag(r.Event.Version))
+	}
+	return nil
+}
+
+// watchChanges отдаёт ленту изменений WatchChanges как Server-Sent Events: изменение - событие
+// с id (токеном продолжения), типом kind и EventChange в JSON. Ответы без изменений передаются
+// строкой id без данных: клиент запоминает токен, а соединение не простаивает.
+// Продолжить ленту можно с заголовка Last-Event-ID или параметра resumeToken.
+func watchChanges(streamsCtx context.Context, client pb.StoragerClient, logg *zap.Logger) runtime.HandlerFunc {
+	writeError := gatewayErrorHandler(logg)
+
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		defer context.AfterFunc(streamsCtx, cancel)()
+
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+		}
+		token := r.URL.Query().Get("resumeToken")
+		if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+			token = lastID
+		}
+
+		stream, err := client.WatchChanges(ctx, &pb.WatchChangesRequest{ResumeToken: token})
+		var resp *pb.WatchChangesResponse
+		if err == nil {
+			// ошибки до первого ответа (токен, права) отдаются как у остальных методов
+			resp, err = stream.Recv()
+		}
+		if err != nil {
+			writeError(ctx, nil, nil, w, r, err)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		// поток живёт дольше WriteTimeout сервера
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			logg.Error("error in disabling write deadline", zap.Error(err))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		for {
+			if err := writeChanges(w, resp); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			if resp, err = stream.Recv(); err != nil {
+				if ctx.Err() == nil {
+					logg.Error("error in receiving event changes", zap.Error(err))
+				}
+				return
+			}
+		}
+	}
+}
+
+// writeChanges пишет ответ WatchChanges событиями SSE.
+func writeChanges(w io.Writer, resp *pb.WatchChangesResponse) error {
+	if len(resp.Changes) == 0 {
+		_, err := fmt.Fprintf(w, "id: %s\n\n", resp.ResumeToken)
+		return err
+	}
+	for _, c := range resp.Changes {
+		data, err := protojson.Marshal(c)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", c.ResumeToken, c.Kind, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gatewayErrorHandler отвечает на ошибки шлюза телом application/problem+json,
+// как и обработчики chi: код ответа и type берутся по коду статуса gRPC.
+func gatewayErrorHandler(logg *zap.Logger) runtime.ErrorHandlerFunc {
+	return func(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler,
+		w http.ResponseWriter, r *http.Request, err error,
+	) {
+		st := status.Convert(err)
+		code := runtime.HTTPStatusFromCode(st.Code())
+		var httpErr *runtime.HTTPStatusError
+		if errors.As(err, &httpErr) {
+			code = httpErr.HTTPStatus
+		}
+		problemType := problemTypes[codeKinds[st.Code()]]
+		// устаревшая версия из If-Match - 412, из поля expectedVersion - конфликт
+		if st.Code() == codes.Aborted && r.Header.Get("If-Match") != "" {
+			code = http.StatusPreconditionFailed
+			problemType = problemTypes[errPreconditionFailed]
+		}
+
+		p := problem.Details{
+			Type:     problemType,
+			Status:   code,
+			Instance: r.URL.Path,
+		}
+		if code != http.StatusInternalServerError {
+			p.Detail = st.Message()
+		}
+
+		if err := problem.Write(w, p); err != nil {
+			logg.Error("error in writing resp:", zap.Error(err))
+		}
+	}
+}
+
+// openAPISpec отдаёт спецификацию REST-шлюза.
+func openAPISpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pb.OpenAPISpec)
+}
+
+// deprecated помечает маршруты chi, которые заменены REST-шлюзом /v1/ и оставлены,
+// пока клиенты не перейдут на него.
+func deprecated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", `</openapi.json>; rel="successor-version"`)
+		h(w, r)
+	}
+}
@@ -0,0 +1,367 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/adettelle/hw/hw12_13_14_15_calendar/api"
+	"github.com/c2fo/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// gatewayStorager - gRPC-сервис для проверки шлюза: отдаёт событие "1" версии 3,
+// остальные не находит, и запоминает заголовок authorization.
+type gatewayStorager struct {
+	pb.UnimplementedStoragerServer
+	authorization []string
+}
+
+func (s *gatewayStorager) GetEventByID(ctx context.Context, in *pb.GetEventByIDRequest) (*pb.GetEventByIDResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = md.Get("authorization")
+
+	if in.Id != "1" {
+		return nil, status.Error(codes.NotFound, "event not found")
+	}
+	return &pb.GetEventByIDResponse{
+		Event: &pb.Event{Id: in.Id, Title: "title", CalendarID: in.CalendarID, Version: 3},
+	}, nil
+}
+
+func (s *gatewayStorager) UpdateEventByID(_ context.Context, in *pb.UpdateEventByIDRequest,
+) (*pb.UpdateEventByIDResponse, error) {
+	if in.ExpectedVersion != 0 && in.ExpectedVersion != 3 {
+		return nil, status.Error(codes.Aborted, "event has been modified")
+	}
+	return &pb.UpdateEventByIDResponse{}, nil
+}
+
+// BatchDeleteEvents удаляет событие "1", остальные не находит.
+func (s *gatewayStorager) BatchDeleteEvents(_ context.Context, in *pb.BatchDeleteEventsRequest,
+) (*pb.BatchResponse, error) {
+	resp := &pb.BatchResponse{}
+	for _, id := range in.Ids {
+		r := &pb.BatchResult{Id: id}
+		if id != "1" {
+			r.Code, r.Reason, r.Error = int32(codes.NotFound), "NOT_FOUND", "event not found"
+		}
+		resp.Results = append(resp.Results, r)
+	}
+	return resp, nil
+}
+
+// GetTrash отдаёт корзину с событием "2"; RestoreEvent восстанавливает только его.
+func (s *gatewayStorager) GetTrash(_ context.Context, in *pb.GetTrashRequest) (*pb.GetTrashResponse, error) {
+	return &pb.GetTrashResponse{
+		Events: []*pb.Event{{Id: "2", CalendarID: in.CalendarID, DeletedAt: timestamppb.Now()}},
+	}, nil
+}
+
+func (s *gatewayStorager) RestoreEvent(_ context.Context, in *pb.RestoreEventRequest,
+) (*pb.RestoreEventResponse, error) {
+	if in.Id != "2" {
+		return nil, status.Error(codes.NotFound, "event not found")
+	}
+	return &pb.RestoreEventResponse{}, nil
+}
+
+// GetEventHistory отдаёт историю события "1" из одной записи с ID запроса из метаданных.
+func (s *gatewayStorager) GetEventHistory(ctx context.Context, in *pb.GetEventHistoryRequest,
+) (*pb.GetEventHistoryResponse, error) {
+	if in.Id != "1" {
+		return nil, status.Error(codes.NotFound, "event not found")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	entry := &pb.AuditEntry{Id: "1", EventID: in.Id, Operation: "create", After: &pb.Event{Id: in.Id}}
+	if values := md.Get("x-request-id"); len(values) > 0 {
+		entry.RequestID = values[0]
+	}
+	return &pb.GetEventHistoryResponse{Entries: []*pb.AuditEntry{entry}}, nil
+}
+
+// WatchChanges отдаёт токен, одно изменение события "1" и закрывает ленту.
+// Токен "abc" считается неверным.
+func (s *gatewayStorager) WatchChanges(in *pb.WatchChangesRequest, stream pb.Storager_WatchChangesServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	s.authorization = md.Get("authorization")
+
+	if in.ResumeToken == "abc" {
+		return status.Error(codes.InvalidArgument, "invalid resume token")
+	}
+	if err := stream.Send(&pb.WatchChangesResponse{ResumeToken: in.ResumeToken}); err != nil {
+		return err
+	}
+	return stream.Send(&pb.WatchChangesResponse{
+		Changes:     []*pb.EventChange{{Kind: "updated", EventID: "1", ResumeToken: "8"}},
+		ResumeToken: "8",
+	})
+}
+
+// newTestGateway запускает gatewayStorager в памяти и возвращает роутер со шлюзом к нему.
+func newTestGateway(t *testing.T) (http.Handler, *gatewayStorager) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	storager := &gatewayStorager{}
+	pb.RegisterStoragerServer(srv, storager)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	gateway, err := NewGateway(context.Background(), conn, zap.NewNop())
+	require.NoError(t, err)
+	return NewRouter(New(nil, zap.NewNop(), nil), gateway, zap.NewNop()), storager
+}
+
+func TestGatewayGetEvent(t *testing.T) {
+	router, storager := newTestGateway(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/calendars/c1/events/1", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, []string{"Bearer token"}, storager.authorization)
+	require.Equal(t, `"3"`, response.Header().Get("ETag"))
+
+	var resp struct {
+		Event struct {
+			ID         string `json:"id"`
+			Title      string `json:"title"`
+			CalendarID string `json:"calendarID"`
+		} `json:"event"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&resp))
+	require.Equal(t, "1", resp.Event.ID)
+	require.Equal(t, "title", resp.Event.Title)
+	require.Equal(t, "c1", resp.Event.CalendarID)
+}
+
+func TestGatewayErrorProblem(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/events/2", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusNotFound, response.Code)
+	p := decodeProblem(t, response)
+	require.Equal(t, "urn:calendar:problem:not-found", p.Type)
+	require.Equal(t, "event not found", p.Detail)
+	require.Equal(t, "/v1/events/2", p.Instance)
+
+	// метод, который сервис не реализует, - 501 без текста ошибки в ответе
+	request = httptest.NewRequest(http.MethodGet, "/v1/calendars", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusNotImplemented, response.Code)
+	require.Equal(t, "about:blank", decodeProblem(t, response).Type)
+}
+
+func TestGatewayUpdateEventIfMatch(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	update := func(ifMatch string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPut, "/v1/events/1", strings.NewReader(body))
+		if ifMatch != "" {
+			request.Header.Set("If-Match", ifMatch)
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	require.Equal(t, http.StatusOK, update(`"3"`, `{}`).Code)
+
+	response := update(`"2"`, `{}`)
+	require.Equal(t, http.StatusPreconditionFailed, response.Code)
+	require.Equal(t, "urn:calendar:problem:precondition-failed", decodeProblem(t, response).Type)
+
+	// устаревшая версия в поле expectedVersion - конфликт
+	response = update("", `{"expectedVersion":"2"}`)
+	require.Equal(t, http.StatusConflict, response.Code)
+	require.Equal(t, "urn:calendar:problem:conflict", decodeProblem(t, response).Type)
+
+	require.Equal(t, http.StatusBadRequest, update("abc", `{}`).Code)
+
+	// слабый ETag не совпадает, из списка подходит текущая версия, "*" - любая
+	require.Equal(t, http.StatusPreconditionFailed, update(`W/"3"`, `{}`).Code)
+	require.Equal(t, http.StatusOK, update(`"2", "3"`, `{}`).Code)
+	require.Equal(t, http.StatusPreconditionFailed, update(`"1", "2"`, `{}`).Code)
+	require.Equal(t, http.StatusOK, update("*", `{}`).Code)
+}
+
+func TestGatewayBatchDelete(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/events:batchDelete",
+		strings.NewReader(`{"ids":["1","2"],"mode":"BEST_EFFORT"}`))
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var body struct {
+		Results []struct {
+			ID     string `json:"id"`
+			Code   int    `json:"code"`
+			Reason string `json:"reason"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	require.Len(t, body.Results, 2)
+	require.Equal(t, "1", body.Results[0].ID)
+	require.Equal(t, 0, body.Results[0].Code)
+	require.Equal(t, int(codes.NotFound), body.Results[1].Code)
+	require.Equal(t, "NOT_FOUND", body.Results[1].Reason)
+}
+
+func TestGatewayTrash(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	serve := func(method string, target string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(method, target, strings.NewReader(`{}`)))
+		return response
+	}
+
+	response := serve(http.MethodGet, "/v1/calendars/5/events:trash")
+	require.Equal(t, http.StatusOK, response.Code)
+	var body struct {
+		Events []struct {
+			ID         string `json:"id"`
+			CalendarID string `json:"calendarID"`
+			DeletedAt  string `json:"deletedAt"`
+		} `json:"events"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	require.Len(t, body.Events, 1)
+	require.Equal(t, "2", body.Events[0].ID)
+	require.Equal(t, "5", body.Events[0].CalendarID)
+	require.NotEmpty(t, body.Events[0].DeletedAt)
+
+	require.Equal(t, http.StatusOK, serve(http.MethodPost, "/v1/events/2:restore").Code)
+	response = serve(http.MethodPost, "/v1/events/1:restore")
+	require.Equal(t, http.StatusNotFound, response.Code)
+	require.Equal(t, "urn:calendar:problem:not-found", decodeProblem(t, response).Type)
+}
+
+func TestGatewayEventHistory(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/calendars/5/events/1/history", nil)
+	request.Header.Set("X-Request-ID", "req-1")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "req-1", response.Header().Get("X-Request-ID"))
+
+	var body struct {
+		Entries []struct {
+			Operation string `json:"operation"`
+			RequestID string `json:"requestID"`
+			After     struct {
+				ID string `json:"id"`
+			} `json:"after"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	require.Len(t, body.Entries, 1)
+	require.Equal(t, "create", body.Entries[0].Operation)
+	require.Equal(t, "req-1", body.Entries[0].RequestID)
+	require.Equal(t, "1", body.Entries[0].After.ID)
+
+	// без заголовка ID запроса создаётся сервером
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/events/2/history", nil))
+	require.Equal(t, http.StatusNotFound, response.Code)
+	require.NotEmpty(t, response.Header().Get("X-Request-ID"))
+}
+
+func TestGatewayMetrics(t *testing.T) {
+	router, _ := newTestGateway(t)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/events/1", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// метка route - шаблон пути шлюза, а не сам путь
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Body.String(),
+		`calendar_http_requests_total{method="GET",route="/v1/events/{id=*}",status="200"}`)
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	router := NewRouter(New(nil, zap.NewNop(), nil), nil, zap.NewNop())
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var spec struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&spec))
+	require.NotNil(t, spec.Paths["/v1/events/{id}"])
+}
+
+func TestDeprecatedRoutes(t *testing.T) {
+	router := NewRouter(New(nil, zap.NewNop(), nil), nil, zap.NewNop())
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{")))
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Equal(t, "true", response.Header().Get("Deprecation"))
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, "", response.Header().Get("Deprecation"))
+}
+
+func TestGatewayWatchChanges(t *testing.T) {
+	router, storager := newTestGateway(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/events/changes?resumeToken=5", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	request.Header.Set("Last-Event-ID", "7")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+	require.Equal(t, []string{"Bearer token"}, storager.authorization)
+	// Last-Event-ID важнее параметра resumeToken
+	require.Equal(t, "id: 7\n\n"+
+		"id: 8\nevent: updated\ndata: {\"kind\":\"updated\",\"eventID\":\"1\",\"resumeToken\":\"8\"}\n\n",
+		strings.ReplaceAll(response.Body.String(), ", ", ","))
+
+	request = httptest.NewRequest(http.MethodGet, "/v1/events/changes?resumeToken=abc", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.Equal(t, "urn:calendar:problem:validation", decodeProblem(t, response).Type)
+}
@@ -0,0 +1,169 @@
+package internalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/ical"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// maxICSSize - ограничение на размер загружаемого .ics файла.
+const maxICSSize = 10 << 20
+
+type importedEvent struct {
+	UID      string   `json:"uid"`
+	ID       string   `json:"id,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type importResult struct {
+	Imported int             `json:"imported"`
+	Failed   int             `json:"failed"`
+	Events   []importedEvent `json:"events"`
+}
+
+// ExportEvents отдаёт все события пользователя в формате iCalendar.
+func (eh *EventHandlers) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	events, err := eh.Storager.GetEventsByUserID(r.Context(), userID)
+	if err != nil {
+		eh.Logg.Error("error in getting events:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, uidHost(r), events); err != nil {
+		eh.Logg.Error("error in encoding calendar:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="calendar.ics"`)
+
+	_, err = w.Write(buf.Bytes())
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// ImportEvents принимает .ics файл (телом запроса или полем file формы multipart)
+// и создаёт события пользователя. Ошибки и пропущенные свойства возвращаются по каждому событию.
+// Событие с UID, который уже есть в календаре (в том числе выгруженное отсюда же), не создаётся
+// повторно: по нему возвращается ошибка.
+func (eh *EventHandlers) ImportEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	body, err := readICS(w, r)
+	if err != nil {
+		eh.Logg.Error("error in reading calendar:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+	defer body.Close()
+
+	items, err := ical.Decode(body)
+	if err != nil {
+		eh.Logg.Error("error in decoding calendar:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	res := importResult{Events: make([]importedEvent, 0, len(items))}
+	for _, item := range items {
+		ie := importedEvent{UID: item.UID, Warnings: item.Warnings}
+
+		if item.Err != nil {
+			ie.Error = item.Err.Error()
+			res.Failed++
+			res.Events = append(res.Events, ie)
+			continue
+		}
+
+		id, err := eh.importEvent(r, item, userID)
+		if err != nil {
+			eh.Logg.Error("error in adding event:", zap.Error(err), zap.String("uid", item.UID))
+			ie.Error = err.Error()
+			res.Failed++
+		} else {
+			ie.ID = id
+			res.Imported++
+		}
+		res.Events = append(res.Events, ie)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// importEvent создаёт событие из item, если это не событие пользователя, выгруженное ранее.
+func (eh *EventHandlers) importEvent(r *http.Request, item ical.Item, userID string) (string, error) {
+	if id, ok := ical.LocalEventID(item.UID, uidHost(r)); ok {
+		_, err := eh.Storager.GetEventByID(id, userID)
+		if err == nil {
+			return "", storage.ErrDuplicateUID
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+	}
+	return eh.Storager.AddEventByID(r.Context(), item.Event, userID)
+}
+
+// uidHost - домен UID выгружаемых событий: имя хоста запроса без порта.
+func uidHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		return host
+	}
+	return r.Host
+}
+
+func readICS(w http.ResponseWriter, r *http.Request) (io.ReadCloser, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxICSSize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	if err := r.ParseMultipartForm(maxICSSize); err != nil {
+		return nil, err
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, errors.Join(errors.New("form field \"file\" is required"), err)
+	}
+	return file, nil
+}
@@ -0,0 +1,76 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+type loginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Login проверяет логин и пароль и выдаёт токен доступа.
+// Токен передаётся в остальные запросы в заголовке Authorization: Bearer <token>.
+func (eh *EventHandlers) Login(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		eh.Logg.Error("error in reading login request:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+	if req.Login == "" || req.Password == "" {
+		eh.writeError(w, r, storage.Invalid("login and password are required"))
+		return
+	}
+
+	account, err := eh.Storager.GetAccountByLogin(r.Context(), req.Login)
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			// не сообщаем, что именно неверно - логин или пароль
+			eh.writeError(w, r, auth.ErrInvalidCredentials)
+			return
+		}
+		eh.Logg.Error("error in getting account:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	if err := auth.CheckPassword(account.PasswordHash, req.Password); err != nil {
+		eh.writeError(w, r, auth.ErrInvalidCredentials)
+		return
+	}
+
+	token, expiresAt, err := eh.Tokens.Issue(account.ID, account.TokenVersion)
+	if err != nil {
+		eh.Logg.Error("error in issuing token:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(loginResponse{Token: token, ExpiresAt: expiresAt})
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
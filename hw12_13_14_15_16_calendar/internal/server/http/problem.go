@@ -0,0 +1,76 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/problem"
+	"go.uber.org/zap"
+)
+
+// errUnauthenticated - в контексте запроса нет пользователя.
+var errUnauthenticated = errors.New("authentication required")
+
+// problemTypes - поле type ответа problem+json для каждого вида ошибки.
+var problemTypes = map[error]string{
+	storage.ErrNotFound:   "urn:calendar:problem:not-found",
+	storage.ErrForbidden:  "urn:calendar:problem:forbidden",
+	storage.ErrValidation: "urn:calendar:problem:validation",
+	storage.ErrConflict:   "urn:calendar:problem:conflict",
+	errPreconditionFailed: "urn:calendar:problem:precondition-failed",
+}
+
+// errorStatus переводит ошибку в код ответа по её виду; ошибки без вида - 500.
+func errorStatus(err error) int {
+	if errors.Is(err, errPreconditionFailed) {
+		return http.StatusPreconditionFailed
+	}
+	switch storage.Kind(err) {
+	case storage.ErrNotFound:
+		return http.StatusNotFound
+	case storage.ErrForbidden:
+		return http.StatusForbidden
+	case storage.ErrValidation:
+		return http.StatusBadRequest
+	case storage.ErrConflict:
+		return http.StatusConflict
+	}
+	if errors.Is(err, errUnauthenticated) || errors.Is(err, auth.ErrInvalidCredentials) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// problemType возвращает поле type ответа на ошибку; ошибки без вида - about:blank.
+func problemType(err error) string {
+	if errors.Is(err, errPreconditionFailed) {
+		return problemTypes[errPreconditionFailed]
+	}
+	return problemTypes[storage.Kind(err)]
+}
+
+// badRequest помечает ошибку разбора запроса (JSON, параметры) как ошибку проверки.
+func badRequest(err error) error {
+	return fmt.Errorf("%w: %w", storage.ErrValidation, err)
+}
+
+// writeError отвечает на ошибку телом application/problem+json (RFC 7807).
+// Текст внутренних ошибок клиенту не отдаётся: они видны только в логе.
+func (eh *EventHandlers) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	p := problem.Details{
+		Type:     problemType(err),
+		Status:   status,
+		Instance: r.URL.Path,
+	}
+	if status != http.StatusInternalServerError {
+		p.Detail = err.Error()
+	}
+
+	if err := problem.Write(w, p); err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+	}
+}
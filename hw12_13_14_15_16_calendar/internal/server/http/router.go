@@ -1,20 +1,69 @@
 package internalhttp
 
 import (
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	httpauth "github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/auth"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/logger"
+	httpmetrics "github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/metrics"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/requestid"
+	httptracing "github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/mware/tracing"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
-func NewRouter(h *EventHandlers, logg *zap.Logger) chi.Router {
+// NewRouter собирает маршруты сервера. Запросы /v1/... уходят в REST-шлюз gateway (nil - без шлюза);
+// маршруты chi, которые он заменяет, помечены как устаревшие (см. deprecated).
+// Каждый запрос получает ID из заголовка X-Request-ID (или новый), он попадает в журнал аудита.
+// Метрики запросов отдаются по /metrics; каждый запрос - спан трейса из заголовка traceparent.
+// Токены удалённых аккаунтов и выпущенные до смены пароля отклоняются.
+func NewRouter(h *EventHandlers, gateway http.Handler, logg *zap.Logger) chi.Router {
 	r := chi.NewRouter()
+	r.Use(requestid.WithRequestID, httptracing.WithTracing, httpmetrics.WithMetrics)
 
 	r.Get(`/`, logger.WithLogging(h.mainPage, logg))
-	r.Get(`/user/{userid}/event/{id}`, logger.WithLogging(h.GetEventByID, logg))
-	r.Put(`/user/{userid}/event/`, logger.WithLogging(h.AddEvent, logg))
-	r.Post(`/update/user/{userid}/event/{id}`, logger.WithLogging(h.UpdateEventeByID, logg))
-	r.Delete(`/user/{userid}/event/{id}`, logger.WithLogging(h.DeleteEventByID, logg))
-	r.Get(`/user/{userid}/events/`, logger.WithLogging(h.GetEventListingByUserID, logg))
+	r.Handle(`/metrics`, metrics.Handler())
+	r.Get(`/openapi.json`, logger.WithLogging(openAPISpec, logg))
+	if gateway != nil {
+		r.Handle(`/v1/*`, logger.WithLogging(gateway.ServeHTTP, logg))
+	}
+	r.Post(`/login`, logger.WithLogging(deprecated(h.Login), logg))
+	r.Post(`/accounts`, logger.WithLogging(deprecated(h.Register), logg))
+
+	r.Group(func(r chi.Router) {
+		r.Use(httpauth.Authenticate(auth.NewVerifier(h.Tokens, h.Storager), logg))
+
+		r.Get(`/user/{userid}/event/{id}`, logger.WithLogging(deprecated(h.GetEventByID), logg))
+		r.Put(`/user/{userid}/event/`, logger.WithLogging(deprecated(h.AddEvent), logg))
+		r.Post(`/update/user/{userid}/event/{id}`, logger.WithLogging(deprecated(h.UpdateEventeByID), logg))
+		r.Delete(`/user/{userid}/event/{id}`, logger.WithLogging(deprecated(h.DeleteEventByID), logg))
+		r.Put(`/user/{userid}/event/{id}/rsvp`, logger.WithLogging(deprecated(h.RespondToEvent), logg))
+		r.Get(`/user/{userid}/events/`, logger.WithLogging(deprecated(h.GetEventListingByUserID), logg))
+		r.Get(`/user/{userid}/events/search`, logger.WithLogging(deprecated(h.SearchEvents), logg))
+		r.Get(`/user/{userid}/events/ics`, logger.WithLogging(h.ExportEvents, logg))
+		r.Post(`/user/{userid}/events/ics`, logger.WithLogging(h.ImportEvents, logg))
+		r.Get(`/user/{userid}/freebusy`, logger.WithLogging(deprecated(h.GetFreeBusy), logg))
+		r.Get(`/user/{userid}/account`, logger.WithLogging(deprecated(h.GetAccount), logg))
+		r.Post(`/user/{userid}/account/password`, logger.WithLogging(deprecated(h.ChangePassword), logg))
+		r.Delete(`/user/{userid}/account`, logger.WithLogging(deprecated(h.DeleteAccount), logg))
+		r.Put(`/user/{userid}/account/channels`, logger.WithLogging(deprecated(h.SetChannels), logg))
+		r.Put(`/user/{userid}/account/timezone`, logger.WithLogging(deprecated(h.SetTimeZone), logg))
+
+		r.Get(`/calendars`, logger.WithLogging(deprecated(h.GetCalendars), logg))
+		r.Post(`/calendars`, logger.WithLogging(deprecated(h.CreateCalendar), logg))
+		r.Delete(`/calendars/{calendarid}`, logger.WithLogging(deprecated(h.DeleteCalendar), logg))
+		r.Get(`/calendars/{calendarid}/acl`, logger.WithLogging(deprecated(h.GetCalendarACL), logg))
+		r.Put(`/calendars/{calendarid}/acl`, logger.WithLogging(deprecated(h.SetCalendarACL), logg))
+		r.Delete(`/calendars/{calendarid}/acl/{granteeid}`, logger.WithLogging(deprecated(h.RevokeCalendarAccess), logg))
+		r.Get(`/calendars/{calendarid}/event/{id}`, logger.WithLogging(deprecated(h.GetEventByID), logg))
+		r.Put(`/calendars/{calendarid}/event/`, logger.WithLogging(deprecated(h.AddEvent), logg))
+		r.Post(`/calendars/{calendarid}/event/{id}`, logger.WithLogging(deprecated(h.UpdateEventeByID), logg))
+		r.Delete(`/calendars/{calendarid}/event/{id}`, logger.WithLogging(deprecated(h.DeleteEventByID), logg))
+		r.Get(`/calendars/{calendarid}/events/`, logger.WithLogging(deprecated(h.GetEventListingByUserID), logg))
+		r.Get(`/calendars/{calendarid}/events/search`, logger.WithLogging(deprecated(h.SearchEvents), logg))
+	})
 
 	return r
 }
@@ -0,0 +1,95 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/app"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// SearchEvents ищет события пользователя или календаря calendarid:
+// ?from=&to= (RFC 3339), q - слова из названия или описания, notified=true|false,
+// sort=start|created|title (с "-" - по убыванию), limit, cursor - nextCursor предыдущей страницы,
+// tz - зона повторяющихся событий без своей зоны, по умолчанию зона аккаунта.
+func (eh *EventHandlers) SearchEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	q, err := parseSearchQuery(r.URL.Query())
+	if err != nil {
+		eh.Logg.Error("error in parsing search query:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+
+	q.CalendarID = r.PathValue("calendarid")
+	if q.Location, err = app.UserLocation(r.Context(), eh.Storager, userID, r.URL.Query().Get("tz")); err != nil {
+		eh.Logg.Error("error in resolving time zone:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	res, err := eh.Storager.SearchEvents(r.Context(), userID, q)
+	if err != nil {
+		eh.Logg.Error("error in searching events:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	resp, err := json.Marshal(res)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+func parseSearchQuery(query url.Values) (storage.SearchQuery, error) {
+	q := storage.SearchQuery{
+		Text:   query.Get("q"),
+		Sort:   query.Get("sort"),
+		Cursor: query.Get("cursor"),
+	}
+
+	var err error
+	if v := query.Get("from"); v != "" {
+		if q.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return q, err
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if q.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return q, err
+		}
+	}
+	if v := query.Get("notified"); v != "" {
+		notified, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, err
+		}
+		q.Notified = &notified
+	}
+	if v := query.Get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
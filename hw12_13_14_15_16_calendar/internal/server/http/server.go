@@ -9,9 +9,11 @@ import (
 
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/configs"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/app"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
@@ -19,6 +21,9 @@ type Server struct {
 	logg     *zap.Logger
 	storager app.Storager
 	srv      *http.Server
+	grpcConn *grpc.ClientConn // соединение REST-шлюза с gRPC-сервером
+	// cancelStreams закрывает потоки ленты изменений: сами они не заканчиваются
+	cancelStreams context.CancelFunc
 }
 
 type Logger interface { // TODO
@@ -27,9 +32,23 @@ type Logger interface { // TODO
 type Application interface { // TODO
 }
 
-func NewServer(cfg *configs.Config, logg *zap.Logger, _ Application, storager app.Storager) *Server {
-	eventHandlers := New(storager, logg)
-	router := NewRouter(eventHandlers, logg)
+func NewServer(cfg *configs.Config, logg *zap.Logger, _ Application, storager app.Storager,
+	tokens *auth.TokenManager,
+) (*Server, error) {
+	grpcConn, err := dialGRPC(cfg.GatewayUpstreamAddress())
+	if err != nil {
+		return nil, err
+	}
+	streamsCtx, cancelStreams := context.WithCancel(context.Background())
+	gateway, err := NewGateway(streamsCtx, grpcConn, logg)
+	if err != nil {
+		cancelStreams()
+		grpcConn.Close()
+		return nil, err
+	}
+
+	eventHandlers := New(storager, logg, tokens)
+	router := NewRouter(eventHandlers, gateway, logg)
 	srv := &http.Server{
 		Addr:         cfg.Address,
 		Handler:      router,
@@ -37,7 +56,10 @@ func NewServer(cfg *configs.Config, logg *zap.Logger, _ Application, storager ap
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
-	return &Server{cfg: cfg, logg: logg, storager: storager, srv: srv}
+	return &Server{
+		cfg: cfg, logg: logg, storager: storager, srv: srv,
+		grpcConn: grpcConn, cancelStreams: cancelStreams,
+	}, nil
 }
 
 func (s *Server) Start(ctx context.Context, logg *zap.Logger) error {
@@ -50,10 +72,14 @@ func (s *Server) Start(ctx context.Context, logg *zap.Logger) error {
 }
 
 func (s *Server) Stop(ctx context.Context) error {
+	s.cancelStreams()
 	err := s.srv.Shutdown(ctx)
 	if err != nil {
 		return err
 	}
+	if err := s.grpcConn.Close(); err != nil {
+		return err
+	}
 	s.logg.Info("Gracefully shutting down server")
 	return nil
 }
@@ -65,35 +91,49 @@ func (eh *EventHandlers) mainPage(res http.ResponseWriter, _ *http.Request) {
 type EventHandlers struct {
 	Storager app.Storager
 	Logg     *zap.Logger
+	Tokens   *auth.TokenManager
 }
 
-func New(storager app.Storager, logg *zap.Logger) *EventHandlers {
+func New(storager app.Storager, logg *zap.Logger, tokens *auth.TokenManager) *EventHandlers {
 	return &EventHandlers{
 		Storager: storager,
 		Logg:     logg,
+		Tokens:   tokens,
 	}
 }
 
 func (eh *EventHandlers) GetEventByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID := r.PathValue("userid")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
 	eventID := r.PathValue("id")
 
-	event, err := eh.Storager.GetEventByID(eventID, userID)
+	var event storage.Event
+	var err error
+	if calendarID := r.PathValue("calendarid"); calendarID != "" {
+		event, err = eh.Storager.GetCalendarEvent(r.Context(), calendarID, eventID, userID)
+	} else {
+		event, err = eh.Storager.GetEventByID(eventID, userID)
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		eh.Logg.Error("error in getting event:", zap.Error(err))
+		eh.writeError(w, r, err)
 		return
 	}
 	data, err := json.Marshal(event)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.writeError(w, r, err)
 		return
 	}
+	w.Header().Set("ETag", eventETag(event.Version))
 
 	_, err = w.Write(data)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
 		return
 	}
 }
@@ -101,7 +141,11 @@ func (eh *EventHandlers) GetEventByID(w http.ResponseWriter, r *http.Request) {
 func (eh *EventHandlers) AddEvent(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID := r.PathValue("userid")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
 
 	var buf bytes.Buffer
 	var event storage.EventCreateDTO
@@ -110,13 +154,13 @@ func (eh *EventHandlers) AddEvent(w http.ResponseWriter, r *http.Request) {
 	_, err := buf.ReadFrom(r.Body)
 	if err != nil {
 		eh.Logg.Error("error in reading body:", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		eh.writeError(w, r, badRequest(err))
 		return
 	}
 
 	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
 		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		eh.writeError(w, r, badRequest(err))
 		return
 	}
 
@@ -124,20 +168,49 @@ func (eh *EventHandlers) AddEvent(w http.ResponseWriter, r *http.Request) {
 	err = validate.Struct(event)
 	if err != nil {
 		eh.Logg.Error("error in validating:", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		eh.writeError(w, r, badRequest(err))
 		return
 	}
 
-	if event.End.Before(event.Start) {
+	if err := storage.ValidateEventTime(event.Start, event.End); err != nil {
 		eh.Logg.Error("event star_time after end_time", zap.Any("start", event.Start), zap.Any("end", event.End))
-		w.WriteHeader(http.StatusBadRequest) // TODO !!!!!
+		eh.writeError(w, r, err)
 		return
 	}
 
-	createdID, err := eh.Storager.AddEventByID(context.Background(), event, userID)
+	if err := storage.ValidateRRule(event.RRule); err != nil {
+		eh.Logg.Error("error in parsing rrule:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	if err := storage.ValidateTimeZone(event.TimeZone); err != nil {
+		eh.Logg.Error("error in validating time zone:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	if err := storage.ValidateReminders(event.Reminders); err != nil {
+		eh.Logg.Error("error in validating reminders:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	if err := storage.ValidateAttendees(userID, event.Attendees); err != nil {
+		eh.Logg.Error("error in validating attendees:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	var createdID string
+	if calendarID := r.PathValue("calendarid"); calendarID != "" {
+		createdID, err = eh.Storager.AddCalendarEvent(r.Context(), calendarID, event, userID)
+	} else {
+		createdID, err = eh.Storager.AddEventByID(context.Background(), event, userID)
+	}
 	if err != nil {
 		eh.Logg.Error("error in adding event:", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.writeError(w, r, err)
 		return
 	}
 
@@ -152,7 +225,7 @@ func (eh *EventHandlers) AddEvent(w http.ResponseWriter, r *http.Request) {
 	data, err := json.Marshal(res)
 	if err != nil {
 		eh.Logg.Error("error in marshalling event:", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.writeError(w, r, err)
 		return
 	}
 
@@ -160,23 +233,39 @@ func (eh *EventHandlers) AddEvent(w http.ResponseWriter, r *http.Request) {
 
 	_, err = w.Write(data)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
 		return
 	}
 }
 
 func (eh *EventHandlers) DeleteEventByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
 	eventID := r.PathValue("id")
-	err := eh.Storager.DeleteEventByID(context.Background(), eventID)
+
+	var err error
+	if calendarID := r.PathValue("calendarid"); calendarID != "" {
+		err = eh.Storager.DeleteCalendarEvent(r.Context(), calendarID, eventID, userID)
+	} else {
+		err = eh.Storager.DeleteEventByID(context.Background(), eventID, userID)
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.Logg.Error("error in deleting event:", zap.Error(err))
+		eh.writeError(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
 
 func (eh *EventHandlers) UpdateEventeByID(w http.ResponseWriter, r *http.Request) {
-	userID := r.PathValue("userid")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
 	eventID := r.PathValue("id")
 
 	var buf bytes.Buffer
@@ -185,26 +274,85 @@ func (eh *EventHandlers) UpdateEventeByID(w http.ResponseWriter, r *http.Request
 	_, err := buf.ReadFrom(r.Body)
 	if err != nil {
 		eh.Logg.Error("error in reading body:", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		eh.writeError(w, r, badRequest(err))
 		return
 	}
 
 	if err = json.Unmarshal(buf.Bytes(), &event); err != nil {
 		eh.Logg.Error("error in unmarshalling json:", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		eh.writeError(w, r, badRequest(err))
 		return
 	}
 
-	if event.End.Before(*event.Start) {
-		eh.Logg.Error("event star_time after end_time", zap.Any("start", event.Start), zap.Any("end", event.End))
-		w.WriteHeader(http.StatusBadRequest)
+	// If-Match важнее поля version в теле
+	match, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		eh.writeError(w, r, err)
 		return
 	}
+	ifMatch, err := match.expectedVersion(func() (int64, error) {
+		e, err := eh.Storager.GetEventByID(eventID, userID)
+		return e.Version, err
+	})
+	if err != nil {
+		eh.writeError(w, r, err)
+		return
+	}
+	if ifMatch != 0 {
+		event.Version = ifMatch
+	}
 
-	err = eh.Storager.UpdateEventByID(context.Background(), eventID, event, userID)
+	if event.Start != nil && event.End != nil {
+		if err := storage.ValidateEventTime(*event.Start, *event.End); err != nil {
+			eh.Logg.Error("event star_time after end_time", zap.Any("start", event.Start), zap.Any("end", event.End))
+			eh.writeError(w, r, err)
+			return
+		}
+	}
+
+	if event.RRule != nil {
+		if err := storage.ValidateRRule(*event.RRule); err != nil {
+			eh.Logg.Error("error in parsing rrule:", zap.Error(err))
+			eh.writeError(w, r, err)
+			return
+		}
+	}
+
+	if event.TimeZone != nil {
+		if err := storage.ValidateTimeZone(*event.TimeZone); err != nil {
+			eh.Logg.Error("error in validating time zone:", zap.Error(err))
+			eh.writeError(w, r, err)
+			return
+		}
+	}
+
+	if event.Reminders != nil {
+		if err := storage.ValidateReminders(*event.Reminders); err != nil {
+			eh.Logg.Error("error in validating reminders:", zap.Error(err))
+			eh.writeError(w, r, err)
+			return
+		}
+	}
+
+	if event.Attendees != nil {
+		if err := storage.ValidateAttendees(userID, *event.Attendees); err != nil {
+			eh.Logg.Error("error in validating attendees:", zap.Error(err))
+			eh.writeError(w, r, err)
+			return
+		}
+	}
+
+	if calendarID := r.PathValue("calendarid"); calendarID != "" {
+		err = eh.Storager.UpdateCalendarEvent(r.Context(), calendarID, eventID, event, userID)
+	} else {
+		err = eh.Storager.UpdateEventByID(context.Background(), eventID, event, userID)
+	}
 	if err != nil {
 		eh.Logg.Error("error in updating event:", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
+		if ifMatch != 0 {
+			err = preconditionFailed(err)
+		}
+		eh.writeError(w, r, err)
 		return
 	}
 
@@ -222,32 +370,48 @@ func NewEventsListResponse(events []storage.EventGetDTO) []*storage.EventGetDTO
 func (eh *EventHandlers) GetEventListingByUserID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	userID := r.PathValue("userid")
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
 	period := r.URL.Query().Get("period")
 	if period == "" {
 		period = "day"
 	}
 
+	// день, неделя и месяц считаются в зоне из параметра tz, а без него - в зоне аккаунта
+	loc, err := app.UserLocation(r.Context(), eh.Storager, userID, r.URL.Query().Get("tz"))
+	if err != nil {
+		eh.Logg.Error("error in resolving time zone:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
 	var parsedTime time.Time
-	var err error
 
 	date := r.URL.Query().Get("date")
 
 	if date == "" {
-		now := time.Now()
-		parsedTime = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 1, 0, time.Local)
+		parsedTime = time.Now().In(loc)
 	} else {
-		parsedTime, err = time.Parse("2006-01-02", date)
+		parsedTime, err = time.ParseInLocation("2006-01-02", date, loc)
 		if err != nil {
 			eh.Logg.Error("error in parsing time:", zap.Error(err))
-			w.WriteHeader(http.StatusBadRequest)
+			eh.writeError(w, r, badRequest(err))
 			return
 		}
 	}
 
-	events, err := eh.Storager.GetEventListingByUserID(userID, parsedTime, period)
+	var events []storage.Event
+	if calendarID := r.PathValue("calendarid"); calendarID != "" {
+		events, err = eh.Storager.GetCalendarEventListing(r.Context(), calendarID, parsedTime, period, userID)
+	} else {
+		events, err = eh.Storager.GetEventListingByUserID(userID, parsedTime, period)
+	}
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		eh.Logg.Error("error in getting event listing:", zap.Error(err))
+		eh.writeError(w, r, err)
 		return
 	}
 
@@ -260,14 +424,86 @@ func (eh *EventHandlers) GetEventListingByUserID(w http.ResponseWriter, r *http.
 	resp, err := json.Marshal(events)
 	if err != nil {
 		eh.Logg.Error("error in marshalling json", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
+		eh.writeError(w, r, err)
+		return
+	}
+
+	_, err = w.Write(resp)
+	if err != nil {
+		eh.Logg.Error("error in writing resp:", zap.Error(err))
+		return
+	}
+}
+
+// GetFreeBusy отдаёт занятые интервалы и свободные промежутки пользователя.
+// Параметры: from и to в RFC 3339 (обязательны), minFree - минимальная длина
+// свободного промежутка в формате time.Duration (например, 30m), по умолчанию 0,
+// tz - зона интервалов ответа, по умолчанию зона аккаунта.
+func (eh *EventHandlers) GetFreeBusy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		eh.writeError(w, r, errUnauthenticated)
+		return
+	}
+	query := r.URL.Query()
+
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		eh.Logg.Error("error in parsing from:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		eh.Logg.Error("error in parsing to:", zap.Error(err))
+		eh.writeError(w, r, badRequest(err))
+		return
+	}
+	if !to.After(from) {
+		eh.writeError(w, r, storage.Invalid("to must be after from"))
+		return
+	}
+
+	var minFree time.Duration
+	if v := query.Get("minFree"); v != "" {
+		minFree, err = time.ParseDuration(v)
+		if err != nil {
+			eh.Logg.Error("error in parsing minFree:", zap.Error(err))
+			eh.writeError(w, r, badRequest(err))
+			return
+		}
+		if minFree < 0 {
+			eh.writeError(w, r, storage.Invalid("minFree must not be negative"))
+			return
+		}
+	}
+
+	loc, err := app.UserLocation(r.Context(), eh.Storager, userID, query.Get("tz"))
+	if err != nil {
+		eh.Logg.Error("error in resolving time zone:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	fb, err := eh.Storager.GetFreeBusy(r.Context(), userID, from.In(loc), to.In(loc), minFree)
+	if err != nil {
+		eh.Logg.Error("error in getting free/busy:", zap.Error(err))
+		eh.writeError(w, r, err)
+		return
+	}
+
+	resp, err := json.Marshal(fb)
+	if err != nil {
+		eh.Logg.Error("error in marshalling json", zap.Error(err))
+		eh.writeError(w, r, err)
 		return
 	}
 
 	_, err = w.Write(resp)
 	if err != nil {
 		eh.Logg.Error("error in writing resp:", zap.Error(err))
-		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
@@ -3,14 +3,18 @@ package internalhttp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/mocks"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/problem"
 	"github.com/c2fo/testify/require"
 	"github.com/golang/mock/gomock"
 	"go.uber.org/zap"
@@ -18,6 +22,9 @@ import (
 
 const layout = "2006-01-02 15:04:05"
 
+// userCtx - контекст запроса, прошедшего аутентификацию как пользователь 1.
+var userCtx = auth.WithUserID(context.Background(), "1")
+
 func TestGetEventByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -42,7 +49,7 @@ func TestGetEventByID(t *testing.T) {
 	end := createdAt.AddDate(0, 0, 2)
 	notification := createdAt.AddDate(0, 0, 1)
 
-	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, nil)
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, reqURL, nil)
 	require.NoError(t, err)
 	request.SetPathValue("userid", "1")
 	request.SetPathValue("id", "1")
@@ -58,6 +65,7 @@ func TestGetEventByID(t *testing.T) {
 		Description:  "description1",
 		UserID:       "1",
 		Notification: notification,
+		Version:      2,
 	}
 
 	m.EXPECT().GetEventByID(eventID, userID).Return(expectedEvent, nil)
@@ -66,6 +74,7 @@ func TestGetEventByID(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, response.Code)
 	require.Contains(t, response.Header().Get("Content-Type"), "application/json")
+	require.Equal(t, `"2"`, response.Header().Get("ETag"))
 
 	var actual storage.Event
 	err = json.NewDecoder(response.Body).Decode(&actual)
@@ -118,7 +127,7 @@ func TestAddEventByID(t *testing.T) {
 	wantHTTPStatus := 201
 
 	request, err := http.NewRequestWithContext(
-		context.Background(), http.MethodPut, reqURL, strings.NewReader(reqBody),
+		userCtx, http.MethodPut, reqURL, strings.NewReader(reqBody),
 	)
 	require.NoError(t, err)
 	request.SetPathValue("userid", "1")
@@ -177,7 +186,7 @@ func TestUpdateEventByID(t *testing.T) {
 	wantHTTPStatus := 202
 
 	request, err := http.NewRequestWithContext(
-		context.Background(), http.MethodPost, reqURL, strings.NewReader(reqBody),
+		userCtx, http.MethodPost, reqURL, strings.NewReader(reqBody),
 	)
 	require.NoError(t, err)
 	request.SetPathValue("userid", "1")
@@ -193,6 +202,112 @@ func TestUpdateEventByID(t *testing.T) {
 	require.Equal(t, wantHTTPStatus, response.Code)
 }
 
+func TestParseIfMatch(t *testing.T) {
+	errCurrent := errors.New("no event")
+	tests := []struct {
+		header  string
+		current int64 // текущая версия события; 0 - событие не найдено
+		want    int64
+		wantErr error
+	}{
+		{header: "", want: 0},
+		{header: "*", want: 0},
+		{header: ` * `, want: 0},
+		{header: `"3"`, want: 3},
+		{header: ` "3" `, want: 3},
+		{header: `"2", "3"`, current: 3, want: 3},
+		{header: `"2","3"`, current: 2, want: 2},
+		{header: `"2", "3"`, current: 4, wantErr: errPreconditionFailed},
+		{header: `"2", "3"`, wantErr: errCurrent},
+		// слабые ETag при сильном сравнении не совпадают
+		{header: `W/"3"`, current: 3, wantErr: errPreconditionFailed},
+		{header: `W/"3", "4"`, current: 3, want: 4},
+		{header: `W/"2", W/"3"`, current: 3, wantErr: errPreconditionFailed},
+		{header: `"abc"`, wantErr: errPreconditionFailed},
+		{header: `"0"`, wantErr: errPreconditionFailed},
+		{header: `abc`, wantErr: storage.ErrValidation},
+		{header: `"3`, wantErr: storage.ErrValidation},
+		{header: `"3", `, wantErr: storage.ErrValidation},
+		{header: `"3"4"`, wantErr: storage.ErrValidation},
+		{header: `W/3`, wantErr: storage.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			m, err := parseIfMatch(tt.header)
+			var got int64
+			if err == nil {
+				got, err = m.expectedVersion(func() (int64, error) {
+					if tt.current == 0 {
+						return 0, errCurrent
+					}
+					return tt.current, nil
+				})
+			}
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateEventIfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	title := "new_title"
+	update := func(ifMatch string, body string) *httptest.ResponseRecorder {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPost, "/update/user/1/event/1",
+			strings.NewReader(body))
+		require.NoError(t, err)
+		request.SetPathValue("id", "1")
+		if ifMatch != "" {
+			request.Header.Set("If-Match", ifMatch)
+		}
+		response := httptest.NewRecorder()
+		eh.UpdateEventeByID(response, request)
+		return response
+	}
+
+	// версия из If-Match важнее поля version
+	m.EXPECT().UpdateEventByID(gomock.Any(), "1", storage.EventUpdateDTO{Title: &title, Version: 3}, "1").
+		Return(nil)
+	require.Equal(t, http.StatusAccepted, update(`"3"`, `{"title":"new_title","version":2}`).Code)
+
+	stale := storage.CheckVersion("1", 4, 3)
+	m.EXPECT().UpdateEventByID(gomock.Any(), "1", storage.EventUpdateDTO{Title: &title, Version: 3}, "1").
+		Return(stale)
+	response := update(`"3"`, `{"title":"new_title"}`)
+	require.Equal(t, http.StatusPreconditionFailed, response.Code)
+	require.Equal(t, "urn:calendar:problem:precondition-failed", decodeProblem(t, response).Type)
+
+	// из списка версий выбирается текущая версия события
+	m.EXPECT().GetEventByID("1", "1").Return(storage.Event{ID: "1", Version: 3}, nil)
+	m.EXPECT().UpdateEventByID(gomock.Any(), "1", storage.EventUpdateDTO{Title: &title, Version: 3}, "1").
+		Return(nil)
+	require.Equal(t, http.StatusAccepted, update(`"2", "3"`, `{"title":"new_title"}`).Code)
+	require.Equal(t, http.StatusPreconditionFailed, update(`W/"3"`, `{"title":"new_title"}`).Code)
+
+	// устаревшая версия из тела - конфликт
+	m.EXPECT().UpdateEventByID(gomock.Any(), "1", storage.EventUpdateDTO{Title: &title, Version: 3}, "1").
+		Return(stale)
+	response = update("", `{"title":"new_title","version":3}`)
+	require.Equal(t, http.StatusConflict, response.Code)
+	require.Equal(t, "urn:calendar:problem:conflict", decodeProblem(t, response).Type)
+
+	response = update("W/abc", `{"title":"new_title"}`)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
 func TestUpdateEventWithWrongEndDate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -233,7 +348,7 @@ func TestUpdateEventWithWrongEndDate(t *testing.T) {
 	wantHTTPStatus := 400
 
 	request, err := http.NewRequestWithContext(
-		context.Background(), http.MethodPost, reqURL, strings.NewReader(reqBody),
+		userCtx, http.MethodPost, reqURL, strings.NewReader(reqBody),
 	)
 	require.NoError(t, err)
 	request.SetPathValue("userid", "1")
@@ -267,14 +382,14 @@ func TestDeleteEventByID(t *testing.T) {
 	wantHTTPStatus := 200
 
 	request, err := http.NewRequestWithContext(
-		context.Background(), http.MethodDelete, reqURL, nil,
+		userCtx, http.MethodDelete, reqURL, nil,
 	)
 	require.NoError(t, err)
 	request.SetPathValue("id", "1")
 
 	response := httptest.NewRecorder()
 
-	m.EXPECT().DeleteEventByID(context.Background(), eventID).Return(nil)
+	m.EXPECT().DeleteEventByID(context.Background(), eventID, "1").Return(nil)
 
 	eh.DeleteEventByID(response, request)
 
@@ -304,7 +419,7 @@ func TestGetEventListByID(t *testing.T) {
 	end := createdAt.AddDate(0, 0, 2)
 	notification := createdAt.AddDate(0, 0, 1)
 
-	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, nil)
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, reqURL, nil)
 	require.NoError(t, err)
 	request.SetPathValue("userid", "1")
 	parsedTime := baseTime
@@ -334,6 +449,7 @@ func TestGetEventListByID(t *testing.T) {
 	}
 	expectedEvents := []storage.Event{expectedEvent1, expectedEvent2}
 
+	m.EXPECT().GetAccountByID(gomock.Any(), userID).Return(storage.Account{ID: userID, TimeZone: "UTC"}, nil)
 	m.EXPECT().GetEventListingByUserID(userID, parsedTime, period).Return(expectedEvents, nil)
 
 	eh.GetEventListingByUserID(response, request)
@@ -354,3 +470,890 @@ func TestGetEventListByID(t *testing.T) {
 	// require.Equal(t, expectedEvent.UserID, actual.UserID)
 	// require.True(t, expectedEvent.Notification.Equal(actual.Notification))
 }
+
+func TestGetEventListTimeZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	// дата из запроса - полночь в зоне аккаунта
+	m.EXPECT().GetAccountByID(gomock.Any(), "1").Return(storage.Account{ID: "1", TimeZone: "Asia/Tokyo"}, nil)
+	m.EXPECT().GetEventListingByUserID("1", gomock.Any(), "week").DoAndReturn(
+		func(_ string, date time.Time, _ string) ([]storage.Event, error) {
+			require.Equal(t, "Asia/Tokyo", date.Location().String())
+			require.Equal(t, "2025-09-19 00:00", date.Format("2006-01-02 15:04"))
+			return nil, nil
+		})
+
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/?date=2025-09-19&period=week", nil)
+	require.NoError(t, err)
+	response := httptest.NewRecorder()
+	eh.GetEventListingByUserID(response, request)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	// параметр tz важнее зоны аккаунта, и аккаунт не запрашивается
+	m.EXPECT().GetEventListingByUserID("1", gomock.Any(), "day").DoAndReturn(
+		func(_ string, date time.Time, _ string) ([]storage.Event, error) {
+			require.Equal(t, "America/New_York", date.Location().String())
+			return nil, nil
+		})
+
+	request, err = http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/?tz=America/New_York", nil)
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	eh.GetEventListingByUserID(response, request)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	for _, query := range []string{"?tz=Mars/Olympus", "?tz=Local"} {
+		request, err = http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/"+query, nil)
+		require.NoError(t, err)
+		response = httptest.NewRecorder()
+		eh.GetEventListingByUserID(response, request)
+		require.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+
+	m.EXPECT().GetEventListingByUserID("1", gomock.Any(), "year").Return(nil, storage.ErrUnknownPeriod)
+	request, err = http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/?tz=UTC&period=year", nil)
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	eh.GetEventListingByUserID(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestAddEventWithInvalidRRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     l,
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+
+	baseTime, err := time.ParseInLocation(layout, "2025-09-19 13:01:45", time.UTC)
+	require.NoError(t, err)
+
+	event := storage.EventCreateDTO{
+		Title: "title1",
+		Start: baseTime,
+		End:   baseTime.Add(time.Hour),
+		RRule: "FREQ=YEARLY",
+	}
+
+	jsonData, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	request, err := http.NewRequestWithContext(
+		userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(string(jsonData)),
+	)
+	require.NoError(t, err)
+	request.SetPathValue("userid", "1")
+
+	response := httptest.NewRecorder()
+
+	m.EXPECT().AddEventByID(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	eh.AddEvent(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestAddEventWithInvalidReminders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+	m.EXPECT().AddEventByID(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, reminders := range []string{`["soon"]`, `[600]`, `["10m", "10m"]`, `["5w"]`, `["-1h"]`} {
+		body := `{"title": "title1", "dateStart": "2025-09-19T13:00:00Z", "dateEnd": "2025-09-19T14:00:00Z",
+			"reminders": ` + reminders + `}`
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(body))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		eh.AddEvent(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, reminders)
+	}
+}
+
+func TestImportEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     l,
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:a",
+		"SUMMARY:title1",
+		"DTSTART:20250919T130145Z",
+		"DTEND:20250919T140145Z",
+		"X-UNKNOWN:value",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:b",
+		"SUMMARY:title2",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:7@calendar.example.com",
+		"SUMMARY:exported",
+		"DTSTART:20250919T130145Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	request, err := http.NewRequestWithContext(
+		userCtx, http.MethodPost, "/user/1/events/ics", strings.NewReader(ics),
+	)
+	require.NoError(t, err)
+	request.Host = "calendar.example.com:8081"
+	request.Header.Set("Content-Type", "text/calendar")
+	request.SetPathValue("userid", "1")
+
+	response := httptest.NewRecorder()
+
+	start := time.Date(2025, time.September, 19, 13, 1, 45, 0, time.UTC)
+	m.EXPECT().AddEventByID(gomock.Any(), storage.EventCreateDTO{
+		Title: "title1",
+		Start: start,
+		End:   start.Add(time.Hour),
+		UID:   "a",
+	}, "1").Return("10", nil)
+	// событие, выгруженное отсюда же, повторно не создаётся
+	m.EXPECT().GetEventByID("7", "1").Return(storage.Event{ID: "7"}, nil)
+
+	eh.ImportEvents(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var res importResult
+	err = json.NewDecoder(response.Body).Decode(&res)
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Imported)
+	require.Equal(t, 2, res.Failed)
+	require.Len(t, res.Events, 3)
+	require.Equal(t, "10", res.Events[0].ID)
+	require.Len(t, res.Events[0].Warnings, 1)
+	require.Equal(t, "b", res.Events[1].UID)
+	require.NotEmpty(t, res.Events[1].Error)
+	require.Equal(t, storage.ErrDuplicateUID.Error(), res.Events[2].Error)
+}
+
+func TestExportEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+
+	eh := &EventHandlers{
+		Storager: mockStorage,
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+
+	start := time.Date(2025, time.September, 19, 13, 1, 45, 0, time.UTC)
+	m.EXPECT().GetEventsByUserID(gomock.Any(), "1").Return([]storage.Event{{
+		ID:    "1",
+		Title: "title1",
+		Start: start,
+		End:   start.Add(time.Hour),
+		RRule: "FREQ=DAILY;COUNT=2",
+	}, {
+		ID:       "2",
+		Title:    "title2",
+		Start:    start,
+		End:      start.Add(time.Hour),
+		RRule:    "FREQ=WEEKLY",
+		TimeZone: "Europe/Berlin",
+		UID:      "imported@example.com",
+	}}, nil)
+
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/ics", nil)
+	require.NoError(t, err)
+	request.Host = "calendar.example.com:8081"
+	request.SetPathValue("userid", "1")
+
+	response := httptest.NewRecorder()
+
+	eh.ExportEvents(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Header().Get("Content-Type"), "text/calendar")
+	body := response.Body.String()
+	require.Contains(t, body, "BEGIN:VCALENDAR\r\n")
+	require.Contains(t, body, "UID:1@calendar.example.com\r\n")
+	require.Contains(t, body, "DTSTART:20250919T130145Z\r\n")
+	require.Contains(t, body, "RRULE:FREQ=DAILY;COUNT=2\r\n")
+	require.Contains(t, body, "UID:imported@example.com\r\n")
+	require.Contains(t, body, "DTSTART;TZID=Europe/Berlin:20250919T150145\r\n")
+	require.Contains(t, body, "TZID:Europe/Berlin\r\n")
+}
+
+func TestAddEventOverlapConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     l,
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+
+	start := time.Date(2025, time.September, 19, 13, 0, 0, 0, time.UTC)
+	event := storage.EventCreateDTO{
+		Title:         "title1",
+		Start:         start,
+		End:           start.Add(time.Hour),
+		RejectOverlap: true,
+	}
+
+	jsonData, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	request, err := http.NewRequestWithContext(
+		userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(string(jsonData)),
+	)
+	require.NoError(t, err)
+	request.SetPathValue("userid", "1")
+
+	response := httptest.NewRecorder()
+
+	m.EXPECT().AddEventByID(gomock.Any(), event, "1").Return("", storage.ErrOverlap)
+
+	eh.AddEvent(response, request)
+
+	require.Equal(t, http.StatusConflict, response.Code)
+}
+
+func TestGetFreeBusy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     l,
+	}
+
+	m := eh.Storager.(*mocks.MockStorager)
+
+	from := time.Date(2025, time.September, 19, 9, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+	expected := storage.FreeBusy{
+		Busy: []storage.Interval{{Start: from, End: from.Add(time.Hour)}},
+		Free: []storage.Interval{{Start: from.Add(time.Hour), End: to}},
+	}
+
+	m.EXPECT().GetAccountByID(gomock.Any(), "1").Return(storage.Account{ID: "1", TimeZone: "UTC"}, nil)
+	m.EXPECT().GetFreeBusy(gomock.Any(), "1", from, to, 30*time.Minute).Return(expected, nil)
+
+	reqURL := "/user/1/freebusy?from=2025-09-19T09:00:00Z&to=2025-09-19T17:00:00Z&minFree=30m"
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, reqURL, nil)
+	require.NoError(t, err)
+	request.SetPathValue("userid", "1")
+
+	response := httptest.NewRecorder()
+	eh.GetFreeBusy(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var actual storage.FreeBusy
+	err = json.NewDecoder(response.Body).Decode(&actual)
+	require.NoError(t, err)
+	require.Len(t, actual.Busy, 1)
+	require.Len(t, actual.Free, 1)
+	require.True(t, to.Equal(actual.Free[0].End))
+
+	badRequest, err := http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/freebusy?from=x", nil)
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	eh.GetFreeBusy(response, badRequest)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	tokens := auth.NewTokenManager("secret", time.Hour)
+	router := NewRouter(New(mockStorage, zap.NewNop(), tokens), nil, zap.NewNop())
+
+	hash, err := auth.HashPassword("user1")
+	require.NoError(t, err)
+	account := storage.Account{ID: "1", Login: "user1@gmail.com", PasswordHash: hash, TokenVersion: 2}
+
+	mockStorage.EXPECT().GetAccountByLogin(gomock.Any(), "user1@gmail.com").Return(account, nil).Times(2)
+	mockStorage.EXPECT().GetAccountByLogin(gomock.Any(), "nobody").Return(storage.Account{}, storage.ErrAccountNotFound)
+
+	login := func(body string) *httptest.ResponseRecorder {
+		request, err := http.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/login", strings.NewReader(body),
+		)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	response := login(`{"login": "user1@gmail.com", "password": "user1"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var res loginResponse
+	err = json.NewDecoder(response.Body).Decode(&res)
+	require.NoError(t, err)
+	userID, version, err := tokens.Parse(res.Token)
+	require.NoError(t, err)
+	require.Equal(t, "1", userID)
+	require.Equal(t, int64(2), version)
+
+	require.Equal(t, http.StatusUnauthorized, login(`{"login": "user1@gmail.com", "password": "bad"}`).Code)
+	require.Equal(t, http.StatusUnauthorized, login(`{"login": "nobody", "password": "user1"}`).Code)
+	require.Equal(t, http.StatusBadRequest, login(`{"login": "user1@gmail.com"}`).Code)
+}
+
+func TestRouterRequiresToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	tokens := auth.NewTokenManager("secret", time.Hour)
+	router := NewRouter(New(mockStorage, zap.NewNop(), tokens), nil, zap.NewNop())
+
+	token, _, err := tokens.Issue("1", 1)
+	require.NoError(t, err)
+	expired, _, err := auth.NewTokenManager("secret", -time.Hour).Issue("1", 1)
+	require.NoError(t, err)
+	// выпущен до смены пароля
+	stale, _, err := tokens.Issue("1", 0)
+	require.NoError(t, err)
+	deleted, _, err := tokens.Issue("2", 0)
+	require.NoError(t, err)
+	broken, _, err := tokens.Issue("3", 0)
+	require.NoError(t, err)
+
+	mockStorage.EXPECT().GetAccountByID(gomock.Any(), "1").Return(storage.Account{ID: "1", TokenVersion: 1}, nil).
+		AnyTimes()
+	mockStorage.EXPECT().GetAccountByID(gomock.Any(), "2").Return(storage.Account{}, storage.ErrAccountNotFound)
+	mockStorage.EXPECT().GetAccountByID(gomock.Any(), "3").Return(storage.Account{}, errors.New("db is down"))
+	mockStorage.EXPECT().GetEventByID("5", "1").Return(storage.Event{ID: "5", UserID: "1"}, nil)
+
+	get := func(url, authorization string) int {
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, get("/user/1/event/5", ""))
+	require.Equal(t, http.StatusUnauthorized, get("/user/1/event/5", "Bearer "+expired))
+	require.Equal(t, http.StatusUnauthorized, get("/user/1/event/5", "Bearer garbage"))
+	require.Equal(t, http.StatusUnauthorized, get("/user/1/event/5", "Bearer "+stale))
+	require.Equal(t, http.StatusUnauthorized, get("/user/2/event/5", "Bearer "+deleted))
+	require.Equal(t, http.StatusInternalServerError, get("/user/3/event/5", "Bearer "+broken))
+	require.Equal(t, http.StatusForbidden, get("/user/2/event/5", "Bearer "+token))
+	require.Equal(t, http.StatusOK, get("/user/1/event/5", "Bearer "+token))
+	require.Equal(t, http.StatusOK, get("/", ""))
+}
+
+func TestRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := New(mockStorage, zap.NewNop(), nil)
+
+	register := func(body string) *httptest.ResponseRecorder {
+		request, err := http.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/accounts", strings.NewReader(body),
+		)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		eh.Register(response, request)
+		return response
+	}
+
+	mockStorage.EXPECT().CreateAccount(gomock.Any(), "new@gmail.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, hash string) (string, error) {
+			require.NoError(t, auth.CheckPassword(hash, "secret1"))
+			return "2", nil
+		})
+	mockStorage.EXPECT().CreateAccount(gomock.Any(), "user1@gmail.com", gomock.Any()).
+		Return("", storage.ErrLoginTaken)
+
+	response := register(`{"login": "new@gmail.com", "password": "secret1"}`)
+	require.Equal(t, http.StatusCreated, response.Code)
+	require.Contains(t, response.Body.String(), `"id":"2"`)
+
+	require.Equal(t, http.StatusConflict, register(`{"login": "user1@gmail.com", "password": "secret1"}`).Code)
+	require.Equal(t, http.StatusBadRequest, register(`{"login": "not-an-email", "password": "secret1"}`).Code)
+	require.Equal(t, http.StatusBadRequest, register(`{"login": "new@gmail.com", "password": "123"}`).Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := New(mockStorage, zap.NewNop(), nil)
+
+	hash, err := auth.HashPassword("user1")
+	require.NoError(t, err)
+	account := storage.Account{ID: "1", Login: "user1@gmail.com", PasswordHash: hash}
+
+	mockStorage.EXPECT().GetAccountByID(gomock.Any(), "1").Return(account, nil).Times(2)
+	mockStorage.EXPECT().UpdateAccountPassword(gomock.Any(), "1", gomock.Any()).Return(nil)
+
+	change := func(body string) int {
+		request, err := http.NewRequestWithContext(
+			userCtx, http.MethodPost, "/user/1/account/password", strings.NewReader(body),
+		)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		eh.ChangePassword(response, request)
+		return response.Code
+	}
+
+	require.Equal(t, http.StatusForbidden, change(`{"oldPassword": "wrong", "newPassword": "secret1"}`))
+	require.Equal(t, http.StatusNoContent, change(`{"oldPassword": "user1", "newPassword": "secret1"}`))
+	require.Equal(t, http.StatusBadRequest, change(`{"oldPassword": "user1"}`))
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := New(mockStorage, zap.NewNop(), nil)
+
+	mockStorage.EXPECT().DeleteAccountByID(gomock.Any(), "1").Return(nil)
+
+	request, err := http.NewRequestWithContext(userCtx, http.MethodDelete, "/user/1/account", nil)
+	require.NoError(t, err)
+	response := httptest.NewRecorder()
+	eh.DeleteAccount(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestSetChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := New(mockStorage, zap.NewNop(), nil)
+
+	mockStorage.EXPECT().SetAccountChannels(gomock.Any(), "1", []storage.NotificationChannel{
+		{Type: storage.ChannelEmail, Target: "user1@gmail.com"},
+		{Type: storage.ChannelWebhook, Target: "https://example.com/hook"},
+	}).Return(nil)
+
+	set := func(body string) int {
+		request, err := http.NewRequestWithContext(
+			userCtx, http.MethodPut, "/user/1/account/channels", strings.NewReader(body),
+		)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		eh.SetChannels(response, request)
+		return response.Code
+	}
+
+	require.Equal(t, http.StatusNoContent, set(`[
+		{"type": "email", "target": "user1@gmail.com"},
+		{"type": "webhook", "target": "https://example.com/hook"}
+	]`))
+	require.Equal(t, http.StatusBadRequest, set(`[{"type": "sms", "target": "+100"}]`))
+	require.Equal(t, http.StatusBadRequest, set(`{"type": "log"}`))
+}
+
+func TestSetTimeZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	m.EXPECT().SetAccountTimeZone(gomock.Any(), "1", "Europe/Moscow").Return(nil)
+
+	for body, want := range map[string]int{
+		`{"timeZone": "Europe/Moscow"}`: http.StatusNoContent,
+		`{"timeZone": "Moscow"}`:        http.StatusBadRequest,
+		`{"timeZone": ""}`:              http.StatusBadRequest,
+		`"Europe/Moscow"`:               http.StatusBadRequest,
+	} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/account/timezone",
+			strings.NewReader(body))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		eh.SetTimeZone(response, request)
+		require.Equal(t, want, response.Code, body)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	from := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	notified := false
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	want := storage.SearchQuery{
+		From: from, To: from.AddDate(0, 1, 0), Location: berlin, Text: "планёрка", Notified: &notified,
+		Sort: "-start", Limit: 20, Cursor: "abc",
+	}
+	m.EXPECT().SearchEvents(gomock.Any(), "1", want).Return(storage.SearchResult{
+		Events:     []storage.Event{{ID: "7", Title: "Планёрка"}},
+		NextCursor: "next",
+	}, nil)
+
+	reqURL := "/user/1/events/search?from=2025-09-01T00:00:00Z&to=2025-10-01T00:00:00Z" +
+		"&q=%D0%BF%D0%BB%D0%B0%D0%BD%D1%91%D1%80%D0%BA%D0%B0&notified=false&sort=-start&limit=20&cursor=abc&tz=Europe/Berlin"
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, reqURL, nil)
+	require.NoError(t, err)
+	response := httptest.NewRecorder()
+	eh.SearchEvents(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	var res storage.SearchResult
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&res))
+	require.Equal(t, "next", res.NextCursor)
+	require.Len(t, res.Events, 1)
+	require.Equal(t, "7", res.Events[0].ID)
+
+	for _, query := range []string{"from=yesterday", "to=2025-13-01T00:00:00Z", "notified=maybe", "limit=ten"} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/search?"+query, nil)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		eh.SearchEvents(response, request)
+		require.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+
+	m.EXPECT().GetAccountByID(gomock.Any(), "1").Return(storage.Account{ID: "1"}, nil)
+	m.EXPECT().SearchEvents(gomock.Any(), "1", gomock.Any()).Return(storage.SearchResult{}, storage.ErrInvalidCursor)
+	request, err = http.NewRequestWithContext(userCtx, http.MethodGet, "/user/1/events/search?cursor=broken", nil)
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	eh.SearchEvents(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestRespondToEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	m.EXPECT().RespondToEvent(gomock.Any(), "7", "1", storage.StatusAccepted).Return(nil)
+	m.EXPECT().RespondToEvent(gomock.Any(), "8", "1", storage.StatusDeclined).Return(storage.ErrNotInvited)
+
+	for _, tc := range []struct {
+		id   string
+		body string
+		want int
+	}{
+		{"7", `{"status": "accepted"}`, http.StatusNoContent},
+		{"8", `{"status": "declined"}`, http.StatusNotFound},
+		{"7", `{"status": "pending"}`, http.StatusBadRequest},
+		{"7", `accepted`, http.StatusBadRequest},
+	} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/event/"+tc.id+"/rsvp",
+			strings.NewReader(tc.body))
+		require.NoError(t, err)
+		request.SetPathValue("id", tc.id)
+
+		response := httptest.NewRecorder()
+		eh.RespondToEvent(response, request)
+		require.Equal(t, tc.want, response.Code, tc.body)
+	}
+}
+
+func TestAddEventWithInvalidAttendees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	m.EXPECT().AddEventByID(gomock.Any(), gomock.Any(), "1").Return("", storage.ErrInvalidAttendee)
+
+	for _, attendees := range []string{`["1"]`, `["2", "2"]`, `["404"]`} {
+		body := `{"title": "t", "dateStart": "2025-09-19T10:00:00Z", "dateEnd": "2025-09-19T11:00:00Z",
+			"attendees": ` + attendees + `}`
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(body))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		eh.AddEvent(response, request)
+		require.Equal(t, http.StatusBadRequest, response.Code, attendees)
+	}
+}
+
+func TestCalendarACL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	m.EXPECT().CreateCalendar(gomock.Any(), "Дежурства", "1").Return("5", nil)
+	m.EXPECT().SetCalendarACL(gomock.Any(), "5", storage.ACLEntry{UserID: "2", Role: storage.RoleEditor}, "1").
+		Return(nil)
+	m.EXPECT().SetCalendarACL(gomock.Any(), "6", gomock.Any(), "1").Return(storage.ErrForbidden)
+	m.EXPECT().SetCalendarACL(gomock.Any(), "7", gomock.Any(), "1").Return(storage.ErrCalendarNotFound)
+	m.EXPECT().RevokeCalendarAccess(gomock.Any(), "5", "2", "1").Return(nil)
+
+	request, err := http.NewRequestWithContext(userCtx, http.MethodPost, "/calendars",
+		strings.NewReader(`{"name": "Дежурства"}`))
+	require.NoError(t, err)
+	response := httptest.NewRecorder()
+	eh.CreateCalendar(response, request)
+	require.Equal(t, http.StatusCreated, response.Code)
+	require.Equal(t, `{"id":"5"}`, response.Body.String())
+
+	request, err = http.NewRequestWithContext(userCtx, http.MethodPost, "/calendars", strings.NewReader(`{"name": ""}`))
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	eh.CreateCalendar(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	for _, tc := range []struct {
+		calendarID string
+		body       string
+		want       int
+	}{
+		{"5", `{"userId": "2", "role": "editor"}`, http.StatusNoContent},
+		{"5", `{"userId": "2", "role": "owner"}`, http.StatusBadRequest},
+		{"6", `{"userId": "2", "role": "viewer"}`, http.StatusForbidden},
+		{"7", `{"userId": "2", "role": "viewer"}`, http.StatusNotFound},
+	} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/calendars/"+tc.calendarID+"/acl",
+			strings.NewReader(tc.body))
+		require.NoError(t, err)
+		request.SetPathValue("calendarid", tc.calendarID)
+
+		response := httptest.NewRecorder()
+		eh.SetCalendarACL(response, request)
+		require.Equal(t, tc.want, response.Code, tc.body)
+	}
+
+	request, err = http.NewRequestWithContext(userCtx, http.MethodDelete, "/calendars/5/acl/2", nil)
+	require.NoError(t, err)
+	request.SetPathValue("calendarid", "5")
+	request.SetPathValue("granteeid", "2")
+	response = httptest.NewRecorder()
+	eh.RevokeCalendarAccess(response, request)
+	require.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestCalendarEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	m.EXPECT().AddCalendarEvent(gomock.Any(), "5", gomock.Any(), "1").Return("12", nil)
+	m.EXPECT().AddCalendarEvent(gomock.Any(), "6", gomock.Any(), "1").Return("", storage.ErrForbidden)
+	m.EXPECT().GetCalendarEvent(gomock.Any(), "5", "12", "1").Return(storage.Event{ID: "12", CalendarID: "5"}, nil)
+	m.EXPECT().DeleteCalendarEvent(gomock.Any(), "5", "13", "1").Return(storage.ErrEventNotFound)
+
+	body := `{"title": "t", "dateStart": "2025-09-19T10:00:00Z", "dateEnd": "2025-09-19T11:00:00Z"}`
+	for calendarID, want := range map[string]int{"5": http.StatusCreated, "6": http.StatusForbidden} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/calendars/"+calendarID+"/event/",
+			strings.NewReader(body))
+		require.NoError(t, err)
+		request.SetPathValue("calendarid", calendarID)
+
+		response := httptest.NewRecorder()
+		eh.AddEvent(response, request)
+		require.Equal(t, want, response.Code, calendarID)
+	}
+
+	request, err := http.NewRequestWithContext(userCtx, http.MethodGet, "/calendars/5/event/12", nil)
+	require.NoError(t, err)
+	request.SetPathValue("calendarid", "5")
+	request.SetPathValue("id", "12")
+	response := httptest.NewRecorder()
+	eh.GetEventByID(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var e storage.Event
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &e))
+	require.Equal(t, "5", e.CalendarID)
+
+	request, err = http.NewRequestWithContext(userCtx, http.MethodDelete, "/calendars/5/event/13", nil)
+	require.NoError(t, err)
+	request.SetPathValue("calendarid", "5")
+	request.SetPathValue("id", "13")
+	response = httptest.NewRecorder()
+	eh.DeleteEventByID(response, request)
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// decodeProblem проверяет, что ответ - problem+json, и разбирает его.
+func decodeProblem(t *testing.T, response *httptest.ResponseRecorder) problem.Details {
+	t.Helper()
+
+	require.Equal(t, problem.ContentType, response.Header().Get("Content-Type"))
+	var p problem.Details
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &p))
+	require.Equal(t, response.Code, p.Status)
+	require.Equal(t, http.StatusText(p.Status), p.Title)
+	return p
+}
+
+func TestErrorProblems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorager(ctrl)
+	eh := &EventHandlers{
+		Storager: mockStorage,
+		Logg:     zap.NewNop(),
+	}
+	m := eh.Storager.(*mocks.MockStorager)
+
+	for _, tc := range []struct {
+		err   error
+		want  int
+		typ   string
+		found bool // текст ошибки попадает в detail
+	}{
+		{fmt.Errorf("%w: 5", storage.ErrEventNotFound), http.StatusNotFound, "urn:calendar:problem:not-found", true},
+		{fmt.Errorf("%w: editor role is required", storage.ErrForbidden), http.StatusForbidden,
+			"urn:calendar:problem:forbidden", true},
+		{fmt.Errorf("%w: duplicate user 2", storage.ErrInvalidAttendee), http.StatusBadRequest,
+			"urn:calendar:problem:validation", true},
+		{fmt.Errorf("%w: 3", storage.ErrOverlap), http.StatusConflict, "urn:calendar:problem:conflict", true},
+		{errors.New("pq: connection refused"), http.StatusInternalServerError, "about:blank", false},
+	} {
+		m.EXPECT().DeleteEventByID(gomock.Any(), "5", "1").Return(tc.err)
+
+		request, err := http.NewRequestWithContext(userCtx, http.MethodDelete, "/user/1/event/5", nil)
+		require.NoError(t, err)
+		request.SetPathValue("id", "5")
+
+		response := httptest.NewRecorder()
+		eh.DeleteEventByID(response, request)
+		require.Equal(t, tc.want, response.Code, tc.err)
+
+		p := decodeProblem(t, response)
+		require.Equal(t, tc.typ, p.Type)
+		require.Equal(t, "/user/1/event/5", p.Instance)
+		if tc.found {
+			require.Equal(t, tc.err.Error(), p.Detail)
+		} else {
+			require.Empty(t, p.Detail)
+		}
+	}
+}
+
+func TestAddEventEndBeforeStart(t *testing.T) {
+	eh := &EventHandlers{Logg: zap.NewNop()}
+
+	body := `{"title": "t", "dateStart": "2025-09-19T11:00:00Z", "dateEnd": "2025-09-19T10:00:00Z"}`
+	request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(body))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	eh.AddEvent(response, request)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	p := decodeProblem(t, response)
+	require.Equal(t, "urn:calendar:problem:validation", p.Type)
+	require.Contains(t, p.Detail, "is before start")
+
+	for _, body := range []string{`{`, `{"dateStart": "2025-09-19T10:00:00Z", "dateEnd": "2025-09-19T11:00:00Z"}`} {
+		request, err := http.NewRequestWithContext(userCtx, http.MethodPut, "/user/1/event/", strings.NewReader(body))
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		eh.AddEvent(response, request)
+		require.Equal(t, http.StatusBadRequest, response.Code, body)
+		require.Equal(t, "urn:calendar:problem:validation", decodeProblem(t, response).Type)
+	}
+}
+
+func TestUnauthenticatedProblem(t *testing.T) {
+	eh := &EventHandlers{Logg: zap.NewNop()}
+
+	request, err := http.NewRequest(http.MethodGet, "/calendars", nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	eh.GetCalendars(response, request)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+	require.Equal(t, "about:blank", decodeProblem(t, response).Type)
+
+	// ответы middleware аутентификации - тоже problem+json
+	router := NewRouter(New(nil, zap.NewNop(), auth.NewTokenManager("secret", time.Hour)), nil, zap.NewNop())
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+	require.Equal(t, "missing access token", decodeProblem(t, response).Detail)
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"time"
+)
+
+var (
+	ErrAccountNotFound = newError(ErrNotFound, "account not found")
+	ErrLoginTaken      = newError(ErrConflict, "login is already taken")
+)
+
+type Account struct {
+	ID           string    `json:"id"`
+	Login        string    `json:"login"`
+	PasswordHash string    `json:"-"` // bcrypt-хэш пароля, наружу не отдаётся;
+	CreatedAt    time.Time `json:"createdAt"`
+	// Каналы доставки напоминаний; пустой список - DefaultChannels.
+	Channels []NotificationChannel `json:"channels"`
+	// Зона IANA, в которой считаются дни, недели и месяцы списка событий.
+	TimeZone string `json:"timeZone"`
+	// Версия токенов доступа; растёт при смене пароля, и выпущенные раньше токены отзываются.
+	TokenVersion int64 `json:"-"`
+}
+
+type AccountCreateDTO struct {
+	Login    string `json:"login" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required,min=6,max=72"` // bcrypt учитывает только 72 байта
+}
+
+type PasswordChangeDTO struct {
+	OldPassword string `json:"oldPassword" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=6,max=72"`
+}
+
+type TimeZoneDTO struct {
+	TimeZone string `json:"timeZone"` // имя IANA, например "Europe/Moscow"
+}
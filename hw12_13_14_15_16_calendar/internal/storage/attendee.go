@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MaxAttendees - сколько пользователей можно пригласить на одно событие.
+const MaxAttendees = 100
+
+// AttendeeStatus - ответ приглашённого пользователя.
+type AttendeeStatus string
+
+const (
+	StatusPending   AttendeeStatus = "pending" // приглашение ещё без ответа
+	StatusAccepted  AttendeeStatus = "accepted"
+	StatusDeclined  AttendeeStatus = "declined"
+	StatusTentative AttendeeStatus = "tentative"
+)
+
+var (
+	ErrInvalidAttendee = newError(ErrValidation, "invalid attendee")
+	ErrInvalidStatus   = newError(ErrValidation, "invalid attendee status")
+	ErrNotInvited      = newError(ErrNotFound, "user is not invited to the event")
+)
+
+// Attendee - приглашённый на событие пользователь и его ответ.
+type Attendee struct {
+	UserID string         `json:"userId"`
+	Status AttendeeStatus `json:"status"`
+}
+
+// ResponseCounts - сколько приглашённых ответили тем или иным образом.
+type ResponseCounts struct {
+	Pending   int `json:"pending"`
+	Accepted  int `json:"accepted"`
+	Declined  int `json:"declined"`
+	Tentative int `json:"tentative"`
+}
+
+type RSVPDTO struct {
+	Status AttendeeStatus `json:"status"` // accepted, declined или tentative
+}
+
+// ValidateRSVP проверяет ответ на приглашение; вернуть приглашение в pending нельзя.
+func ValidateRSVP(status AttendeeStatus) error {
+	switch status {
+	case StatusAccepted, StatusDeclined, StatusTentative:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+}
+
+// ValidateAttendees проверяет список приглашённых события владельца ownerID:
+// владельца и повторы приглашать нельзя. Существование аккаунтов проверяет хранилище.
+func ValidateAttendees(ownerID string, userIDs []string) error {
+	if len(userIDs) > MaxAttendees {
+		return fmt.Errorf("%w: at most %d attendees are allowed", ErrInvalidAttendee, MaxAttendees)
+	}
+	for i, id := range userIDs {
+		switch {
+		case id == "":
+			return fmt.Errorf("%w: empty user id", ErrInvalidAttendee)
+		case id == ownerID:
+			return fmt.Errorf("%w: the owner cannot be invited", ErrInvalidAttendee)
+		case slices.Contains(userIDs[:i], id):
+			return fmt.Errorf("%w: duplicate user %s", ErrInvalidAttendee, id)
+		}
+	}
+	return nil
+}
+
+// MergeAttendees возвращает новый список приглашённых: у тех, кто был приглашён и раньше,
+// ответ сохраняется, новые получают pending. Порядок - как в userIDs.
+func MergeAttendees(old []Attendee, userIDs []string) []Attendee {
+	res := make([]Attendee, 0, len(userIDs))
+	for _, id := range userIDs {
+		a := Attendee{UserID: id, Status: StatusPending}
+		if i := slices.IndexFunc(old, func(o Attendee) bool { return o.UserID == id }); i >= 0 {
+			a.Status = old[i].Status
+		}
+		res = append(res, a)
+	}
+	return res
+}
+
+// CountResponses считает ответы приглашённых.
+func CountResponses(attendees []Attendee) ResponseCounts {
+	var c ResponseCounts
+	for _, a := range attendees {
+		switch a.Status {
+		case StatusAccepted:
+			c.Accepted++
+		case StatusDeclined:
+			c.Declined++
+		case StatusTentative:
+			c.Tentative++
+		default:
+			c.Pending++
+		}
+	}
+	return c
+}
+
+// Invited сообщает, приглашён ли пользователь на событие.
+func (e Event) Invited(userID string) bool {
+	return slices.ContainsFunc(e.Attendees, func(a Attendee) bool { return a.UserID == userID })
+}
+
+// Recipients возвращает, кому напоминать о событии: владельцу и приглашённым,
+// кроме отказавшихся.
+func (e Event) Recipients() []string {
+	res := []string{e.UserID}
+	for _, a := range e.Attendees {
+		if a.Status != StatusDeclined {
+			res = append(res, a.UserID)
+		}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestValidateAttendees(t *testing.T) {
+	require.NoError(t, ValidateAttendees("1", nil))
+	require.NoError(t, ValidateAttendees("1", []string{"2", "3"}))
+
+	for _, ids := range [][]string{{"1"}, {"2", "2"}, {""}} {
+		err := ValidateAttendees("1", ids)
+		require.True(t, errors.Is(err, ErrInvalidAttendee), ids)
+	}
+}
+
+func TestValidateRSVP(t *testing.T) {
+	require.NoError(t, ValidateRSVP(StatusAccepted))
+	require.NoError(t, ValidateRSVP(StatusTentative))
+	require.True(t, errors.Is(ValidateRSVP(StatusPending), ErrInvalidStatus))
+	require.True(t, errors.Is(ValidateRSVP("maybe"), ErrInvalidStatus))
+}
+
+func TestMergeAttendees(t *testing.T) {
+	old := []Attendee{{UserID: "2", Status: StatusAccepted}, {UserID: "3", Status: StatusDeclined}}
+
+	got := MergeAttendees(old, []string{"4", "2"})
+	require.Equal(t, []Attendee{{UserID: "4", Status: StatusPending}, {UserID: "2", Status: StatusAccepted}}, got)
+	require.Equal(t, ResponseCounts{Pending: 1, Accepted: 1}, CountResponses(got))
+}
+
+func TestEventRecipients(t *testing.T) {
+	e := Event{UserID: "1", Attendees: []Attendee{
+		{UserID: "2", Status: StatusAccepted},
+		{UserID: "3", Status: StatusDeclined},
+		{UserID: "4", Status: StatusPending},
+	}}
+
+	require.Equal(t, []string{"1", "2", "4"}, e.Recipients())
+	require.True(t, e.Invited("3"))
+	require.False(t, e.Invited("1"))
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"time"
+)
+
+// AuditOperation - операция над событием в журнале аудита.
+type AuditOperation string
+
+const (
+	AuditCreate  AuditOperation = "create"
+	AuditUpdate  AuditOperation = "update"
+	AuditDelete  AuditOperation = "delete"  // перенос в корзину
+	AuditRestore AuditOperation = "restore" // восстановление из корзины
+)
+
+// AuditEntry - запись журнала аудита: кто, когда и в каком запросе изменил событие.
+// Журнал только дополняется; записи остаются и после окончательного удаления события.
+type AuditEntry struct {
+	ID        string         `json:"id"`
+	EventID   string         `json:"eventId"`
+	ActorID   string         `json:"actorId"` // пользователь, выполнивший операцию
+	Operation AuditOperation `json:"operation"`
+	Before    *Event         `json:"before,omitempty"` // событие до операции; nil при создании
+	After     *Event         `json:"after"`
+	RequestID string         `json:"requestId"` // ID запроса HTTP/gRPC (см. requestid)
+	CreatedAt time.Time      `json:"createdAt"`
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+)
+
+// BatchMode - как пакетная операция обходится с ошибками отдельных событий.
+type BatchMode string
+
+const (
+	// BatchAtomic - всё или ничего: при ошибке любого события пакет не меняет ничего.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort - каждое событие обрабатывается отдельно, ошибка - в его результате.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// MaxBatchSize ограничивает число событий в одном пакете.
+const MaxBatchSize = 1000
+
+// BatchResult - результат одного события пакета.
+type BatchResult struct {
+	ID  string `json:"id"` // ID созданного, изменённого или удалённого события;
+	Err error  `json:"-"`  // nil - событие обработано.
+}
+
+// EventBatchUpdate - изменение одного события в пакете.
+type EventBatchUpdate struct {
+	ID    string         `json:"id"`
+	Event EventUpdateDTO `json:"event"`
+}
+
+// BatchItemError - ошибка события с номером Index, из-за которой не выполнен весь пакет
+// в режиме BatchAtomic. Вид ошибки (Kind) - как у Err.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("event %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error { return e.Err }
+
+// ValidateBatch проверяет режим и размер пакета из n событий.
+func ValidateBatch(mode BatchMode, n int) error {
+	if mode != BatchAtomic && mode != BatchBestEffort {
+		return Invalid("invalid batch mode %q: expected %s or %s", mode, BatchAtomic, BatchBestEffort)
+	}
+	if n == 0 {
+		return Invalid("empty batch")
+	}
+	if n > MaxBatchSize {
+		return Invalid("at most %d events are allowed in a batch", MaxBatchSize)
+	}
+	return nil
+}
+
+// ValidateEvent проверяет новое событие пользователя userID: время, правило повторения,
+// напоминания и приглашённых, как это делают обработчики одиночных запросов.
+func ValidateEvent(e EventCreateDTO, userID string) error {
+	if err := ValidateEventTime(e.Start, e.End); err != nil {
+		return err
+	}
+	if err := ValidateRRule(e.RRule); err != nil {
+		return err
+	}
+	if err := ValidateTimeZone(e.TimeZone); err != nil {
+		return err
+	}
+	if err := ValidateReminders(e.Reminders); err != nil {
+		return err
+	}
+	return ValidateAttendees(userID, e.Attendees)
+}
+
+// ValidateEventUpdate - ValidateEvent для изменения: проверяются только заданные поля.
+func ValidateEventUpdate(e EventUpdateDTO, userID string) error {
+	if e.Start != nil && e.End != nil {
+		if err := ValidateEventTime(*e.Start, *e.End); err != nil {
+			return err
+		}
+	}
+	if e.RRule != nil {
+		if err := ValidateRRule(*e.RRule); err != nil {
+			return err
+		}
+	}
+	if e.TimeZone != nil {
+		if err := ValidateTimeZone(*e.TimeZone); err != nil {
+			return err
+		}
+	}
+	if e.Reminders != nil {
+		if err := ValidateReminders(*e.Reminders); err != nil {
+			return err
+		}
+	}
+	if e.Attendees != nil {
+		return ValidateAttendees(userID, *e.Attendees)
+	}
+	return nil
+}
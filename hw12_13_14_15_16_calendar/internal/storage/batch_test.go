@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestValidateBatch(t *testing.T) {
+	require.NoError(t, ValidateBatch(BatchAtomic, 1))
+	require.NoError(t, ValidateBatch(BatchBestEffort, MaxBatchSize))
+
+	require.True(t, errors.Is(ValidateBatch("all", 1), ErrValidation))
+	require.True(t, errors.Is(ValidateBatch(BatchAtomic, 0), ErrValidation))
+	require.True(t, errors.Is(ValidateBatch(BatchAtomic, MaxBatchSize+1), ErrValidation))
+}
+
+func TestValidateEvent(t *testing.T) {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	require.NoError(t, ValidateEvent(EventCreateDTO{Start: start, End: end, RRule: "FREQ=DAILY"}, "1"))
+
+	for _, e := range []EventCreateDTO{
+		{Start: end, End: start},
+		{Start: start, End: end, RRule: "FREQ=HOURLY"},
+		{Start: start, End: end, Attendees: []string{"1"}},
+	} {
+		require.True(t, errors.Is(ValidateEvent(e, "1"), ErrValidation), e)
+	}
+
+	require.NoError(t, ValidateEventUpdate(EventUpdateDTO{Start: &start}, "1"))
+	require.True(t, errors.Is(ValidateEventUpdate(EventUpdateDTO{Start: &end, End: &start}, "1"), ErrValidation))
+}
+
+func TestBatchItemError(t *testing.T) {
+	err := error(&BatchItemError{Index: 2, Err: ErrOverlap})
+	require.Equal(t, "event 2: "+ErrOverlap.Error(), err.Error())
+	require.True(t, errors.Is(err, ErrConflict))
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PersonalCalendarName - название личного календаря, который есть у каждого аккаунта.
+// В нём лежат события, созданные через адреса /user/{userid}/...
+const PersonalCalendarName = "Personal"
+
+// Role - права аккаунта на календарь.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"  // всё, включая права других и удаление календаря
+	RoleEditor Role = "editor" // чтение и изменение событий
+	RoleViewer Role = "viewer" // только чтение событий
+)
+
+var (
+	ErrCalendarNotFound = newError(ErrNotFound, "calendar not found")
+	ErrInvalidCalendar  = newError(ErrValidation, "invalid calendar")
+	ErrInvalidRole      = newError(ErrValidation, "invalid role")
+	ErrEventNotFound    = newError(ErrNotFound, "event not found")
+)
+
+// Calendar - календарь, которому принадлежат события.
+type Calendar struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"ownerId"`
+	Personal  bool      `json:"personal"` // личный календарь владельца, удалить нельзя;
+	CreatedAt time.Time `json:"createdAt"`
+	Role      Role      `json:"role"` // права пользователя, запросившего календарь.
+}
+
+// ACLEntry - права одного аккаунта на календарь.
+type ACLEntry struct {
+	UserID string `json:"userId"`
+	Role   Role   `json:"role"`
+}
+
+type CalendarCreateDTO struct {
+	Name string `json:"name"`
+}
+
+// ValidateCalendarName проверяет название календаря.
+func ValidateCalendarName(name string) error {
+	if n := len(strings.TrimSpace(name)); n == 0 || n > 255 {
+		return fmt.Errorf("%w: name must be 1 to 255 bytes", ErrInvalidCalendar)
+	}
+	return nil
+}
+
+// ValidateGrant проверяет выдаваемые права: передать владение календарём нельзя.
+func ValidateGrant(entry ACLEntry) error {
+	if entry.UserID == "" {
+		return fmt.Errorf("%w: empty user id", ErrInvalidRole)
+	}
+	switch entry.Role {
+	case RoleEditor, RoleViewer:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q, expected editor or viewer", ErrInvalidRole, entry.Role)
+	}
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleOwner:
+		return 3
+	case RoleEditor:
+		return 2
+	case RoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CheckRole проверяет, хватает ли роли role для действия, требующего роли need.
+// Без доступа к календарю он считается несуществующим, чтобы не раскрывать чужие календари.
+func CheckRole(role Role, need Role) error {
+	switch {
+	case role.rank() == 0:
+		return ErrCalendarNotFound
+	case role.rank() < need.rank():
+		return fmt.Errorf("%w: %s role is required", ErrForbidden, need)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestCheckRole(t *testing.T) {
+	require.NoError(t, CheckRole(RoleOwner, RoleOwner))
+	require.NoError(t, CheckRole(RoleEditor, RoleViewer))
+	require.NoError(t, CheckRole(RoleViewer, RoleViewer))
+
+	require.True(t, errors.Is(CheckRole(RoleViewer, RoleEditor), ErrForbidden))
+	require.True(t, errors.Is(CheckRole(RoleEditor, RoleOwner), ErrForbidden))
+	// без доступа календарь считается несуществующим
+	require.True(t, errors.Is(CheckRole("", RoleViewer), ErrCalendarNotFound))
+	require.True(t, errors.Is(CheckRole("admin", RoleViewer), ErrCalendarNotFound))
+}
+
+func TestValidateGrant(t *testing.T) {
+	require.NoError(t, ValidateGrant(ACLEntry{UserID: "2", Role: RoleEditor}))
+	require.NoError(t, ValidateGrant(ACLEntry{UserID: "2", Role: RoleViewer}))
+
+	for _, e := range []ACLEntry{{UserID: "2", Role: RoleOwner}, {UserID: "2"}, {Role: RoleViewer}} {
+		require.True(t, errors.Is(ValidateGrant(e), ErrInvalidRole), e)
+	}
+	require.True(t, errors.Is(ValidateCalendarName(" "), ErrInvalidCalendar))
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeKind - что произошло с событием.
+type ChangeKind string
+
+const (
+	ChangeCreated  ChangeKind = "created"
+	ChangeUpdated  ChangeKind = "updated"
+	ChangeDeleted  ChangeKind = "deleted"
+	ChangeNotified ChangeKind = "notified" // отправлено напоминание
+)
+
+// changeBatch - сколько изменений FollowChanges читает за раз.
+const changeBatch = 100
+
+// Change - запись ленты изменений событий. Получатели записи - пользователи, которые
+// видели событие в момент изменения: участники его календаря и приглашённые.
+type Change struct {
+	// ID - токен продолжения: лента продолжается с изменений после него.
+	ID         string
+	Kind       ChangeKind
+	EventID    string
+	CalendarID string
+	ChangedAt  time.Time
+	// Event - текущее состояние события; nil, если его уже удалили.
+	Event *Event
+}
+
+// ChangeFeed - лента изменений событий хранилища.
+type ChangeFeed interface {
+	// ChangeToken возвращает токен текущего конца ленты.
+	ChangeToken(ctx context.Context) (string, error)
+	// GetChanges возвращает не больше limit изменений для пользователя после токена after.
+	GetChanges(ctx context.Context, userID string, after string, limit int) ([]Change, error)
+	// WaitForChanges возвращает канал, который закроется, когда в ленте появятся новые изменения.
+	WaitForChanges() <-chan struct{}
+}
+
+// ErrChangeTokenExpired - изменения после токена уже удалены по сроку хранения ленты:
+// продолжить ленту без пропусков нельзя, клиенту нужно заново прочитать события.
+var ErrChangeTokenExpired = newError(ErrConflict, "resume token expired, resync")
+
+// ChangePosition - место изменения в ленте. Tx - номер транзакции, записавшей изменение
+// (0 у хранилищ без транзакций), Seq - номер изменения. Лента упорядочена по (Tx, Seq):
+// так изменения транзакции, которая получила номера раньше, но завершилась позже,
+// не оказываются позади уже прочитанных.
+type ChangePosition struct {
+	Tx  int64
+	Seq int64
+}
+
+// String возвращает токен ленты: "Tx.Seq" или "Seq", если Tx нет.
+func (p ChangePosition) String() string {
+	if p.Tx == 0 {
+		return strconv.FormatInt(p.Seq, 10)
+	}
+	return strconv.FormatInt(p.Tx, 10) + "." + strconv.FormatInt(p.Seq, 10)
+}
+
+// Before сообщает, находится ли p в ленте раньше q.
+func (p ChangePosition) Before(q ChangePosition) bool {
+	return p.Tx < q.Tx || p.Tx == q.Tx && p.Seq < q.Seq
+}
+
+// ParseChangeToken разбирает токен ленты (см. ChangePosition.String).
+func ParseChangeToken(token string) (ChangePosition, error) {
+	txPart, seqPart, hasTx := strings.Cut(token, ".")
+	if !hasTx {
+		txPart, seqPart = "0", token
+	}
+	tx, errTx := strconv.ParseInt(txPart, 10, 64)
+	seq, errSeq := strconv.ParseInt(seqPart, 10, 64)
+	if errTx != nil || errSeq != nil || tx < 0 || seq < 0 || hasTx && tx == 0 {
+		return ChangePosition{}, Invalid("invalid resume token %q", token)
+	}
+	return ChangePosition{Tx: tx, Seq: seq}, nil
+}
+
+// FollowChanges передаёт в send изменения для пользователя после токена after, пока ctx
+// не отменён или send не вернёт ошибку. Пустой after - с текущего конца ленты.
+// send получает пачку изменений и токен, с которого продолжать после неё. Сначала и
+// каждый idle без изменений send вызывается с пустой пачкой: клиент узнаёт токен,
+// а соединение проверяется; заодно лента перечитывается, если сигнал о новых изменениях потерян.
+func FollowChanges(ctx context.Context, feed ChangeFeed, userID string, after string, idle time.Duration,
+	send func(changes []Change, token string) error,
+) error {
+	if after == "" {
+		var err error
+		if after, err = feed.ChangeToken(ctx); err != nil {
+			return err
+		}
+	} else if _, err := ParseChangeToken(after); err != nil {
+		return err
+	}
+	if err := send(nil, after); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+
+	for {
+		// канал берём до чтения, чтобы не пропустить изменение между чтением и ожиданием
+		wake := feed.WaitForChanges()
+		changes, err := feed.GetChanges(ctx, userID, after, changeBatch)
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			after = changes[len(changes)-1].ID
+			if err := send(changes, after); err != nil {
+				return err
+			}
+			timer.Reset(idle)
+			continue
+		}
+
+		select {
+		case <-wake:
+		case <-timer.C:
+			if err := send(nil, after); err != nil {
+				return err
+			}
+			timer.Reset(idle)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Broadcast будит всех, кто ждёт канала Wait, при каждом вызове Notify.
+// Нулевое значение готово к работе.
+type Broadcast struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// Wait возвращает канал, который закроется при следующем Notify.
+func (b *Broadcast) Wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ch == nil {
+		b.ch = make(chan struct{})
+	}
+	return b.ch
+}
+
+// Notify закрывает канал, выданный Wait.
+func (b *Broadcast) Notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ch != nil {
+		close(b.ch)
+		b.ch = nil
+	}
+}
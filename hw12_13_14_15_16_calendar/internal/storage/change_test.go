@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+// testFeed - лента в памяти, все изменения в которой видны любому пользователю.
+type testFeed struct {
+	mu      sync.Mutex
+	changes []Change
+	changed Broadcast
+}
+
+func (f *testFeed) add(kind ChangeKind) {
+	f.mu.Lock()
+	f.changes = append(f.changes, Change{ID: strconv.Itoa(len(f.changes) + 1), Kind: kind})
+	f.mu.Unlock()
+	f.changed.Notify()
+}
+
+func (f *testFeed) ChangeToken(_ context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return strconv.Itoa(len(f.changes)), nil
+}
+
+func (f *testFeed) GetChanges(_ context.Context, _ string, after string, limit int) ([]Change, error) {
+	pos, err := ParseChangeToken(after)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changes := f.changes[min(int(pos.Seq), len(f.changes)):]
+	return changes[:min(limit, len(changes))], nil
+}
+
+func (f *testFeed) WaitForChanges() <-chan struct{} {
+	return f.changed.Wait()
+}
+
+func TestParseChangeToken(t *testing.T) {
+	for token, want := range map[string]ChangePosition{
+		"42":      {Seq: 42},
+		"0":       {},
+		"1000.42": {Tx: 1000, Seq: 42},
+		"1000.0":  {Tx: 1000},
+	} {
+		pos, err := ParseChangeToken(token)
+		require.NoError(t, err, token)
+		require.Equal(t, want, pos, token)
+		require.Equal(t, token, pos.String())
+	}
+
+	for _, token := range []string{"", "abc", "-1", "1.", ".1", "0.5", "1.-5", "1.2.3"} {
+		_, err := ParseChangeToken(token)
+		require.True(t, errors.Is(err, ErrValidation), token)
+	}
+}
+
+func TestChangePositionBefore(t *testing.T) {
+	require.True(t, ChangePosition{Tx: 1, Seq: 9}.Before(ChangePosition{Tx: 2, Seq: 1}))
+	require.True(t, ChangePosition{Tx: 2, Seq: 1}.Before(ChangePosition{Tx: 2, Seq: 2}))
+	require.False(t, ChangePosition{Tx: 2, Seq: 2}.Before(ChangePosition{Tx: 2, Seq: 2}))
+	require.False(t, ChangePosition{Tx: 3}.Before(ChangePosition{Tx: 2, Seq: 100}))
+	require.True(t, errors.Is(ErrChangeTokenExpired, ErrConflict))
+}
+
+func TestFollowChanges(t *testing.T) {
+	feed := &testFeed{}
+	feed.add(ChangeCreated)
+
+	type batch struct {
+		kinds []ChangeKind
+		token string
+	}
+	batches := make(chan batch, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- FollowChanges(ctx, feed, "1", "", 50*time.Millisecond, func(changes []Change, token string) error {
+			var kinds []ChangeKind
+			for _, c := range changes {
+				kinds = append(kinds, c.Kind)
+			}
+			batches <- batch{kinds: kinds, token: token}
+			return nil
+		})
+	}()
+
+	// без токена лента начинается с текущего конца
+	require.Equal(t, batch{token: "1"}, <-batches)
+	feed.add(ChangeUpdated)
+	require.Equal(t, batch{kinds: []ChangeKind{ChangeUpdated}, token: "2"}, <-batches)
+	// без изменений приходит пустая пачка с тем же токеном
+	require.Equal(t, batch{token: "2"}, <-batches)
+
+	cancel()
+	require.True(t, errors.Is(<-done, context.Canceled))
+
+	// с токена лента отдаёт пропущенные изменения
+	err := FollowChanges(context.Background(), feed, "1", "0", time.Minute, func(changes []Change, token string) error {
+		if len(changes) == 0 {
+			return nil
+		}
+		require.Len(t, changes, 2)
+		require.Equal(t, "2", token)
+		return errors.New("stop")
+	})
+	require.Equal(t, "stop", err.Error())
+
+	err = FollowChanges(context.Background(), feed, "1", "abc", time.Minute, func([]Change, string) error { return nil })
+	require.True(t, errors.Is(err, ErrValidation))
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+)
+
+// Каналы доставки напоминаний.
+const (
+	ChannelEmail   = "email"   // письмо на Target
+	ChannelWebhook = "webhook" // POST JSON на URL из Target
+	ChannelLog     = "log"     // файл или stdout отправителя, Target не нужен
+)
+
+var ErrInvalidChannel = newError(ErrValidation, "invalid notification channel")
+
+// NotificationChannel - куда аккаунт получает напоминания.
+type NotificationChannel struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+}
+
+// DefaultChannels используются, если аккаунт не выбрал каналы.
+var DefaultChannels = []NotificationChannel{{Type: ChannelLog}}
+
+// ValidateChannels проверяет тип и адрес каждого канала.
+func ValidateChannels(channels []NotificationChannel) error {
+	for i, c := range channels {
+		switch c.Type {
+		case ChannelEmail:
+			if _, err := mail.ParseAddress(c.Target); err != nil {
+				return fmt.Errorf("%w: #%d: bad email %q", ErrInvalidChannel, i, c.Target)
+			}
+		case ChannelWebhook:
+			u, err := url.Parse(c.Target)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("%w: #%d: bad webhook url %q", ErrInvalidChannel, i, c.Target)
+			}
+		case ChannelLog:
+			if c.Target != "" {
+				return fmt.Errorf("%w: #%d: log channel takes no target", ErrInvalidChannel, i)
+			}
+		default:
+			return fmt.Errorf("%w: #%d: unknown type %q", ErrInvalidChannel, i, c.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestValidateChannels(t *testing.T) {
+	require.NoError(t, ValidateChannels(nil))
+	require.NoError(t, ValidateChannels([]NotificationChannel{
+		{Type: ChannelEmail, Target: "user1@gmail.com"},
+		{Type: ChannelWebhook, Target: "https://example.com/hook"},
+		{Type: ChannelLog},
+	}))
+
+	for _, bad := range []NotificationChannel{
+		{Type: ChannelEmail, Target: "not an email"},
+		{Type: ChannelWebhook, Target: "ftp://example.com"},
+		{Type: ChannelWebhook, Target: "/relative"},
+		{Type: ChannelLog, Target: "x"},
+		{Type: "sms", Target: "+100"},
+	} {
+		err := ValidateChannels([]NotificationChannel{bad})
+		require.True(t, errors.Is(err, ErrInvalidChannel), bad)
+	}
+}
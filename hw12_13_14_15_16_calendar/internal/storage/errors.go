@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Виды ошибок предметной области. Каждая ошибка хранилища и проверок запроса относится
+// к одному из них (errors.Is), а HTTP и gRPC переводят вид в код ответа.
+// Ошибки без вида - внутренние.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrForbidden  = errors.New("not enough permissions")
+	ErrValidation = errors.New("invalid request")
+	ErrConflict   = errors.New("conflict")
+)
+
+// kindError - ошибка со своим текстом, относящаяся к виду kind.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+func (e *kindError) Unwrap() error { return e.kind }
+
+// newError создаёт ошибку вида kind с текстом msg; текст вида в сообщение не попадает.
+func newError(kind error, msg string) error {
+	return &kindError{msg: msg, kind: kind}
+}
+
+// Invalid создаёт ошибку проверки запроса с текстом format.
+func Invalid(format string, args ...any) error {
+	return newError(ErrValidation, fmt.Sprintf(format, args...))
+}
+
+// Kind возвращает вид ошибки или nil, если ошибка внутренняя.
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrForbidden, ErrValidation, ErrConflict} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestKind(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		kind error
+	}{
+		{ErrEventNotFound, ErrNotFound},
+		{fmt.Errorf("%w: 7", ErrCalendarNotFound), ErrNotFound},
+		{ErrNotInvited, ErrNotFound},
+		{fmt.Errorf("%w: editor role is required", ErrForbidden), ErrForbidden},
+		{fmt.Errorf("%w: %q", ErrInvalidRRule, "FREQ=HOURLY"), ErrValidation},
+		{ErrInvalidTimeZone, ErrValidation},
+		{Invalid("limit must be positive"), ErrValidation},
+		{ErrOverlap, ErrConflict},
+		{ErrLoginTaken, ErrConflict},
+		{errors.New("connection refused"), nil},
+		{nil, nil},
+	} {
+		require.Equal(t, tc.kind, Kind(tc.err), tc.err)
+	}
+
+	// вид не меняет текст ошибки
+	require.Equal(t, "account not found", ErrAccountNotFound.Error())
+	require.Equal(t, "limit must be positive", Invalid("limit must be positive").Error())
+}
+
+func TestValidateEventTime(t *testing.T) {
+	start := time.Date(2025, 9, 19, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, ValidateEventTime(start, start))
+	require.NoError(t, ValidateEventTime(start, start.Add(1)))
+	require.True(t, errors.Is(ValidateEventTime(start, start.Add(-1)), ErrValidation))
+}
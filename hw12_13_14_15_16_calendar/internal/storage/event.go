@@ -1,51 +1,152 @@
 package storage
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+var ErrInvalidEvent = newError(ErrValidation, "invalid event")
+
+// ErrStaleVersion - событие изменили после того, как клиент прочитал ожидаемую версию.
+var ErrStaleVersion = newError(ErrConflict, "event has been modified")
+
+// ErrDuplicateUID - в календаре уже есть событие с таким UID iCalendar.
+var ErrDuplicateUID = newError(ErrConflict, "event with this UID already exists")
 
 type Event struct {
-	ID           string
-	Title        string    `json:"title" validate:"required,min=1"`
-	CreatedAt    time.Time `json:"createdAt"`
-	Start        time.Time `json:"dateStart" validate:"required"` // Дата и время события;
-	End          time.Time `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
-	Description  string    `json:"description"`                   // Описание события - длинный текст, опционально;
-	UserID       string    `json:"userId"`                        // ID пользователя, владельца события;
-	Notification time.Time `json:"notification"`
-	// (дата и время, когда высылать уведомление) За сколько времени высылать уведомление
-	Notified bool `json:"notified"`
+	ID          string
+	Title       string    `json:"title" validate:"required,min=1"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Start       time.Time `json:"dateStart" validate:"required"` // Дата и время события;
+	End         time.Time `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
+	Description string    `json:"description"`                   // Описание события - длинный текст, опционально;
+	UserID      string    `json:"userId"`                        // ID пользователя, автора события;
+	CalendarID  string    `json:"calendarId"`                    // ID календаря, которому принадлежит событие;
+	// Устарело: абсолютное время уведомления, используйте Reminders.
+	Notification time.Time      `json:"notification"`
+	Notified     bool           `json:"notified"`  // отправлено хотя бы одно напоминание
+	RRule        string         `json:"rrule"`     // Правило повторения (RFC 5545 RRULE), опционально;
+	ExDates      []time.Time    `json:"exDates"`   // Даты-исключения повторяющегося события, опционально.
+	Reminders    []Offset       `json:"reminders"` // За сколько до начала (каждого повторения) напомнить.
+	Attendees    []Attendee     `json:"attendees"` // Приглашённые пользователи и их ответы;
+	Responses    ResponseCounts `json:"responses"` // сколько приглашённых как ответили.
+	// Часовой пояс (IANA), в котором разворачивается правило повторения: BYDAY и время начала
+	// повторений считаются по местным часам этой зоны. По умолчанию - зона аккаунта автора.
+	TimeZone string `json:"timeZone"`
+	// UID iCalendar, с которым событие импортировано; уникален в календаре. Пустой - событие
+	// создано не импортом.
+	UID string `json:"uid,omitempty"`
+	// Версия события: растёт при каждом изменении через UpdateEvent. Ответы приглашённых
+	// и отправка напоминаний её не меняют.
+	Version int64 `json:"version"`
+	// Когда событие перенесено в корзину; nil - событие не удалено.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 type EventCreateDTO struct {
-	Title        string    `json:"title" validate:"required,min=1"`
-	Start        time.Time `json:"dateStart" validate:"required"` // Дата и время события;
-	End          time.Time `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
-	Description  string    `json:"description"`                   // Описание события - длинный текст, опционально;
-	Notification time.Time `json:"notification"`                  // За сколько времени высылать уведомление, опционально.
-	Notified     bool      `json:"notified"`
+	Title        string      `json:"title" validate:"required,min=1"`
+	Start        time.Time   `json:"dateStart" validate:"required"` // Дата и время события;
+	End          time.Time   `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
+	Description  string      `json:"description"`                   // Описание события - длинный текст, опционально;
+	Notification time.Time   `json:"notification"`                  // Устарело: используйте Reminders.
+	Notified     bool        `json:"notified"`
+	RRule        string      `json:"rrule"`   // Правило повторения (RFC 5545 RRULE), опционально;
+	ExDates      []time.Time `json:"exDates"` // Даты-исключения повторяющегося события, опционально.
+	// Смещения напоминаний до начала, например ["10m", "1d"]. Не заданы - берётся
+	// Notification или DefaultReminders; пустой список - без напоминаний.
+	Reminders []Offset `json:"reminders"`
+	// ID аккаунтов приглашённых пользователей, опционально.
+	Attendees []string `json:"attendees"`
+	// Часовой пояс правила повторения (IANA), опционально; по умолчанию - зона аккаунта.
+	TimeZone string `json:"timeZone"`
+	// UID iCalendar, опционально: второе событие с тем же UID в календарь не добавляется.
+	UID string `json:"uid"`
+	// Не сохраняется: отклонить событие, если оно пересекается с другими событиями пользователя.
+	RejectOverlap bool `json:"rejectOverlap"`
+}
+
+// ValidateTimeZone проверяет часовой пояс события; пустой - зона аккаунта.
+func ValidateTimeZone(name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := LoadLocation(name)
+	return err
+}
+
+// Location возвращает зону, в которой разворачивается правило повторения события.
+// Событие без зоны (или с неизвестной зоной) разворачивается в fallback.
+func (e Event) Location(fallback *time.Location) *time.Location {
+	if loc, err := LoadLocation(e.TimeZone); err == nil {
+		return loc
+	}
+	return fallback
+}
+
+// ValidateEventTime проверяет, что событие не заканчивается раньше, чем начинается.
+func ValidateEventTime(start, end time.Time) error {
+	if end.Before(start) {
+		return fmt.Errorf("%w: end %s is before start %s", ErrInvalidEvent,
+			end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return nil
 }
 
 type EventUpdateDTO struct {
-	Title        *string    `json:"title" validate:"required,min=1"`
-	Start        *time.Time `json:"dateStart" validate:"required"` // Дата и время события;
-	End          *time.Time `json:"dateEnd" validate:"required"`   // Длительность события (или дата и время окончания);
-	Description  *string    `json:"description"`                   // Описание события - длинный текст, опционально;
-	Notification *time.Time `json:"notification"`                  // За сколько времени высылать уведомление, опционально.
-	Notified     bool       `json:"notified"`
+	Title        *string      `json:"title" validate:"required,min=1"`
+	Start        *time.Time   `json:"dateStart" validate:"required"` // Дата и время события;
+	End          *time.Time   `json:"dateEnd" validate:"required"`   // Длительность события (или дата и время окончания);
+	Description  *string      `json:"description"`                   // Описание события - длинный текст, опционально;
+	Notification *time.Time   `json:"notification"`                  // Устарело: используйте Reminders.
+	Notified     bool         `json:"notified"`
+	RRule        *string      `json:"rrule"`     // Правило повторения (RFC 5545 RRULE), опционально;
+	ExDates      *[]time.Time `json:"exDates"`   // Даты-исключения повторяющегося события, опционально.
+	Reminders    *[]Offset    `json:"reminders"` // Заменяет напоминания; уже отправленные по оставшимся смещениям не повторяются.
+	Attendees    *[]string    `json:"attendees"` // Заменяет приглашённых; ответы оставшихся сохраняются.
+	// Часовой пояс правила повторения (IANA), опционально.
+	TimeZone *string `json:"timeZone"`
+	// Не сохраняется: отклонить изменение, если событие пересечётся с другими событиями пользователя.
+	RejectOverlap bool `json:"rejectOverlap"`
+	// Не сохраняется: версия, от которой клиент делал изменение; 0 - без проверки.
+	Version int64 `json:"version"`
+}
+
+// CheckVersion проверяет, что событие eventID версии version не менялось после версии
+// expected, которую прочитал клиент; expected 0 - без проверки.
+func CheckVersion(eventID string, version, expected int64) error {
+	if expected != 0 && expected != version {
+		return fmt.Errorf("%w: %s has version %d, not %d", ErrStaleVersion, eventID, version, expected)
+	}
+	return nil
 }
 
 type EventGetDTO struct {
-	ID           string    `json:"id" validate:"required,min=1"`
-	Title        string    `json:"title" validate:"required,min=1"`
-	Start        time.Time `json:"dateStart" validate:"required"` // Дата и время события;
-	End          time.Time `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
-	Description  string    `json:"description"`                   // Описание события - длинный текст, опционально;
-	Notification time.Time `json:"notification"`                  // За сколько времени высылать уведомление, опционально.
-	Notified     bool      `json:"notified"`
+	ID           string         `json:"id" validate:"required,min=1"`
+	Title        string         `json:"title" validate:"required,min=1"`
+	Start        time.Time      `json:"dateStart" validate:"required"` // Дата и время события;
+	End          time.Time      `json:"dateEnd" validate:"required"`   // дата и время окончания (Длительность события);
+	Description  string         `json:"description"`                   // Описание события - длинный текст, опционально;
+	Notification time.Time      `json:"notification"`                  // Устарело: используйте Reminders.
+	Notified     bool           `json:"notified"`
+	RRule        string         `json:"rrule"`     // Правило повторения (RFC 5545 RRULE), опционально;
+	ExDates      []time.Time    `json:"exDates"`   // Даты-исключения повторяющегося события, опционально.
+	Reminders    []Offset       `json:"reminders"` // За сколько до начала напомнить.
+	Attendees    []Attendee     `json:"attendees"`
+	Responses    ResponseCounts `json:"responses"`
+	Version      int64          `json:"version"`
 }
 
 type EventToNotify struct {
 	ID     string    `json:"id"`
 	Title  string    `json:"title"`
-	Start  time.Time `json:"dateStart"` // Дата и время события;
-	UserID string    `json:"userId" `
+	Start  time.Time `json:"dateStart"` // Дата и время события (для повторяющегося - повторения);
+	UserID string    `json:"userId" `   // кому напоминаем: владельцу или приглашённому;
+	// За сколько до начала это напоминание.
+	Reminder Offset `json:"reminder"`
+	// Ключ одного напоминания: повторная доставка того же сообщения приходит с тем же ключом.
+	IdempotencyKey string `json:"idempotencyKey"`
+	// Каналы аккаунта на момент постановки в очередь.
+	Channels []NotificationChannel `json:"channels,omitempty"`
+	// Контекст трассировки создания события (traceparent); в outbox хранится отдельно от тела.
+	TraceParent string `json:"-"`
 }
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"slices"
+	"time"
+)
+
+// OverlapHorizon - на сколько вперёд проверяются пересечения повторяющегося события.
+const OverlapHorizon = 365 * 24 * time.Hour
+
+var ErrOverlap = newError(ErrConflict, "event overlaps with existing events")
+
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type FreeBusy struct {
+	Busy []Interval `json:"busy"`
+	Free []Interval `json:"free"`
+}
+
+// OverlapWindow возвращает интервал, в котором нужно искать пересечения с событием e.
+func OverlapWindow(e Event) (time.Time, time.Time) {
+	if e.RRule == "" {
+		return e.Start, e.End
+	}
+	return e.Start, e.Start.Add(OverlapHorizon)
+}
+
+// FindOverlaps возвращает события из existing, пересекающиеся с e.
+// Событие с тем же ID (само e при обновлении) не учитывается.
+func FindOverlaps(e Event, existing []Event) ([]Event, error) {
+	from, to := OverlapWindow(e)
+
+	own, err := Occurrences(e, time.UTC, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Event
+	for _, x := range existing {
+		if x.ID == e.ID {
+			continue
+		}
+		// повторение, начавшееся до from, может ещё продолжаться
+		occ, err := Occurrences(x, time.UTC, from.Add(-x.End.Sub(x.Start)), to)
+		if err != nil {
+			return nil, err
+		}
+		if overlapping(own, occ) {
+			res = append(res, x)
+		}
+	}
+	return res, nil
+}
+
+func overlapping(a, b []Event) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Start.Before(y.End) && y.Start.Before(x.End) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetFreeBusy считает занятые интервалы пользователя в [from, to) и свободные промежутки
+// не короче minFree. events - все события, которые могут попасть в интервал.
+// Зона from - зона запроса: в ней разворачиваются события без своей зоны и возвращаются интервалы.
+func GetFreeBusy(events []Event, from, to time.Time, minFree time.Duration) (FreeBusy, error) {
+	busy := []Interval{}
+	for _, e := range events {
+		occ, err := Occurrences(e, from.Location(), from.Add(-e.End.Sub(e.Start)), to)
+		if err != nil {
+			return FreeBusy{}, err
+		}
+		for _, o := range occ {
+			if !o.End.After(from) {
+				continue
+			}
+			busy = append(busy, Interval{Start: maxTime(o.Start, from), End: minTime(o.End, to)})
+		}
+	}
+
+	slices.SortFunc(busy, func(a, b Interval) int { return a.Start.Compare(b.Start) })
+
+	merged := []Interval{}
+	for _, b := range busy {
+		if n := len(merged); n > 0 && !b.Start.After(merged[n-1].End) {
+			merged[n-1].End = maxTime(merged[n-1].End, b.End)
+			continue
+		}
+		merged = append(merged, b)
+	}
+
+	free := []Interval{}
+	cursor := from
+	for _, b := range merged {
+		if b.Start.Sub(cursor) >= minFree && b.Start.After(cursor) {
+			free = append(free, Interval{Start: cursor, End: b.Start})
+		}
+		cursor = maxTime(cursor, b.End)
+	}
+	if to.Sub(cursor) >= minFree && to.After(cursor) {
+		free = append(free, Interval{Start: cursor, End: to})
+	}
+
+	return FreeBusy{Busy: merged, Free: free}, nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
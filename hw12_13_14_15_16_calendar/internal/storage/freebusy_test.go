@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	// понедельник, 1 сентября 2025
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+	existing := []Event{
+		{ID: "1", Start: start, End: start.Add(time.Hour)},
+		{ID: "2", Start: start.Add(-48 * time.Hour), End: start.Add(-47 * time.Hour), RRule: "FREQ=WEEKLY;BYDAY=WE"},
+	}
+
+	overlaps, err := FindOverlaps(Event{ID: "new", Start: start.Add(30 * time.Minute), End: start.Add(2 * time.Hour)}, existing)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	require.Equal(t, "1", overlaps[0].ID)
+
+	// события, касающиеся границами, не пересекаются
+	overlaps, err = FindOverlaps(Event{ID: "new", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}, existing)
+	require.NoError(t, err)
+	require.Empty(t, overlaps)
+
+	// среда, 10 сентября - повторение события "2"
+	wednesday := start.AddDate(0, 0, 9)
+	overlaps, err = FindOverlaps(Event{ID: "new", Start: wednesday, End: wednesday.Add(time.Hour)}, existing)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	require.Equal(t, "2", overlaps[0].ID)
+
+	// повторяющееся новое событие пересекается с разовым
+	overlaps, err = FindOverlaps(Event{
+		ID: "new", Start: start.AddDate(0, 0, -7), End: start.AddDate(0, 0, -7).Add(time.Hour), RRule: "FREQ=WEEKLY",
+	}, existing)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	require.Equal(t, "1", overlaps[0].ID)
+
+	// при обновлении событие не пересекается само с собой
+	overlaps, err = FindOverlaps(existing[0], existing)
+	require.NoError(t, err)
+	require.Empty(t, overlaps)
+}
+
+func TestGetFreeBusy(t *testing.T) {
+	day := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	events := []Event{
+		{ID: "1", Start: at(9, 0), End: at(10, 0)},
+		{ID: "2", Start: at(9, 30), End: at(11, 0)},
+		{ID: "3", Start: at(11, 15), End: at(12, 0)},
+		{ID: "4", Start: at(7, 0), End: at(8, 30)},
+		{ID: "5", Start: at(16, 0), End: at(16, 30), RRule: "FREQ=DAILY"},
+	}
+
+	fb, err := GetFreeBusy(events, at(8, 0), at(18, 0), 30*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []Interval{
+		{Start: at(8, 0), End: at(8, 30)},
+		{Start: at(9, 0), End: at(11, 0)},
+		{Start: at(11, 15), End: at(12, 0)},
+		{Start: at(16, 0), End: at(16, 30)},
+	}, fb.Busy)
+	// промежуток 11:00-11:15 короче minFree
+	require.Equal(t, []Interval{
+		{Start: at(8, 30), End: at(9, 0)},
+		{Start: at(12, 0), End: at(16, 0)},
+		{Start: at(16, 30), End: at(18, 0)},
+	}, fb.Free)
+
+	fb, err = GetFreeBusy(nil, at(8, 0), at(9, 0), 0)
+	require.NoError(t, err)
+	require.Empty(t, fb.Busy)
+	require.Equal(t, []Interval{{Start: at(8, 0), End: at(9, 0)}}, fb.Free)
+}
+
+func TestGetFreeBusyInRequestZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// ежедневно в 09:00 по Берлину, время записано в UTC
+	start := time.Date(2026, time.March, 27, 8, 0, 0, 0, time.UTC)
+	events := []Event{{ID: "1", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY", TimeZone: "Europe/Berlin"}}
+
+	// после перехода на летнее время 09:00 по Берлину - это 07:00 UTC
+	from := time.Date(2026, time.March, 30, 0, 0, 0, 0, berlin)
+	fb, err := GetFreeBusy(events, from, from.AddDate(0, 0, 1), 0)
+	require.NoError(t, err)
+	require.Len(t, fb.Busy, 1)
+	require.Equal(t, time.Date(2026, time.March, 30, 9, 0, 0, 0, berlin), fb.Busy[0].Start)
+	require.Equal(t, berlin, fb.Busy[0].Start.Location())
+}
@@ -0,0 +1,60 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// recordAudit вызывается под блокировкой s.mu на запись: дописывает операцию пользователя
+// actorID в журнал аудита. before - событие до операции, nil при создании.
+func (s *Storage) recordAudit(ctx context.Context, op storage.AuditOperation, actorID string,
+	before *storage.Event, after storage.Event,
+) {
+	s.auditSeq++
+	s.Audit = append(s.Audit, storage.AuditEntry{
+		ID:        strconv.FormatInt(s.auditSeq, 10),
+		EventID:   after.ID,
+		ActorID:   actorID,
+		Operation: op,
+		Before:    before,
+		After:     &after,
+		RequestID: requestid.FromContext(ctx),
+		CreatedAt: time.Now(),
+	})
+}
+
+// GetEventHistory возвращает журнал аудита события календаря, старые записи - первыми;
+// пустой calendarID - личный календарь пользователя. История события в корзине тоже доступна.
+func (s *Storage) GetEventHistory(_ context.Context, calendarID string, id string,
+	userID string,
+) ([]storage.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if calendarID == "" {
+		calendarID = s.personalCalendar(userID)
+	} else if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	e, ok := s.Events[id]
+	if !ok {
+		e, ok = s.Trash[id]
+	}
+	if !ok || e.CalendarID != calendarID {
+		return nil, fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
+	}
+
+	entries := []storage.AuditEntry{}
+	for _, a := range s.Audit {
+		if a.EventID == id {
+			entries = append(entries, a)
+		}
+	}
+	return entries, nil
+}
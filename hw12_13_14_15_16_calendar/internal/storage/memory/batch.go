@@ -0,0 +1,133 @@
+package memorystorage
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// AddEvents добавляет пакет событий в календарь; пустой calendarID - личный календарь пользователя.
+func (s *Storage) AddEvents(ctx context.Context, calendarID string, events []storage.EventCreateDTO,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(events)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if calendarID == "" {
+		calendarID = s.ensurePersonalCalendar(userID)
+	} else if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleEditor); err != nil {
+		return nil, err
+	}
+	return s.runBatch(mode, len(events), func(i int) (string, error) {
+		if err := storage.ValidateEvent(events[i], userID); err != nil {
+			return "", err
+		}
+		return s.addEvent(ctx, calendarID, events[i], userID)
+	})
+}
+
+// UpdateEvents меняет пакет событий календаря; пустой calendarID - личный календарь пользователя.
+func (s *Storage) UpdateEvents(ctx context.Context, calendarID string, updates []storage.EventBatchUpdate,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(updates)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendarID, err := s.editorCalendar(calendarID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.runBatch(mode, len(updates), func(i int) (string, error) {
+		u := updates[i]
+		if err := storage.ValidateEventUpdate(u.Event, userID); err != nil {
+			return u.ID, err
+		}
+		return u.ID, s.updateEvent(ctx, calendarID, u.ID, u.Event, userID)
+	})
+}
+
+// DeleteEventsByID удаляет пакет событий календаря; пустой calendarID - личный календарь пользователя.
+func (s *Storage) DeleteEventsByID(ctx context.Context, calendarID string, ids []string,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(ids)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendarID, err := s.editorCalendar(calendarID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.runBatch(mode, len(ids), func(i int) (string, error) {
+		return ids[i], s.deleteEvent(ctx, calendarID, ids[i], userID)
+	})
+}
+
+// editorCalendar вызывается под блокировкой s.mu: календарь, события которого меняются,
+// если у пользователя есть права editor; пустой calendarID - личный календарь.
+func (s *Storage) editorCalendar(calendarID string, userID string) (string, error) {
+	if calendarID == "" {
+		return s.personalCalendar(userID), nil
+	}
+	return calendarID, storage.CheckRole(s.role(calendarID, userID), storage.RoleEditor)
+}
+
+// runBatch вызывается под блокировкой s.mu на запись: выполняет do для каждого из n событий.
+// В режиме BatchAtomic первая ошибка возвращает хранилище к состоянию до пакета.
+func (s *Storage) runBatch(mode storage.BatchMode, n int,
+	do func(i int) (string, error),
+) ([]storage.BatchResult, error) {
+	var restore func()
+	if mode == storage.BatchAtomic {
+		restore = s.snapshot()
+	}
+
+	results := make([]storage.BatchResult, 0, n)
+	for i := range n {
+		id, err := do(i)
+		if err != nil && mode == storage.BatchAtomic {
+			restore()
+			return nil, &storage.BatchItemError{Index: i, Err: err}
+		}
+		results = append(results, storage.BatchResult{ID: id, Err: err})
+	}
+	return results, nil
+}
+
+// snapshot вызывается под блокировкой s.mu на запись: запоминает события, корзину, отметки
+// напоминаний, контексты трассировки, ленту изменений и журнал аудита и возвращает функцию,
+// которая их восстанавливает.
+func (s *Storage) snapshot() func() {
+	events, trash := maps.Clone(s.Events), maps.Clone(s.Trash)
+	fired := make(map[string]map[storage.Offset]time.Time, len(s.Fired))
+	for id, offsets := range s.Fired {
+		fired[id] = maps.Clone(offsets)
+	}
+	traceParents := maps.Clone(s.TraceParents)
+	changes, changeSeq := len(s.Changes), s.changeSeq
+	audit, auditSeq := len(s.Audit), s.auditSeq
+
+	return func() {
+		s.Events = events
+		s.Trash = trash
+		s.Fired = fired
+		s.TraceParents = traceParents
+		s.Changes = s.Changes[:changes]
+		s.changeSeq = changeSeq
+		s.Audit = s.Audit[:audit]
+		s.auditSeq = auditSeq
+	}
+}
@@ -0,0 +1,252 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/google/uuid"
+)
+
+// personalCalendar вызывается под блокировкой s.mu: ID личного календаря пользователя
+// или пустая строка, если его ещё нет.
+func (s *Storage) personalCalendar(userID string) string {
+	for id, c := range s.Calendars {
+		if c.Personal && c.OwnerID == userID {
+			return id
+		}
+	}
+	return ""
+}
+
+// ensurePersonalCalendar вызывается под блокировкой s.mu на запись. Как и раньше,
+// memorystorage не требует аккаунта для событий пользователя: личный календарь
+// создаётся при первом событии.
+func (s *Storage) ensurePersonalCalendar(userID string) string {
+	if id := s.personalCalendar(userID); id != "" {
+		return id
+	}
+	return s.addCalendar(storage.PersonalCalendarName, userID, true)
+}
+
+// addCalendar вызывается под блокировкой s.mu на запись.
+func (s *Storage) addCalendar(name string, ownerID string, personal bool) string {
+	id := uuid.New().String()
+	s.Calendars[id] = storage.Calendar{
+		ID:        id,
+		Name:      name,
+		OwnerID:   ownerID,
+		Personal:  personal,
+		CreatedAt: time.Now(),
+	}
+	s.ACL[id] = map[string]storage.Role{ownerID: storage.RoleOwner}
+	return id
+}
+
+// deleteCalendar вызывается под блокировкой s.mu на запись: удаляет календарь с событиями и корзиной.
+func (s *Storage) deleteCalendar(calendarID string) {
+	for eventID, e := range s.Events {
+		if e.CalendarID == calendarID {
+			s.recordChange(storage.ChangeDeleted, e)
+			delete(s.Events, eventID)
+			delete(s.Fired, eventID)
+			delete(s.TraceParents, eventID)
+		}
+	}
+	for eventID, e := range s.Trash {
+		if e.CalendarID == calendarID {
+			delete(s.Trash, eventID)
+			delete(s.Fired, eventID)
+			delete(s.TraceParents, eventID)
+		}
+	}
+	delete(s.ACL, calendarID)
+	delete(s.Calendars, calendarID)
+}
+
+// role вызывается под блокировкой s.mu: права пользователя на календарь.
+func (s *Storage) role(calendarID string, userID string) storage.Role {
+	return s.ACL[calendarID][userID]
+}
+
+// visibleTo вызывается под блокировкой s.mu: видит ли пользователь событие по адресам
+// /user/{userid}/... - из своего личного календаря или по приглашению.
+func (s *Storage) visibleTo(userID string) func(storage.Event) bool {
+	personal := s.personalCalendar(userID)
+	return func(e storage.Event) bool {
+		return (personal != "" && e.CalendarID == personal) || e.Invited(userID)
+	}
+}
+
+func (s *Storage) CreateCalendar(_ context.Context, name string, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addCalendar(name, userID, false), nil
+}
+
+// GetCalendars возвращает календари, к которым у пользователя есть доступ: личный первым.
+func (s *Storage) GetCalendars(_ context.Context, userID string) ([]storage.Calendar, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []storage.Calendar{}
+	for id, c := range s.Calendars {
+		if role := s.role(id, userID); role != "" {
+			c.Role = role
+			result = append(result, c)
+		}
+	}
+	slices.SortFunc(result, func(a, b storage.Calendar) int {
+		switch {
+		case a.Personal != b.Personal && a.Personal:
+			return -1
+		case a.Personal != b.Personal:
+			return 1
+		case !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			return strings.Compare(a.ID, b.ID)
+		}
+	})
+	return result, nil
+}
+
+// DeleteCalendar удаляет календарь вместе с событиями. Личный календарь удалить нельзя.
+func (s *Storage) DeleteCalendar(_ context.Context, calendarID string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleOwner); err != nil {
+		return err
+	}
+	if s.Calendars[calendarID].Personal {
+		return fmt.Errorf("%w: personal calendar cannot be deleted", storage.ErrForbidden)
+	}
+	s.deleteCalendar(calendarID)
+	return nil
+}
+
+// GetCalendarACL возвращает права на календарь: владельца первым, остальных по ID.
+func (s *Storage) GetCalendarACL(_ context.Context, calendarID string, userID string) ([]storage.ACLEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	result := []storage.ACLEntry{}
+	for id, role := range s.ACL[calendarID] {
+		result = append(result, storage.ACLEntry{UserID: id, Role: role})
+	}
+	slices.SortFunc(result, func(a, b storage.ACLEntry) int {
+		switch {
+		case a.Role == storage.RoleOwner:
+			return -1
+		case b.Role == storage.RoleOwner:
+			return 1
+		default:
+			return strings.Compare(a.UserID, b.UserID)
+		}
+	})
+	return result, nil
+}
+
+// SetCalendarACL выдаёт аккаунту права editor или viewer либо меняет их.
+func (s *Storage) SetCalendarACL(_ context.Context, calendarID string, entry storage.ACLEntry, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleOwner); err != nil {
+		return err
+	}
+	if entry.UserID == s.Calendars[calendarID].OwnerID {
+		return fmt.Errorf("%w: owner's role cannot be changed", storage.ErrInvalidRole)
+	}
+	if _, ok := s.Accounts[entry.UserID]; !ok {
+		return storage.ErrAccountNotFound
+	}
+	s.ACL[calendarID][entry.UserID] = entry.Role
+	return nil
+}
+
+// RevokeCalendarAccess отзывает права аккаунта на календарь.
+func (s *Storage) RevokeCalendarAccess(_ context.Context, calendarID string, granteeID string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleOwner); err != nil {
+		return err
+	}
+	if granteeID == s.Calendars[calendarID].OwnerID {
+		return fmt.Errorf("%w: owner's role cannot be changed", storage.ErrInvalidRole)
+	}
+	delete(s.ACL[calendarID], granteeID)
+	return nil
+}
+
+func (s *Storage) AddCalendarEvent(ctx context.Context, calendarID string,
+	ec storage.EventCreateDTO, userID string,
+) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleEditor); err != nil {
+		return "", err
+	}
+	return s.addEvent(ctx, calendarID, ec, userID)
+}
+
+func (s *Storage) UpdateCalendarEvent(ctx context.Context, calendarID string, id string,
+	event storage.EventUpdateDTO, userID string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleEditor); err != nil {
+		return err
+	}
+	return s.updateEvent(ctx, calendarID, id, event, userID)
+}
+
+func (s *Storage) DeleteCalendarEvent(ctx context.Context, calendarID string, id string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleEditor); err != nil {
+		return err
+	}
+	return s.deleteEvent(ctx, calendarID, id, userID)
+}
+
+func (s *Storage) GetCalendarEvent(_ context.Context, calendarID string, id string, userID string,
+) (storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleViewer); err != nil {
+		return storage.Event{}, err
+	}
+	event, ok := s.Events[id]
+	if !ok || event.CalendarID != calendarID {
+		return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
+	}
+	return event, nil
+}
+
+// GetCalendarEventListing - список событий календаря на день/неделю/месяц, как GetEventListingByUserID.
+func (s *Storage) GetCalendarEventListing(_ context.Context, calendarID string,
+	date time.Time, period string, userID string,
+) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.listing(func(e storage.Event) bool { return e.CalendarID == calendarID }, date, period)
+}
@@ -0,0 +1,89 @@
+package memorystorage
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// recordChange вызывается под блокировкой s.mu на запись: добавляет изменение события в ленту.
+// Получатели - участники календаря события, его приглашённые и also.
+func (s *Storage) recordChange(kind storage.ChangeKind, e storage.Event, also ...string) {
+	recipients := slices.Collect(maps.Keys(s.ACL[e.CalendarID]))
+	recipients = append(recipients, attendeeIDs(e.Attendees)...)
+	recipients = append(recipients, also...)
+	slices.Sort(recipients)
+
+	s.changeSeq++
+	s.Changes = append(s.Changes, ChangeEntry{
+		Change: storage.Change{
+			ID:         strconv.FormatInt(s.changeSeq, 10),
+			Kind:       kind,
+			EventID:    e.ID,
+			CalendarID: e.CalendarID,
+			ChangedAt:  time.Now(),
+		},
+		Seq:        s.changeSeq,
+		Recipients: slices.Compact(recipients),
+	})
+	s.changed.Notify()
+}
+
+func attendeeIDs(attendees []storage.Attendee) []string {
+	ids := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		ids = append(ids, a.UserID)
+	}
+	return ids
+}
+
+// ChangeToken возвращает токен текущего конца ленты.
+func (s *Storage) ChangeToken(_ context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return strconv.FormatInt(s.changeSeq, 10), nil
+}
+
+// GetChanges возвращает не больше limit изменений для пользователя после токена after
+// с текущим состоянием событий. Если изменения после after уже удалены по сроку хранения,
+// возвращает storage.ErrChangeTokenExpired.
+func (s *Storage) GetChanges(_ context.Context, userID string, after string, limit int) ([]storage.Change, error) {
+	pos, err := storage.ParseChangeToken(after)
+	if err != nil {
+		return nil, err
+	}
+	seq := pos.Seq
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if seq < s.changeHorizon {
+		return nil, storage.ErrChangeTokenExpired
+	}
+
+	var result []storage.Change
+	for _, c := range s.Changes {
+		if len(result) == limit {
+			break
+		}
+		if c.Seq <= seq || !slices.Contains(c.Recipients, userID) {
+			continue
+		}
+		change := c.Change
+		if e, ok := s.Events[c.EventID]; ok {
+			change.Event = &e
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// WaitForChanges возвращает канал, который закроется при следующем изменении в ленте.
+func (s *Storage) WaitForChanges() <-chan struct{} {
+	return s.changed.Wait()
+}
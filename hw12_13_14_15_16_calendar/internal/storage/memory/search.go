@@ -0,0 +1,109 @@
+package memorystorage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// SearchEvents ищет события пользователя или календаря q.CalendarID, как sqlstorage: фильтры, сортировка
+// с ID для одинаковых ключей и курсор, указывающий на последнее событие страницы.
+func (s *Storage) SearchEvents(_ context.Context, userID string, q storage.SearchQuery) (storage.SearchResult, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return storage.SearchResult{}, err
+	}
+	field, desc, _ := q.SortField()
+	cursor, hasCursor, err := q.DecodeCursor()
+	if err != nil {
+		return storage.SearchResult{}, err
+	}
+	var cursorKey any
+	if hasCursor {
+		if cursorKey, err = storage.ParseSortKey(field, cursor.Key); err != nil {
+			return storage.SearchResult{}, err
+		}
+	}
+
+	// order > 0, если событие идёт в выдаче после ключа key с ID id
+	order := func(e storage.Event, key any, id string) int {
+		c := compareKey(e, field, key)
+		if c == 0 {
+			c = strings.Compare(e.ID, id)
+		}
+		if desc {
+			c = -c
+		}
+		return c
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	match := s.visibleTo(userID)
+	if q.CalendarID != "" {
+		if err := storage.CheckRole(s.role(q.CalendarID, userID), storage.RoleViewer); err != nil {
+			return storage.SearchResult{}, err
+		}
+		match = func(e storage.Event) bool { return e.CalendarID == q.CalendarID }
+	}
+
+	found := []storage.Event{}
+	for _, e := range s.Events {
+		if !match(e) {
+			continue
+		}
+		if q.Notified != nil && e.Notified != *q.Notified {
+			continue
+		}
+		if q.Text != "" && !storage.MatchText(e, q.Text) {
+			continue
+		}
+		if hasCursor && order(e, cursorKey, cursor.ID) <= 0 {
+			continue
+		}
+		ok, err := storage.InRange(e, q.Location, q.From, q.To)
+		if err != nil {
+			return storage.SearchResult{}, err
+		}
+		if ok {
+			found = append(found, e)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b storage.Event) int {
+		return order(a, sortValue(b, field), b.ID)
+	})
+
+	res := storage.SearchResult{Events: found}
+	if len(found) > q.Limit {
+		res.Events = found[:q.Limit]
+		res.NextCursor = q.CursorAfter(res.Events[q.Limit-1])
+	}
+	return res, nil
+}
+
+func sortValue(e storage.Event, field string) any {
+	switch field {
+	case storage.SortCreated:
+		return e.CreatedAt
+	case storage.SortTitle:
+		return e.Title
+	default:
+		return e.Start
+	}
+}
+
+func compareKey(e storage.Event, field string, key any) int {
+	switch k := key.(type) {
+	case string:
+		return strings.Compare(e.Title, k)
+	case time.Time:
+		return sortValue(e, field).(time.Time).Compare(k)
+	default:
+		return 0
+	}
+}
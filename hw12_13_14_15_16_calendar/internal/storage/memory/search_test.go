@@ -0,0 +1,74 @@
+package memorystorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+)
+
+func TestSearchEvents(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+
+	titles := []string{"Ретро", "Планёрка", "Демо", "Планёрка команды", "Обед"}
+	ids := make([]string, len(titles))
+	for i, title := range titles {
+		s := start.AddDate(0, 0, i)
+		id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+			Title: title, Start: s, End: s.Add(time.Hour), Description: "спринт 42",
+		}, "1")
+		require.NoError(t, err)
+		ids[i] = id
+	}
+	_, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "Планёрка", Start: start, End: start.Add(time.Hour)}, "2")
+	require.NoError(t, err)
+	// серия по пятницам, начавшаяся раньше, повторяется 5 сентября - внутри периода поиска
+	series, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Стендап", Start: start.AddDate(0, -1, 0), End: start.AddDate(0, -1, 0).Add(15 * time.Minute),
+		RRule: "FREQ=WEEKLY",
+	}, "1")
+	require.NoError(t, err)
+
+	// постранично по возрастанию начала
+	q := storage.SearchQuery{From: start, To: start.AddDate(0, 0, 5), Limit: 2}
+	var got []string
+	for page := 0; ; page++ {
+		require.True(t, page < 5)
+		res, err := store.SearchEvents(ctx, "1", q)
+		require.NoError(t, err)
+		for _, e := range res.Events {
+			got = append(got, e.ID)
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		q.Cursor = res.NextCursor
+	}
+	require.Equal(t, []string{series, ids[0], ids[1], ids[2], ids[3], ids[4]}, got)
+
+	// текст, признак отправки и сортировка по названию по убыванию
+	res, err := store.SearchEvents(ctx, "1", storage.SearchQuery{Text: "планёрка", Sort: "-title"})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 2)
+	require.Equal(t, ids[3], res.Events[0].ID)
+	require.Equal(t, ids[1], res.Events[1].ID)
+
+	notified := true
+	res, err = store.SearchEvents(ctx, "1", storage.SearchQuery{Text: "спринт", Notified: &notified})
+	require.NoError(t, err)
+	require.Empty(t, res.Events)
+	notified = false
+	res, err = store.SearchEvents(ctx, "1", storage.SearchQuery{Text: "спринт", Notified: &notified})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 5)
+
+	_, err = store.SearchEvents(ctx, "1", storage.SearchQuery{Sort: storage.SortTitle, Cursor: q.Cursor})
+	require.True(t, errors.Is(err, storage.ErrInvalidCursor))
+	_, err = store.SearchEvents(ctx, "1", storage.SearchQuery{Sort: "length"})
+	require.True(t, errors.Is(err, storage.ErrInvalidSearch))
+}
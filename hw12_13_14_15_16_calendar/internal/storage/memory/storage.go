@@ -2,30 +2,102 @@ package memorystorage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 type Storage struct {
 	Events map[string]storage.Event
-	mu     sync.RWMutex
+	// Trash - удалённые события, которые ещё можно восстановить.
+	Trash     map[string]storage.Event
+	Accounts  map[string]storage.Account
+	Calendars map[string]storage.Calendar
+	// ACL - права на календари по ID календаря и ID аккаунта.
+	ACL    map[string]map[string]storage.Role
+	Outbox []OutboxEntry
+	// Fired - начало повторения, о котором уже напомнили, по ID события и смещению напоминания.
+	Fired map[string]map[storage.Offset]time.Time
+	// TraceParents - контекст трассировки создания события по его ID (как event.trace_parent).
+	TraceParents map[string]string
+	// Changes - лента изменений событий в порядке записи.
+	Changes   []ChangeEntry
+	changeSeq int64
+	// changeHorizon - номер последнего изменения, удалённого из ленты по сроку хранения.
+	changeHorizon int64
+	// Audit - журнал аудита изменений событий в порядке записи.
+	Audit    []storage.AuditEntry
+	auditSeq int64
+	changed  storage.Broadcast
+	mu       sync.RWMutex
+	Logg     *zap.Logger
+}
+
+type OutboxEntry struct {
+	storage.OutboxMessage
+	// DeliveredAt - когда сообщение опубликовано; нулевое - ещё не опубликовано.
+	DeliveredAt time.Time
+}
+
+// ChangeEntry - изменение ленты вместе с номером и получателями, как в таблице event_change.
+type ChangeEntry struct {
+	storage.Change
+	Seq        int64
+	Recipients []string
+}
+
+// defaultAccount повторяет аккаунт из миграции 000006_create_account (пароль user1).
+var defaultAccount = storage.Account{
+	ID:           "1",
+	Login:        "user1@gmail.com",
+	PasswordHash: "$2a$10$KcneQnip6BY62j8o7N90SO4tD2mNrFqMonF5pkxdTh0YiKpRBUEbu",
+	TimeZone:     storage.DefaultTimeZone,
+}
+
+// defaultCalendar - личный календарь defaultAccount, как в миграции 000017_create_calendar.
+var defaultCalendar = storage.Calendar{
+	ID:       "1",
+	Name:     storage.PersonalCalendarName,
+	OwnerID:  defaultAccount.ID,
+	Personal: true,
 }
 
 func New() *Storage {
 	events := map[string]storage.Event{}
-	return &Storage{Events: events}
+	accounts := map[string]storage.Account{defaultAccount.ID: defaultAccount}
+	calendars := map[string]storage.Calendar{defaultCalendar.ID: defaultCalendar}
+	acl := map[string]map[string]storage.Role{defaultCalendar.ID: {defaultAccount.ID: storage.RoleOwner}}
+	fired := map[string]map[storage.Offset]time.Time{}
+	return &Storage{
+		Events: events, Trash: map[string]storage.Event{},
+		Accounts: accounts, Calendars: calendars, ACL: acl, Fired: fired,
+		TraceParents: map[string]string{},
+		Logg:         zap.NewNop(),
+	}
 }
 
-func (s *Storage) AddEventByID(_ context.Context,
+// AddEventByID добавляет событие в личный календарь пользователя.
+func (s *Storage) AddEventByID(ctx context.Context,
 	ec storage.EventCreateDTO, userID string,
 ) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.addEvent(ctx, s.ensurePersonalCalendar(userID), ec, userID)
+}
+
+// addEvent вызывается под блокировкой s.mu, права на календарь уже проверены.
+func (s *Storage) addEvent(ctx context.Context, calendarID string, ec storage.EventCreateDTO,
+	userID string,
+) (string, error) {
 	id := uuid.New().String()
 	event := storage.Event{
 		ID:           id,
@@ -35,22 +107,63 @@ func (s *Storage) AddEventByID(_ context.Context,
 		End:          ec.End,
 		Description:  ec.Description,
 		UserID:       userID,
+		CalendarID:   calendarID,
 		Notification: ec.Notification,
 		Notified:     ec.Notified,
+		RRule:        ec.RRule,
+		ExDates:      ec.ExDates,
+		Reminders:    storage.RemindersOrDefault(ec.Reminders, ec.Start, ec.Notification),
+		TimeZone:     storage.EventTimeZone(ec.TimeZone, s.Accounts[userID].TimeZone),
+		UID:          ec.UID,
+		Version:      1,
+	}
+	// как уникальный индекс в sqlstorage, UID занят и событием в корзине
+	if ec.UID != "" {
+		for _, events := range []map[string]storage.Event{s.Events, s.Trash} {
+			for _, e := range events {
+				if e.CalendarID == calendarID && e.UID == ec.UID {
+					return "", storage.ErrDuplicateUID
+				}
+			}
+		}
+	}
+	if err := s.setAttendees(&event, ec.Attendees); err != nil {
+		return "", err
+	}
+	if ec.RejectOverlap {
+		if err := s.checkOverlaps(event); err != nil {
+			return "", err
+		}
 	}
 	s.Events[id] = event
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		s.TraceParents[id] = traceParent
+	}
+	s.recordChange(storage.ChangeCreated, event)
+	s.recordAudit(ctx, storage.AuditCreate, userID, nil, event)
 	return id, nil
 }
 
-func (s *Storage) UpdateEventByID(_ context.Context, id string,
-	event storage.EventUpdateDTO, _ string,
-) error { // ctx userID
+// UpdateEventByID меняет событие личного календаря пользователя.
+func (s *Storage) UpdateEventByID(ctx context.Context, id string,
+	event storage.EventUpdateDTO, userID string,
+) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, ok := s.Events[id]
-	if !ok {
-		return fmt.Errorf("there is no event with id: %s", id)
+	return s.updateEvent(ctx, s.personalCalendar(userID), id, event, userID)
+}
+
+// updateEvent вызывается под блокировкой s.mu, права на календарь уже проверены.
+func (s *Storage) updateEvent(ctx context.Context, calendarID string, id string, event storage.EventUpdateDTO,
+	userID string,
+) error {
+	stored, ok := s.Events[id]
+	if !ok || stored.CalendarID != calendarID {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
+	}
+	if err := storage.CheckVersion(id, stored.Version, event.Version); err != nil {
+		return err
 	}
 
 	e := s.Events[id]
@@ -69,88 +182,209 @@ func (s *Storage) UpdateEventByID(_ context.Context, id string,
 	if event.Notification != nil {
 		e.Notification = *event.Notification
 	}
+	if event.RRule != nil {
+		e.RRule = *event.RRule
+	}
+	if event.ExDates != nil {
+		e.ExDates = *event.ExDates
+	}
+	if event.TimeZone != nil && *event.TimeZone != "" {
+		e.TimeZone = *event.TimeZone
+	}
+	if event.Reminders != nil {
+		e.Reminders = storage.RemindersOrDefault(*event.Reminders, e.Start, e.Notification)
+	}
+	if event.Attendees != nil {
+		if err := s.setAttendees(&e, *event.Attendees); err != nil {
+			return err
+		}
+	}
 	if !event.Notified {
 		e.Notified = true
 	}
+	if event.RejectOverlap {
+		if err := s.checkOverlaps(e); err != nil {
+			return err
+		}
+	}
 
+	e.Version++
 	s.Events[id] = e
+	s.updateFired(e)
+	// убранные из приглашённых тоже узнают об изменении
+	s.recordChange(storage.ChangeUpdated, e, attendeeIDs(stored.Attendees)...)
+	s.recordAudit(ctx, storage.AuditUpdate, userID, &stored, e)
 
 	return nil
 }
 
-func (s *Storage) DeleteEventByID(_ context.Context, id string) error { // ctx
+// DeleteEventByID переносит в корзину событие личного календаря пользователя.
+func (s *Storage) DeleteEventByID(ctx context.Context, id string, userID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, ok := s.Events[id]
-	if !ok {
-		return fmt.Errorf("nothing to delete with id: %s", id)
+	return s.deleteEvent(ctx, s.personalCalendar(userID), id, userID)
+}
+
+// deleteEvent вызывается под блокировкой s.mu, права на календарь уже проверены.
+func (s *Storage) deleteEvent(ctx context.Context, calendarID string, id string, userID string) error {
+	event, ok := s.Events[id]
+	if !ok || event.CalendarID != calendarID {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
 	}
+	s.recordChange(storage.ChangeDeleted, event)
+	s.trashEvent(id, time.Now())
+	s.recordAudit(ctx, storage.AuditDelete, userID, &event, s.Trash[id])
+	return nil
+}
+
+// trashEvent вызывается под блокировкой s.mu на запись: переносит событие в корзину.
+// Отметки напоминаний остаются, чтобы после восстановления о нём не напомнили повторно.
+func (s *Storage) trashEvent(id string, now time.Time) {
+	e := s.Events[id]
+	e.DeletedAt = &now
+	s.Trash[id] = e
 	delete(s.Events, id)
+}
+
+// setAttendees вызывается под блокировкой s.mu: задаёт событию приглашённых,
+// сохраняя ответы тех, кто был приглашён и раньше. Аккаунты должны существовать.
+func (s *Storage) setAttendees(e *storage.Event, userIDs []string) error {
+	for _, id := range userIDs {
+		if _, ok := s.Accounts[id]; !ok {
+			return fmt.Errorf("%w: no account %s", storage.ErrInvalidAttendee, id)
+		}
+	}
+	e.Attendees = storage.MergeAttendees(e.Attendees, userIDs)
+	e.Responses = storage.CountResponses(e.Attendees)
 	return nil
 }
 
-const (
-	day   = "day"
-	week  = "week"
-	month = "month"
-)
+// RespondToEvent записывает ответ приглашённого пользователя на приглашение.
+func (s *Storage) RespondToEvent(_ context.Context, eventID string, userID string,
+	status storage.AttendeeStatus,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-// получить список событий на день/неделю/месяц;
-// date - дата; если неделя или месяц - то будет вокруг этой даты.
+	e, ok := s.Events[eventID]
+	if !ok {
+		return storage.ErrNotInvited
+	}
+	i := slices.IndexFunc(e.Attendees, func(a storage.Attendee) bool { return a.UserID == userID })
+	if i < 0 {
+		return storage.ErrNotInvited
+	}
+
+	e.Attendees = slices.Clone(e.Attendees)
+	e.Attendees[i].Status = status
+	e.Responses = storage.CountResponses(e.Attendees)
+	s.Events[eventID] = e
+	s.recordChange(storage.ChangeUpdated, e)
+	return nil
+}
+
+// updateFired вызывается под блокировкой s.mu после изменения события, как в sqlstorage:
+// напоминания с удалёнными смещениями забываются, а если неповторяющееся событие
+// перенесли раньше уже напомненного начала, о нём напомнят снова.
+func (s *Storage) updateFired(e storage.Event) {
+	for offset, firedFor := range s.Fired[e.ID] {
+		if !slices.Contains(e.Reminders, offset) || (e.RRule == "" && firedFor.After(e.Start)) {
+			delete(s.Fired[e.ID], offset)
+		}
+	}
+}
+
+// получить список событий на день/неделю/месяц, в которые попадает date;
+// границы периода считаются в зоне date (см. storage.ListingWindow).
+// Повторяющиеся события разворачиваются в отдельные повторения.
+// В список попадают события личного календаря и те, на которые пользователя пригласили.
 func (s *Storage) GetEventListingByUserID(userID string, date time.Time, period string) ([]storage.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var (
-		start time.Time
-		end   time.Time
-	)
+	return s.listing(s.visibleTo(userID), date, period)
+}
 
-	result := []storage.Event{}
+// listing вызывается под блокировкой s.mu: события, для которых match истинно,
+// развёрнутые в повторения внутри периода; время повторений - в зоне date.
+// Событие, которое не удалось развернуть, пропускается.
+func (s *Storage) listing(match func(storage.Event) bool, date time.Time, period string) ([]storage.Event, error) {
+	start, end, err := storage.ListingWindow(date, period)
+	if err != nil {
+		return nil, err
+	}
 
-	switch period {
-	case day:
-		start = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 1, 0, time.Local)
-		end = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, time.Local)
-	case week:
-		start = StartOfWeek(date)
-		end = EndOfWeek(date)
-	case month:
-		start = StartOfMonth(date)
-		end = EndOfMonth(date)
+	result := []storage.Event{}
+	for _, event := range s.Events {
+		if !match(event) {
+			continue
+		}
+		occurrences, err := storage.Occurrences(event, date.Location(), start, end)
+		if err != nil {
+			s.Logg.Error("error in expanding recurring event, skipping it",
+				zap.Error(err), zap.String("eventID", event.ID))
+			continue
+		}
+		result = append(result, occurrences...)
 	}
 
+	slices.SortFunc(result, func(a, b storage.Event) int { return a.Start.Compare(b.Start) })
+
+	return result, nil
+}
+
+func (s *Storage) GetEventsByUserID(_ context.Context, userID string) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	visible := s.visibleTo(userID)
+	result := []storage.Event{}
 	for _, event := range s.Events {
-		if event.Start.After(start) && event.Start.Before(end) && event.UserID == userID {
+		if visible(event) {
 			result = append(result, event)
 		}
 	}
 
+	slices.SortFunc(result, func(a, b storage.Event) int { return a.Start.Compare(b.Start) })
+
 	return result, nil
 }
 
-func StartOfWeek(date time.Time) time.Time {
-	daysSinceSunday := int(date.Weekday())
-	s := date.AddDate(0, 0, -daysSinceSunday+1)
-	startDate := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 1, 0, time.Local)
-	return startDate
-}
+// checkOverlaps вызывается под блокировкой s.mu; пересечения ищутся в календаре события.
+func (s *Storage) checkOverlaps(e storage.Event) error {
+	var calendarEvents []storage.Event
+	for _, x := range s.Events {
+		if x.CalendarID == e.CalendarID {
+			calendarEvents = append(calendarEvents, x)
+		}
+	}
 
-func EndOfWeek(date time.Time) time.Time {
-	daysUntilSaturday := 7 - int(date.Weekday())
-	e := date.AddDate(0, 0, daysUntilSaturday)
-	endDate := time.Date(e.Year(), e.Month(), e.Day(), 23, 59, 59, 0, time.Local)
-	return endDate
+	overlaps, err := storage.FindOverlaps(e, calendarEvents)
+	if err != nil {
+		return err
+	}
+	if len(overlaps) > 0 {
+		return fmt.Errorf("%w: %s", storage.ErrOverlap, overlaps[0].ID)
+	}
+	return nil
 }
 
-func StartOfMonth(date time.Time) time.Time {
-	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
-}
+func (s *Storage) GetFreeBusy(_ context.Context, userID string,
+	from, to time.Time, minFree time.Duration,
+) (storage.FreeBusy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func EndOfMonth(date time.Time) time.Time {
-	firstDayOfNextMonth := StartOfMonth(date).AddDate(0, 1, 0)
-	return firstDayOfNextMonth.Add(-time.Second)
+	calendarID := s.personalCalendar(userID)
+	var userEvents []storage.Event
+	for _, e := range s.Events {
+		if e.CalendarID == calendarID {
+			userEvents = append(userEvents, e)
+		}
+	}
+
+	return storage.GetFreeBusy(userEvents, from, to, minFree)
 }
 
 // получить уведомление за N дней до события.
@@ -158,23 +392,323 @@ func (s *Storage) Notify(_ uint) (string, error) { // day
 	return "", nil
 }
 
-func (s *Storage) GetEventByID(id string, _ string) (storage.Event, error) { // userID
+func (s *Storage) GetEventByID(id string, userID string) (storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	event, ok := s.Events[id]
-	if !ok {
-		return storage.Event{}, fmt.Errorf("no evend with id %s", id)
+	if !ok || !s.visibleTo(userID)(event) {
+		return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
 	}
 	return event, nil
 }
 
-func (s *Storage) SetNotified(_ context.Context, _ []string) ([]string, error) {
-	return nil, nil
+// SetNotified помечает события как отправленные и возвращает ID реально помеченных.
+func (s *Storage) SetNotified(_ context.Context, ids []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []string
+	for _, id := range ids {
+		e, ok := s.Events[id]
+		if !ok || e.Notified {
+			continue
+		}
+		e.Notified = true
+		s.Events[id] = e
+		s.recordChange(storage.ChangeNotified, e)
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// EnqueueNotifications помечает напоминания отправленными и под той же блокировкой
+// кладёт их в outbox. Возвращает ID событий, по которым напоминание реально
+// поставлено в очередь; уже отправленные пропускаются.
+func (s *Storage) EnqueueNotifications(_ context.Context, events []storage.EventToNotify) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type reminderKey struct {
+		id     string
+		offset storage.Offset
+	}
+	// due - пора ли напомнить по ключу; решается по первому получателю,
+	// остальные получатели того же напоминания идут следом
+	due := make(map[reminderKey]bool)
+
+	var result []string
+	for _, n := range events {
+		e, ok := s.Events[n.ID]
+		if !ok || !slices.Contains(e.Reminders, n.Reminder) {
+			continue
+		}
+		k := reminderKey{n.ID, n.Reminder}
+		if _, decided := due[k]; !decided {
+			firedFor, fired := s.Fired[n.ID][n.Reminder]
+			due[k] = !fired || firedFor.Before(n.Start)
+		}
+		if !due[k] {
+			continue
+		}
+
+		n.IdempotencyKey = storage.NotificationKey(n)
+		data, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.Fired[n.ID] == nil {
+			s.Fired[n.ID] = map[storage.Offset]time.Time{}
+		}
+		s.Fired[n.ID][n.Reminder] = n.Start
+		e.Notified = true
+		s.Events[n.ID] = e
+		if !slices.Contains(result, n.ID) {
+			result = append(result, n.ID)
+		}
+
+		if slices.ContainsFunc(s.Outbox, func(o OutboxEntry) bool { return o.IdempotencyKey == n.IdempotencyKey }) {
+			continue
+		}
+		s.Outbox = append(s.Outbox, OutboxEntry{OutboxMessage: storage.OutboxMessage{
+			ID:             strconv.Itoa(len(s.Outbox) + 1),
+			IdempotencyKey: n.IdempotencyKey,
+			Payload:        data,
+			TraceParent:    n.TraceParent,
+		}})
+	}
+	for _, id := range result {
+		s.recordChange(storage.ChangeNotified, s.Events[id])
+	}
+	return result, nil
+}
+
+func (s *Storage) PendingOutbox(_ context.Context, limit int) ([]storage.OutboxMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []storage.OutboxMessage
+	for _, o := range s.Outbox {
+		if len(result) == limit {
+			break
+		}
+		if o.DeliveredAt.IsZero() {
+			result = append(result, o.OutboxMessage)
+		}
+	}
+	return result, nil
 }
 
+func (s *Storage) MarkOutboxDelivered(_ context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for i := range s.Outbox {
+		if slices.Contains(ids, s.Outbox[i].ID) && s.Outbox[i].DeliveredAt.IsZero() {
+			s.Outbox[i].DeliveredAt = now
+		}
+	}
+	return nil
+}
+
+// CollectEventsToNotify возвращает напоминания всех пользователей, время которых наступило,
+// по одному на каждое напоминание события и получателя (как в sqlstorage).
+// Событие, которое не удалось развернуть, пропускается.
 func (s *Storage) CollectEventsToNotify(_ context.Context) ([]storage.EventToNotify, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
 	var events []storage.EventToNotify
+	for _, e := range s.Events {
+		for _, offset := range e.Reminders {
+			r := storage.Reminder{Offset: offset, FiredFor: s.Fired[e.ID][offset]}
+			start, ok, err := storage.DueOccurrence(e, r, now)
+			if err != nil {
+				s.Logg.Error("error in expanding recurring event, skipping it",
+					zap.Error(err), zap.String("eventID", e.ID))
+				break
+			}
+			if !ok {
+				continue
+			}
+			for _, userID := range e.Recipients() {
+				events = append(events, storage.EventToNotify{
+					ID:          e.ID,
+					Title:       e.Title,
+					Start:       start,
+					UserID:      userID,
+					Channels:    s.Accounts[userID].Channels,
+					Reminder:    offset,
+					TraceParent: s.TraceParents[e.ID],
+				})
+			}
+		}
+	}
 	return events, nil
 }
 
-func (s *Storage) DeleteEvents(_ context.Context) error {
+// DeleteEvents переносит в корзину события всех пользователей, закончившиеся раньше, чем retention назад,
+// окончательно удаляет события, пролежавшие в корзине дольше trashRetention, изменения ленты
+// и доставленные сообщения outbox старше retention. Удаление устаревших событий в ленту не попадает.
+// Событие, которое не удалось развернуть, остаётся на месте.
+func (s *Storage) DeleteEvents(_ context.Context, retention time.Duration, trashRetention time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-retention)
+	for id, e := range s.Events {
+		outdated, err := storage.Outdated(e, cutoff)
+		if err != nil {
+			s.Logg.Error("error in expanding recurring event, skipping it",
+				zap.Error(err), zap.String("eventID", e.ID))
+			continue
+		}
+		if outdated {
+			s.trashEvent(id, now)
+		}
+	}
+	for id, e := range s.Trash {
+		if e.DeletedAt.Before(now.Add(-trashRetention)) {
+			delete(s.Trash, id)
+			delete(s.Fired, id)
+			delete(s.TraceParents, id)
+		}
+	}
+	s.Changes = slices.DeleteFunc(s.Changes, func(c ChangeEntry) bool {
+		if !c.ChangedAt.Before(cutoff) {
+			return false
+		}
+		s.changeHorizon = max(s.changeHorizon, c.Seq)
+		return true
+	})
+	s.Outbox = slices.DeleteFunc(s.Outbox, func(o OutboxEntry) bool {
+		return !o.DeliveredAt.IsZero() && o.DeliveredAt.Before(cutoff)
+	})
+	return nil
+}
+
+func (s *Storage) GetAccountByLogin(_ context.Context, login string) (storage.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.Accounts {
+		if a.Login == login {
+			return a, nil
+		}
+	}
+	return storage.Account{}, storage.ErrAccountNotFound
+}
+
+func (s *Storage) CreateAccount(_ context.Context, login string, passwordHash string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.Accounts {
+		if a.Login == login {
+			return "", storage.ErrLoginTaken
+		}
+	}
+
+	id := uuid.New().String()
+	s.Accounts[id] = storage.Account{
+		ID:           id,
+		Login:        login,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+		TimeZone:     storage.DefaultTimeZone,
+	}
+	s.ensurePersonalCalendar(id)
+	return id, nil
+}
+
+func (s *Storage) GetAccountByID(_ context.Context, id string) (storage.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.Accounts[id]
+	if !ok {
+		return storage.Account{}, storage.ErrAccountNotFound
+	}
+	return a, nil
+}
+
+// UpdateAccountPassword меняет пароль и увеличивает версию токенов: выпущенные раньше токены отзываются.
+func (s *Storage) UpdateAccountPassword(_ context.Context, id string, passwordHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.Accounts[id]
+	if !ok {
+		return storage.ErrAccountNotFound
+	}
+	a.PasswordHash = passwordHash
+	a.TokenVersion++
+	s.Accounts[id] = a
+	return nil
+}
+
+func (s *Storage) SetAccountChannels(_ context.Context, id string, channels []storage.NotificationChannel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.Accounts[id]
+	if !ok {
+		return storage.ErrAccountNotFound
+	}
+	a.Channels = slices.Clone(channels)
+	s.Accounts[id] = a
+	return nil
+}
+
+func (s *Storage) SetAccountTimeZone(_ context.Context, id string, timeZone string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.Accounts[id]
+	if !ok {
+		return storage.ErrAccountNotFound
+	}
+	a.TimeZone = timeZone
+	s.Accounts[id] = a
+	return nil
+}
+
+// DeleteAccountByID удаляет аккаунт вместе с его календарями, правами и приглашениями.
+// События, созданные им в чужих календарях, переходят к владельцам этих календарей.
+func (s *Storage) DeleteAccountByID(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.Accounts[id]; !ok {
+		return storage.ErrAccountNotFound
+	}
+	for calendarID, c := range s.Calendars {
+		if c.OwnerID == id {
+			s.deleteCalendar(calendarID)
+		}
+	}
+	for _, acl := range s.ACL {
+		delete(acl, id)
+	}
+	// события в корзине восстанавливаются уже без удалённого аккаунта
+	for _, events := range []map[string]storage.Event{s.Events, s.Trash} {
+		for eventID, e := range events {
+			if e.UserID == id {
+				e.UserID = s.Calendars[e.CalendarID].OwnerID
+				events[eventID] = e
+			}
+			if e.Invited(id) {
+				e.Attendees = slices.DeleteFunc(slices.Clone(e.Attendees),
+					func(a storage.Attendee) bool { return a.UserID == id })
+				e.Responses = storage.CountResponses(e.Attendees)
+				events[eventID] = e
+			}
+		}
+	}
+	delete(s.Accounts, id)
 	return nil
 }
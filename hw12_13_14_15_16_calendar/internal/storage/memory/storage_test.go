@@ -2,9 +2,13 @@ package memorystorage
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
 	"github.com/c2fo/testify/require"
 )
@@ -144,9 +148,17 @@ func TestStorageDelete(t *testing.T) {
 	_, err = store.GetEventByID(id1, user1)
 	require.NoError(t, err)
 
-	store.DeleteEventByID(ctx, id1)
+	err = store.DeleteEventByID(ctx, id1, user2)
+	require.Error(t, err)
+	require.Equal(t, len(store.Events), 2)
+
+	err = store.DeleteEventByID(ctx, id1, user1)
+	require.NoError(t, err)
 	require.Equal(t, len(store.Events), 1)
 
+	_, err = store.GetEventByID(id2, user1)
+	require.Error(t, err)
+
 	event2, err := store.GetEventByID(id2, user2)
 	require.NoError(t, err)
 
@@ -200,14 +212,14 @@ func TestStorageGetEventListing(t *testing.T) {
 
 	require.Equal(t, len(store.Events), 3)
 
-	res1, err := store.GetEventListingByUserID("1", date2, day)
+	res1, err := store.GetEventListingByUserID("1", date2, storage.PeriodDay)
 	require.NoError(t, err)
 	require.Equal(t, len(res1), 1)
 	event2, err := store.GetEventByID(id2, user1)
 	require.NoError(t, err)
 	require.Equal(t, res1[0], event2)
 
-	res2, err := store.GetEventListingByUserID("1", date1, week)
+	res2, err := store.GetEventListingByUserID("1", date1, storage.PeriodWeek)
 	require.NoError(t, err)
 	require.Equal(t, len(res2), 2)
 
@@ -216,7 +228,7 @@ func TestStorageGetEventListing(t *testing.T) {
 		require.True(t, ok)
 	}
 
-	res3, err := store.GetEventListingByUserID("1", date1, month)
+	res3, err := store.GetEventListingByUserID("1", date1, storage.PeriodMonth)
 	require.NoError(t, err)
 	require.Equal(t, len(res3), 3)
 
@@ -225,3 +237,894 @@ func TestStorageGetEventListing(t *testing.T) {
 		require.True(t, ok)
 	}
 }
+
+func TestStorageGetEventListingRecurring(t *testing.T) {
+	store := New()
+	user1 := "1"
+	// понедельник, 1 сентября 2025
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.Local)
+
+	standUp := storage.EventCreateDTO{
+		Title:   "stand-up",
+		Start:   start,
+		End:     start.Add(15 * time.Minute),
+		RRule:   "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		ExDates: []time.Time{start.AddDate(0, 0, 9)}, // среда, 10 сентября
+	}
+	ctx := context.Background()
+
+	id, err := store.AddEventByID(ctx, standUp, user1)
+	require.NoError(t, err)
+
+	res, err := store.GetEventListingByUserID(user1, start.AddDate(0, 0, 2), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, id, res[0].ID)
+	require.Equal(t, start.AddDate(0, 0, 2), res[0].Start)
+	require.Equal(t, start.AddDate(0, 0, 2).Add(15*time.Minute), res[0].End)
+
+	res, err = store.GetEventListingByUserID(user1, start.AddDate(0, 0, 9), storage.PeriodWeek)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, start.AddDate(0, 0, 7), res[0].Start)
+	require.Equal(t, start.AddDate(0, 0, 11), res[1].Start)
+
+	res, err = store.GetEventListingByUserID(user1, start, storage.PeriodMonth)
+	require.NoError(t, err)
+	require.Len(t, res, 12)
+}
+
+func TestStorageRejectOverlap(t *testing.T) {
+	store := New()
+	user1 := "1"
+	user2 := "2"
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.Local)
+	ctx := context.Background()
+
+	_, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "event1",
+		Start: start,
+		End:   start.Add(time.Hour),
+	}, user1)
+	require.NoError(t, err)
+
+	overlapping := storage.EventCreateDTO{
+		Title:         "event2",
+		Start:         start.Add(30 * time.Minute),
+		End:           start.Add(90 * time.Minute),
+		RejectOverlap: true,
+	}
+
+	_, err = store.AddEventByID(ctx, overlapping, user1)
+	require.True(t, errors.Is(err, storage.ErrOverlap))
+	require.Equal(t, 1, len(store.Events))
+
+	// у другого пользователя пересечения нет
+	_, err = store.AddEventByID(ctx, overlapping, user2)
+	require.NoError(t, err)
+
+	// без флага пересечение допускается, как и раньше
+	overlapping.RejectOverlap = false
+	id, err := store.AddEventByID(ctx, overlapping, user1)
+	require.NoError(t, err)
+
+	later := start.Add(3 * time.Hour)
+	laterEnd := later.Add(time.Hour)
+	err = store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Start: &later, End: &laterEnd, RejectOverlap: true}, user1)
+	require.NoError(t, err)
+
+	err = store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Start: &start, End: &laterEnd, RejectOverlap: true}, user1)
+	require.True(t, errors.Is(err, storage.ErrOverlap))
+
+	fb, err := store.GetFreeBusy(ctx, user1, start, start.Add(5*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, fb.Busy, 2)
+	require.Len(t, fb.Free, 2)
+}
+
+func TestStorageGetAccountByLogin(t *testing.T) {
+	store := New()
+
+	account, err := store.GetAccountByLogin(context.Background(), "user1@gmail.com")
+	require.NoError(t, err)
+	require.Equal(t, "1", account.ID)
+	require.NoError(t, auth.CheckPassword(account.PasswordHash, "user1"))
+
+	_, err = store.GetAccountByLogin(context.Background(), "nobody")
+	require.True(t, errors.Is(err, storage.ErrAccountNotFound))
+}
+
+func TestStorageAccountCRUD(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	id, err := store.CreateAccount(ctx, "user2@gmail.com", "hash1")
+	require.NoError(t, err)
+
+	_, err = store.CreateAccount(ctx, "user2@gmail.com", "hash2")
+	require.True(t, errors.Is(err, storage.ErrLoginTaken))
+
+	account, err := store.GetAccountByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "user2@gmail.com", account.Login)
+	require.Equal(t, "hash1", account.PasswordHash)
+
+	require.NoError(t, store.UpdateAccountPassword(ctx, id, "hash2"))
+	account, err = store.GetAccountByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "hash2", account.PasswordHash)
+
+	start := time.Now()
+	_, err = store.AddEventByID(ctx, storage.EventCreateDTO{Title: "own", Start: start, End: start.Add(time.Hour)}, id)
+	require.NoError(t, err)
+	otherID, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "other", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteAccountByID(ctx, id))
+	require.Len(t, store.Events, 1)
+	_, err = store.GetEventByID(otherID, "1")
+	require.NoError(t, err)
+
+	_, err = store.GetAccountByID(ctx, id)
+	require.True(t, errors.Is(err, storage.ErrAccountNotFound))
+	require.True(t, errors.Is(store.DeleteAccountByID(ctx, id), storage.ErrAccountNotFound))
+	require.True(t, errors.Is(store.UpdateAccountPassword(ctx, id, "hash3"), storage.ErrAccountNotFound))
+}
+
+func TestStorageNotifyPipelineAcrossAccounts(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	now := time.Now()
+
+	soon := storage.EventCreateDTO{Title: "soon", Start: now.Add(30 * time.Minute), End: now.Add(time.Hour)}
+	later := storage.EventCreateDTO{Title: "later", Start: now.Add(5 * time.Hour), End: now.Add(6 * time.Hour)}
+	old := storage.EventCreateDTO{Title: "old", Start: now.AddDate(-2, 0, 0), End: now.AddDate(-2, 0, 0).Add(time.Hour)}
+	// сегодняшнее повторение серии начнётся через три часа - напоминать рано
+	oldSeries := storage.EventCreateDTO{
+		Title: "series", Start: old.Start.Add(3 * time.Hour), End: old.End.Add(3 * time.Hour), RRule: "FREQ=DAILY",
+	}
+
+	id1, err := store.AddEventByID(ctx, soon, "1")
+	require.NoError(t, err)
+	id2, err := store.AddEventByID(ctx, soon, "2")
+	require.NoError(t, err)
+	_, err = store.AddEventByID(ctx, later, "2")
+	require.NoError(t, err)
+	_, err = store.AddEventByID(ctx, old, "3")
+	require.NoError(t, err)
+	seriesID, err := store.AddEventByID(ctx, oldSeries, "3")
+	require.NoError(t, err)
+
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	users := map[string]string{}
+	for _, e := range events {
+		users[e.ID] = e.UserID
+	}
+	require.Equal(t, map[string]string{id1: "1", id2: "2"}, users)
+
+	notified, err := store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Len(t, notified, 2)
+
+	collected, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, collected, 0)
+
+	notified, err = store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Len(t, notified, 0)
+
+	// устаревшее событие сначала попадает в корзину
+	require.NoError(t, store.DeleteEvents(ctx, 365*24*time.Hour, time.Hour))
+	require.Len(t, store.Events, 4)
+	require.Len(t, store.Trash, 1)
+	_, err = store.GetEventByID(seriesID, "3")
+	require.NoError(t, err)
+}
+
+func TestStorageEnqueueNotifications(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Now().Add(30 * time.Minute)
+
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+
+	channels := []storage.NotificationChannel{{Type: storage.ChannelEmail, Target: "user1@gmail.com"}}
+	require.NoError(t, store.SetAccountChannels(ctx, "1", channels))
+
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, channels, events[0].Channels)
+
+	marked, err := store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+
+	// повторный вызов (например, после сбоя планировщика) не дублирует сообщение
+	marked, err = store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Len(t, marked, 0)
+
+	pending, err := store.PendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, storage.NotificationKey(events[0]), pending[0].IdempotencyKey)
+	require.Contains(t, string(pending[0].Payload), pending[0].IdempotencyKey)
+
+	require.NoError(t, store.MarkOutboxDelivered(ctx, []string{pending[0].ID}))
+	pending, err = store.PendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 0)
+
+	// доставленное сообщение удаляется очисткой только после retention
+	require.NoError(t, store.DeleteEvents(ctx, time.Hour, time.Hour))
+	require.Len(t, store.Outbox, 1)
+	store.Outbox[0].DeliveredAt = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, store.DeleteEvents(ctx, time.Hour, time.Hour))
+	require.Len(t, store.Outbox, 0)
+}
+
+func TestStorageMultipleReminders(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Now().Add(5 * time.Minute)
+
+	reminders := []storage.Offset{storage.Offset(time.Hour), 0, storage.Offset(10 * time.Minute)}
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "t", Start: start, End: start.Add(time.Hour), Reminders: reminders,
+	}, "1")
+	require.NoError(t, err)
+	// без напоминаний: пустой список не заменяется напоминанием по умолчанию
+	_, err = store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "silent", Start: start, End: start.Add(time.Hour), Reminders: []storage.Offset{},
+	}, "1")
+	require.NoError(t, err)
+
+	e, err := store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, []storage.Offset{0, storage.Offset(10 * time.Minute), storage.Offset(time.Hour)}, e.Reminders)
+
+	// за пять минут до начала наступили напоминания за час и за 10 минут, но не "в момент начала"
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	var offsets []storage.Offset
+	for _, n := range events {
+		require.Equal(t, id, n.ID)
+		require.True(t, start.Equal(n.Start))
+		offsets = append(offsets, n.Reminder)
+	}
+	slices.Sort(offsets)
+	require.Equal(t, []storage.Offset{storage.Offset(10 * time.Minute), storage.Offset(time.Hour)}, offsets)
+	require.NotEqual(t, storage.NotificationKey(events[0]), storage.NotificationKey(events[1]))
+
+	marked, err := store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// перенос на более позднее время снова включает напоминания
+	later := start.Add(30 * time.Minute)
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Start: &later}, "1"))
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, storage.Offset(time.Hour), events[0].Reminder)
+	require.True(t, later.Equal(events[0].Start))
+
+	// удалённое напоминание больше не срабатывает
+	only := []storage.Offset{storage.Offset(10 * time.Minute)}
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Reminders: &only}, "1"))
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestStorageRecurringReminders(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	first := time.Now().Add(5*time.Minute).AddDate(0, 0, -3)
+
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "standup", Start: first, End: first.Add(15 * time.Minute), RRule: "FREQ=DAILY",
+		Reminders: []storage.Offset{storage.Offset(10 * time.Minute)},
+	}, "1")
+	require.NoError(t, err)
+
+	// напоминание о сегодняшнем повторении, а не о первом
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.True(t, first.AddDate(0, 0, 3).Equal(events[0].Start))
+
+	marked, err := store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// вчерашнее повторение считается напомненным - завтрашнее снова напоминается
+	store.Fired[id][storage.Offset(10*time.Minute)] = first.AddDate(0, 0, 2)
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestStorageCollectSkipsBrokenEvent(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Now().Add(5 * time.Minute)
+
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "ok", Start: start, End: start.Add(time.Hour),
+		Reminders: []storage.Offset{storage.Offset(10 * time.Minute)},
+	}, "1")
+	require.NoError(t, err)
+	// правило, записанное до проверки rrule, не разворачивается
+	store.Events["broken"] = storage.Event{
+		ID: "broken", Title: "broken", Start: start, End: start.Add(time.Hour), RRule: "FREQ=SECONDLY",
+		UserID: "1", Reminders: []storage.Offset{storage.Offset(10 * time.Minute)},
+	}
+
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, id, events[0].ID)
+}
+
+func TestStorageListingAndCleanupSkipBrokenEvent(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Now().Add(-48 * time.Hour).Truncate(time.Hour)
+
+	old, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "old", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+	// правило, записанное до проверки rrule, не разворачивается
+	store.Events["broken"] = storage.Event{
+		ID: "broken", Title: "broken", Start: start, End: start.Add(time.Hour), RRule: "FREQ=SECONDLY", UserID: "1",
+	}
+
+	res, err := store.GetEventListingByUserID("1", start, storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, old, res[0].ID)
+
+	// очистка не останавливается на сломанном событии и оставляет его на месте
+	require.NoError(t, store.DeleteEvents(ctx, time.Hour, time.Hour))
+	_, ok := store.Events[old]
+	require.False(t, ok)
+	_, ok = store.Events["broken"]
+	require.True(t, ok)
+}
+
+func TestStorageGetEventListingInTimeZone(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	// 19 сентября 23:30 UTC - это уже 20 сентября в Токио
+	start := time.Date(2025, time.September, 19, 23, 30, 0, 0, time.UTC)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "late", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+
+	account, err := store.GetAccountByID(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, storage.DefaultTimeZone, account.TimeZone)
+
+	res, err := store.GetEventListingByUserID("1", time.Date(2025, time.September, 19, 0, 0, 0, 0, time.UTC), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, id, res[0].ID)
+
+	require.NoError(t, store.SetAccountTimeZone(ctx, "1", "Asia/Tokyo"))
+	account, err = store.GetAccountByID(ctx, "1")
+	require.NoError(t, err)
+	tokyo, err := account.Location()
+	require.NoError(t, err)
+
+	res, err = store.GetEventListingByUserID("1", time.Date(2025, time.September, 19, 0, 0, 0, 0, tokyo), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Empty(t, res)
+	res, err = store.GetEventListingByUserID("1", time.Date(2025, time.September, 20, 0, 0, 0, 0, tokyo), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	_, err = store.GetEventListingByUserID("1", start, "year")
+	require.True(t, errors.Is(err, storage.ErrUnknownPeriod))
+	require.True(t, errors.Is(store.SetAccountTimeZone(ctx, "missing", "UTC"), storage.ErrAccountNotFound))
+}
+
+func TestStorageRecurringInAccountTimeZone(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.SetAccountTimeZone(ctx, "1", "Europe/Berlin"))
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// понедельник 00:30 по Берлину; клиент прислал время в UTC, то есть в воскресенье
+	start := time.Date(2026, time.March, 16, 0, 30, 0, 0, berlin).UTC()
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "weekly", Start: start, End: start.Add(time.Hour), RRule: "FREQ=WEEKLY;BYDAY=MO",
+	}, "1")
+	require.NoError(t, err)
+
+	e, err := store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", e.TimeZone)
+
+	// неделя после перехода на летнее время: повторение по-прежнему в понедельник 00:30
+	res, err := store.GetEventListingByUserID("1", time.Date(2026, time.April, 1, 0, 0, 0, 0, berlin), storage.PeriodWeek)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.True(t, time.Date(2026, time.March, 30, 0, 30, 0, 0, berlin).Equal(res[0].Start))
+}
+
+func TestStorageDuplicateUID(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+	e := storage.EventCreateDTO{Title: "imported", Start: start, End: start.Add(time.Hour), UID: "a@example.com"}
+
+	id, err := store.AddEventByID(ctx, e, "1")
+	require.NoError(t, err)
+	_, err = store.AddEventByID(ctx, e, "1")
+	require.True(t, errors.Is(err, storage.ErrDuplicateUID))
+
+	// UID уникален в календаре: в другом календаре событие с тем же UID создаётся
+	calendarID, err := store.CreateCalendar(ctx, "team", "1")
+	require.NoError(t, err)
+	_, err = store.AddCalendarEvent(ctx, calendarID, e, "1")
+	require.NoError(t, err)
+
+	got, err := store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, "a@example.com", got.UID)
+}
+
+func TestStorageAttendees(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	guestID, err := store.CreateAccount(ctx, "guest@example.com", "hash")
+	require.NoError(t, err)
+	otherID, err := store.CreateAccount(ctx, "other@example.com", "hash")
+	require.NoError(t, err)
+
+	_, err = store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "t", Start: time.Now(), End: time.Now().Add(time.Hour), Attendees: []string{"404"},
+	}, "1")
+	require.True(t, errors.Is(err, storage.ErrInvalidAttendee))
+
+	start := time.Now().Add(30 * time.Minute)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Планёрка", Start: start, End: start.Add(time.Hour), Attendees: []string{guestID, otherID},
+	}, "1")
+	require.NoError(t, err)
+
+	// приглашённый видит событие в своих списках, но не может его менять
+	e, err := store.GetEventByID(id, guestID)
+	require.NoError(t, err)
+	require.Equal(t, "1", e.UserID)
+	require.Equal(t, storage.ResponseCounts{Pending: 2}, e.Responses)
+
+	listing, err := store.GetEventListingByUserID(guestID, start, storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, listing, 1)
+
+	title := "чужое"
+	require.Error(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title}, guestID))
+
+	require.NoError(t, store.RespondToEvent(ctx, id, guestID, storage.StatusAccepted))
+	require.NoError(t, store.RespondToEvent(ctx, id, otherID, storage.StatusDeclined))
+	require.True(t, errors.Is(store.RespondToEvent(ctx, id, "1", storage.StatusAccepted), storage.ErrNotInvited))
+
+	e, err = store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, storage.ResponseCounts{Accepted: 1, Declined: 1}, e.Responses)
+
+	// напоминание получают владелец и принявший приглашение, но не отказавшийся
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	var recipients []string
+	for _, n := range events {
+		recipients = append(recipients, n.UserID)
+	}
+	slices.Sort(recipients)
+	want := []string{"1", guestID}
+	slices.Sort(want)
+	require.Equal(t, want, recipients)
+
+	marked, err := store.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+	pending, err := store.PendingOutbox(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	// при замене списка ответ оставшихся сохраняется
+	attendees := []string{guestID}
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Attendees: &attendees}, "1"))
+	e, err = store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, []storage.Attendee{{UserID: guestID, Status: storage.StatusAccepted}}, e.Attendees)
+	_, err = store.GetEventByID(id, otherID)
+	require.Error(t, err)
+
+	require.NoError(t, store.DeleteAccountByID(ctx, guestID))
+	e, err = store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Empty(t, e.Attendees)
+}
+
+func TestStorageCalendars(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	editorID, err := store.CreateAccount(ctx, "editor@example.com", "hash")
+	require.NoError(t, err)
+	viewerID, err := store.CreateAccount(ctx, "viewer@example.com", "hash")
+	require.NoError(t, err)
+
+	calendars, err := store.GetCalendars(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	require.True(t, calendars[0].Personal)
+	personalID := calendars[0].ID
+
+	calID, err := store.CreateCalendar(ctx, "Дежурства", "1")
+	require.NoError(t, err)
+	require.NoError(t, store.SetCalendarACL(ctx, calID, storage.ACLEntry{UserID: editorID, Role: storage.RoleEditor}, "1"))
+	require.NoError(t, store.SetCalendarACL(ctx, calID, storage.ACLEntry{UserID: viewerID, Role: storage.RoleViewer}, "1"))
+	require.True(t, errors.Is(store.SetCalendarACL(ctx, calID,
+		storage.ACLEntry{UserID: "404", Role: storage.RoleViewer}, "1"), storage.ErrAccountNotFound))
+	require.True(t, errors.Is(store.SetCalendarACL(ctx, calID,
+		storage.ACLEntry{UserID: viewerID, Role: storage.RoleEditor}, editorID), storage.ErrForbidden))
+
+	acl, err := store.GetCalendarACL(ctx, calID, viewerID)
+	require.NoError(t, err)
+	require.Len(t, acl, 3)
+	require.Equal(t, storage.ACLEntry{UserID: "1", Role: storage.RoleOwner}, acl[0])
+
+	calendars, err = store.GetCalendars(ctx, editorID)
+	require.NoError(t, err)
+	require.Len(t, calendars, 2)
+	require.Equal(t, calID, calendars[1].ID)
+	require.Equal(t, storage.RoleEditor, calendars[1].Role)
+
+	// редактор добавляет событие в общий календарь, читатель его видит, но менять не может
+	start := time.Now().Add(time.Hour)
+	id, err := store.AddCalendarEvent(ctx, calID, storage.EventCreateDTO{
+		Title: "Дежурство", Start: start, End: start.Add(time.Hour),
+	}, editorID)
+	require.NoError(t, err)
+
+	e, err := store.GetCalendarEvent(ctx, calID, id, viewerID)
+	require.NoError(t, err)
+	require.Equal(t, calID, e.CalendarID)
+	require.Equal(t, editorID, e.UserID)
+
+	listing, err := store.GetCalendarEventListing(ctx, calID, start, storage.PeriodDay, viewerID)
+	require.NoError(t, err)
+	require.Len(t, listing, 1)
+
+	title := "чужое"
+	err = store.UpdateCalendarEvent(ctx, calID, id, storage.EventUpdateDTO{Title: &title}, viewerID)
+	require.True(t, errors.Is(err, storage.ErrForbidden))
+	require.True(t, errors.Is(store.DeleteCalendarEvent(ctx, calID, id, viewerID), storage.ErrForbidden))
+	_, err = store.GetCalendarEvent(ctx, personalID, id, "1")
+	require.True(t, errors.Is(err, storage.ErrEventNotFound))
+
+	// события общего календаря не попадают в личные списки
+	_, err = store.GetEventByID(id, editorID)
+	require.Error(t, err)
+
+	// без прав календарь не виден вовсе
+	require.NoError(t, store.RevokeCalendarAccess(ctx, calID, viewerID, "1"))
+	_, err = store.GetCalendarEvent(ctx, calID, id, viewerID)
+	require.True(t, errors.Is(err, storage.ErrCalendarNotFound))
+
+	// события удалённого автора остаются в календаре и переходят владельцу
+	require.NoError(t, store.DeleteAccountByID(ctx, editorID))
+	e, err = store.GetCalendarEvent(ctx, calID, id, "1")
+	require.NoError(t, err)
+	require.Equal(t, "1", e.UserID)
+
+	require.True(t, errors.Is(store.DeleteCalendar(ctx, personalID, "1"), storage.ErrForbidden))
+	require.NoError(t, store.DeleteCalendar(ctx, calID, "1"))
+	_, err = store.GetCalendarEvent(ctx, calID, id, "1")
+	require.True(t, errors.Is(err, storage.ErrCalendarNotFound))
+}
+
+func TestStorageEventVersion(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+	e, err := store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), e.Version)
+
+	// двое прочитали версию 1: первое изменение проходит, второе - конфликт
+	title := "first"
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Version: 1}, "1"))
+	title = "second"
+	err = store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Version: 1}, "1")
+	require.True(t, errors.Is(err, storage.ErrStaleVersion))
+	require.True(t, errors.Is(err, storage.ErrConflict))
+
+	e, err = store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, "first", e.Title)
+	require.Equal(t, int64(2), e.Version)
+
+	// без версии изменение не проверяется
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title}, "1"))
+	e, err = store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), e.Version)
+}
+
+func TestStorageChanges(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	guestID, err := store.CreateAccount(ctx, "guest@example.com", "hash")
+	require.NoError(t, err)
+	otherID, err := store.CreateAccount(ctx, "other@example.com", "hash")
+	require.NoError(t, err)
+
+	token, err := store.ChangeToken(ctx)
+	require.NoError(t, err)
+	wake := store.WaitForChanges()
+
+	start := time.Now().Add(time.Hour)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Планёрка", Start: start, End: start.Add(time.Hour), Attendees: []string{guestID},
+	}, "1")
+	require.NoError(t, err)
+	select {
+	case <-wake:
+	default:
+		t.Fatal("WaitForChanges channel is not closed after a change")
+	}
+
+	title := "Ретро"
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title}, "1"))
+	require.NoError(t, store.RespondToEvent(ctx, id, guestID, storage.StatusAccepted))
+	// убранный из приглашённых узнаёт об этом из ленты
+	none := []string{}
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Attendees: &none}, "1"))
+	require.NoError(t, store.DeleteEventByID(ctx, id, "1"))
+
+	changes, err := store.GetChanges(ctx, "1", token, 10)
+	require.NoError(t, err)
+	require.Equal(t, []storage.ChangeKind{
+		storage.ChangeCreated, storage.ChangeUpdated, storage.ChangeUpdated, storage.ChangeUpdated, storage.ChangeDeleted,
+	}, changeKinds(changes))
+	require.Equal(t, id, changes[0].EventID)
+	require.Nil(t, changes[0].Event) // событие уже удалено
+
+	changes, err = store.GetChanges(ctx, guestID, token, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+
+	changes, err = store.GetChanges(ctx, otherID, token, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// лента продолжается с токена и отдаёт не больше limit изменений
+	changes, err = store.GetChanges(ctx, "1", token, 2)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	changes, err = store.GetChanges(ctx, "1", changes[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	_, err = store.GetChanges(ctx, "1", "abc", 10)
+	require.True(t, errors.Is(err, storage.ErrValidation))
+}
+
+func TestStorageChangesExpired(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	token, err := store.ChangeToken(ctx)
+	require.NoError(t, err)
+	start := time.Now().Add(time.Hour)
+	_, err = store.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+
+	// срок хранения ленты истёк: изменение после токена удалено, молча пропускать его нельзя
+	require.NoError(t, store.DeleteEvents(ctx, -2*time.Hour, time.Hour))
+	_, err = store.GetChanges(ctx, "1", token, 10)
+	require.True(t, errors.Is(err, storage.ErrChangeTokenExpired))
+	require.True(t, errors.Is(err, storage.ErrConflict))
+
+	// после повторной синхронизации лента продолжается с нового токена
+	token, err = store.ChangeToken(ctx)
+	require.NoError(t, err)
+	changes, err := store.GetChanges(ctx, "1", token, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func changeKinds(changes []storage.Change) []storage.ChangeKind {
+	kinds := make([]storage.ChangeKind, 0, len(changes))
+	for _, c := range changes {
+		kinds = append(kinds, c.Kind)
+	}
+	return kinds
+}
+
+func TestStorageBatch(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour)
+	valid := storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}
+	invalid := storage.EventCreateDTO{Title: "bad", Start: start, End: start.Add(-time.Hour)}
+	token, err := store.ChangeToken(ctx)
+	require.NoError(t, err)
+
+	// atomic: ошибка второго события отменяет весь пакет
+	_, err = store.AddEvents(ctx, "", []storage.EventCreateDTO{valid, invalid}, storage.BatchAtomic, "1")
+	var itemErr *storage.BatchItemError
+	require.True(t, errors.As(err, &itemErr))
+	require.Equal(t, 1, itemErr.Index)
+	require.True(t, errors.Is(err, storage.ErrValidation))
+	events, err := store.GetEventsByUserID(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+	changes, err := store.GetChanges(ctx, "1", token, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 0)
+
+	// best_effort: ошибка достаётся только своему событию
+	results, err := store.AddEvents(ctx, "", []storage.EventCreateDTO{valid, invalid, valid},
+		storage.BatchBestEffort, "1")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.True(t, errors.Is(results[1].Err, storage.ErrValidation))
+	require.NoError(t, results[2].Err)
+	events, err = store.GetEventsByUserID(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	title := "renamed"
+	updates := []storage.EventBatchUpdate{
+		{ID: results[0].ID, Event: storage.EventUpdateDTO{Title: &title}},
+		{ID: results[2].ID, Event: storage.EventUpdateDTO{Title: &title, Version: 5}},
+	}
+	_, err = store.UpdateEvents(ctx, "", updates, storage.BatchAtomic, "1")
+	require.True(t, errors.Is(err, storage.ErrStaleVersion))
+	e, err := store.GetEventByID(results[0].ID, "1")
+	require.NoError(t, err)
+	require.Equal(t, "t", e.Title)
+
+	// чужой пользователь не видит событий личного календаря
+	results, err = store.DeleteEventsByID(ctx, "", []string{results[0].ID, results[2].ID}, storage.BatchBestEffort, "2")
+	require.NoError(t, err)
+	for _, r := range results {
+		require.True(t, errors.Is(r.Err, storage.ErrEventNotFound))
+	}
+
+	ids := []string{events[0].ID, "404", events[1].ID}
+	results, err = store.DeleteEventsByID(ctx, "", ids, storage.BatchBestEffort, "1")
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.True(t, errors.Is(results[1].Err, storage.ErrEventNotFound))
+	require.NoError(t, results[2].Err)
+	events, err = store.GetEventsByUserID(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+
+	_, err = store.DeleteEventsByID(ctx, "", nil, storage.BatchAtomic, "1")
+	require.True(t, errors.Is(err, storage.ErrValidation))
+}
+
+func TestStorageTrash(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	start := time.Now().Add(30 * time.Minute)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteEventByID(ctx, id, "1"))
+
+	// удалённое событие не видно и о нём не напоминают
+	_, err = store.GetEventByID(id, "1")
+	require.True(t, errors.Is(err, storage.ErrEventNotFound))
+	events, err := store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+	require.True(t, errors.Is(store.DeleteEventByID(ctx, id, "1"), storage.ErrEventNotFound))
+
+	trash, err := store.GetTrash(ctx, "", "1")
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, id, trash[0].ID)
+	require.NotNil(t, trash[0].DeletedAt)
+	trash, err = store.GetTrash(ctx, "", "2")
+	require.NoError(t, err)
+	require.Len(t, trash, 0)
+
+	require.True(t, errors.Is(store.RestoreEvent(ctx, "", id, "2"), storage.ErrEventNotFound))
+	require.NoError(t, store.RestoreEvent(ctx, "", id, "1"))
+	e, err := store.GetEventByID(id, "1")
+	require.NoError(t, err)
+	require.Nil(t, e.DeletedAt)
+	events, err = store.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.True(t, errors.Is(store.RestoreEvent(ctx, "", id, "1"), storage.ErrEventNotFound))
+
+	// планировщик окончательно удаляет пролежавшие в корзине дольше trashRetention
+	require.NoError(t, store.DeleteEventByID(ctx, id, "1"))
+	require.NoError(t, store.DeleteEvents(ctx, 365*24*time.Hour, time.Hour))
+	require.Len(t, store.Trash, 1)
+	deletedAt := time.Now().Add(-2 * time.Hour)
+	e = store.Trash[id]
+	e.DeletedAt = &deletedAt
+	store.Trash[id] = e
+	require.NoError(t, store.DeleteEvents(ctx, 365*24*time.Hour, time.Hour))
+	require.Len(t, store.Trash, 0)
+	require.True(t, errors.Is(store.RestoreEvent(ctx, "", id, "1"), storage.ErrEventNotFound))
+}
+
+func TestStorageAudit(t *testing.T) {
+	store := New()
+	ctx := requestid.WithID(context.Background(), "req-1")
+
+	start := time.Now().Add(30 * time.Minute)
+	id, err := store.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, "1")
+	require.NoError(t, err)
+	title := "new"
+	require.NoError(t, store.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Notified: true}, "1"))
+
+	// отменённый пакет не остаётся в журнале
+	other := "other"
+	updates := []storage.EventBatchUpdate{
+		{ID: id, Event: storage.EventUpdateDTO{Title: &other, Notified: true}},
+		{ID: "missing", Event: storage.EventUpdateDTO{Title: &other, Notified: true}},
+	}
+	_, err = store.UpdateEvents(ctx, "", updates, storage.BatchAtomic, "1")
+	require.Error(t, err)
+
+	require.NoError(t, store.DeleteEventByID(ctx, id, "1"))
+	require.NoError(t, store.RestoreEvent(ctx, "", id, "1"))
+
+	history, err := store.GetEventHistory(ctx, "", id, "1")
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	ops := make([]storage.AuditOperation, 0, len(history))
+	for _, a := range history {
+		ops = append(ops, a.Operation)
+		require.Equal(t, "1", a.ActorID)
+		require.Equal(t, "req-1", a.RequestID)
+		require.Equal(t, id, a.After.ID)
+	}
+	require.Equal(t, []storage.AuditOperation{
+		storage.AuditCreate, storage.AuditUpdate, storage.AuditDelete, storage.AuditRestore,
+	}, ops)
+	require.Nil(t, history[0].Before)
+	require.Equal(t, "t", history[1].Before.Title)
+	require.Equal(t, "new", history[1].After.Title)
+	require.NotNil(t, history[2].After.DeletedAt)
+	require.Nil(t, history[3].After.DeletedAt)
+
+	// чужой пользователь истории не видит
+	_, err = store.GetEventHistory(ctx, "", id, "2")
+	require.True(t, errors.Is(err, storage.ErrEventNotFound))
+}
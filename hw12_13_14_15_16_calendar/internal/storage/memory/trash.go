@@ -0,0 +1,55 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// GetTrash возвращает события из корзины календаря, последние удалённые - первыми;
+// пустой calendarID - личный календарь пользователя.
+func (s *Storage) GetTrash(_ context.Context, calendarID string, userID string) ([]storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if calendarID == "" {
+		calendarID = s.personalCalendar(userID)
+	} else if err := storage.CheckRole(s.role(calendarID, userID), storage.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	events := []storage.Event{}
+	for _, e := range s.Trash {
+		if e.CalendarID == calendarID {
+			events = append(events, e)
+		}
+	}
+	slices.SortFunc(events, func(a, b storage.Event) int { return b.DeletedAt.Compare(*a.DeletedAt) })
+	return events, nil
+}
+
+// RestoreEvent возвращает событие из корзины календаря; пустой calendarID - личный
+// календарь пользователя. В ленте изменений восстановленное событие создаётся заново.
+func (s *Storage) RestoreEvent(ctx context.Context, calendarID string, id string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendarID, err := s.editorCalendar(calendarID, userID)
+	if err != nil {
+		return err
+	}
+	trashed, ok := s.Trash[id]
+	if !ok || trashed.CalendarID != calendarID {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
+	}
+
+	e := trashed
+	e.DeletedAt = nil
+	s.Events[id] = e
+	delete(s.Trash, id)
+	s.recordChange(storage.ChangeCreated, e)
+	s.recordAudit(ctx, storage.AuditRestore, userID, &trashed, e)
+	return nil
+}
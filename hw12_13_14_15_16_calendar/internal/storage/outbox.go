@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"strconv"
+	"time"
+)
+
+// OutboxMessage - напоминание, сохранённое в outbox в одной транзакции с пометкой события.
+// Релей планировщика публикует такие сообщения и помечает их доставленными.
+type OutboxMessage struct {
+	ID             string
+	IdempotencyKey string
+	Payload        []byte // EventToNotify в JSON
+	TraceParent    string // контекст трассировки напоминания, уходит в брокер вместе с сообщением
+}
+
+// NotificationKey возвращает ключ идемпотентности напоминания о событии e.
+// Ключ включает начало события (повторения), чтобы перенесённое событие получило
+// новое напоминание, смещение, чтобы напоминания за разное время не склеивались,
+// и получателя, чтобы владелец и приглашённые получили каждый своё.
+func NotificationKey(e EventToNotify) string {
+	return "event-" + e.ID + "-" + strconv.FormatInt(e.Start.Unix(), 10) +
+		"-" + strconv.FormatInt(int64(time.Duration(e.Reminder)/time.Second), 10) + "-" + e.UserID
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Периоды списка событий.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// DefaultTimeZone - зона аккаунта, которому зона не задана.
+const DefaultTimeZone = "UTC"
+
+var (
+	ErrUnknownPeriod   = newError(ErrValidation, "unknown period")
+	ErrInvalidTimeZone = newError(ErrValidation, "invalid time zone")
+)
+
+// ListingWindow возвращает границы [from, to) дня, недели (с понедельника) или месяца,
+// в которые попадает date. Границы считаются в зоне date: полночь там, где живёт
+// пользователь, а не на сервере. Переход на летнее время учитывается AddDate.
+func ListingWindow(date time.Time, period string) (time.Time, time.Time, error) {
+	loc := date.Location()
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+
+	switch period {
+	case PeriodDay:
+		return from, from.AddDate(0, 0, 1), nil
+	case PeriodWeek:
+		sinceMonday := (int(from.Weekday()) + 6) % 7
+		from = from.AddDate(0, 0, -sinceMonday)
+		return from, from.AddDate(0, 0, 7), nil
+	case PeriodMonth:
+		from = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, loc)
+		return from, from.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", ErrUnknownPeriod, period)
+	}
+}
+
+// LoadLocation загружает зону по имени IANA, например "Europe/Moscow".
+// Пустое имя и "Local" не принимаются: результат зависел бы от сервера.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return loc, nil
+}
+
+// EventTimeZone возвращает зону нового события: заданную в запросе, иначе зону аккаунта автора.
+func EventTimeZone(requested, accountZone string) string {
+	switch {
+	case requested != "":
+		return requested
+	case accountZone != "":
+		return accountZone
+	default:
+		return DefaultTimeZone
+	}
+}
+
+// Location возвращает зону аккаунта; незаданная зона - DefaultTimeZone.
+func (a Account) Location() (*time.Location, error) {
+	if a.TimeZone == "" {
+		return LoadLocation(DefaultTimeZone)
+	}
+	return LoadLocation(a.TimeZone)
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestListingWindow(t *testing.T) {
+	tokyo, err := LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// в UTC ещё 19 сентября (пятница), в Токио уже суббота, 20-е
+	date := time.Date(2025, time.September, 19, 20, 0, 0, 0, time.UTC).In(tokyo)
+
+	tests := []struct {
+		period   string
+		from, to time.Time
+	}{
+		{PeriodDay, time.Date(2025, time.September, 20, 0, 0, 0, 0, tokyo), time.Date(2025, time.September, 21, 0, 0, 0, 0, tokyo)},
+		{PeriodWeek, time.Date(2025, time.September, 15, 0, 0, 0, 0, tokyo), time.Date(2025, time.September, 22, 0, 0, 0, 0, tokyo)},
+		{PeriodMonth, time.Date(2025, time.September, 1, 0, 0, 0, 0, tokyo), time.Date(2025, time.October, 1, 0, 0, 0, 0, tokyo)},
+	}
+	for _, tt := range tests {
+		from, to, err := ListingWindow(date, tt.period)
+		require.NoError(t, err)
+		require.True(t, tt.from.Equal(from), tt.period)
+		require.True(t, tt.to.Equal(to), tt.period)
+	}
+
+	_, _, err = ListingWindow(date, "year")
+	require.True(t, errors.Is(err, ErrUnknownPeriod))
+}
+
+func TestListingWindowWeekStartsOnMonday(t *testing.T) {
+	sunday := time.Date(2025, time.September, 7, 12, 0, 0, 0, time.UTC)
+	from, to, err := ListingWindow(sunday, PeriodWeek)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2025, time.September, 8, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestListingWindowDST(t *testing.T) {
+	berlin, err := LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// 30 марта 2025 в Берлине переводят часы: в этом дне 23 часа
+	from, to, err := ListingWindow(time.Date(2025, time.March, 30, 12, 0, 0, 0, berlin), PeriodDay)
+	require.NoError(t, err)
+	require.Equal(t, 23*time.Hour, to.Sub(from))
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Moscow", loc.String())
+
+	for _, name := range []string{"", "Local", "Mars/Olympus", "../etc/passwd"} {
+		_, err := LoadLocation(name)
+		require.True(t, errors.Is(err, ErrInvalidTimeZone), name)
+	}
+
+	loc, err = Account{}.Location()
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, loc)
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxReminders - сколько напоминаний можно задать одному событию.
+	MaxReminders = 10
+	// MaxReminderOffset - самое раннее напоминание - за четыре недели до начала.
+	MaxReminderOffset = Offset(4 * 7 * 24 * time.Hour)
+	// ReminderGrace - насколько позже начала повторения ещё можно отправить напоминание.
+	// Нужно для напоминаний "в момент начала" (смещение 0): планировщик работает по тикеру
+	// и видит начало события уже прошедшим.
+	ReminderGrace = 5 * time.Minute
+	// reminderHorizon - насколько вперёд NextReminder ищет повторение.
+	reminderHorizon = 366 * 24 * time.Hour
+)
+
+var ErrInvalidReminder = newError(ErrValidation, "invalid reminder")
+
+// DefaultReminders задаются событию, у которого напоминания не указаны:
+// прежде планировщик напоминал обо всех событиях за час.
+var DefaultReminders = []Offset{Offset(time.Hour)}
+
+// Offset - за сколько до начала события напомнить.
+// В JSON - строка: "10m", "1h30m", "1d", "2w".
+type Offset time.Duration
+
+// ParseOffset разбирает смещение. Кроме единиц time.ParseDuration понимает
+// дни "d" и недели "w", которые должны идти в начале: "1w2d", "1d12h".
+func ParseOffset(s string) (Offset, error) {
+	rest := strings.TrimSpace(s)
+	var total time.Duration
+
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(rest, unit.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidReminder, s)
+		}
+		total += time.Duration(n) * unit.size
+		rest = rest[i+1:]
+	}
+
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidReminder, s)
+		}
+		total += d
+	} else if total == 0 && strings.TrimSpace(s) == "" {
+		return 0, fmt.Errorf("%w: empty offset", ErrInvalidReminder)
+	}
+	return Offset(total), nil
+}
+
+// String записывает смещение в том же виде, в каком его понимает ParseOffset.
+func (o Offset) String() string {
+	d := time.Duration(o)
+	if d == 0 {
+		return "0m"
+	}
+
+	var sb strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if n := d / unit.size; n > 0 {
+			sb.WriteString(strconv.FormatInt(int64(n), 10) + unit.suffix)
+			d -= n * unit.size
+		}
+	}
+	if d > 0 {
+		sb.WriteString(d.String())
+	}
+	return sb.String()
+}
+
+func (o Offset) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+func (o *Offset) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: offset must be a string like \"10m\" or \"1d\"", ErrInvalidReminder)
+	}
+	parsed, err := ParseOffset(s)
+	if err != nil {
+		return err
+	}
+	*o = parsed
+	return nil
+}
+
+// ValidateReminders проверяет смещения напоминаний события.
+func ValidateReminders(reminders []Offset) error {
+	if len(reminders) > MaxReminders {
+		return fmt.Errorf("%w: at most %d reminders are allowed", ErrInvalidReminder, MaxReminders)
+	}
+	for i, r := range reminders {
+		if r < 0 || r > MaxReminderOffset {
+			return fmt.Errorf("%w: offset %s is out of range", ErrInvalidReminder, r)
+		}
+		if slices.Contains(reminders[:i], r) {
+			return fmt.Errorf("%w: duplicate offset %s", ErrInvalidReminder, r)
+		}
+	}
+	return nil
+}
+
+// RemindersOrDefault возвращает напоминания нового события. Если они не заданы (nil),
+// используется устаревшее абсолютное время notification, а без него - DefaultReminders.
+// Пустой, но не nil срез означает "без напоминаний".
+func RemindersOrDefault(reminders []Offset, start, notification time.Time) []Offset {
+	if reminders != nil {
+		res := slices.Clone(reminders)
+		slices.Sort(res)
+		return res
+	}
+	if !notification.IsZero() && notification.Before(start) {
+		return []Offset{Offset(start.Sub(notification))}
+	}
+	return slices.Clone(DefaultReminders)
+}
+
+// Reminder - напоминание события и повторение, о котором по нему уже напомнили.
+type Reminder struct {
+	Offset Offset
+	// FiredFor - начало последнего повторения, о котором напомнили; нулевое - ещё не напоминали.
+	FiredFor time.Time
+}
+
+// DueOccurrence возвращает начало повторения события, о котором пора напомнить в момент now.
+// Это ближайшее ещё не напомненное повторение (позже FiredFor), начинающееся не раньше
+// now-ReminderGrace, время напоминания которого (начало минус смещение) уже наступило.
+// Каждое повторение выдаётся не более одного раза, если после отправки сохранять его в FiredFor.
+func DueOccurrence(e Event, r Reminder, now time.Time) (time.Time, bool, error) {
+	from := now.Add(-ReminderGrace)
+	if !r.FiredFor.IsZero() && !r.FiredFor.Before(from) {
+		from = r.FiredFor.Add(time.Nanosecond)
+	}
+	to := now.Add(time.Duration(r.Offset) + time.Nanosecond)
+	if !from.Before(to) {
+		return time.Time{}, false, nil
+	}
+
+	occ, err := Occurrences(e, time.UTC, from, to)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if len(occ) == 0 {
+		return time.Time{}, false, nil
+	}
+	return occ[0].Start, true, nil
+}
+
+// NextReminder возвращает время, раньше которого DueOccurrence для r не выдаст повторения:
+// время напоминания ближайшего ещё не напомненного повторения, начинающегося не раньше
+// now-ReminderGrace. Если такого повторения нет в ближайший год, возвращается время, когда
+// стоит проверить снова. Планировщик хранит его, чтобы не разворачивать серию на каждом проходе.
+func NextReminder(e Event, r Reminder, now time.Time) (time.Time, error) {
+	from := now.Add(-ReminderGrace)
+	if !r.FiredFor.IsZero() && !r.FiredFor.Before(from) {
+		from = r.FiredFor.Add(time.Nanosecond)
+	}
+	to := from.Add(reminderHorizon)
+
+	occ, err := Occurrences(e, time.UTC, from, to)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(occ) == 0 {
+		return to.Add(-time.Duration(r.Offset)), nil
+	}
+	return occ[0].Start.Add(-time.Duration(r.Offset)), nil
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"10m", 10 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1d", 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1w1d", 8 * 24 * time.Hour},
+		{"0m", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOffset(tt.in)
+			require.NoError(t, err)
+			require.Equal(t, Offset(tt.want), got)
+
+			// String даёт то, что снова разбирается в то же смещение
+			again, err := ParseOffset(got.String())
+			require.NoError(t, err)
+			require.Equal(t, got, again)
+		})
+	}
+
+	for _, in := range []string{"", "abc", "-10m", "1h2d", "xd"} {
+		_, err := ParseOffset(in)
+		require.True(t, errors.Is(err, ErrInvalidReminder), in)
+	}
+}
+
+func TestOffsetString(t *testing.T) {
+	require.Equal(t, "10m", Offset(10*time.Minute).String())
+	require.Equal(t, "1h30m", Offset(90*time.Minute).String())
+	require.Equal(t, "1d2h", Offset(26*time.Hour).String())
+	require.Equal(t, "0m", Offset(0).String())
+}
+
+func TestOffsetJSON(t *testing.T) {
+	var e EventCreateDTO
+	require.NoError(t, json.Unmarshal([]byte(`{"reminders": ["10m", "1d"]}`), &e))
+	require.Equal(t, []Offset{Offset(10 * time.Minute), Offset(24 * time.Hour)}, e.Reminders)
+
+	data, err := json.Marshal(Event{Reminders: e.Reminders})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"reminders":["10m","1d"]`)
+
+	require.Error(t, json.Unmarshal([]byte(`{"reminders": [600]}`), &e))
+}
+
+func TestValidateReminders(t *testing.T) {
+	require.NoError(t, ValidateReminders(nil))
+	require.NoError(t, ValidateReminders([]Offset{0, Offset(time.Hour)}))
+	require.Error(t, ValidateReminders([]Offset{Offset(time.Hour), Offset(time.Hour)}))
+	require.Error(t, ValidateReminders([]Offset{MaxReminderOffset + 1}))
+	require.Error(t, ValidateReminders(make([]Offset, MaxReminders+1)))
+}
+
+func TestRemindersOrDefault(t *testing.T) {
+	start := time.Date(2025, time.September, 19, 10, 0, 0, 0, time.UTC)
+
+	require.Equal(t, DefaultReminders, RemindersOrDefault(nil, start, time.Time{}))
+	require.Equal(t, []Offset{Offset(15 * time.Minute)}, RemindersOrDefault(nil, start, start.Add(-15*time.Minute)))
+	require.Empty(t, RemindersOrDefault([]Offset{}, start, time.Time{}))
+	require.Equal(t, []Offset{Offset(time.Minute), Offset(time.Hour)},
+		RemindersOrDefault([]Offset{Offset(time.Hour), Offset(time.Minute)}, start, time.Time{}))
+}
+
+func TestDueOccurrence(t *testing.T) {
+	start := time.Date(2025, time.September, 19, 10, 0, 0, 0, time.UTC)
+	e := Event{ID: "1", Start: start, End: start.Add(time.Hour)}
+	r := Reminder{Offset: Offset(10 * time.Minute)}
+
+	// рано
+	_, due, err := DueOccurrence(e, r, start.Add(-11*time.Minute))
+	require.NoError(t, err)
+	require.False(t, due)
+
+	occ, due, err := DueOccurrence(e, r, start.Add(-10*time.Minute))
+	require.NoError(t, err)
+	require.True(t, due)
+	require.Equal(t, start, occ)
+
+	// после отправки повторно не выдаётся
+	r.FiredFor = occ
+	_, due, err = DueOccurrence(e, r, start.Add(-5*time.Minute))
+	require.NoError(t, err)
+	require.False(t, due)
+
+	// напоминание "в момент начала" срабатывает с опозданием тикера, но не позже ReminderGrace
+	atStart := Reminder{}
+	_, due, err = DueOccurrence(e, atStart, start.Add(3*time.Second))
+	require.NoError(t, err)
+	require.True(t, due)
+	_, due, err = DueOccurrence(e, atStart, start.Add(ReminderGrace+time.Second))
+	require.NoError(t, err)
+	require.False(t, due)
+}
+
+func TestDueOccurrenceRecurring(t *testing.T) {
+	start := time.Date(2025, time.September, 15, 10, 0, 0, 0, time.UTC) // понедельник
+	e := Event{ID: "1", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=3"}
+	r := Reminder{Offset: Offset(time.Hour)}
+
+	occ, due, err := DueOccurrence(e, r, start.Add(-30*time.Minute))
+	require.NoError(t, err)
+	require.True(t, due)
+	require.Equal(t, start, occ)
+	r.FiredFor = occ
+
+	// до следующего повторения ещё больше часа
+	_, due, err = DueOccurrence(e, r, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.False(t, due)
+
+	occ, due, err = DueOccurrence(e, r, start.Add(23*time.Hour))
+	require.NoError(t, err)
+	require.True(t, due)
+	require.Equal(t, start.AddDate(0, 0, 1), occ)
+	r.FiredFor = occ
+
+	occ, due, err = DueOccurrence(e, r, start.AddDate(0, 0, 2).Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, due)
+	require.Equal(t, start.AddDate(0, 0, 2), occ)
+	r.FiredFor = occ
+
+	// серия закончилась
+	_, due, err = DueOccurrence(e, r, start.AddDate(0, 0, 3).Add(-time.Minute))
+	require.NoError(t, err)
+	require.False(t, due)
+}
+
+func TestNextReminder(t *testing.T) {
+	start := time.Date(2025, time.September, 15, 10, 0, 0, 0, time.UTC)
+	e := Event{ID: "1", Start: start, End: start.Add(time.Hour), RRule: "FREQ=WEEKLY;COUNT=2"}
+	r := Reminder{Offset: Offset(time.Hour)}
+
+	next, err := NextReminder(e, r, start.Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, start.Add(-time.Hour), next)
+
+	// пока время не наступило, DueOccurrence ничего не выдаёт, а в это время - выдаёт
+	_, due, err := DueOccurrence(e, r, next.Add(-time.Second))
+	require.NoError(t, err)
+	require.False(t, due)
+	_, due, err = DueOccurrence(e, r, next)
+	require.NoError(t, err)
+	require.True(t, due)
+
+	r.FiredFor = start
+	next, err = NextReminder(e, r, start)
+	require.NoError(t, err)
+	require.Equal(t, start.AddDate(0, 0, 7).Add(-time.Hour), next)
+
+	// серия закончилась: проверить снова не раньше чем через год
+	r.FiredFor = start.AddDate(0, 0, 7)
+	next, err = NextReminder(e, r, r.FiredFor)
+	require.NoError(t, err)
+	require.True(t, next.After(r.FiredFor.Add(360*24*time.Hour)))
+
+	_, err = NextReminder(Event{Start: start, End: start, RRule: "FREQ=SECONDLY"}, r, start)
+	require.True(t, errors.Is(err, ErrInvalidRRule))
+}
@@ -0,0 +1,366 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Поддерживаемое подмножество RFC 5545 RRULE: FREQ (DAILY/WEEKLY/MONTHLY),
+// INTERVAL, COUNT, UNTIL и BYDAY.
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// maxPeriods ограничивает разворачивание правила без COUNT и UNTIL.
+const maxPeriods = 100000
+
+var ErrInvalidRRule = newError(ErrValidation, "invalid rrule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum - элемент BYDAY: день недели и, для MONTHLY, его порядковый номер в месяце
+// (1MO - первый понедельник, -1FR - последняя пятница, 0 - каждый).
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+type RRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []WeekdayNum
+}
+
+// ParseRRule разбирает правило вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// Префикс "RRULE:" допускается.
+func ParseRRule(s string) (RRule, error) {
+	r := RRule{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return RRule{}, fmt.Errorf("%w: %q", ErrInvalidRRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("interval must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+			if err == nil && r.Count < 1 {
+				err = errors.New("count must be positive")
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(val)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(val)
+		default:
+			err = errors.New("unsupported part")
+		}
+		if err != nil {
+			return RRule{}, fmt.Errorf("%w: %s: %w", ErrInvalidRRule, part, err)
+		}
+	}
+
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return RRule{}, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRRule, r.Freq)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return RRule{}, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRRule)
+	}
+	if r.Freq != FreqMonthly {
+		for _, wd := range r.ByDay {
+			if wd.N != 0 {
+				return RRule{}, fmt.Errorf("%w: numeric BYDAY is allowed only with FREQ=MONTHLY", ErrInvalidRRule)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// ValidateRRule проверяет правило повторения события; пустое правило - событие не повторяется.
+func ValidateRRule(rrule string) error {
+	if rrule == "" {
+		return nil
+	}
+	_, err := ParseRRule(rrule)
+	return err
+}
+
+func parseUntil(val string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", val); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102T150405", val); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102", val)
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	var res []WeekdayNum
+	for _, item := range strings.Split(val, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("bad weekday %q", item)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("bad weekday %q", item)
+		}
+		var n int
+		if num := item[:len(item)-2]; num != "" {
+			var err error
+			n, err = strconv.Atoi(num)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("bad weekday %q", item)
+			}
+		}
+		res = append(res, WeekdayNum{N: n, Day: day})
+	}
+	return res, nil
+}
+
+// String возвращает правило в каноническом виде RFC 5545.
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			d := strings.ToUpper(wd.Day.String()[:2])
+			if wd.N != 0 {
+				d = strconv.Itoa(wd.N) + d
+			}
+			days = append(days, d)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between возвращает начала повторений с dtstart, попадающие в [from, to).
+// Даты из exDates исключаются, но учитываются в COUNT (как в RFC 5545).
+func (r RRule) Between(dtstart time.Time, exDates []time.Time, from, to time.Time) []time.Time {
+	var res []time.Time
+	n := 0
+
+	for period := range maxPeriods {
+		candidates := r.periodCandidates(dtstart, period)
+		if candidates == nil {
+			return res
+		}
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && c.After(r.Until) {
+				return res
+			}
+			if !c.Before(to) {
+				return res
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return res
+			}
+			if c.Before(from) || isExDate(c, exDates) {
+				continue
+			}
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// periodCandidates возвращает отсортированные кандидаты для period-го интервала правила.
+// Для MONTHLY пустой месяц (например, 31-е число) даёт пустой, но не nil срез.
+func (r RRule) periodCandidates(dtstart time.Time, period int) []time.Time {
+	step := period * r.Interval
+	h, m, s := dtstart.Clock()
+	at := func(y int, mon time.Month, d int) time.Time {
+		return time.Date(y, mon, d, h, m, s, dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		d := dtstart.AddDate(0, 0, step)
+		if len(r.ByDay) > 0 && !r.hasWeekday(d.Weekday()) {
+			return []time.Time{}
+		}
+		return []time.Time{at(d.Year(), d.Month(), d.Day())}
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			d := dtstart.AddDate(0, 0, 7*step)
+			return []time.Time{at(d.Year(), d.Month(), d.Day())}
+		}
+		// неделя начинается с понедельника (WKST=MO)
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, 7*step-offset)
+		res := make([]time.Time, 0, len(r.ByDay))
+		for i := range 7 {
+			d := monday.AddDate(0, 0, i)
+			if r.hasWeekday(d.Weekday()) {
+				res = append(res, at(d.Year(), d.Month(), d.Day()))
+			}
+		}
+		return res
+	case FreqMonthly:
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, dtstart.Location())
+		if len(r.ByDay) == 0 {
+			d := at(first.Year(), first.Month(), dtstart.Day())
+			if d.Month() != first.Month() {
+				return []time.Time{}
+			}
+			return []time.Time{d}
+		}
+		return r.monthlyByDay(first, at)
+	}
+	return nil
+}
+
+func (r RRule) monthlyByDay(first time.Time, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	res := []time.Time{}
+
+	for _, wd := range r.ByDay {
+		var days []int
+		for d := 1; d <= daysInMonth; d++ {
+			if first.AddDate(0, 0, d-1).Weekday() == wd.Day {
+				days = append(days, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			for _, d := range days {
+				res = append(res, at(first.Year(), first.Month(), d))
+			}
+		case wd.N > 0 && wd.N <= len(days):
+			res = append(res, at(first.Year(), first.Month(), days[wd.N-1]))
+		case wd.N < 0 && -wd.N <= len(days):
+			res = append(res, at(first.Year(), first.Month(), days[len(days)+wd.N]))
+		}
+	}
+
+	slices.SortFunc(res, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(res, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+func (r RRule) hasWeekday(d time.Weekday) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == d {
+			return true
+		}
+	}
+	return false
+}
+
+func isExDate(t time.Time, exDates []time.Time) bool {
+	for _, ex := range exDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrences разворачивает событие в повторения, начинающиеся в [from, to).
+// Неповторяющееся событие возвращается, если оно попадает в интервал.
+// У повторений тот же ID, что и у исходного события; сдвигаются только Start и End.
+// Правило разворачивается в зоне события (Event.Location): день недели и местное время
+// начала не зависят от зоны, в которой хранится e.Start, и не сдвигаются при переходе
+// на летнее время. loc - зона запроса: в ней возвращаются Start и End, и в ней
+// разворачиваются события без своей зоны.
+func Occurrences(e Event, loc *time.Location, from, to time.Time) ([]Event, error) {
+	if e.RRule == "" {
+		if !e.Start.Before(from) && e.Start.Before(to) {
+			e.Start, e.End = e.Start.In(loc), e.End.In(loc)
+			return []Event{e}, nil
+		}
+		return nil, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := e.End.Sub(e.Start)
+	starts := rule.Between(e.Start.In(e.Location(loc)), e.ExDates, from, to)
+	res := make([]Event, 0, len(starts))
+	for _, start := range starts {
+		start = start.In(loc)
+		occ := e
+		occ.Start = start
+		occ.End = start.Add(duration)
+		res = append(res, occ)
+	}
+	return res, nil
+}
+
+// Outdated сообщает, закончилось ли событие (для повторяющегося - последнее повторение) до before.
+// Бесконечная серия (без COUNT и UNTIL) устаревшей не бывает.
+func Outdated(e Event, before time.Time) (bool, error) {
+	if e.RRule == "" {
+		return e.End.Before(before), nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return false, err
+	}
+	if rule.Count == 0 && rule.Until.IsZero() {
+		return false, nil
+	}
+
+	// серия конечна, поэтому Between остановится на COUNT или UNTIL
+	occ, err := Occurrences(e, time.UTC, before.Add(-e.End.Sub(e.Start)),
+		time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return false, err
+	}
+	for _, o := range occ {
+		if !o.End.Before(before) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
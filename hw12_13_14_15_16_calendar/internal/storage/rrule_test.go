@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4")
+	require.NoError(t, err)
+	require.Equal(t, FreqWeekly, r.Freq)
+	require.Equal(t, 2, r.Interval)
+	require.Equal(t, 4, r.Count)
+	require.Equal(t, []WeekdayNum{{Day: time.Monday}, {Day: time.Wednesday}}, r.ByDay)
+	require.Equal(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=MO,WE", r.String())
+
+	r, err = ParseRRule("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20251231T000000Z")
+	require.NoError(t, err)
+	require.Equal(t, []WeekdayNum{{N: -1, Day: time.Friday}}, r.ByDay)
+	require.Equal(t, time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC), r.Until)
+
+	for _, bad := range []string{
+		"",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;BYMONTH=1",
+	} {
+		_, err := ParseRRule(bad)
+		require.True(t, errors.Is(err, ErrInvalidRRule), bad)
+	}
+}
+
+func TestRRuleBetween(t *testing.T) {
+	// среда, 3 сентября 2025
+	dtstart := time.Date(2025, time.September, 3, 10, 0, 0, 0, time.UTC)
+	from := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	day := func(d int) time.Time { return time.Date(2025, time.September, d, 10, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		rule    string
+		exDates []time.Time
+		want    []time.Time
+	}{
+		{rule: "FREQ=DAILY;COUNT=3", want: []time.Time{day(3), day(4), day(5)}},
+		{rule: "FREQ=DAILY;INTERVAL=10", want: []time.Time{day(3), day(13), day(23)}},
+		{rule: "FREQ=DAILY;UNTIL=20250905T100000Z", want: []time.Time{day(3), day(4), day(5)}},
+		{rule: "FREQ=WEEKLY", want: []time.Time{day(3), day(10), day(17), day(24)}},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", want: []time.Time{day(3), day(8), day(10), day(15)}},
+		{
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			exDates: []time.Time{day(8)},
+			want:    []time.Time{day(3), day(10), day(15)},
+		},
+		{rule: "FREQ=MONTHLY;BYDAY=-1FR", want: []time.Time{day(26)}},
+		{rule: "FREQ=MONTHLY;COUNT=2", want: []time.Time{day(3)}},
+	}
+
+	for _, tc := range tests {
+		r, err := ParseRRule(tc.rule)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, r.Between(dtstart, tc.exDates, from, to), tc.rule)
+	}
+}
+
+func TestRRuleMonthlySkipsShortMonths(t *testing.T) {
+	dtstart := time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC)
+	r, err := ParseRRule("FREQ=MONTHLY;COUNT=3")
+	require.NoError(t, err)
+
+	got := r.Between(dtstart, nil, dtstart, dtstart.AddDate(1, 0, 0))
+	require.Equal(t, []time.Time{
+		dtstart,
+		time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, time.May, 31, 9, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestOccurrences(t *testing.T) {
+	start := time.Date(2025, time.September, 1, 9, 0, 0, 0, time.UTC)
+	e := Event{
+		ID:    "1",
+		Title: "stand-up",
+		Start: start,
+		End:   start.Add(15 * time.Minute),
+		RRule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+	}
+
+	from := time.Date(2025, time.September, 5, 0, 0, 0, 0, time.UTC)
+	occ, err := Occurrences(e, time.UTC, from, from.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, occ, 5)
+	for _, o := range occ {
+		require.Equal(t, "1", o.ID)
+		require.Equal(t, 15*time.Minute, o.End.Sub(o.Start))
+		require.NotEqual(t, time.Saturday, o.Start.Weekday())
+		require.NotEqual(t, time.Sunday, o.Start.Weekday())
+	}
+
+	e.RRule = ""
+	occ, err = Occurrences(e, time.UTC, from, from.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Empty(t, occ)
+}
+
+func TestOccurrencesInEventTimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// понедельник 00:30 по Берлину - ещё воскресенье в UTC; сервер хранит начало в UTC
+	start := time.Date(2026, time.March, 16, 0, 30, 0, 0, berlin).UTC()
+	e := Event{
+		ID:       "1",
+		Start:    start,
+		End:      start.Add(time.Hour),
+		RRule:    "FREQ=WEEKLY;BYDAY=MO",
+		TimeZone: "Europe/Berlin",
+	}
+
+	// 29 марта 2026 года Берлин переходит на летнее время
+	occ, err := Occurrences(e, time.UTC, start, start.AddDate(0, 0, 27))
+	require.NoError(t, err)
+	require.Len(t, occ, 4)
+	for _, o := range occ {
+		local := o.Start.In(berlin)
+		require.Equal(t, time.Monday, local.Weekday())
+		require.Equal(t, 0, local.Hour())
+		require.Equal(t, 30, local.Minute())
+		require.Equal(t, time.UTC, o.Start.Location())
+		require.Equal(t, time.Hour, o.End.Sub(o.Start))
+	}
+	require.Equal(t, time.Date(2026, time.April, 5, 22, 30, 0, 0, time.UTC), occ[3].Start)
+
+	// событие без зоны разворачивается в зоне запроса
+	e.TimeZone = ""
+	occ, err = Occurrences(e, berlin, start, start.AddDate(0, 0, 27))
+	require.NoError(t, err)
+	require.Len(t, occ, 4)
+	require.Equal(t, berlin, occ[0].Start.Location())
+	require.Equal(t, time.Monday, occ[0].Start.Weekday())
+
+	// в UTC это понедельники 23:30 - в Берлине уже вторники
+	occ, err = Occurrences(e, time.UTC, start, start.AddDate(0, 0, 27))
+	require.NoError(t, err)
+	require.NotEmpty(t, occ)
+	require.Equal(t, time.Tuesday, occ[0].Start.In(berlin).Weekday())
+}
+
+func TestOccurrencesAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// начало пришло от клиента с фиксированным смещением зимнего времени
+	start := time.Date(2026, time.March, 27, 9, 0, 0, 0, time.FixedZone("+0100", 3600))
+	e := Event{Start: start, End: start.Add(30 * time.Minute), RRule: "FREQ=DAILY;COUNT=4", TimeZone: "Europe/Berlin"}
+
+	occ, err := Occurrences(e, time.UTC, start, start.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Len(t, occ, 4)
+	for _, o := range occ {
+		require.Equal(t, 9, o.Start.In(berlin).Hour())
+	}
+	require.Equal(t, time.Date(2026, time.March, 30, 7, 0, 0, 0, time.UTC), occ[3].Start.UTC())
+}
+
+func TestOutdated(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	e := Event{Start: start, End: start.Add(time.Hour)}
+
+	for rrule, want := range map[string]bool{
+		"":                                   true,
+		"FREQ=DAILY":                         false,
+		"FREQ=DAILY;COUNT=10":                true,
+		"FREQ=MONTHLY;COUNT=13":              false,
+		"FREQ=WEEKLY;UNTIL=20241201T000000Z": true,
+		"FREQ=WEEKLY;UNTIL=20250201T000000Z": false,
+	} {
+		e.RRule = rrule
+		got, err := Outdated(e, cutoff)
+		require.NoError(t, err)
+		require.Equal(t, want, got, rrule)
+	}
+
+	e.RRule = ""
+	e.End = cutoff
+	got, err := Outdated(e, cutoff)
+	require.NoError(t, err)
+	require.False(t, got)
+}
+
+func TestValidateRRule(t *testing.T) {
+	require.NoError(t, ValidateRRule(""))
+	require.NoError(t, ValidateRRule("RRULE:FREQ=WEEKLY;BYDAY=MO,WE"))
+	require.True(t, errors.Is(ValidateRRule("FREQ=SECONDLY"), ErrInvalidRRule))
+	require.True(t, errors.Is(ValidateRRule("FREQ"), ErrValidation))
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultSearchLimit - размер страницы поиска, если limit не задан.
+	DefaultSearchLimit = 50
+	// MaxSearchLimit - самая большая страница поиска.
+	MaxSearchLimit = 500
+)
+
+// Поля сортировки результатов поиска; с префиксом "-" - по убыванию.
+const (
+	SortStart   = "start"
+	SortCreated = "created"
+	SortTitle   = "title"
+)
+
+var (
+	ErrInvalidSearch = newError(ErrValidation, "invalid search")
+	ErrInvalidCursor = newError(ErrValidation, "invalid cursor")
+)
+
+// farFuture - верхняя граница поиска без to; до неё доходят только конечные серии.
+var farFuture = time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SearchQuery - параметры поиска событий пользователя. Нулевые поля не ограничивают поиск.
+type SearchQuery struct {
+	// CalendarID - искать в этом календаре; пустой - в личном календаре и приглашениях.
+	CalendarID string
+	// Событие (для повторяющегося - хотя бы одно повторение) начинается в [From, To).
+	From time.Time
+	To   time.Time
+	// Location - зона запроса, в ней разворачиваются события без своей зоны; nil - UTC.
+	Location *time.Location
+	// Text - слова, каждое из которых должно встретиться в названии или описании.
+	Text     string
+	Notified *bool
+	// Sort - start, created или title, с "-" - по убыванию; по умолчанию start.
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+// SearchResult - страница найденных событий. Повторяющиеся события не разворачиваются.
+// NextCursor передаётся в следующий запрос; пустой - страниц больше нет.
+type SearchResult struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// Cursor - позиция в выдаче: ключ сортировки и ID последнего события страницы.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// Normalize проверяет запрос и подставляет значения по умолчанию.
+func (q SearchQuery) Normalize() (SearchQuery, error) {
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return q, fmt.Errorf("%w: from must be before to", ErrInvalidSearch)
+	}
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultSearchLimit
+	case q.Limit < 0 || q.Limit > MaxSearchLimit:
+		return q, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, MaxSearchLimit)
+	}
+	if q.Sort == "" {
+		q.Sort = SortStart
+	}
+	if _, _, err := q.SortField(); err != nil {
+		return q, err
+	}
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Location == nil {
+		q.Location = time.UTC
+	}
+	return q, nil
+}
+
+// SortField возвращает поле сортировки и её направление.
+func (q SearchQuery) SortField() (string, bool, error) {
+	field, desc := strings.CutPrefix(q.Sort, "-")
+	switch field {
+	case SortStart, SortCreated, SortTitle:
+		return field, desc, nil
+	default:
+		return "", false, fmt.Errorf("%w: unknown sort %q", ErrInvalidSearch, q.Sort)
+	}
+}
+
+// DecodeCursor разбирает курсор запроса. Курсор, выданный для другой сортировки, не принимается.
+func (q SearchQuery) DecodeCursor() (Cursor, bool, error) {
+	if q.Cursor == "" {
+		return Cursor{}, false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return Cursor{}, false, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != q.Sort || c.ID == "" {
+		return Cursor{}, false, ErrInvalidCursor
+	}
+	return c, true, nil
+}
+
+// CursorAfter возвращает курсор, указывающий на событие e в выдаче запроса q.
+func (q SearchQuery) CursorAfter(e Event) string {
+	field, _, _ := q.SortField()
+	data, _ := json.Marshal(Cursor{Sort: q.Sort, Key: SortKey(e, field), ID: e.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// SortKey - значение поля сортировки события в том виде, в каком оно хранится в курсоре.
+func SortKey(e Event, field string) string {
+	switch field {
+	case SortCreated:
+		return e.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortTitle:
+		return e.Title
+	default:
+		return e.Start.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// ParseSortKey разбирает ключ курсора: время для start и created, строку для title.
+func ParseSortKey(field, key string) (any, error) {
+	if field == SortTitle {
+		return key, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// InRange сообщает, начинается ли событие (или хотя бы одно его повторение) в [from, to).
+// loc - зона запроса (см. Occurrences).
+func InRange(e Event, loc *time.Location, from, to time.Time) (bool, error) {
+	if from.IsZero() && to.IsZero() {
+		return true, nil
+	}
+	if to.IsZero() {
+		to = farFuture
+	}
+	if e.RRule != "" && to.Equal(farFuture) {
+		// бесконечная серия повторяется и после from
+		rule, err := ParseRRule(e.RRule)
+		if err != nil {
+			return false, err
+		}
+		if rule.Count == 0 && rule.Until.IsZero() {
+			return true, nil
+		}
+	}
+	occ, err := Occurrences(e, loc, from, to)
+	if err != nil {
+		return false, err
+	}
+	return len(occ) > 0, nil
+}
+
+// MatchText сообщает, встречается ли каждое слово text в названии или описании события.
+// Сравнение без учёта регистра и по целым словам - как to_tsvector('simple') в sqlstorage.
+func MatchText(e Event, text string) bool {
+	words := make(map[string]bool)
+	for _, w := range searchWords(e.Title + " " + e.Description) {
+		words[w] = true
+	}
+	for _, w := range searchWords(text) {
+		if !words[w] {
+			return false
+		}
+	}
+	return true
+}
+
+func searchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestSearchQueryNormalize(t *testing.T) {
+	q, err := SearchQuery{Text: "  planning "}.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, DefaultSearchLimit, q.Limit)
+	require.Equal(t, SortStart, q.Sort)
+	require.Equal(t, "planning", q.Text)
+
+	q, err = SearchQuery{Sort: "-title"}.Normalize()
+	require.NoError(t, err)
+	field, desc, err := q.SortField()
+	require.NoError(t, err)
+	require.Equal(t, SortTitle, field)
+	require.True(t, desc)
+
+	now := time.Now()
+	for _, bad := range []SearchQuery{
+		{Sort: "duration"},
+		{Limit: -1},
+		{Limit: MaxSearchLimit + 1},
+		{From: now, To: now},
+	} {
+		_, err := bad.Normalize()
+		require.True(t, errors.Is(err, ErrInvalidSearch), bad)
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	q := SearchQuery{Sort: "-created"}
+	e := Event{ID: "42", CreatedAt: time.Date(2025, time.September, 1, 10, 0, 0, 123456000, time.UTC)}
+
+	q.Cursor = q.CursorAfter(e)
+	c, ok, err := q.DecodeCursor()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "42", c.ID)
+
+	key, err := ParseSortKey(SortCreated, c.Key)
+	require.NoError(t, err)
+	require.True(t, e.CreatedAt.Equal(key.(time.Time)))
+
+	// курсор другой сортировки и испорченный курсор не принимаются
+	other := SearchQuery{Sort: SortStart, Cursor: q.Cursor}
+	_, _, err = other.DecodeCursor()
+	require.True(t, errors.Is(err, ErrInvalidCursor))
+	q.Cursor = "not a cursor"
+	_, _, err = q.DecodeCursor()
+	require.True(t, errors.Is(err, ErrInvalidCursor))
+}
+
+func TestMatchText(t *testing.T) {
+	e := Event{Title: "Планёрка команды", Description: "Обсуждаем релиз 2.0, see the roadmap"}
+
+	require.True(t, MatchText(e, "планёрка"))
+	require.True(t, MatchText(e, "РЕЛИЗ roadmap"))
+	require.True(t, MatchText(e, ""))
+	require.False(t, MatchText(e, "план"), "only whole words match")
+	require.False(t, MatchText(e, "релиз ретро"), "every word must match")
+}
+
+func TestInRange(t *testing.T) {
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+	from := start.AddDate(0, 1, 0)
+	to := from.AddDate(0, 0, 7)
+
+	single := Event{Start: start, End: start.Add(time.Hour)}
+	daily := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY"}
+	short := Event{Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=3"}
+
+	tests := []struct {
+		e        Event
+		from, to time.Time
+		want     bool
+	}{
+		{single, time.Time{}, time.Time{}, true},
+		{single, start, time.Time{}, true},
+		{single, time.Time{}, start, false},
+		{single, from, to, false},
+		{daily, from, to, true},
+		{daily, from, time.Time{}, true},
+		{short, from, time.Time{}, false},
+		{short, start.AddDate(0, 0, 2), time.Time{}, true},
+	}
+	for i, tt := range tests {
+		got, err := InRange(tt.e, time.UTC, tt.from, tt.to)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, i)
+	}
+}
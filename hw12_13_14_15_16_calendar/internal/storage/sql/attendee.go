@@ -0,0 +1,117 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// foreignKeyViolation - код ошибки PostgreSQL при нарушении внешнего ключа.
+const foreignKeyViolation = "23503"
+
+// attendeesColumn - приглашённые события в JSON, как storage.Attendee.
+const attendeesColumn = `coalesce((select json_agg(json_build_object(
+		'userId', a.account_id::text, 'status', a.status) order by a.account_id)
+	from event_attendee a where a.event_id = event.id), '[]')`
+
+// visibleTo - условие "событие личного календаря пользователя $1 или он на него приглашён".
+const visibleTo = `(calendar_id = ` + personalCalendar + `
+	or id in (select event_id from event_attendee where account_id = $1))`
+
+// toAttendees разбирает attendeesColumn и считает ответы.
+func toAttendees(data []byte, e *storage.Event) error {
+	if err := json.Unmarshal(data, &e.Attendees); err != nil {
+		return err
+	}
+	e.Responses = storage.CountResponses(e.Attendees)
+	return nil
+}
+
+// replaceAttendees задаёт событию новый список приглашённых. Ответы тех,
+// кто был приглашён и раньше, сохраняются; новые получают pending.
+func replaceAttendees(ctx context.Context, tx *sql.Tx, eventID string, userIDs []string) error {
+	ids, err := parseIDs(userIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", storage.ErrInvalidAttendee, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `delete from event_attendee
+		where event_id = $1 and account_id <> all($2::bigint[]);`, eventID, ids)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `insert into event_attendee (event_id, account_id)
+		select $1, unnest($2::bigint[]) on conflict do nothing;`, eventID, ids)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w: no such account", storage.ErrInvalidAttendee)
+	}
+	return err
+}
+
+// insertAttendees приглашает на события eventIDs аккаунты userIDs: i-й аккаунт - на i-е событие.
+func insertAttendees(ctx context.Context, tx *sql.Tx, eventIDs []int64, userIDs []string) error {
+	ids, err := parseIDs(userIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", storage.ErrInvalidAttendee, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `insert into event_attendee (event_id, account_id)
+		select * from unnest($1::bigint[], $2::bigint[]) on conflict do nothing;`, eventIDs, ids)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w: no such account", storage.ErrInvalidAttendee)
+	}
+	return err
+}
+
+// RespondToEvent записывает ответ приглашённого пользователя на приглашение.
+func (s *DBStorage) RespondToEvent(ctx context.Context, eventID string, userID string,
+	status storage.AttendeeStatus,
+) error {
+	ids, err := parseIDs([]string{eventID, userID})
+	if err != nil {
+		return storage.ErrNotInvited
+	}
+
+	sqlSt := `update event_attendee set status = $3 where event_id = $1 and account_id = $2
+		and event_id in (select id from event where deleted_at is null);`
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, sqlSt, ids[0], ids[1], string(status))
+	if err != nil {
+		s.Logg.Error("error in responding to invitation", zap.Error(err), zap.String("eventID", eventID))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotInvited
+	}
+	if err := recordChanges(ctx, tx, storage.ChangeUpdated, ids[:1], nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// attendeeIDs возвращает приглашённых события.
+func attendeeIDs(ctx context.Context, tx *sql.Tx, eventID string) ([]int64, error) {
+	ids, err := queryIDs(ctx, tx, `select account_id from event_attendee where event_id = $1;`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return parseIDs(ids)
+}
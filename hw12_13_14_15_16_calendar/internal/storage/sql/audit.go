@@ -0,0 +1,118 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// auditSnapshot возвращает события eventIDs, в том числе из корзины, по ID - состояние
+// для журнала аудита.
+func auditSnapshot(ctx context.Context, tx *sql.Tx, eventIDs []int64) (map[string]storage.Event, error) {
+	events, err := selectEvents(ctx, tx, `id = any($1::bigint[])`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]storage.Event, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+	return byID, nil
+}
+
+// recordAudit записывает в журнал аудита операцию op пользователя actorID над событиями eventIDs
+// в транзакции tx. before - события до операции (см. auditSnapshot), nil при создании;
+// состояние после операции читается из транзакции.
+func recordAudit(ctx context.Context, tx *sql.Tx, op storage.AuditOperation, actorID string,
+	eventIDs []int64, before map[string]storage.Event,
+) error {
+	after, err := auditSnapshot(ctx, tx, eventIDs)
+	if err != nil {
+		return err
+	}
+
+	befores := make([]string, len(eventIDs))
+	afters := make([]string, len(eventIDs))
+	for i, n := range eventIDs {
+		id := strconv.FormatInt(n, 10)
+		if e, ok := before[id]; ok {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			befores[i] = string(data)
+		}
+		data, err := json.Marshal(after[id])
+		if err != nil {
+			return err
+		}
+		afters[i] = string(data)
+	}
+
+	_, err = tx.ExecContext(ctx, `insert into event_audit (event_id, actor_id, operation, before, after, request_id)
+		select t.id, $2::bigint, $3, nullif(t.before, '')::jsonb, t.after::jsonb, $4
+		from unnest($1::bigint[], $5::text[], $6::text[]) as t(id, before, after);`,
+		eventIDs, actorID, string(op), requestid.FromContext(ctx), befores, afters)
+	return err
+}
+
+// GetEventHistory возвращает журнал аудита события календаря, старые записи - первыми;
+// пустой calendarID - личный календарь пользователя. История события в корзине тоже доступна.
+func (s *DBStorage) GetEventHistory(ctx context.Context, calendarID string, eventID string,
+	userID string,
+) ([]storage.AuditEntry, error) {
+	if _, err := parseIDs([]string{eventID}); err != nil {
+		return nil, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	var err error
+	if calendarID == "" {
+		err = s.DB.QueryRowContext(ctx, `select id from event where id = $2 and calendar_id = `+personalCalendar+`;`,
+			userID, eventID).Scan(&eventID)
+	} else {
+		if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleViewer); err != nil {
+			return nil, err
+		}
+		err = s.DB.QueryRowContext(ctx, `select id from event where id = $1 and calendar_id = $2;`,
+			eventID, calendarID).Scan(&eventID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `select id, event_id, actor_id, operation, before, after, request_id, created_at
+		from event_audit where event_id = $1 order by id;`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []storage.AuditEntry{}
+	for rows.Next() {
+		var a storage.AuditEntry
+		var before, after []byte
+		err := rows.Scan(&a.ID, &a.EventID, &a.ActorID, &a.Operation, &before, &after, &a.RequestID, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if before != nil {
+			if err := json.Unmarshal(before, &a.Before); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal(after, &a.After); err != nil {
+			return nil, err
+		}
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
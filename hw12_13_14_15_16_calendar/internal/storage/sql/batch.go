@@ -0,0 +1,274 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// AddEvents добавляет пакет событий в календарь; пустой calendarID - личный календарь пользователя.
+// Все события вставляются одним многострочным insert (см. insertEvents).
+func (s *DBStorage) AddEvents(ctx context.Context, calendarID string, events []storage.EventCreateDTO,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(events)); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]storage.BatchResult, len(events))
+	for i, e := range events {
+		results[i].Err = storage.ValidateEvent(e, userID)
+	}
+	if err := checkAttendeeAccounts(ctx, tx, events, results); err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(events, func(e storage.EventCreateDTO) bool { return e.RejectOverlap }) {
+		if err := lockCalendar(ctx, tx, calendarID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = runBatch(ctx, tx, mode, results, func(idx []int) error {
+		batch := make([]storage.EventCreateDTO, 0, len(idx))
+		for _, i := range idx {
+			batch = append(batch, events[i])
+		}
+		ids, err := insertEvents(ctx, tx, calendarID, batch, userID)
+		if err != nil {
+			return err
+		}
+		for k, i := range idx {
+			if !events[i].RejectOverlap {
+				continue
+			}
+			if err := checkOverlaps(ctx, tx, ids[k], calendarID); err != nil {
+				return &storage.BatchItemError{Index: i, Err: err}
+			}
+		}
+		for k, i := range idx {
+			results[i].ID = ids[k]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, tx.Commit()
+}
+
+// UpdateEvents меняет пакет событий календаря; пустой calendarID - личный календарь пользователя.
+// Изменения разные у каждого события, поэтому применяются по одному в общей транзакции.
+func (s *DBStorage) UpdateEvents(ctx context.Context, calendarID string, updates []storage.EventBatchUpdate,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(updates)); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]storage.BatchResult, len(updates))
+	for i, u := range updates {
+		results[i] = storage.BatchResult{ID: u.ID, Err: storage.ValidateEventUpdate(u.Event, userID)}
+	}
+
+	err = runBatch(ctx, tx, mode, results, func(idx []int) error {
+		for _, i := range idx {
+			if err := s.updateEventTx(ctx, tx, calendarID, updates[i].ID, updates[i].Event, userID); err != nil {
+				return &storage.BatchItemError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, tx.Commit()
+}
+
+// DeleteEventsByID переносит в корзину пакет событий календаря одним запросом;
+// пустой calendarID - личный календарь пользователя.
+func (s *DBStorage) DeleteEventsByID(ctx context.Context, calendarID string, ids []string,
+	mode storage.BatchMode, userID string,
+) ([]storage.BatchResult, error) {
+	if err := storage.ValidateBatch(mode, len(ids)); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// нечисловые ID, события других календарей и уже удалённые не найдены
+	var numIDs []int64
+	for _, id := range ids {
+		if n, err := parseIDs([]string{id}); err == nil {
+			numIDs = append(numIDs, n[0])
+		}
+	}
+	found, err := queryIDs(ctx, tx, `select id from event where calendar_id = $1 and id = any($2::bigint[])
+		and deleted_at is null;`,
+		calendarID, numIDs)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]storage.BatchResult, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+		if !slices.Contains(found, id) || slices.Contains(ids[:i], id) {
+			results[i].Err = fmt.Errorf("%w: %s", storage.ErrEventNotFound, id)
+		}
+	}
+
+	err = runBatch(ctx, tx, mode, results, func(idx []int) error {
+		eventIDs := make([]string, 0, len(idx))
+		for _, i := range idx {
+			eventIDs = append(eventIDs, ids[i])
+		}
+		numIDs, err := parseIDs(eventIDs)
+		if err != nil {
+			return err
+		}
+		before, err := auditSnapshot(ctx, tx, numIDs)
+		if err != nil {
+			return err
+		}
+		if err := recordChanges(ctx, tx, storage.ChangeDeleted, numIDs, nil); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `update event set deleted_at = now() where id = any($1::bigint[]);`, numIDs)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, storage.AuditDelete, userID, numIDs, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, tx.Commit()
+}
+
+// runBatch применяет apply к событиям пакета, прошедшим проверки (results[i].Err == nil).
+// apply сообщает, на каком событии ошибка, через storage.BatchItemError.
+// В режиме BatchAtomic любая ошибка отменяет весь пакет. В режиме BatchBestEffort пакет
+// сначала применяется целиком в точке сохранения, а если не вышло - по одному событию,
+// чтобы ошибка досталась только своему событию.
+func runBatch(ctx context.Context, tx *sql.Tx, mode storage.BatchMode, results []storage.BatchResult,
+	apply func(idx []int) error,
+) error {
+	valid := make([]int, 0, len(results))
+	for i, r := range results {
+		if r.Err == nil {
+			valid = append(valid, i)
+			continue
+		}
+		if mode == storage.BatchAtomic {
+			return &storage.BatchItemError{Index: i, Err: r.Err}
+		}
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+	if mode == storage.BatchAtomic {
+		return apply(valid)
+	}
+
+	if err := inSavepoint(ctx, tx, func() error { return apply(valid) }); err == nil {
+		return nil
+	}
+	for _, i := range valid {
+		err := inSavepoint(ctx, tx, func() error { return apply([]int{i}) })
+		var itemErr *storage.BatchItemError
+		if errors.As(err, &itemErr) {
+			err = itemErr.Err
+		}
+		results[i].Err = err
+	}
+	return nil
+}
+
+// inSavepoint выполняет do в точке сохранения: при ошибке отменяются только изменения do,
+// и транзакция продолжается.
+func inSavepoint(ctx context.Context, tx *sql.Tx, do func() error) error {
+	if _, err := tx.ExecContext(ctx, `savepoint batch_item;`); err != nil {
+		return err
+	}
+	if err := do(); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, `rollback to savepoint batch_item;`); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `release savepoint batch_item;`)
+	return err
+}
+
+// checkAttendeeAccounts отмечает ошибкой события пакета, среди приглашённых которых есть
+// несуществующие аккаунты, чтобы ошибка внешнего ключа не досталась всему пакету.
+func checkAttendeeAccounts(ctx context.Context, tx *sql.Tx, events []storage.EventCreateDTO,
+	results []storage.BatchResult,
+) error {
+	var all []int64
+	for i, e := range events {
+		if results[i].Err != nil {
+			continue
+		}
+		ids, err := parseIDs(e.Attendees)
+		if err != nil {
+			results[i].Err = fmt.Errorf("%w: %w", storage.ErrInvalidAttendee, err)
+			continue
+		}
+		all = append(all, ids...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	found, err := queryIDs(ctx, tx, `select id from account where id = any($1::bigint[]);`, all)
+	if err != nil {
+		return err
+	}
+	for i, e := range events {
+		if results[i].Err != nil {
+			continue
+		}
+		for _, a := range e.Attendees {
+			if !slices.Contains(found, a) {
+				results[i].Err = fmt.Errorf("%w: no account %s", storage.ErrInvalidAttendee, a)
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// personalCalendar - ID личного календаря пользователя $1.
+const personalCalendar = `(select id from calendar where personal and owner_id = $1)`
+
+// calendarRole возвращает права пользователя на календарь; без прав - пустую роль.
+func calendarRole(ctx context.Context, q querier, calendarID string, userID string) (storage.Role, error) {
+	ids, err := parseIDs([]string{calendarID, userID})
+	if err != nil {
+		return "", nil
+	}
+
+	var role storage.Role
+	err = q.QueryRowContext(ctx, `select role from calendar_acl where calendar_id = $1 and account_id = $2;`,
+		ids[0], ids[1]).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+// checkCalendar проверяет, что у пользователя есть права need на календарь.
+func checkCalendar(ctx context.Context, q querier, calendarID string, userID string, need storage.Role) error {
+	role, err := calendarRole(ctx, q, calendarID, userID)
+	if err != nil {
+		return err
+	}
+	return storage.CheckRole(role, need)
+}
+
+// resolveCalendar возвращает календарь, в котором пользователь меняет события:
+// calendarID, если у него есть права need, а для пустого calendarID - его личный календарь.
+func resolveCalendar(ctx context.Context, tx *sql.Tx, calendarID string, userID string,
+	need storage.Role,
+) (string, error) {
+	if calendarID != "" {
+		return calendarID, checkCalendar(ctx, tx, calendarID, userID, need)
+	}
+
+	err := tx.QueryRowContext(ctx, `select id from calendar where personal and owner_id = $1;`,
+		userID).Scan(&calendarID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrCalendarNotFound
+	}
+	return calendarID, err
+}
+
+// Ключ рекомендательной блокировки - пара (пространство, ID): у каждого вида ресурсов своё
+// пространство, поэтому блокировки разных ресурсов с одинаковым ID не мешают друг другу.
+const lockSpaceCalendar int32 = 0x63616c01 // "cal" и номер ресурса
+
+// advisoryLock берёт рекомендательную блокировку ресурса id из пространства space до конца транзакции.
+func advisoryLock(ctx context.Context, tx *sql.Tx, space int32, id string) error {
+	_, err := tx.ExecContext(ctx, `select pg_advisory_xact_lock($1::integer, $2::integer);`, space, id)
+	return err
+}
+
+// lockCalendar сериализует изменения событий календаря до конца транзакции,
+// чтобы проверка пересечений не пропустила параллельно добавленное событие.
+func lockCalendar(ctx context.Context, tx *sql.Tx, calendarID string) error {
+	return advisoryLock(ctx, tx, lockSpaceCalendar, calendarID)
+}
+
+// eventInCalendar проверяет, что событие лежит в календаре и не удалено в корзину.
+func eventInCalendar(ctx context.Context, tx *sql.Tx, eventID string, calendarID string) error {
+	if _, err := parseIDs([]string{eventID}); err != nil {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	var id string
+	err := tx.QueryRowContext(ctx, `select id from event where id = $1 and calendar_id = $2 and deleted_at is null;`,
+		eventID, calendarID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+	return err
+}
+
+// insertCalendar создаёт календарь и права владельца на него.
+func insertCalendar(ctx context.Context, tx *sql.Tx, name string, ownerID string, personal bool) (string, error) {
+	var id string
+	err := tx.QueryRowContext(ctx, `insert into calendar (name, owner_id, personal)
+		values ($1, $2, $3) returning id;`, name, ownerID, personal).Scan(&id)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.ExecContext(ctx, `insert into calendar_acl (calendar_id, account_id, role)
+		values ($1, $2, $3);`, id, ownerID, string(storage.RoleOwner))
+	return id, err
+}
+
+func (s *DBStorage) CreateCalendar(ctx context.Context, name string, userID string) (string, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, err := insertCalendar(ctx, tx, name, userID, false)
+	if err != nil {
+		s.Logg.Error("error in creating calendar", zap.Error(err))
+		return "", err
+	}
+	return id, tx.Commit()
+}
+
+// GetCalendars возвращает календари, к которым у пользователя есть доступ: личный первым.
+func (s *DBStorage) GetCalendars(ctx context.Context, userID string) ([]storage.Calendar, error) {
+	sqlSt := `select c.id, c.name, c.owner_id, c.personal, c.created_at, a.role
+		from calendar c join calendar_acl a on a.calendar_id = c.id
+		where a.account_id = $1
+		order by c.personal desc, c.created_at, c.id;`
+
+	rows, err := s.DB.QueryContext(ctx, sqlSt, userID)
+	if err != nil {
+		s.Logg.Error("error in getting calendars", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []storage.Calendar{}
+	for rows.Next() {
+		var c storage.Calendar
+		if err := rows.Scan(&c.ID, &c.Name, &c.OwnerID, &c.Personal, &c.CreatedAt, &c.Role); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+// DeleteCalendar удаляет календарь; события удаляются каскадно. Личный календарь удалить нельзя.
+func (s *DBStorage) DeleteCalendar(ctx context.Context, calendarID string, userID string) error {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleOwner); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := recordDeletion(ctx, tx, `calendar_id = $1`, calendarID); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from calendar where id = $1 and not personal;`, calendarID)
+	if err != nil {
+		s.Logg.Error("error in deleting calendar", zap.Error(err))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: personal calendar cannot be deleted", storage.ErrForbidden)
+	}
+	return tx.Commit()
+}
+
+// GetCalendarACL возвращает права на календарь: владельца первым, остальных по ID.
+func (s *DBStorage) GetCalendarACL(ctx context.Context, calendarID string, userID string) ([]storage.ACLEntry, error) {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	sqlSt := `select account_id, role from calendar_acl where calendar_id = $1
+		order by role = 'owner' desc, account_id;`
+
+	rows, err := s.DB.QueryContext(ctx, sqlSt, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []storage.ACLEntry{}
+	for rows.Next() {
+		var e storage.ACLEntry
+		if err := rows.Scan(&e.UserID, &e.Role); err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// SetCalendarACL выдаёт аккаунту права editor или viewer либо меняет их.
+func (s *DBStorage) SetCalendarACL(ctx context.Context, calendarID string, entry storage.ACLEntry, userID string) error {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleOwner); err != nil {
+		return err
+	}
+	grantee, err := parseIDs([]string{entry.UserID})
+	if err != nil {
+		return storage.ErrAccountNotFound
+	}
+
+	// права владельца не меняются: условие where не даёт перезаписать строку owner
+	sqlSt := `insert into calendar_acl (calendar_id, account_id, role) values ($1, $2, $3)
+		on conflict (calendar_id, account_id) do update set role = excluded.role
+		where calendar_acl.role <> 'owner';`
+
+	res, err := s.DB.ExecContext(ctx, sqlSt, calendarID, grantee[0], string(entry.Role))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return storage.ErrAccountNotFound
+		}
+		s.Logg.Error("error in granting calendar access", zap.Error(err))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: owner's role cannot be changed", storage.ErrInvalidRole)
+	}
+	return nil
+}
+
+// RevokeCalendarAccess отзывает права аккаунта на календарь.
+func (s *DBStorage) RevokeCalendarAccess(ctx context.Context, calendarID string, granteeID string, userID string) error {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleOwner); err != nil {
+		return err
+	}
+	if granteeID == userID {
+		return fmt.Errorf("%w: owner's role cannot be changed", storage.ErrInvalidRole)
+	}
+	grantee, err := parseIDs([]string{granteeID})
+	if err != nil {
+		return nil
+	}
+
+	_, err = s.DB.ExecContext(ctx, `delete from calendar_acl
+		where calendar_id = $1 and account_id = $2 and role <> 'owner';`, calendarID, grantee[0])
+	if err != nil {
+		s.Logg.Error("error in revoking calendar access", zap.Error(err))
+	}
+	return err
+}
+
+func (s *DBStorage) AddCalendarEvent(ctx context.Context, calendarID string,
+	e storage.EventCreateDTO, userID string,
+) (string, error) {
+	if calendarID == "" {
+		return "", storage.ErrCalendarNotFound
+	}
+	return s.addEvent(ctx, calendarID, e, userID)
+}
+
+func (s *DBStorage) UpdateCalendarEvent(ctx context.Context, calendarID string, eventID string,
+	event storage.EventUpdateDTO, userID string,
+) error {
+	if calendarID == "" {
+		return storage.ErrCalendarNotFound
+	}
+	return s.updateEvent(ctx, calendarID, eventID, event, userID)
+}
+
+func (s *DBStorage) DeleteCalendarEvent(ctx context.Context, calendarID string, eventID string, userID string) error {
+	if calendarID == "" {
+		return storage.ErrCalendarNotFound
+	}
+	return s.deleteEvent(ctx, calendarID, eventID, userID)
+}
+
+func (s *DBStorage) GetCalendarEvent(ctx context.Context, calendarID string, eventID string, userID string,
+) (storage.Event, error) {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleViewer); err != nil {
+		return storage.Event{}, err
+	}
+	if _, err := parseIDs([]string{eventID}); err != nil {
+		return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	events, err := selectEvents(ctx, s.DB, notDeleted+` and calendar_id = $1 and id = $2`, calendarID, eventID)
+	if err != nil {
+		s.Logg.Error("error in getting event by id", zap.Error(err), zap.String("eventID", eventID))
+		return storage.Event{}, err
+	}
+	if len(events) == 0 {
+		return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+	return events[0], nil
+}
+
+// GetCalendarEventListing - список событий календаря на день/неделю/месяц, как GetEventListingByUserID.
+func (s *DBStorage) GetCalendarEventListing(ctx context.Context, calendarID string,
+	date time.Time, period string, userID string,
+) ([]storage.Event, error) {
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.listing(ctx, `calendar_id = $1`, calendarID, date, period)
+}
@@ -0,0 +1,193 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+// listenRetry - пауза перед повторной подпиской на event_change после ошибки.
+const listenRetry = 5 * time.Second
+
+// recordChanges записывает в ленту изменение kind для событий eventIDs. Получатели - участники
+// календаря события, его приглашённые и also. При удалении вызывается до удаления события.
+func recordChanges(ctx context.Context, tx *sql.Tx, kind storage.ChangeKind, eventIDs []int64, also []int64) error {
+	if also == nil {
+		also = []int64{}
+	}
+	_, err := tx.ExecContext(ctx, `insert into event_change (event_id, calendar_id, kind, recipients)
+		select e.id, e.calendar_id, $2, array(
+			select account_id from calendar_acl where calendar_id = e.calendar_id
+			union select account_id from event_attendee where event_id = e.id
+			union select unnest($3::bigint[]))
+		from event e where e.id = any($1::bigint[]) order by e.id;`, eventIDs, string(kind), also)
+	return err
+}
+
+// recordChange - recordChanges для одного события.
+func recordChange(ctx context.Context, tx *sql.Tx, kind storage.ChangeKind, eventID string, also ...int64) error {
+	ids, err := parseIDs([]string{eventID})
+	if err != nil {
+		return err
+	}
+	return recordChanges(ctx, tx, kind, ids, also)
+}
+
+// recordDeletion записывает удаление событий, выбранных условием where с параметром $1 = arg,
+// перед их каскадным удалением вместе с календарём или аккаунтом. Удаление событий
+// из корзины уже записано.
+func recordDeletion(ctx context.Context, tx *sql.Tx, where string, arg string) error {
+	ids, err := queryIDs(ctx, tx, `select id from event where `+notDeleted+` and `+where, arg)
+	if err != nil {
+		return err
+	}
+	eventIDs, err := parseIDs(ids)
+	if err != nil {
+		return err
+	}
+	return recordChanges(ctx, tx, storage.ChangeDeleted, eventIDs, nil)
+}
+
+// ChangeToken возвращает токен текущего конца ленты: все транзакции раньше xmin текущего
+// снимка завершены, а изменения остальных будут в ленте после токена.
+func (s *DBStorage) ChangeToken(ctx context.Context) (string, error) {
+	var xmin int64
+	err := s.DB.QueryRowContext(ctx, `select pg_snapshot_xmin(pg_current_snapshot())::text::bigint;`).Scan(&xmin)
+	if err != nil {
+		return "", err
+	}
+	return storage.ChangePosition{Tx: xmin}.String(), nil
+}
+
+// GetChanges возвращает не больше limit изменений для пользователя после токена after
+// с текущим состоянием событий. Изменения отдаются в порядке (tx, id) и только из транзакций
+// раньше xmin снимка: все они уже завершены, и новые изменения не окажутся позади отданных.
+// Если изменения после after уже удалены по сроку хранения, возвращает storage.ErrChangeTokenExpired.
+func (s *DBStorage) GetChanges(ctx context.Context, userID string, after string, limit int) ([]storage.Change, error) {
+	pos, err := storage.ParseChangeToken(after)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := parseIDs([]string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `select tx, id, kind, event_id, calendar_id, changed_at from event_change
+		where recipients @> array[$1::bigint]
+			and tx < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+			and (tx, id) > ($2::bigint, $3::bigint)
+		order by tx, id limit $4;`, ids[0], pos.Tx, pos.Seq, limit)
+	if err != nil {
+		s.Logg.Error("error in getting event changes", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []storage.Change
+	var eventIDs []int64
+	for rows.Next() {
+		var c storage.Change
+		var p storage.ChangePosition
+		var eventID int64
+		if err := rows.Scan(&p.Tx, &p.Seq, &c.Kind, &eventID, &c.CalendarID, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		c.ID = p.String()
+		c.EventID = strconv.FormatInt(eventID, 10)
+		changes = append(changes, c)
+		eventIDs = append(eventIDs, eventID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// граница читается после изменений: удаление, успевшее до чтения ленты, она уже отражает
+	var horizon storage.ChangePosition
+	err = s.DB.QueryRowContext(ctx, `select tx, id from event_change_horizon;`).Scan(&horizon.Tx, &horizon.Seq)
+	if err != nil {
+		s.Logg.Error("error in getting event change horizon", zap.Error(err))
+		return nil, err
+	}
+	if pos.Before(horizon) {
+		return nil, storage.ErrChangeTokenExpired
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	events, err := selectEvents(ctx, s.DB, notDeleted+` and id = any($1::bigint[])`, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*storage.Event, len(events))
+	for i := range events {
+		byID[events[i].ID] = &events[i]
+	}
+	for i := range changes {
+		changes[i].Event = byID[changes[i].EventID]
+	}
+	return changes, nil
+}
+
+// WaitForChanges возвращает канал, который закроется по уведомлению event_change.
+// Подписка на уведомления запускается при первом вызове и работает, пока не отменён s.Ctx.
+func (s *DBStorage) WaitForChanges() <-chan struct{} {
+	s.listenOnce.Do(func() {
+		go s.listenChanges(s.Ctx)
+	})
+	return s.changes.Wait()
+}
+
+// listenChanges держит подписку LISTEN event_change и будит ожидающих ленту при каждом уведомлении.
+// После ошибки подписка повторяется; ожидающие будятся, чтобы не пропустить изменения за это время.
+func (s *DBStorage) listenChanges(ctx context.Context) {
+	for {
+		err := s.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		s.Logg.Error("error in listening for event changes", zap.Error(err))
+		s.changes.Notify()
+
+		select {
+		case <-time.After(listenRetry):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// listen подписывается на event_change на отдельном соединении и ждёт уведомлений.
+// Соединение после подписки в пул не возвращается.
+func (s *DBStorage) listen(ctx context.Context) error {
+	conn, err := s.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("event changes require the pgx driver")
+		}
+		pgConn := c.Conn()
+		if _, err := pgConn.Exec(ctx, `listen event_change`); err != nil {
+			return errors.Join(err, driver.ErrBadConn)
+		}
+		for {
+			if _, err := pgConn.WaitForNotification(ctx); err != nil {
+				return errors.Join(err, driver.ErrBadConn)
+			}
+			s.changes.Notify()
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package sqlstorage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// batchSize ограничивает число ID в одном запросе с массивом.
+const batchSize = 1000
+
+var errInvalidID = errors.New("invalid id")
+
+// column - имя столбца. Имена задаются только константами в коде,
+// значения всегда передаются через плейсхолдеры.
+type column string
+
+type assignment struct {
+	col   column
+	value any
+}
+
+// updateQuery собирает UPDATE с позиционными плейсхолдерами $1, $2, ...
+// Столбцы идут в порядке вызовов Set, поэтому текст запроса детерминирован.
+type updateQuery struct {
+	table string
+	sets  []assignment
+	where []assignment
+}
+
+func newUpdate(table string) *updateQuery {
+	return &updateQuery{table: table}
+}
+
+// Set добавляет "col = $n".
+func (q *updateQuery) Set(col column, value any) *updateQuery {
+	q.sets = append(q.sets, assignment{col: col, value: value})
+	return q
+}
+
+// Where добавляет условие "col = $n"; условия объединяются через AND.
+func (q *updateQuery) Where(col column, value any) *updateQuery {
+	q.where = append(q.where, assignment{col: col, value: value})
+	return q
+}
+
+// Empty сообщает, что обновлять нечего.
+func (q *updateQuery) Empty() bool {
+	return len(q.sets) == 0
+}
+
+// Build возвращает текст запроса и аргументы к нему.
+func (q *updateQuery) Build() (string, []any) {
+	args := make([]any, 0, len(q.sets)+len(q.where))
+
+	var sb strings.Builder
+	sb.WriteString("update " + q.table + " set ")
+	for i, a := range q.sets {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		args = append(args, a.value)
+		fmt.Fprintf(&sb, "%s = $%d", a.col, len(args))
+	}
+	for i, a := range q.where {
+		if i == 0 {
+			sb.WriteString(" where ")
+		} else {
+			sb.WriteString(" and ")
+		}
+		args = append(args, a.value)
+		fmt.Fprintf(&sb, "%s = $%d", a.col, len(args))
+	}
+	sb.WriteString(";")
+
+	return sb.String(), args
+}
+
+// whereClause собирает условия, объединяемые через AND. Каждый "?" в условии
+// заменяется на очередной плейсхолдер $n, значения идут в аргументы запроса.
+type whereClause struct {
+	conds []string
+	args  []any
+}
+
+// Add добавляет условие; число "?" в нём должно совпадать с числом значений.
+func (w *whereClause) Add(cond string, values ...any) *whereClause {
+	for _, v := range values {
+		w.args = append(w.args, v)
+		cond = strings.Replace(cond, "?", "$"+strconv.Itoa(len(w.args)), 1)
+	}
+	w.conds = append(w.conds, cond)
+	return w
+}
+
+func (w *whereClause) String() string {
+	return strings.Join(w.conds, " and ")
+}
+
+// parseIDs переводит ID событий в числа для передачи массивом (id = any($1)).
+// Нечисловой ID - ошибка, а не часть текста запроса.
+func parseIDs(ids []string) ([]int64, error) {
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", errInvalidID, id)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
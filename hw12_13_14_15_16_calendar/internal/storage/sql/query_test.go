@@ -0,0 +1,34 @@
+package sqlstorage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+)
+
+func TestUpdateQueryBuild(t *testing.T) {
+	title := "title"
+	q := newUpdate("event").
+		Set("title", &title).
+		Set("notified", false).
+		Where("id", "7").
+		Where("account_id", "1")
+
+	require.False(t, q.Empty())
+
+	sqlSt, args := q.Build()
+	require.Equal(t, "update event set title = $1, notified = $2 where id = $3 and account_id = $4;", sqlSt)
+	require.Equal(t, []any{&title, false, "7", "1"}, args)
+
+	require.True(t, newUpdate("event").Where("id", "7").Empty())
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, err := parseIDs([]string{"1", "42"})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 42}, ids)
+
+	_, err = parseIDs([]string{"1", "1); drop table event; --"})
+	require.True(t, errors.Is(err, errInvalidID))
+}
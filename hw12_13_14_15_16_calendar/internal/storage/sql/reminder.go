@@ -0,0 +1,104 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+)
+
+// remindersColumn - смещения напоминаний события в секундах по возрастанию.
+const remindersColumn = `array(select offset_seconds from event_reminder r
+	where r.event_id = event.id order by offset_seconds)`
+
+func toSeconds(reminders []storage.Offset) []int64 {
+	secs := make([]int64, 0, len(reminders))
+	for _, r := range reminders {
+		secs = append(secs, int64(time.Duration(r)/time.Second))
+	}
+	return secs
+}
+
+func toOffsets(secs []int64) []storage.Offset {
+	reminders := make([]storage.Offset, 0, len(secs))
+	for _, s := range secs {
+		reminders = append(reminders, storage.Offset(time.Duration(s)*time.Second))
+	}
+	return reminders
+}
+
+// replaceReminders задаёт событию новый набор напоминаний. У смещений, которые
+// были и раньше, сохраняется fired_for, чтобы о том же повторении не напомнить дважды.
+func replaceReminders(ctx context.Context, tx *sql.Tx, eventID string, reminders []storage.Offset) error {
+	secs := toSeconds(reminders)
+
+	_, err := tx.ExecContext(ctx, `delete from event_reminder
+		where event_id = $1 and offset_seconds <> all($2::bigint[]);`, eventID, secs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `insert into event_reminder (event_id, offset_seconds)
+		select $1, unnest($2::bigint[]) on conflict do nothing;`, eventID, secs)
+	return err
+}
+
+// rearmReminders снова включает напоминания неповторяющегося события, начало которого
+// перенесли раньше уже напомненного: иначе о новом начале не напомнили бы.
+// Перенос на более позднее время обрабатывается сам: новое начало позже fired_for.
+func rearmReminders(ctx context.Context, tx *sql.Tx, eventID string) error {
+	_, err := tx.ExecContext(ctx, `update event_reminder r set fired_for = null
+		from event e
+		where e.id = r.event_id and e.id = $1 and e.rrule = '' and r.fired_for > e.date_start;`, eventID)
+	return err
+}
+
+// brokenDue - due_at события, которое не удалось развернуть: о нём не напоминают, пока его не изменят.
+var brokenDue = time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// reminderDue - напоминание повторяющегося события в версии, по которой вычислено его due_at.
+type reminderDue struct {
+	eventID string
+	offset  int64
+	version int64
+}
+
+// resetReminderDues сбрасывает due_at напоминаний изменённого события:
+// его заново вычислит планировщик по новым началу, правилу и исключениям.
+func resetReminderDues(ctx context.Context, tx *sql.Tx, eventID string) error {
+	_, err := tx.ExecContext(ctx, `update event_reminder set due_at = null where event_id = $1;`, eventID)
+	return err
+}
+
+// setReminderDues сохраняет due_at напоминаний. Если событие с тех пор изменили,
+// due_at не записывается: его вычислят заново по новой версии.
+func (s *DBStorage) setReminderDues(ctx context.Context, dues map[reminderDue]time.Time) error {
+	sqlSt := `update event_reminder r set due_at = t.due_at
+		from unnest($1::bigint[], $2::bigint[], $3::bigint[], $4::timestamptz[])
+			as t(event_id, offset_seconds, version, due_at), event e
+		where r.event_id = t.event_id and r.offset_seconds = t.offset_seconds
+			and e.id = t.event_id and e.version = t.version;`
+
+	for batch := range slices.Chunk(slices.Collect(maps.Keys(dues)), batchSize) {
+		eventIDs := make([]string, 0, len(batch))
+		offsets := make([]int64, 0, len(batch))
+		versions := make([]int64, 0, len(batch))
+		dueAt := make([]time.Time, 0, len(batch))
+		for _, k := range batch {
+			eventIDs = append(eventIDs, k.eventID)
+			offsets = append(offsets, k.offset)
+			versions = append(versions, k.version)
+			dueAt = append(dueAt, dues[k])
+		}
+		numIDs, err := parseIDs(eventIDs)
+		if err != nil {
+			return err
+		}
+		if _, err := s.DB.ExecContext(ctx, sqlSt, numIDs, offsets, versions, dueAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
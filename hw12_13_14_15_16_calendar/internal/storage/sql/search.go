@@ -0,0 +1,113 @@
+package sqlstorage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// sortColumns - столбцы сортировки поиска. Названия сравниваются побайтно (collate "C"),
+// как строки в memorystorage; под каждый столбец есть индекс (calendar_id, столбец, id)
+// (миграции 000017 и 000027).
+var sortColumns = map[string]string{
+	storage.SortStart:   `date_start`,
+	storage.SortCreated: `created_at`,
+	storage.SortTitle:   `title collate "C"`,
+}
+
+// SearchEvents ищет события календаря q.CalendarID или, без него, события личного календаря
+// пользователя и те, на которые его пригласили, с keyset-пагинацией по (столбец сортировки, id).
+// Текст ищется полнотекстовым индексом по названию и описанию. Повторяющиеся события
+// отбираются запросом с запасом и проверяются на повторения в [From, To) в Go,
+// поэтому выборка идёт пачками, пока не наберётся страница.
+func (s *DBStorage) SearchEvents(ctx context.Context, userID string, q storage.SearchQuery) (storage.SearchResult, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return storage.SearchResult{}, err
+	}
+	field, desc, _ := q.SortField()
+	cursor, hasCursor, err := q.DecodeCursor()
+	if err != nil {
+		return storage.SearchResult{}, err
+	}
+
+	if q.CalendarID != "" {
+		if err := checkCalendar(ctx, s.DB, q.CalendarID, userID, storage.RoleViewer); err != nil {
+			return storage.SearchResult{}, err
+		}
+	}
+
+	col := sortColumns[field]
+	dir, cmp := "asc", ">"
+	if desc {
+		dir, cmp = "desc", "<"
+	}
+	batch := q.Limit + 1
+	order := " order by " + col + " " + dir + ", id " + dir + " limit " + strconv.Itoa(batch)
+
+	res := storage.SearchResult{Events: []storage.Event{}}
+	for {
+		w := &whereClause{}
+		w.Add(notDeleted)
+		if q.CalendarID != "" {
+			w.Add("calendar_id = ?", q.CalendarID)
+		} else {
+			w.Add("(calendar_id = (select id from calendar where personal and owner_id = ?)"+
+				" or id in (select event_id from event_attendee where account_id = ?))", userID, userID)
+		}
+		if !q.From.IsZero() {
+			w.Add("(rrule <> '' or date_start >= ?)", q.From)
+		}
+		if !q.To.IsZero() {
+			w.Add("date_start < ?", q.To)
+		}
+		if q.Notified != nil {
+			w.Add("notified = ?", *q.Notified)
+		}
+		if q.Text != "" {
+			w.Add("search @@ plainto_tsquery('simple', ?)", q.Text)
+		}
+		if hasCursor {
+			key, err := storage.ParseSortKey(field, cursor.Key)
+			if err != nil {
+				return storage.SearchResult{}, err
+			}
+			id, err := strconv.ParseInt(cursor.ID, 10, 64)
+			if err != nil {
+				return storage.SearchResult{}, storage.ErrInvalidCursor
+			}
+			w.Add("("+col+", id) "+cmp+" (?, ?)", key, id)
+		}
+
+		events, err := selectEvents(ctx, s.DB, w.String()+order, w.args...)
+		if err != nil {
+			s.Logg.Error("error in searching events", zap.Error(err))
+			return storage.SearchResult{}, err
+		}
+
+		for _, e := range events {
+			ok, err := storage.InRange(e, q.Location, q.From, q.To)
+			if err != nil {
+				return storage.SearchResult{}, err
+			}
+			if !ok {
+				continue
+			}
+			if len(res.Events) == q.Limit {
+				// есть ещё хотя бы одно событие - значит, есть и следующая страница
+				res.NextCursor = q.CursorAfter(res.Events[q.Limit-1])
+				return res, nil
+			}
+			res.Events = append(res.Events, e)
+		}
+		if len(events) < batch {
+			return res, nil
+		}
+
+		// следующая пачка - после последнего просмотренного события
+		last := events[len(events)-1]
+		cursor, hasCursor = storage.Cursor{Key: storage.SortKey(last, field), ID: last.ID}, true
+	}
+}
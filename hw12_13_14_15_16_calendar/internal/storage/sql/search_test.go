@@ -0,0 +1,71 @@
+package sqlstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/c2fo/testify/require"
+)
+
+func TestSearchEvents(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+
+	titles := []string{"Ретро", "Планёрка", "Демо", "Планёрка команды", "Обед"}
+	ids := make([]string, len(titles))
+	for i, title := range titles {
+		st := start.AddDate(0, 0, i)
+		id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+			Title: title, Start: st, End: st.Add(time.Hour), Description: "спринт 42",
+		}, userID)
+		require.NoError(t, err)
+		ids[i] = id
+	}
+	// серия по пятницам, начавшаяся раньше, повторяется 5 сентября - внутри периода поиска
+	series, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Стендап", Start: start.AddDate(0, -1, 0), End: start.AddDate(0, -1, 0).Add(15 * time.Minute),
+		RRule: "FREQ=WEEKLY",
+	}, userID)
+	require.NoError(t, err)
+	// серия, закончившаяся до периода, отбрасывается уже после запроса
+	_, err = s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Старое", Start: start.AddDate(0, -2, 0), End: start.AddDate(0, -2, 0).Add(time.Hour),
+		RRule: "FREQ=DAILY;COUNT=2",
+	}, userID)
+	require.NoError(t, err)
+
+	q := storage.SearchQuery{From: start, To: start.AddDate(0, 0, 5), Limit: 2}
+	var got []string
+	for page := 0; ; page++ {
+		require.True(t, page < 5)
+		res, err := s.SearchEvents(ctx, userID, q)
+		require.NoError(t, err)
+		for _, e := range res.Events {
+			got = append(got, e.ID)
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		q.Cursor = res.NextCursor
+	}
+	require.Equal(t, []string{series, ids[0], ids[1], ids[2], ids[3], ids[4]}, got)
+
+	res, err := s.SearchEvents(ctx, userID, storage.SearchQuery{Text: "планёрка", Sort: "-title"})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 2)
+	require.Equal(t, ids[3], res.Events[0].ID)
+	require.Equal(t, ids[1], res.Events[1].ID)
+
+	notified := false
+	res, err = s.SearchEvents(ctx, userID, storage.SearchQuery{Text: "спринт", Notified: &notified, Sort: "-created"})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 5)
+	require.Equal(t, ids[4], res.Events[0].ID)
+
+	_, err = s.SearchEvents(ctx, userID, storage.SearchQuery{Sort: storage.SortTitle, Cursor: q.Cursor})
+	require.True(t, errors.Is(err, storage.ErrInvalidCursor))
+}
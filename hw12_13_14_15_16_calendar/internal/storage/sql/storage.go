@@ -3,18 +3,40 @@ package sqlstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
 
+// uniqueViolation - код ошибки PostgreSQL при нарушении ограничения unique.
+const uniqueViolation = "23505"
+
+// eventInsertColumns - сколько значений insertEvents передаёт на одно событие.
+const eventInsertColumns = 14
+
+// eventUIDIndex - уникальный индекс UID iCalendar в календаре (миграция 000026).
+const eventUIDIndex = "event_calendar_ical_uid_idx"
+
+// notDeleted - условие "событие не в корзине".
+const notDeleted = `deleted_at is null`
+
 type DBStorage struct {
 	Ctx  context.Context
 	DB   *sql.DB
 	Logg *zap.Logger
+
+	changes    storage.Broadcast // будит ожидающих ленту изменений
+	listenOnce sync.Once
 }
 
 func New(ctx context.Context, db *sql.DB, logg *zap.Logger) *DBStorage {
@@ -22,6 +44,8 @@ func New(ctx context.Context, db *sql.DB, logg *zap.Logger) *DBStorage {
 }
 
 type eventGetByID struct {
+	UserID       string
+	CalendarID   string
 	Title        string
 	CreatedAt    time.Time
 	Start        time.Time // Дата и время события;
@@ -29,22 +53,37 @@ type eventGetByID struct {
 	Description  string    // Описание события - длинный текст, опционально;
 	Notification time.Time
 	// (дата и время, когда высылать уведомление) За сколько времени высылать уведомление, опционально
-	Notified bool
+	Notified  bool
+	RRule     string
+	ExDates   []time.Time
+	Reminders []int64
+	Attendees []byte
+	Version   int64
+	TimeZone  string
+	UID       string
 }
 
+// GetEventByID возвращает событие пользователя или событие, на которое его пригласили.
 func (s *DBStorage) GetEventByID(eventID string, userID string) (storage.Event, error) {
-	sqlSt := `SELECT title, created_at, date_start, date_end, description, notification, notified
-	 	FROM event WHERE account_id = $1 and id = $2;`
+	if _, err := parseIDs([]string{eventID}); err != nil {
+		return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	sqlSt := `SELECT account_id, calendar_id, title, created_at, date_start, date_end, description, notification, notified,
+		rrule, exdates, ` + remindersColumn + `, ` + attendeesColumn + `, version, time_zone, ical_uid
+	 	FROM event WHERE ` + notDeleted + ` and ` + visibleTo + ` and id = $2;`
 	row := s.DB.QueryRowContext(s.Ctx, sqlSt, userID, eventID)
 
 	var e eventGetByID
+	typeMap := pgtype.NewMap()
 
-	err := row.Scan(&e.Title, &e.CreatedAt, &e.Start, &e.End,
-		&e.Description, &e.Notification, &e.Notified)
+	err := row.Scan(&e.UserID, &e.CalendarID, &e.Title, &e.CreatedAt, &e.Start, &e.End,
+		&e.Description, &e.Notification, &e.Notified, &e.RRule, typeMap.SQLScanner(&e.ExDates),
+		typeMap.SQLScanner(&e.Reminders), &e.Attendees, &e.Version, &e.TimeZone, &e.UID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			s.Logg.Error("no event in DB", zap.Error(err), zap.String("eventID", eventID))
-			return storage.Event{}, err
+			return storage.Event{}, fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
 		}
 		s.Logg.Error("error in getting event by id", zap.Error(err), zap.String("eventID", eventID))
 		return storage.Event{}, err
@@ -57,158 +96,513 @@ func (s *DBStorage) GetEventByID(eventID string, userID string) (storage.Event,
 		Start:        e.Start,
 		End:          e.End,
 		Description:  e.Description,
-		UserID:       userID,
+		UserID:       e.UserID,
+		CalendarID:   e.CalendarID,
 		Notification: e.Notification,
 		Notified:     e.Notified,
+		RRule:        e.RRule,
+		ExDates:      e.ExDates,
+		Reminders:    toOffsets(e.Reminders),
+		TimeZone:     e.TimeZone,
+		UID:          e.UID,
+		Version:      e.Version,
 	}
-	return event, err
+	if err := toAttendees(e.Attendees, &event); err != nil {
+		return storage.Event{}, err
+	}
+	return event, nil
 }
 
+// AddEventByID добавляет событие в личный календарь пользователя.
 func (s *DBStorage) AddEventByID(ctx context.Context,
 	e storage.EventCreateDTO, userID string,
 ) (string, error) { // user_id,
-	sqlSt := `insert into event (title, date_start, date_end, 
-		description, account_id, notification, notified) 
-		values ($1, $2, $3, $4, $5, $6, $7) returning id;`
+	return s.addEvent(ctx, "", e, userID)
+}
 
-	row := s.DB.QueryRowContext(ctx, sqlSt, e.Title, e.Start,
-		e.End, e.Description, userID, e.Notification, e.Notified)
+// addEvent добавляет событие в календарь, если у пользователя есть права editor;
+// пустой calendarID - личный календарь пользователя.
+func (s *DBStorage) addEvent(ctx context.Context, calendarID string,
+	e storage.EventCreateDTO, userID string,
+) (string, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return "", err
+	}
+
+	if e.RejectOverlap {
+		if err := lockCalendar(ctx, tx, calendarID); err != nil {
+			return "", err
+		}
+	}
 
-	var eventID string
-	err := row.Scan(&eventID)
+	ids, err := insertEvents(ctx, tx, calendarID, []storage.EventCreateDTO{e}, userID)
 	if err != nil {
 		return "", err
 	}
+	eventID := ids[0]
+
+	if e.RejectOverlap {
+		if err := checkOverlaps(ctx, tx, eventID, calendarID); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
 
 	s.Logg.Info("Event have been added")
 
 	return eventID, nil
 }
 
+// insertEvents добавляет события в календарь одним многострочным insert и возвращает их ID
+// в порядке events. ID выделяются заранее из последовательности, чтобы сопоставить их со
+// строками; напоминания и приглашённые тоже вставляются одним запросом на все события.
+// С событиями сохраняется контекст трассировки из ctx, чтобы напоминания попали в тот же трейс.
+func insertEvents(ctx context.Context, tx *sql.Tx, calendarID string,
+	events []storage.EventCreateDTO, userID string,
+) ([]string, error) {
+	ids, err := queryIDs(ctx, tx, `select nextval(pg_get_serial_sequence('event', 'id'))
+		from generate_series(1, $1);`, len(events))
+	if err != nil {
+		return nil, err
+	}
+	eventIDs, err := parseIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`insert into event (id, title, date_start, date_end, description, account_id,
+		notification, notified, rrule, exdates, calendar_id, trace_parent, time_zone, ical_uid) values `)
+	args := make([]any, 0, len(events)*eventInsertColumns)
+	traceParent := tracing.TraceParent(ctx)
+	accountZone, err := accountTimeZone(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var reminderEvents, reminderSecs, attendeeEvents []int64
+	var attendees []string
+
+	for i, e := range events {
+		exDates := e.ExDates
+		if exDates == nil {
+			exDates = []time.Time{}
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j := range eventInsertColumns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "$%d", len(args)+j+1)
+		}
+		sb.WriteString(")")
+		args = append(args, eventIDs[i], e.Title, e.Start, e.End, e.Description, userID,
+			e.Notification, e.Notified, e.RRule, exDates, calendarID, traceParent,
+			storage.EventTimeZone(e.TimeZone, accountZone), e.UID)
+
+		for _, secs := range toSeconds(storage.RemindersOrDefault(e.Reminders, e.Start, e.Notification)) {
+			reminderEvents = append(reminderEvents, eventIDs[i])
+			reminderSecs = append(reminderSecs, secs)
+		}
+		for _, a := range e.Attendees {
+			attendeeEvents = append(attendeeEvents, eventIDs[i])
+			attendees = append(attendees, a)
+		}
+	}
+	sb.WriteString(";")
+
+	if _, err := tx.ExecContext(ctx, sb.String(), args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == eventUIDIndex {
+			return nil, storage.ErrDuplicateUID
+		}
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `insert into event_reminder (event_id, offset_seconds)
+		select * from unnest($1::bigint[], $2::bigint[]) on conflict do nothing;`, reminderEvents, reminderSecs)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAttendees(ctx, tx, attendeeEvents, attendees); err != nil {
+		return nil, err
+	}
+
+	if err := recordChanges(ctx, tx, storage.ChangeCreated, eventIDs, nil); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, storage.AuditCreate, userID, eventIDs, nil); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// accountTimeZone возвращает зону аккаунта - зону по умолчанию для его новых событий.
+func accountTimeZone(ctx context.Context, tx *sql.Tx, userID string) (string, error) {
+	var timeZone string
+	err := tx.QueryRowContext(ctx, `select time_zone from account where id = $1;`, userID).Scan(&timeZone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.DefaultTimeZone, nil
+	}
+	return timeZone, err
+}
+
+// UpdateEventByID меняет событие личного календаря пользователя.
 func (s *DBStorage) UpdateEventByID(ctx context.Context,
 	eventID string, event storage.EventUpdateDTO, userID string,
 ) error {
-	pairs := map[string]any{}
+	return s.updateEvent(ctx, "", eventID, event, userID)
+}
+
+// updateEvent меняет событие календаря, если у пользователя есть права editor;
+// пустой calendarID - личный календарь пользователя.
+func (s *DBStorage) updateEvent(ctx context.Context, calendarID string,
+	eventID string, event storage.EventUpdateDTO, userID string,
+) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := s.updateEventTx(ctx, tx, calendarID, eventID, event, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateEventTx меняет событие календаря в транзакции tx от имени пользователя userID;
+// права на календарь уже проверены.
+func (s *DBStorage) updateEventTx(ctx context.Context, tx *sql.Tx, calendarID string,
+	eventID string, event storage.EventUpdateDTO, userID string,
+) error {
+	q := newUpdate("event")
 
 	if event.Title != nil {
-		pairs["title"] = event.Title
+		q.Set("title", event.Title)
 	}
 	if event.Start != nil {
-		pairs["date_start"] = event.Start
+		q.Set("date_start", event.Start)
 	}
 	if event.End != nil {
-		pairs["date_end"] = event.End
+		q.Set("date_end", event.End)
 	}
 	if event.Description != nil {
-		pairs["description"] = event.Description
+		q.Set("description", event.Description)
 	}
 	if event.Notification != nil {
-		pairs["notification"] = event.Notification
+		q.Set("notification", event.Notification)
 	}
 	if !event.Notified {
-		pairs["notified"] = event.Notified
+		q.Set("notified", event.Notified)
+	}
+	if event.RRule != nil {
+		q.Set("rrule", event.RRule)
+	}
+	if event.ExDates != nil {
+		q.Set("exdates", *event.ExDates)
+	}
+	if event.TimeZone != nil && *event.TimeZone != "" {
+		q.Set("time_zone", event.TimeZone)
 	}
 
-	sqlStBase := `update event set `
-	sqlSet := []string{}
-	vals := []any{} // "a", "b"
-	// pairs = title: a, description: b
-	index := 1
-
-	for k, v := range pairs {
-		sqlSet = append(sqlSet, fmt.Sprintf("%s = $%d", k, index))
-		index++
-		vals = append(vals, v)
+	if err := eventInCalendar(ctx, tx, eventID, calendarID); err != nil {
+		return err
+	}
+	if err := checkVersion(ctx, tx, eventID, event.Version); err != nil {
+		return err
+	}
+	ids, err := parseIDs([]string{eventID})
+	if err != nil {
+		return err
+	}
+	before, err := auditSnapshot(ctx, tx, ids)
+	if err != nil {
+		return err
 	}
-	// sqlSet = ["title = $1", "description = $2"]
-	// vals = ["a", "b"]
 
-	if len(pairs) == 0 {
+	if q.Empty() && event.Reminders == nil && event.Attendees == nil {
 		s.Logg.Info("no field to update", zap.String("eventID", eventID))
 		return nil
 	}
-	vals = append(vals, eventID, userID)
+	// update event set title = $1, description = $2 where id = $3 and calendar_id = $4;
+	sqlSt, vals := q.Where("id", eventID).Where("calendar_id", calendarID).Build()
+
+	if event.RejectOverlap {
+		if err := lockCalendar(ctx, tx, calendarID); err != nil {
+			return err
+		}
+	}
+
+	if !q.Empty() {
+		if _, err := tx.ExecContext(ctx, sqlSt, vals...); err != nil {
+			s.Logg.Error("error in updateing event", zap.Error(err), zap.String("eventID", eventID))
+			return err
+		}
+	}
+
+	if event.Reminders != nil || event.Start != nil {
+		if err := s.updateReminders(ctx, tx, eventID, event.Reminders); err != nil {
+			return err
+		}
+	}
+	// убранные из приглашённых тоже узнают об изменении
+	var oldAttendees []int64
+	if event.Attendees != nil {
+		if oldAttendees, err = attendeeIDs(ctx, tx, eventID); err != nil {
+			return err
+		}
+		if err := replaceAttendees(ctx, tx, eventID, *event.Attendees); err != nil {
+			s.Logg.Error("error in updating attendees", zap.Error(err), zap.String("eventID", eventID))
+			return err
+		}
+	}
+
+	if event.RejectOverlap {
+		if err := checkOverlaps(ctx, tx, eventID, calendarID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `update event set version = version + 1 where id = $1;`, eventID); err != nil {
+		return err
+	}
+	if err := resetReminderDues(ctx, tx, eventID); err != nil {
+		return err
+	}
+	if err := recordChange(ctx, tx, storage.ChangeUpdated, eventID, oldAttendees...); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, storage.AuditUpdate, userID, ids, before)
+}
+
+// checkVersion блокирует событие до конца транзакции, чтобы параллельные изменения шли
+// по очереди, и сверяет его версию с ожидаемой (см. storage.CheckVersion).
+func checkVersion(ctx context.Context, tx *sql.Tx, eventID string, expected int64) error {
+	var version int64
+	err := tx.QueryRowContext(ctx, `select version from event where id = $1 for update;`, eventID).Scan(&version)
+	if err != nil {
+		return err
+	}
+	return storage.CheckVersion(eventID, version, expected)
+}
+
+// updateReminders меняет напоминания события: задаёт новый набор,
+// если он передан, и учитывает перенос начала события.
+func (s *DBStorage) updateReminders(ctx context.Context, tx *sql.Tx,
+	eventID string, reminders *[]storage.Offset,
+) error {
+	if reminders != nil {
+		if err := replaceReminders(ctx, tx, eventID, *reminders); err != nil {
+			s.Logg.Error("error in updating reminders", zap.Error(err), zap.String("eventID", eventID))
+			return err
+		}
+	}
+	return rearmReminders(ctx, tx, eventID)
+}
 
-	sqlSt := sqlStBase + strings.Join(sqlSet, ", ") +
-		fmt.Sprintf(" where id = $%d and account_id = $%d;", index, index+1)
-		// " where id = $" + strconv.Itoa(index) +
-		// " and account_id = $" + strconv.Itoa(index+1) + ";" //nolint:gosec
-	// update event set title = $1, description = $2 where id = $3 and account_id = $4;
-	_, err := s.DB.ExecContext(ctx, sqlSt, vals...)
+// checkOverlaps проверяет уже записанное в транзакции событие на пересечения
+// с остальными событиями его календаря.
+func checkOverlaps(ctx context.Context, tx *sql.Tx, eventID string, calendarID string) error {
+	events, err := selectEvents(ctx, tx, `calendar_id = $1 and id = $2`, calendarID, eventID)
 	if err != nil {
-		s.Logg.Error("error in updateing event", zap.Error(err), zap.String("eventID", eventID))
 		return err
 	}
+	if len(events) == 0 {
+		return sql.ErrNoRows
+	}
+	e := events[0]
 
+	from, to := storage.OverlapWindow(e)
+	others, err := selectEvents(ctx, tx, `calendar_id = $1 and id <> $2 and deleted_at is null
+		and date_start < $4 and (rrule <> '' or date_end > $3)`, calendarID, eventID, from, to)
+	if err != nil {
+		return err
+	}
+
+	overlaps, err := storage.FindOverlaps(e, others)
+	if err != nil {
+		return err
+	}
+	if len(overlaps) > 0 {
+		return fmt.Errorf("%w: %s", storage.ErrOverlap, overlaps[0].ID)
+	}
 	return nil
 }
 
-func (s *DBStorage) DeleteEventByID(ctx context.Context, eventID string) error {
-	sqlSt := `delete from event where id = $1;`
+// DeleteEventByID переносит в корзину событие личного календаря пользователя.
+func (s *DBStorage) DeleteEventByID(ctx context.Context, eventID string, userID string) error {
+	return s.deleteEvent(ctx, "", eventID, userID)
+}
+
+// deleteEvent переносит событие календаря в корзину, если у пользователя есть права editor;
+// пустой calendarID - личный календарь пользователя.
+func (s *DBStorage) deleteEvent(ctx context.Context, calendarID string, eventID string, userID string) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := eventInCalendar(ctx, tx, eventID, calendarID); err != nil {
+		return err
+	}
+	ids, err := parseIDs([]string{eventID})
+	if err != nil {
+		return err
+	}
+	before, err := auditSnapshot(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := recordChange(ctx, tx, storage.ChangeDeleted, eventID); err != nil {
+		return err
+	}
 
-	_, err := s.DB.ExecContext(ctx, sqlSt, eventID)
+	sqlSt := `update event set deleted_at = now() where id = $1 and calendar_id = $2;`
+
+	_, err = tx.ExecContext(ctx, sqlSt, eventID, calendarID)
 	if err != nil {
 		s.Logg.Error("error in deleting event from DB", zap.Error(err), zap.String("eventID", eventID))
 		return err
 	}
+	if err := recordAudit(ctx, tx, storage.AuditDelete, userID, ids, before); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 
 	s.Logg.Info("Event is deleted.")
 	return nil
 }
 
-const (
-	day   = "day"
-	week  = "week"
-	month = "month"
-)
-
-// получить список событий на день/неделю/месяц.
+// получить список событий на день/неделю/месяц, в которые попадает date;
+// границы периода считаются в зоне date (см. storage.ListingWindow).
+// Повторяющиеся события выбираются, если начались до конца периода,
+// и разворачиваются в повторения внутри периода.
+// В список попадают события личного календаря и те, на которые пользователя пригласили.
 func (s *DBStorage) GetEventListingByUserID(userID string, date time.Time, period string) ([]storage.Event, error) {
+	return s.listing(context.Background(), visibleTo, userID, date, period)
+}
+
+// listing выбирает события по условию where с параметром $1 = arg и разворачивает их
+// в повторения внутри периода; время повторений - в зоне date.
+// Событие, которое не удалось развернуть, пропускается.
+func (s *DBStorage) listing(ctx context.Context, where string, arg string,
+	date time.Time, period string,
+) ([]storage.Event, error) {
 	events := []storage.Event{}
-	fmt.Println(" !!!!!!!! date:", date)
-	var sqlSt string
-
-	switch period {
-	case day:
-		sqlSt = `SELECT id, title, date_start, date_end, description, notification, notified
-			FROM event
-			WHERE account_id = $1
-			AND date_start >= $2::date
-			AND date_start <  ($2::date + INTERVAL '1 day')
-			ORDER BY date_start;`
-	case week:
-		sqlSt = `SELECT id, title, date_start, date_end, description, notification, notified
-			FROM event
-			WHERE account_id = $1
-			AND date_start >= $2::date
-			AND date_start <  ($2::date + INTERVAL '1 week')
-			ORDER BY date_start;`
-	case month:
-		sqlSt = `SELECT id, title, date_start, date_end, description, notification, notified
-			FROM event
-			WHERE account_id = $1
-			AND date_start >= $2::date
-			AND date_start <  ($2::date + INTERVAL '1 month')
-			ORDER BY date_start;`
-	}
-
-	rows, err := s.DB.QueryContext(context.Background(), sqlSt, userID, date)
-	if err != nil || rows.Err() != nil {
+
+	from, to, err := storage.ListingWindow(date, period)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := selectEvents(ctx, s.DB, notDeleted+` AND `+where+`
+		AND date_start < $3
+		AND (rrule <> '' OR date_start >= $2)`, arg, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range candidates {
+		occurrences, err := storage.Occurrences(e, date.Location(), from, to)
+		if err != nil {
+			s.Logg.Error("error in expanding recurring event, skipping it", zap.Error(err), zap.String("eventID", e.ID))
+			continue
+		}
+		events = append(events, occurrences...)
+	}
+
+	slices.SortFunc(events, func(a, b storage.Event) int { return a.Start.Compare(b.Start) })
+
+	return events, nil
+}
+
+// получить все события личного календаря пользователя и те, на которые его пригласили;
+// повторяющиеся не разворачиваются.
+func (s *DBStorage) GetEventsByUserID(ctx context.Context, userID string) ([]storage.Event, error) {
+	return selectEvents(ctx, s.DB, notDeleted+` AND `+visibleTo+` ORDER BY date_start`, userID)
+}
+
+func (s *DBStorage) GetFreeBusy(ctx context.Context, userID string,
+	from, to time.Time, minFree time.Duration,
+) (storage.FreeBusy, error) {
+	events, err := selectEvents(ctx, s.DB, notDeleted+` AND calendar_id = `+personalCalendar+`
+		AND date_start < $3
+		AND (rrule <> '' OR date_end > $2)`, userID, from, to)
+	if err != nil {
+		s.Logg.Error("error in selecting events for free/busy", zap.Error(err))
+		return storage.FreeBusy{}, err
+	}
+
+	return storage.GetFreeBusy(events, from, to, minFree)
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const eventColumns = `id, account_id, calendar_id, title, created_at, date_start, date_end, description,
+	notification, notified, rrule, exdates, ` + remindersColumn + `, ` + attendeesColumn + `, version, deleted_at,
+	time_zone, ical_uid`
+
+// selectEvents выбирает события по условию where (константная строка с плейсхолдерами).
+func selectEvents(ctx context.Context, q querier, where string, args ...any) ([]storage.Event, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+eventColumns+` FROM event WHERE `+where, args...)
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	typeMap := pgtype.NewMap()
+	events := []storage.Event{}
+
 	for rows.Next() {
 		var e storage.Event
-		err := rows.Scan(&e.ID, &e.Title, &e.Start, &e.End,
-			&e.Description, &e.Notification, &e.Notified)
-		if err != nil || rows.Err() != nil {
+		var reminders []int64
+		var attendees []byte
+		var deletedAt sql.NullTime
+		err := rows.Scan(&e.ID, &e.UserID, &e.CalendarID, &e.Title, &e.CreatedAt, &e.Start, &e.End, &e.Description,
+			&e.Notification, &e.Notified, &e.RRule, typeMap.SQLScanner(&e.ExDates),
+			typeMap.SQLScanner(&reminders), &attendees, &e.Version, &deletedAt, &e.TimeZone, &e.UID)
+		if err != nil {
+			return nil, err
+		}
+		if deletedAt.Valid {
+			e.DeletedAt = &deletedAt.Time
+		}
+		e.Reminders = toOffsets(reminders)
+		if err := toAttendees(attendees, &e); err != nil {
 			return nil, err
 		}
 		events = append(events, e)
 	}
 
-	return events, nil
+	return events, rows.Err()
 }
 
 func (s *DBStorage) Notify(_ uint) (string, error) { // day
@@ -225,6 +619,8 @@ func (s *DBStorage) Close(_ context.Context) error {
 	return nil
 }
 
+// SetNotified помечает события как отправленные и возвращает ID реально помеченных.
+// ID передаются массивом пачками по batchSize, каждая пачка - отдельный запрос в общей транзакции.
 func (s *DBStorage) SetNotified(ctx context.Context, ids []string) ([]string, error) {
 	if len(ids) == 0 {
 		s.Logg.Info("nothing to notify.")
@@ -232,10 +628,208 @@ func (s *DBStorage) SetNotified(ctx context.Context, ids []string) ([]string, er
 	}
 	s.Logg.Info("setting notified events.", zap.Int("amount", len(ids)))
 
-	sqlBase := `update event set notified = true where account_id = 1 `
-	sqlSt := sqlBase + fmt.Sprintf("and id in (%s) returning id;", strings.Join(ids, ", "))
-	rows, err := s.DB.QueryContext(ctx, sqlSt)
-	if err != nil || rows.Err() != nil {
+	numIDs, err := parseIDs(ids)
+	if err != nil {
+		s.Logg.Error("error in setting notified events", zap.Error(err))
+		return nil, err
+	}
+
+	sqlSt := `update event set notified = true
+		where notified = false and id = any($1) returning id;`
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var result []string
+	for batch := range slices.Chunk(numIDs, batchSize) {
+		marked, err := queryIDs(ctx, tx, sqlSt, batch)
+		if err != nil {
+			s.Logg.Error("error in setting notified events", zap.Error(err))
+			return nil, err
+		}
+		markedIDs, err := parseIDs(marked)
+		if err != nil {
+			return nil, err
+		}
+		if err := recordChanges(ctx, tx, storage.ChangeNotified, markedIDs, nil); err != nil {
+			return nil, err
+		}
+		result = append(result, marked...)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.Logg.Info("set notified events.", zap.Int("amount", len(result)))
+	return result, nil
+}
+
+// EnqueueNotifications помечает напоминания отправленными (fired_for - начало повторения)
+// и в той же транзакции кладёт их в outbox. Возвращает ID событий, по которым
+// напоминание реально поставлено в очередь; уже отправленные пропускаются.
+func (s *DBStorage) EnqueueNotifications(ctx context.Context, events []storage.EventToNotify) ([]string, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	numIDs, err := parseIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	type reminderKey struct {
+		id     int64
+		offset int64
+	}
+	// у одного напоминания может быть несколько получателей: владелец и приглашённые
+	byKey := make(map[reminderKey][]storage.EventToNotify, len(events))
+	keyList := make([]reminderKey, 0, len(events))
+	for i, e := range events {
+		k := reminderKey{numIDs[i], toSeconds([]storage.Offset{e.Reminder})[0]}
+		if _, ok := byKey[k]; !ok {
+			keyList = append(keyList, k)
+		}
+		byKey[k] = append(byKey[k], e)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// due_at вычислит следующий проход планировщика
+	markSt := `update event_reminder r set fired_for = t.occ, due_at = null
+		from unnest($1::bigint[], $2::bigint[], $3::timestamptz[]) as t(event_id, offset_seconds, occ)
+		where r.event_id = t.event_id and r.offset_seconds = t.offset_seconds
+			and (r.fired_for is null or r.fired_for < t.occ)
+			and r.event_id in (select id from event where deleted_at is null)
+		returning r.event_id, r.offset_seconds;`
+	insertSt := `insert into outbox (idempotency_key, payload, trace_parent)
+		select k, p::jsonb, tp from unnest($1::text[], $2::text[], $3::text[]) as t(k, p, tp)
+		on conflict (idempotency_key) do nothing;`
+	notifiedSt := `update event set notified = true where id = any($1::bigint[]);`
+
+	var result []string
+	seen := make(map[string]bool)
+	for batch := range slices.Chunk(keyList, batchSize) {
+		eventIDs := make([]int64, 0, len(batch))
+		offsets := make([]int64, 0, len(batch))
+		starts := make([]time.Time, 0, len(batch))
+		for _, k := range batch {
+			eventIDs = append(eventIDs, k.id)
+			offsets = append(offsets, k.offset)
+			starts = append(starts, byKey[k][0].Start)
+		}
+
+		rows, err := tx.QueryContext(ctx, markSt, eventIDs, offsets, starts)
+		if err != nil {
+			s.Logg.Error("error in setting notified reminders", zap.Error(err))
+			return nil, err
+		}
+		var marked []storage.EventToNotify
+		var markedIDs []int64
+		for rows.Next() {
+			var k reminderKey
+			if err := rows.Scan(&k.id, &k.offset); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			marked = append(marked, byKey[k]...)
+			markedIDs = append(markedIDs, k.id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(marked))
+		payloads := make([]string, 0, len(marked))
+		traceParents := make([]string, 0, len(marked))
+		for _, e := range marked {
+			e.IdempotencyKey = storage.NotificationKey(e)
+			data, err := json.Marshal(e)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, e.IdempotencyKey)
+			payloads = append(payloads, string(data))
+			traceParents = append(traceParents, e.TraceParent)
+			if !seen[e.ID] {
+				seen[e.ID] = true
+				result = append(result, e.ID)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, insertSt, keys, payloads, traceParents); err != nil {
+			s.Logg.Error("error in writing outbox", zap.Error(err))
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, notifiedSt, markedIDs); err != nil {
+			s.Logg.Error("error in setting notified events", zap.Error(err))
+			return nil, err
+		}
+		slices.Sort(markedIDs)
+		if err := recordChanges(ctx, tx, storage.ChangeNotified, slices.Compact(markedIDs), nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.Logg.Info("notifications are enqueued.", zap.Int("amount", len(result)))
+	return result, nil
+}
+
+// PendingOutbox возвращает не более limit недоставленных сообщений в порядке записи.
+func (s *DBStorage) PendingOutbox(ctx context.Context, limit int) ([]storage.OutboxMessage, error) {
+	sqlSt := `select id, idempotency_key, payload, trace_parent from outbox
+		where delivered_at is null order by id limit $1;`
+
+	rows, err := s.DB.QueryContext(ctx, sqlSt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []storage.OutboxMessage
+	for rows.Next() {
+		var m storage.OutboxMessage
+		if err := rows.Scan(&m.ID, &m.IdempotencyKey, &m.Payload, &m.TraceParent); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// MarkOutboxDelivered помечает сообщения outbox опубликованными.
+func (s *DBStorage) MarkOutboxDelivered(ctx context.Context, ids []string) error {
+	numIDs, err := parseIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	sqlSt := `update outbox set delivered_at = now() where id = any($1) and delivered_at is null;`
+	for batch := range slices.Chunk(numIDs, batchSize) {
+		if _, err := s.DB.ExecContext(ctx, sqlSt, batch); err != nil {
+			s.Logg.Error("error in marking outbox delivered", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func queryIDs(ctx context.Context, q querier, sqlSt string, args ...any) ([]string, error) {
+	rows, err := q.QueryContext(ctx, sqlSt, args...)
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
@@ -243,55 +837,321 @@ func (s *DBStorage) SetNotified(ctx context.Context, ids []string) ([]string, er
 	var result []string
 	for rows.Next() {
 		var id string
-		err := rows.Scan(&id)
-		if err != nil || rows.Err() != nil {
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
 		result = append(result, id)
 	}
-	s.Logg.Info("set notified events.")
-	return result, nil
+	return result, rows.Err()
 }
 
+// CollectEventsToNotify возвращает напоминания, время которых наступило: по одному
+// на каждое напоминание события и получателя: владельца и приглашённых, кроме отказавшихся.
+// Start - начало повторения, о котором напоминаем.
+// Запрос отбирает кандидатов, а какое повторение пора напомнить, решает storage.DueOccurrence.
+// Повторяющееся событие становится кандидатом только к сохранённому due_at (storage.NextReminder).
+// Событие, которое не удалось развернуть, пропускается до следующего изменения.
 func (s *DBStorage) CollectEventsToNotify(ctx context.Context) ([]storage.EventToNotify, error) {
 	s.Logg.Info("collecting events to notify.")
 
 	var events []storage.EventToNotify
 
-	sqlSt := `SELECT id, title, date_start, account_id 
-		from event where date_start 
-		between now() and (now() + interval '1' hour) and notified = false;`
+	sqlSt := `SELECT e.id, e.title, e.date_start, e.date_end, e.rrule, e.exdates, e.time_zone, e.version,
+			p.account_id, a.notify_channels, r.offset_seconds, r.fired_for, e.trace_parent
+		from event_reminder r
+			join event e on e.id = r.event_id and e.deleted_at is null
+			cross join lateral (select e.account_id
+				union all
+				select ea.account_id from event_attendee ea
+				where ea.event_id = e.id and ea.status <> 'declined') p
+			join account a on a.id = p.account_id
+		where e.date_start - r.offset_seconds * interval '1 second' <= now()
+			and (e.rrule <> '' and (r.due_at is null or r.due_at <= now())
+				or e.rrule = '' and e.date_start > now() - $1 * interval '1 second'
+				and (r.fired_for is null or r.fired_for < e.date_start));`
 
-	rows, err := s.DB.QueryContext(ctx, sqlSt)
-	if err != nil || rows.Err() != nil {
+	rows, err := s.DB.QueryContext(ctx, sqlSt, int64(storage.ReminderGrace/time.Second))
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	now := time.Now()
+	typeMap := pgtype.NewMap()
+	// новое due_at повторяющихся напоминаний, которые сейчас не наступили
+	dues := make(map[reminderDue]time.Time)
 	for rows.Next() {
-		var e storage.EventToNotify
-		err := rows.Scan(&e.ID, &e.Title, &e.Start, &e.UserID)
-		if err != nil || rows.Err() != nil {
+		var e storage.Event
+		var channels []byte
+		var offset int64
+		var firedFor sql.NullTime
+		var traceParent string
+		err := rows.Scan(&e.ID, &e.Title, &e.Start, &e.End, &e.RRule, typeMap.SQLScanner(&e.ExDates), &e.TimeZone,
+			&e.Version, &e.UserID, &channels, &offset, &firedFor, &traceParent)
+		if err != nil {
 			return nil, err
 		}
-		events = append(events, e)
+
+		r := storage.Reminder{Offset: toOffsets([]int64{offset})[0], FiredFor: firedFor.Time}
+		key := reminderDue{eventID: e.ID, offset: offset, version: e.Version}
+		if _, ok := dues[key]; ok {
+			continue // у этого напоминания другой получатель, но оно тоже не наступило
+		}
+		start, ok, err := storage.DueOccurrence(e, r, now)
+		if err != nil {
+			s.Logg.Error("error in expanding recurring event, skipping it", zap.Error(err), zap.String("eventID", e.ID))
+			dues[key] = brokenDue
+			continue
+		}
+		if !ok {
+			if e.RRule != "" {
+				if dues[key], err = storage.NextReminder(e, r, now); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		n := storage.EventToNotify{
+			ID: e.ID, Title: e.Title, Start: start, UserID: e.UserID, Reminder: r.Offset,
+			TraceParent: traceParent,
+		}
+		if err := json.Unmarshal(channels, &n.Channels); err != nil {
+			return nil, err
+		}
+		events = append(events, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.setReminderDues(ctx, dues); err != nil {
+		s.Logg.Error("error in setting reminder due time", zap.Error(err))
+		return nil, err
 	}
 	s.Logg.Info("events to notify are collected.")
 
 	return events, nil
 }
 
-func (s *DBStorage) DeleteEvents(ctx context.Context) error {
+// DeleteEvents переносит в корзину события всех пользователей, закончившиеся раньше, чем retention назад,
+// окончательно удаляет события, пролежавшие в корзине дольше trashRetention, изменения ленты
+// и доставленные сообщения outbox старше retention. Удаление устаревших событий в ленту не попадает.
+// Повторяющееся событие устаревает, только когда закончилось его последнее повторение;
+// событие, которое не удалось развернуть, остаётся на месте.
+func (s *DBStorage) DeleteEvents(ctx context.Context, retention time.Duration, trashRetention time.Duration) error {
 	s.Logg.Info("cleaning outdated events.")
-	sqlSt := `delete from event 
-		where account_id = 1 and date_end < (now() - interval '1' year);`
+	cutoff := time.Now().Add(-retention)
 
-	_, err := s.DB.ExecContext(ctx, sqlSt)
+	sqlSt := `update event set deleted_at = now() where deleted_at is null and rrule = '' and date_end < $1;`
+
+	res, err := s.DB.ExecContext(ctx, sqlSt, cutoff)
+	if err != nil {
+		s.Logg.Error("error in moving events to trash", zap.Error(err))
+		return err
+	}
+	trashed, _ := res.RowsAffected()
+
+	// конечность серии проверяется в Go, так же как в memorystorage
+	recurring, err := selectEvents(ctx, s.DB, notDeleted+" AND rrule <> '' AND date_start < $1", cutoff)
+	if err != nil {
+		s.Logg.Error("error in getting recurring events", zap.Error(err))
+		return err
+	}
+
+	var ids []string
+	for _, e := range recurring {
+		outdated, err := storage.Outdated(e, cutoff)
+		if err != nil {
+			s.Logg.Error("error in expanding recurring event, skipping it", zap.Error(err), zap.String("eventID", e.ID))
+			continue
+		}
+		if outdated {
+			ids = append(ids, e.ID)
+		}
+	}
+
+	numIDs, err := parseIDs(ids)
+	if err != nil {
+		return err
+	}
+	for batch := range slices.Chunk(numIDs, batchSize) {
+		res, err = s.DB.ExecContext(ctx, `update event set deleted_at = now() where id = any($1);`, batch)
+		if err != nil {
+			s.Logg.Error("error in moving events to trash", zap.Error(err))
+			return err
+		}
+		n, _ := res.RowsAffected()
+		trashed += n
+	}
+
+	res, err = s.DB.ExecContext(ctx, `delete from event where deleted_at < $1;`, time.Now().Add(-trashRetention))
+	if err != nil {
+		s.Logg.Error("error in purging trash", zap.Error(err))
+		return err
+	}
+	purged, _ := res.RowsAffected()
+
+	// граница ленты сдвигается к последнему удалённому изменению в той же команде
+	_, err = s.DB.ExecContext(ctx, `with purged as (
+			delete from event_change where changed_at < $1 returning tx, id)
+		update event_change_horizon h set tx = p.tx, id = p.id
+		from (select tx, id from purged order by tx desc, id desc limit 1) p
+		where (p.tx, p.id) > (h.tx, h.id);`, cutoff)
+	if err != nil {
+		s.Logg.Error("error in deleting event changes", zap.Error(err))
+		return err
+	}
+
+	res, err = s.DB.ExecContext(ctx, `delete from outbox where delivered_at < $1;`, cutoff)
+	if err != nil {
+		s.Logg.Error("error in purging outbox", zap.Error(err))
+		return err
+	}
+	delivered, _ := res.RowsAffected()
+
+	s.Logg.Info("DB is clean.", zap.Int64("trashed", trashed), zap.Int64("purged", purged),
+		zap.Int64("outbox", delivered))
+	return nil
+}
+
+func (s *DBStorage) GetAccountByLogin(ctx context.Context, login string) (storage.Account, error) {
+	return s.getAccount(ctx, "login", login)
+}
+
+// CreateAccount создаёт аккаунт вместе с его личным календарём.
+func (s *DBStorage) CreateAccount(ctx context.Context, login string, passwordHash string) (string, error) {
+	sqlSt := `insert into account (login, password) values ($1, $2) returning id;`
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, sqlSt, login, passwordHash).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return "", storage.ErrLoginTaken
+		}
+		s.Logg.Error("error in creating account", zap.Error(err))
+		return "", err
+	}
+
+	if _, err := insertCalendar(ctx, tx, storage.PersonalCalendarName, id, true); err != nil {
+		s.Logg.Error("error in creating personal calendar", zap.Error(err))
+		return "", err
+	}
+	return id, tx.Commit()
+}
+
+func (s *DBStorage) GetAccountByID(ctx context.Context, id string) (storage.Account, error) {
+	return s.getAccount(ctx, "id", id)
+}
+
+func (s *DBStorage) getAccount(ctx context.Context, by column, value string) (storage.Account, error) {
+	sqlSt := `select id, login, password, created_at, notify_channels, time_zone, token_version
+		from account where ` + string(by) + ` = $1;`
+
+	var a storage.Account
+	var channels []byte
+	err := s.DB.QueryRowContext(ctx, sqlSt, value).Scan(&a.ID, &a.Login, &a.PasswordHash, &a.CreatedAt,
+		&channels, &a.TimeZone, &a.TokenVersion)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Account{}, storage.ErrAccountNotFound
+		}
+		s.Logg.Error("error in getting account", zap.Error(err))
+		return storage.Account{}, err
+	}
+	if err := json.Unmarshal(channels, &a.Channels); err != nil {
+		return storage.Account{}, err
+	}
+	return a, nil
+}
+
+func (s *DBStorage) SetAccountChannels(ctx context.Context, id string, channels []storage.NotificationChannel) error {
+	if channels == nil {
+		channels = []storage.NotificationChannel{}
+	}
+	data, err := json.Marshal(channels)
+	if err != nil {
+		return err
+	}
+
+	sqlSt := `update account set notify_channels = $2::jsonb where id = $1;`
+
+	res, err := s.DB.ExecContext(ctx, sqlSt, id, string(data))
+	if err != nil {
+		s.Logg.Error("error in updating channels", zap.Error(err))
+		return err
+	}
+	return accountAffected(res)
+}
+
+func (s *DBStorage) SetAccountTimeZone(ctx context.Context, id string, timeZone string) error {
+	sqlSt := `update account set time_zone = $2 where id = $1;`
+
+	res, err := s.DB.ExecContext(ctx, sqlSt, id, timeZone)
+	if err != nil {
+		s.Logg.Error("error in updating time zone", zap.Error(err))
+		return err
+	}
+	return accountAffected(res)
+}
+
+// UpdateAccountPassword меняет пароль и увеличивает версию токенов: выпущенные раньше токены отзываются.
+func (s *DBStorage) UpdateAccountPassword(ctx context.Context, id string, passwordHash string) error {
+	sqlSt := `update account set password = $2, token_version = token_version + 1 where id = $1;`
+
+	res, err := s.DB.ExecContext(ctx, sqlSt, id, passwordHash)
+	if err != nil {
+		s.Logg.Error("error in updating password", zap.Error(err))
+		return err
+	}
+	return accountAffected(res)
+}
+
+// DeleteAccountByID удаляет аккаунт; его календари с событиями и права удаляются каскадно
+// внешними ключами. События, созданные им в чужих календарях, переходят к владельцам календарей.
+func (s *DBStorage) DeleteAccountByID(ctx context.Context, id string) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
-		s.Logg.Error("error in deleting event from DB", zap.Error(err))
 		return err
 	}
+	defer tx.Rollback()
 
-	s.Logg.Info("DB is clean.")
+	_, err = tx.ExecContext(ctx, `update event e set account_id = c.owner_id from calendar c
+		where c.id = e.calendar_id and e.account_id = $1 and c.owner_id <> $1;`, id)
+	if err != nil {
+		s.Logg.Error("error in reassigning events", zap.Error(err))
+		return err
+	}
+
+	// события календарей аккаунта удаляются каскадно
+	if err := recordDeletion(ctx, tx, `calendar_id in (select id from calendar where owner_id = $1)`, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `delete from account where id = $1;`, id)
+	if err != nil {
+		s.Logg.Error("error in deleting account", zap.Error(err))
+		return err
+	}
+	if err := accountAffected(res); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func accountAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrAccountNotFound
+	}
 	return nil
 }
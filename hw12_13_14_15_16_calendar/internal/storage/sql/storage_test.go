@@ -0,0 +1,720 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/migrator"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/database"
+	"github.com/c2fo/testify/require"
+	"go.uber.org/zap"
+)
+
+// defaultTestDB - postgres из docker-compose-test.yaml:
+// docker compose -f ./docker-compose-test.yaml up -d postgres.
+const defaultTestDB = "host=localhost port=9999 user=postgres password=123456 " +
+	"dbname=integration_test_db sslmode=disable"
+
+// newTestStorage подключается к тестовой БД (TESTDB или defaultTestDB) и создаёт
+// отдельный аккаунт, который удаляется вместе с событиями по окончании теста.
+// Если БД недоступна, тест пропускается.
+func newTestStorage(t *testing.T) (*DBStorage, string) {
+	t.Helper()
+
+	connStr := os.Getenv("TESTDB")
+	if connStr == "" {
+		connStr = defaultTestDB
+	}
+
+	db, err := database.Connect(connStr)
+	if err != nil {
+		t.Skipf("postgres is not available: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logg := zap.NewNop()
+	migrator.MustApplyMigrations(connStr, logg)
+
+	s := &DBStorage{Ctx: context.Background(), DB: db, Logg: logg}
+
+	login := "sqlstorage-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+	userID, err := s.CreateAccount(context.Background(), login, "hash")
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DeleteAccountByID(context.Background(), userID) })
+
+	return s, userID
+}
+
+func TestSetNotifiedBatches(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(30 * time.Minute)
+	var ids []string
+	for range batchSize + 5 {
+		id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+			Title: "reminder",
+			Start: start,
+			End:   start.Add(time.Hour),
+		}, userID)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	marked, err := s.SetNotified(ctx, ids)
+	require.NoError(t, err)
+	slices.Sort(marked)
+	slices.Sort(ids)
+	require.Equal(t, ids, marked)
+
+	// повторно уже помеченные события не возвращаются
+	marked, err = s.SetNotified(ctx, ids[:3])
+	require.NoError(t, err)
+	require.Len(t, marked, 0)
+
+	_, err = s.SetNotified(ctx, []string{ids[0] + ") or (true"})
+	require.Error(t, err)
+}
+
+func TestUpdateEventByIDPartial(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title:       "before",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Description: "kept",
+	}, userID)
+	require.NoError(t, err)
+
+	title := "after; drop table event; --"
+	rrule := "FREQ=DAILY;COUNT=2"
+	err = s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, RRule: &rrule}, userID)
+	require.NoError(t, err)
+
+	e, err := s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, title, e.Title)
+	require.Equal(t, "kept", e.Description)
+	require.Equal(t, rrule, e.RRule)
+	require.True(t, start.Equal(e.Start))
+
+	// чужой пользователь событие не меняет: в его личном календаре такого события нет
+	other := "other"
+	err = s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &other}, userID+"0")
+	require.Error(t, err)
+	e, err = s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, title, e.Title)
+}
+
+func TestEnqueueNotificationsWritesOutbox(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(30 * time.Minute)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+
+	event := storage.EventToNotify{ID: id, Title: "t", Start: start, UserID: userID, Reminder: storage.DefaultReminders[0]}
+	marked, err := s.EnqueueNotifications(ctx, []storage.EventToNotify{event})
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+
+	marked, err = s.EnqueueNotifications(ctx, []storage.EventToNotify{event})
+	require.NoError(t, err)
+	require.Len(t, marked, 0)
+
+	key := storage.NotificationKey(event)
+	var outboxID string
+	err = s.DB.QueryRowContext(ctx,
+		`select id from outbox where idempotency_key = $1 and delivered_at is null;`, key).Scan(&outboxID)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DB.Exec(`delete from outbox where id = $1;`, outboxID) })
+
+	require.NoError(t, s.MarkOutboxDelivered(ctx, []string{outboxID}))
+	pending, err := s.PendingOutbox(ctx, 1000)
+	require.NoError(t, err)
+	for _, m := range pending {
+		require.NotEqual(t, key, m.IdempotencyKey)
+	}
+}
+
+func TestEventVersion(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+	e, err := s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), e.Version)
+
+	title := "first"
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Version: 1}, userID))
+	title = "second"
+	err = s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Version: 1}, userID)
+	require.True(t, errors.Is(err, storage.ErrStaleVersion))
+
+	events, err := s.GetEventsByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "first", events[0].Title)
+	require.Equal(t, int64(2), events[0].Version)
+}
+
+func TestReminders(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "t", Start: start, End: start.Add(time.Hour),
+		Reminders: []storage.Offset{storage.Offset(time.Hour), 0, storage.Offset(10 * time.Minute)},
+	}, userID)
+	require.NoError(t, err)
+
+	e, err := s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, []storage.Offset{0, storage.Offset(10 * time.Minute), storage.Offset(time.Hour)}, e.Reminders)
+
+	own := func(events []storage.EventToNotify) []storage.EventToNotify {
+		var res []storage.EventToNotify
+		for _, n := range events {
+			if n.ID == id {
+				res = append(res, n)
+			}
+		}
+		return res
+	}
+
+	events, err := s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	events = own(events)
+	require.Len(t, events, 2)
+
+	marked, err := s.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+	t.Cleanup(func() {
+		for _, n := range events {
+			s.DB.Exec(`delete from outbox where idempotency_key = $1;`, storage.NotificationKey(n))
+		}
+	})
+
+	events, err = s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, own(events))
+
+	// оставшееся напоминание сохраняет отметку об отправке
+	only := []storage.Offset{storage.Offset(10 * time.Minute)}
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Reminders: &only}, userID))
+	e, err = s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, only, e.Reminders)
+	events, err = s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.Empty(t, own(events))
+}
+
+func TestCollectRecurringDue(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	first := time.Now().Add(5*time.Minute).AddDate(0, 0, -3).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "standup", Start: first, End: first.Add(15 * time.Minute), RRule: "FREQ=DAILY",
+		Reminders: []storage.Offset{storage.Offset(10 * time.Minute)},
+	}, userID)
+	require.NoError(t, err)
+
+	own := func() []storage.EventToNotify {
+		events, err := s.CollectEventsToNotify(ctx)
+		require.NoError(t, err)
+		return slices.DeleteFunc(events, func(n storage.EventToNotify) bool { return n.ID != id })
+	}
+	dueAt := func() time.Time {
+		var due sql.NullTime
+		require.NoError(t, s.DB.QueryRow(`select due_at from event_reminder where event_id = $1;`, id).Scan(&due))
+		return due.Time
+	}
+
+	events := own()
+	require.Len(t, events, 1)
+	_, err = s.EnqueueNotifications(ctx, events)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		s.DB.Exec(`delete from outbox where idempotency_key = $1;`, storage.NotificationKey(events[0]))
+	})
+
+	// после отправки серия разворачивается снова только к завтрашнему напоминанию
+	require.Empty(t, own())
+	require.True(t, first.AddDate(0, 0, 4).Add(-10*time.Minute).Equal(dueAt()))
+
+	// правило, записанное до проверки rrule: событие пропускается, остальные напоминания собираются
+	_, err = s.DB.Exec(`update event set rrule = 'FREQ=SECONDLY' where id = $1;`, id)
+	require.NoError(t, err)
+	_, err = s.DB.Exec(`update event_reminder set due_at = null where event_id = $1;`, id)
+	require.NoError(t, err)
+	require.Empty(t, own())
+	require.True(t, dueAt().After(time.Now().AddDate(100, 0, 0)))
+}
+
+func TestGetEventListingInTimeZone(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	a, err := s.GetAccountByID(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, storage.DefaultTimeZone, a.TimeZone)
+
+	require.NoError(t, s.SetAccountTimeZone(ctx, userID, "Asia/Tokyo"))
+	a, err = s.GetAccountByID(ctx, userID)
+	require.NoError(t, err)
+	tokyo, err := a.Location()
+	require.NoError(t, err)
+
+	// 19 сентября 23:30 UTC - это уже 20 сентября в Токио
+	start := time.Date(2025, time.September, 19, 23, 30, 0, 0, time.UTC)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "late", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+
+	res, err := s.GetEventListingByUserID(userID, time.Date(2025, time.September, 19, 0, 0, 0, 0, tokyo), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	res, err = s.GetEventListingByUserID(userID, time.Date(2025, time.September, 20, 0, 0, 0, 0, tokyo), storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, id, res[0].ID)
+}
+
+func TestRecurringInAccountTimeZone(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+	require.NoError(t, s.SetAccountTimeZone(ctx, userID, "Europe/Berlin"))
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// понедельник 00:30 по Берлину; pgx читает timestamptz в зоне сервера
+	start := time.Date(2026, time.March, 16, 0, 30, 0, 0, berlin).UTC()
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "weekly", Start: start, End: start.Add(time.Hour), RRule: "FREQ=WEEKLY;BYDAY=MO",
+	}, userID)
+	require.NoError(t, err)
+
+	e, err := s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", e.TimeZone)
+
+	// неделя после перехода на летнее время: повторение по-прежнему в понедельник 00:30
+	res, err := s.GetEventListingByUserID(userID, time.Date(2026, time.April, 1, 0, 0, 0, 0, berlin), storage.PeriodWeek)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.True(t, time.Date(2026, time.March, 30, 0, 30, 0, 0, berlin).Equal(res[0].Start))
+}
+
+func TestDuplicateUID(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+	start := time.Date(2025, time.September, 1, 10, 0, 0, 0, time.UTC)
+	uid := "uid-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+	e := storage.EventCreateDTO{Title: "imported", Start: start, End: start.Add(time.Hour), UID: uid}
+
+	id, err := s.AddEventByID(ctx, e, userID)
+	require.NoError(t, err)
+	_, err = s.AddEventByID(ctx, e, userID)
+	require.True(t, errors.Is(err, storage.ErrDuplicateUID))
+
+	got, err := s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, uid, got.UID)
+}
+
+func TestAttendees(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	login := "guest-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+	guestID, err := s.CreateAccount(ctx, login, "hash")
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DeleteAccountByID(context.Background(), guestID) })
+
+	start := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	_, err = s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "t", Start: start, End: start.Add(time.Hour), Attendees: []string{"999999999"},
+	}, userID)
+	require.True(t, errors.Is(err, storage.ErrInvalidAttendee))
+
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Планёрка", Start: start, End: start.Add(time.Hour), Attendees: []string{guestID},
+	}, userID)
+	require.NoError(t, err)
+
+	e, err := s.GetEventByID(id, guestID)
+	require.NoError(t, err)
+	require.Equal(t, userID, e.UserID)
+	require.Equal(t, []storage.Attendee{{UserID: guestID, Status: storage.StatusPending}}, e.Attendees)
+
+	events, err := s.GetEventsByUserID(ctx, guestID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	require.True(t, errors.Is(s.RespondToEvent(ctx, id, userID, storage.StatusAccepted), storage.ErrNotInvited))
+	require.NoError(t, s.RespondToEvent(ctx, id, guestID, storage.StatusTentative))
+	e, err = s.GetEventByID(id, userID)
+	require.NoError(t, err)
+	require.Equal(t, storage.ResponseCounts{Tentative: 1}, e.Responses)
+
+	// напоминание уходит и владельцу, и приглашённому
+	all, err := s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	var notify []storage.EventToNotify
+	var recipients []string
+	for _, n := range all {
+		if n.ID == id {
+			notify = append(notify, n)
+			recipients = append(recipients, n.UserID)
+		}
+	}
+	slices.Sort(recipients)
+	want := []string{userID, guestID}
+	slices.Sort(want)
+	require.Equal(t, want, recipients)
+
+	marked, err := s.EnqueueNotifications(ctx, notify)
+	require.NoError(t, err)
+	require.Equal(t, []string{id}, marked)
+	t.Cleanup(func() {
+		for _, n := range notify {
+			s.DB.Exec(`delete from outbox where idempotency_key = $1;`, storage.NotificationKey(n))
+		}
+	})
+
+	// отказавшийся по-прежнему видит событие в своих списках
+	require.NoError(t, s.RespondToEvent(ctx, id, guestID, storage.StatusDeclined))
+	_, err = s.GetEventByID(id, guestID)
+	require.NoError(t, err)
+
+	none := []string{}
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Attendees: &none}, userID))
+	_, err = s.GetEventByID(id, guestID)
+	require.Error(t, err)
+}
+
+func TestCalendars(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	login := "viewer-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+	viewerID, err := s.CreateAccount(ctx, login, "hash")
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DeleteAccountByID(context.Background(), viewerID) })
+
+	calendars, err := s.GetCalendars(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	require.True(t, calendars[0].Personal)
+	require.True(t, errors.Is(s.DeleteCalendar(ctx, calendars[0].ID, userID), storage.ErrForbidden))
+
+	calID, err := s.CreateCalendar(ctx, "Дежурства", userID)
+	require.NoError(t, err)
+	require.NoError(t, s.SetCalendarACL(ctx, calID, storage.ACLEntry{UserID: viewerID, Role: storage.RoleViewer}, userID))
+	require.True(t, errors.Is(s.SetCalendarACL(ctx, calID,
+		storage.ACLEntry{UserID: "999999999", Role: storage.RoleViewer}, userID), storage.ErrAccountNotFound))
+
+	acl, err := s.GetCalendarACL(ctx, calID, viewerID)
+	require.NoError(t, err)
+	require.Equal(t, []storage.ACLEntry{
+		{UserID: userID, Role: storage.RoleOwner},
+		{UserID: viewerID, Role: storage.RoleViewer},
+	}, acl)
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := s.AddCalendarEvent(ctx, calID, storage.EventCreateDTO{
+		Title: "Дежурство", Start: start, End: start.Add(time.Hour),
+	}, userID)
+	require.NoError(t, err)
+
+	e, err := s.GetCalendarEvent(ctx, calID, id, viewerID)
+	require.NoError(t, err)
+	require.Equal(t, calID, e.CalendarID)
+
+	listing, err := s.GetCalendarEventListing(ctx, calID, start, storage.PeriodDay, viewerID)
+	require.NoError(t, err)
+	require.Len(t, listing, 1)
+
+	_, err = s.AddCalendarEvent(ctx, calID, storage.EventCreateDTO{
+		Title: "t", Start: start, End: start.Add(time.Hour),
+	}, viewerID)
+	require.True(t, errors.Is(err, storage.ErrForbidden))
+	require.True(t, errors.Is(s.DeleteCalendarEvent(ctx, calID, id, viewerID), storage.ErrForbidden))
+
+	require.NoError(t, s.RevokeCalendarAccess(ctx, calID, viewerID, userID))
+	_, err = s.GetCalendarEvent(ctx, calID, id, viewerID)
+	require.True(t, errors.Is(err, storage.ErrCalendarNotFound))
+
+	require.NoError(t, s.DeleteCalendar(ctx, calID, userID))
+	_, err = s.GetEventByID(id, userID)
+	require.Error(t, err)
+}
+
+func TestChanges(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	login := "guest-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com"
+	guestID, err := s.CreateAccount(ctx, login, "hash")
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DeleteAccountByID(context.Background(), guestID) })
+
+	token, err := s.ChangeToken(ctx)
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "Планёрка", Start: start, End: start.Add(time.Hour), Attendees: []string{guestID},
+	}, userID)
+	require.NoError(t, err)
+
+	// уведомление event_change будит ожидающих ленту
+	wake := s.WaitForChanges()
+	title := "Ретро"
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title}, userID))
+	select {
+	case <-wake:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification on event change")
+	}
+
+	none := []string{}
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Attendees: &none}, userID))
+	require.NoError(t, s.DeleteEventByID(ctx, id, userID))
+
+	changes, err := s.GetChanges(ctx, userID, token, 10)
+	require.NoError(t, err)
+	var kinds []storage.ChangeKind
+	for _, c := range changes {
+		require.Equal(t, id, c.EventID)
+		kinds = append(kinds, c.Kind)
+	}
+	require.Equal(t, []storage.ChangeKind{
+		storage.ChangeCreated, storage.ChangeUpdated, storage.ChangeUpdated, storage.ChangeDeleted,
+	}, kinds)
+	require.Nil(t, changes[0].Event)
+
+	// убранный из приглашённых получает изменения до удаления из списка включительно
+	changes, err = s.GetChanges(ctx, guestID, token, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	changes, err = s.GetChanges(ctx, userID, changes[0].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, storage.ChangeUpdated, changes[0].Kind)
+}
+
+func TestChangesWaitForEarlierTransactions(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	token, err := s.ChangeToken(ctx)
+	require.NoError(t, err)
+
+	// транзакция получает id изменения раньше, а завершается позже следующей
+	tx, err := s.DB.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `insert into event_change (event_id, calendar_id, kind, recipients)
+		values (0, 0, 'updated', array[$1::bigint]);`, userID)
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.DeleteEventByID(context.Background(), id, userID) })
+
+	// пока ранняя транзакция не завершена, более поздние изменения не отдаются
+	changes, err := s.GetChanges(ctx, userID, token, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	require.NoError(t, tx.Commit())
+	changes, err = s.GetChanges(ctx, userID, token, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, "0", changes[0].EventID)
+	require.Equal(t, id, changes[1].EventID)
+}
+
+func TestBatch(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	valid := storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}
+	overlapping := storage.EventCreateDTO{Title: "o", Start: start, End: start.Add(time.Hour), RejectOverlap: true}
+	unknown := storage.EventCreateDTO{Title: "a", Start: start, End: start.Add(time.Hour), Attendees: []string{"0"}}
+
+	// atomic: ошибка события отменяет весь пакет
+	_, err := s.AddEvents(ctx, "", []storage.EventCreateDTO{valid, overlapping}, storage.BatchAtomic, userID)
+	var itemErr *storage.BatchItemError
+	require.True(t, errors.As(err, &itemErr))
+	require.Equal(t, 1, itemErr.Index)
+	require.True(t, errors.Is(err, storage.ErrOverlap))
+	events, err := s.GetEventsByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+
+	// best_effort: ошибки достаются только своим событиям
+	results, err := s.AddEvents(ctx, "", []storage.EventCreateDTO{valid, overlapping, unknown, valid},
+		storage.BatchBestEffort, userID)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	require.NoError(t, results[0].Err)
+	require.True(t, errors.Is(results[1].Err, storage.ErrOverlap))
+	require.True(t, errors.Is(results[2].Err, storage.ErrInvalidAttendee))
+	require.NoError(t, results[3].Err)
+	events, err = s.GetEventsByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	title := "renamed"
+	updates := []storage.EventBatchUpdate{
+		{ID: results[0].ID, Event: storage.EventUpdateDTO{Title: &title}},
+		{ID: results[3].ID, Event: storage.EventUpdateDTO{Title: &title, Version: 5}},
+	}
+	results, err = s.UpdateEvents(ctx, "", updates, storage.BatchBestEffort, userID)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.True(t, errors.Is(results[1].Err, storage.ErrStaleVersion))
+
+	ids := []string{updates[0].ID, "abc", updates[1].ID, updates[1].ID}
+	results, err = s.DeleteEventsByID(ctx, "", ids, storage.BatchBestEffort, userID)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.True(t, errors.Is(results[1].Err, storage.ErrEventNotFound))
+	require.NoError(t, results[2].Err)
+	require.True(t, errors.Is(results[3].Err, storage.ErrEventNotFound))
+	events, err = s.GetEventsByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+}
+
+func TestTrash(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteEventByID(ctx, id, userID))
+
+	// удалённое событие не видно и о нём не напоминают
+	_, err = s.GetEventByID(id, userID)
+	require.True(t, errors.Is(err, storage.ErrEventNotFound))
+	events, err := s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.False(t, slices.ContainsFunc(events, func(e storage.EventToNotify) bool { return e.ID == id }))
+	title := "renamed"
+	err = s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title}, userID)
+	require.True(t, errors.Is(err, storage.ErrEventNotFound))
+
+	trash, err := s.GetTrash(ctx, "", userID)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, id, trash[0].ID)
+	require.NotNil(t, trash[0].DeletedAt)
+
+	require.NoError(t, s.RestoreEvent(ctx, "", id, userID))
+	events, err = s.CollectEventsToNotify(ctx)
+	require.NoError(t, err)
+	require.True(t, slices.ContainsFunc(events, func(e storage.EventToNotify) bool { return e.ID == id }))
+	require.True(t, errors.Is(s.RestoreEvent(ctx, "", id, userID), storage.ErrEventNotFound))
+
+	// планировщик окончательно удаляет пролежавшие в корзине дольше trashRetention
+	require.NoError(t, s.DeleteEventByID(ctx, id, userID))
+	_, err = s.DB.ExecContext(ctx, `update event set deleted_at = now() - interval '2 hours' where id = $1;`, id)
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteEvents(ctx, 100*365*24*time.Hour, time.Hour))
+	trash, err = s.GetTrash(ctx, "", userID)
+	require.NoError(t, err)
+	require.Len(t, trash, 0)
+}
+
+func TestListingAndCleanupSkipBrokenEvent(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := context.Background()
+	start := time.Now().Add(-48 * time.Hour).Truncate(time.Hour)
+
+	old, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "old", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+	broken, err := s.AddEventByID(ctx, storage.EventCreateDTO{
+		Title: "broken", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY",
+	}, userID)
+	require.NoError(t, err)
+	// правило, записанное до проверки rrule, не разворачивается
+	_, err = s.DB.Exec(`update event set rrule = 'FREQ=SECONDLY' where id = $1;`, broken)
+	require.NoError(t, err)
+
+	res, err := s.GetEventListingByUserID(userID, start, storage.PeriodDay)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, old, res[0].ID)
+
+	// очистка не останавливается на сломанном событии и оставляет его на месте
+	require.NoError(t, s.DeleteEvents(ctx, time.Hour, time.Hour))
+	trash, err := s.GetTrash(ctx, "", userID)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, old, trash[0].ID)
+	_, err = s.GetEventByID(broken, userID)
+	require.NoError(t, err)
+}
+
+func TestAudit(t *testing.T) {
+	s, userID := newTestStorage(t)
+	ctx := requestid.WithID(context.Background(), "req-1")
+
+	start := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	id, err := s.AddEventByID(ctx, storage.EventCreateDTO{Title: "t", Start: start, End: start.Add(time.Hour)}, userID)
+	require.NoError(t, err)
+	title := "new"
+	require.NoError(t, s.UpdateEventByID(ctx, id, storage.EventUpdateDTO{Title: &title, Notified: true}, userID))
+	require.NoError(t, s.DeleteEventByID(ctx, id, userID))
+	require.NoError(t, s.RestoreEvent(ctx, "", id, userID))
+
+	history, err := s.GetEventHistory(ctx, "", id, userID)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	ops := make([]storage.AuditOperation, 0, len(history))
+	for _, a := range history {
+		ops = append(ops, a.Operation)
+		require.Equal(t, userID, a.ActorID)
+		require.Equal(t, "req-1", a.RequestID)
+		require.Equal(t, id, a.After.ID)
+	}
+	require.Equal(t, []storage.AuditOperation{
+		storage.AuditCreate, storage.AuditUpdate, storage.AuditDelete, storage.AuditRestore,
+	}, ops)
+	require.Nil(t, history[0].Before)
+	require.Equal(t, "t", history[1].Before.Title)
+	require.Equal(t, "new", history[1].After.Title)
+	require.NotNil(t, history[2].After.DeletedAt)
+	require.Nil(t, history[3].After.DeletedAt)
+
+	// журнал только дополняется
+	_, err = s.DB.ExecContext(ctx, `delete from event_audit where event_id = $1;`, id)
+	require.Error(t, err)
+}
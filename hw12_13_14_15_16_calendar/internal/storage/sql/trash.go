@@ -0,0 +1,70 @@
+package sqlstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/storage"
+	"go.uber.org/zap"
+)
+
+// GetTrash возвращает события из корзины календаря, последние удалённые - первыми;
+// пустой calendarID - личный календарь пользователя.
+func (s *DBStorage) GetTrash(ctx context.Context, calendarID string, userID string) ([]storage.Event, error) {
+	if calendarID == "" {
+		return selectEvents(ctx, s.DB, `deleted_at is not null and calendar_id = `+personalCalendar+`
+			order by deleted_at desc, id`, userID)
+	}
+	if err := checkCalendar(ctx, s.DB, calendarID, userID, storage.RoleViewer); err != nil {
+		return nil, err
+	}
+	return selectEvents(ctx, s.DB, `deleted_at is not null and calendar_id = $1
+		order by deleted_at desc, id`, calendarID)
+}
+
+// RestoreEvent возвращает событие из корзины календаря, если у пользователя есть права editor;
+// пустой calendarID - личный календарь пользователя. В ленте изменений восстановленное
+// событие создаётся заново.
+func (s *DBStorage) RestoreEvent(ctx context.Context, calendarID string, eventID string, userID string) error {
+	ids, err := parseIDs([]string{eventID})
+	if err != nil {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	calendarID, err = resolveCalendar(ctx, tx, calendarID, userID, storage.RoleEditor)
+	if err != nil {
+		return err
+	}
+	before, err := auditSnapshot(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `update event set deleted_at = null
+		where id = $1 and calendar_id = $2 and deleted_at is not null;`, eventID, calendarID)
+	if err != nil {
+		s.Logg.Error("error in restoring event", zap.Error(err), zap.String("eventID", eventID))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s", storage.ErrEventNotFound, eventID)
+	}
+
+	if err := recordChange(ctx, tx, storage.ChangeCreated, eventID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, storage.AuditRestore, userID, ids, before); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer создаёт спан на каждый запрос pgx (pgx.ConnConfig.Tracer).
+// Запросы вне трейса, например опросы очереди, спанов не создают.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	ctx, _ = Tracer().Start(ctx, queryName(data.SQL), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		))
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+	End(span, data.Err)
+}
+
+// queryName возвращает имя спана запроса - его первое слово (select, insert, ...).
+func queryName(sql string) string {
+	words := strings.Fields(sql)
+	if len(words) == 0 {
+		return "query"
+	}
+	return strings.ToLower(words[0])
+}
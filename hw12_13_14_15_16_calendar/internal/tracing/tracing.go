@@ -0,0 +1,124 @@
+// Package tracing - трассировка OpenTelemetry календаря, планировщика и отправителя.
+//
+// Контекст трассировки передаётся заголовком W3C traceparent: между HTTP и gRPC - в заголовках
+// и метаданных, а дальше сохраняется вместе с событием, сообщением outbox и сообщением брокера,
+// поэтому напоминание попадает в тот же трейс, что и запрос, создавший событие.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Виды экспорта спанов.
+const (
+	ExporterNone = ""     // спаны не экспортируются, но контекст трассировки передаётся дальше
+	ExporterOTLP = "otlp" // OTLP/gRPC на адрес Config.Endpoint
+	ExporterFile = "file" // JSON по спану в строке в файл Config.Endpoint, для тестов и отладки
+)
+
+// instrumentationName - имя трассировщика спанов, которые создаёт сам календарь.
+const instrumentationName = "github.com/adettelle/hw/hw12_13_14_15_calendar"
+
+// traceParentKey - заголовок W3C с ID трейса и родительского спана.
+const traceParentKey = "traceparent"
+
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Config - параметры Setup.
+type Config struct {
+	Service  string // имя сервиса в ресурсе спанов
+	Exporter string // otlp, file или пусто
+	Endpoint string // адрес коллектора для otlp или путь к файлу для file
+}
+
+// Setup устанавливает глобальные провайдер спанов и пропагатор W3C Trace Context.
+// Возвращённая функция выгружает накопленные спаны и закрывает экспортёр.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	closeExporter := func() error { return nil }
+
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithInsecure()}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		e, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		exporter = e
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.Endpoint, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		exporter, closeExporter = e, f.Close
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, cfg.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.Service))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), closeExporter())
+	}, nil
+}
+
+// Tracer возвращает трассировщик календаря из глобального провайдера.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End завершает span, отмечая в нём ошибку err, если она есть.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent возвращает заголовок traceparent спана из ctx или "", если спана нет.
+// В таком виде контекст трассировки хранится в базе и в сообщениях брокера.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get(traceParentKey)
+}
+
+// WithTraceParent возвращает ctx, в котором родитель следующих спанов - спан из traceParent.
+// Пустой или некорректный traceParent оставляет ctx без изменений.
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
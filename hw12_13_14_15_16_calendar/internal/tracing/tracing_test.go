@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c2fo/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceParent(t *testing.T) {
+	require.Equal(t, "", TraceParent(context.Background()))
+	require.Equal(t, context.Background(), WithTraceParent(context.Background(), ""))
+
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	ctx := WithTraceParent(context.Background(), traceParent)
+	sc := trace.SpanContextFromContext(ctx)
+	require.True(t, sc.IsRemote())
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", sc.TraceID().String())
+	require.Equal(t, traceParent, TraceParent(ctx))
+
+	// некорректный заголовок не даёт родителя
+	require.False(t, trace.SpanContextFromContext(WithTraceParent(context.Background(), "garbage")).IsValid())
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	path := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup(context.Background(),
+		Config{Service: "calendar_test", Exporter: ExporterFile, Endpoint: path})
+	require.NoError(t, err)
+
+	parent := WithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	_, span := Tracer().Start(parent, "create event")
+	End(span, errors.New("boom"))
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	spans := string(data)
+	require.Contains(t, spans, `"Name":"create event"`)
+	require.Contains(t, spans, `"TraceID":"0af7651916cd43dd8448eb211c80319c"`)
+	require.Contains(t, spans, `"Value":"calendar_test"`)
+	require.Contains(t, spans, `"Description":"boom"`)
+	require.Equal(t, 1, strings.Count(spans, "\n"))
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+	require.True(t, errors.Is(err, ErrUnknownExporter))
+}
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "select", queryName("SELECT e.id from event"))
+	require.Equal(t, "update", queryName("\n\t\tupdate event set notified = true"))
+	require.Equal(t, "query", queryName("  "))
+}
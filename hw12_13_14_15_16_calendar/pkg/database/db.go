@@ -6,20 +6,26 @@ import (
 	"log"
 	"time"
 
-	_ "github.com/jackc/pgx/v5" // импортируем pgx для регистрации драйвера database/sql
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
+// Connect открывает соединение с базой; запросы в рамках трейса попадают в него спанами.
 func Connect(dbParams string) (*sql.DB, error) {
 	log.Println("Connecting to DB", dbParams)
-	db, err := sql.Open("pgx", dbParams)
+	cfg, err := pgx.ParseConfig(dbParams)
 	if err != nil {
 		return nil, err
 	}
+	cfg.Tracer = tracing.QueryTracer{}
+	db := stdlib.OpenDB(*cfg)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err = db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -27,17 +27,52 @@ func TestHello(t *testing.T) {
 	require.Equal(t, http.StatusOK, res.StatusCode())
 }
 
-func TestGetInexistentEvent(t *testing.T) {
+// newAuthorizedClient логинится пользователем из миграций и возвращает клиента с токеном.
+func newAuthorizedClient(t *testing.T) *resty.Client {
+	t.Helper()
+
+	client := resty.New()
+
+	var token struct {
+		Token string `json:"token"`
+	}
+	res, err := client.R().
+		SetBody(map[string]string{"login": "user1@gmail.com", "password": "user1"}).
+		SetResult(&token).
+		Post("http://calendar:8081/login")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+
+	client.SetAuthToken(token.Token)
+	return client
+}
+
+func TestUnauthorized(t *testing.T) {
 	client := resty.New()
 	defer client.Close()
 
+	res, err := client.R().Get("http://calendar:8081/user/1/event/9999999")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode())
+
+	res, err = client.R().
+		SetBody(map[string]string{"login": "user1@gmail.com", "password": "wrong"}).
+		Post("http://calendar:8081/login")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode())
+}
+
+func TestGetInexistentEvent(t *testing.T) {
+	client := newAuthorizedClient(t)
+	defer client.Close()
+
 	res, err := client.R().Get("http://calendar:8081/user/1/event/9999999")
 	require.NoError(t, err)
 	require.Equal(t, http.StatusNotFound, res.StatusCode())
 }
 
 func TestPutAndGetEvent(t *testing.T) {
-	client := resty.New()
+	client := newAuthorizedClient(t)
 	defer client.Close()
 
 	var createdID map[string]string
@@ -75,7 +110,7 @@ func TestPutAndGetEvent(t *testing.T) {
 }
 
 func TestPutAndGetListOfEventsForDay(t *testing.T) {
-	client := resty.New()
+	client := newAuthorizedClient(t)
 	defer client.Close()
 
 	var createdID1 map[string]string
@@ -173,7 +208,7 @@ func TestPutAndGetListOfEventsForDay(t *testing.T) {
 }
 
 func TestPutAndGetListOfEventsForDayAndCheckNotification(t *testing.T) {
-	client := resty.New()
+	client := newAuthorizedClient(t)
 	defer client.Close()
 
 	var createdID1 map[string]string
@@ -293,3 +328,74 @@ func TestPutAndGetListOfEventsForDayAndCheckNotification(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, res.StatusCode())
 	}
 }
+
+func TestAccountLifecycle(t *testing.T) {
+	client := resty.New()
+	defer client.Close()
+
+	login := fmt.Sprintf("user-%d@example.com", time.Now().UnixNano())
+
+	var created map[string]string
+	res, err := client.R().
+		SetBody(map[string]string{"login": login, "password": "secret1"}).
+		SetResult(&created).
+		Post("http://calendar:8081/accounts")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, res.StatusCode())
+
+	var token struct {
+		Token string `json:"token"`
+	}
+	res, err = client.R().
+		SetBody(map[string]string{"login": login, "password": "secret1"}).
+		SetResult(&token).
+		Post("http://calendar:8081/login")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+	client.SetAuthToken(token.Token)
+
+	userURL := "http://calendar:8081/user/" + created["id"]
+
+	res, err = client.R().
+		SetBody(map[string]any{
+			"title":     "to be cascaded",
+			"dateStart": time.Now().Add(time.Hour),
+			"dateEnd":   time.Now().Add(2 * time.Hour),
+		}).
+		Put(userURL + "/event/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, res.StatusCode())
+
+	res, err = client.R().Get(userURL + "/account")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+	require.Contains(t, res.String(), login)
+
+	// смена пароля отзывает выпущенные раньше токены
+	res, err = client.R().
+		SetBody(map[string]string{"oldPassword": "secret1", "newPassword": "secret2"}).
+		Post(userURL + "/account/password")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, res.StatusCode())
+
+	res, err = client.R().Get(userURL + "/account")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode())
+
+	res, err = client.R().
+		SetBody(map[string]string{"login": login, "password": "secret2"}).
+		SetResult(&token).
+		Post("http://calendar:8081/login")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+	client.SetAuthToken(token.Token)
+
+	res, err = client.R().Delete(userURL + "/account")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode())
+
+	// токен удалённого аккаунта отклоняется
+	res, err = client.R().Get(userURL + "/account")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode())
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/auth"
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/pkg/problem"
+	"go.uber.org/zap"
+)
+
+// TokenVerifier checks an access token and returns the ID of the user it was issued for.
+// Invalid and revoked tokens are reported with errors wrapping auth.ErrInvalidToken.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (string, error)
+}
+
+// Authenticate is a chi middleware that requires a valid bearer token.
+// The user ID from the token is put into the request context; if the route has
+// a {userid} parameter, it must match the token, otherwise 403 is returned.
+// If the token cannot be checked (e.g. the storage is down), 500 is returned.
+func Authenticate(tokens TokenVerifier, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token, ok := auth.BearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				problem.Write(w, problem.Details{
+					Status: http.StatusUnauthorized, Detail: "missing access token", Instance: r.URL.Path,
+				})
+				return
+			}
+
+			userID, err := tokens.Verify(r.Context(), token)
+			if err != nil && !errors.Is(err, auth.ErrInvalidToken) {
+				logger.Error("error in verifying access token", zap.Error(err))
+				problem.Write(w, problem.Details{Status: http.StatusInternalServerError, Instance: r.URL.Path})
+				return
+			}
+			if err != nil {
+				logger.Info("invalid access token", zap.Error(err))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				problem.Write(w, problem.Details{
+					Status: http.StatusUnauthorized, Detail: "invalid access token", Instance: r.URL.Path,
+				})
+				return
+			}
+
+			// идентификатор в пути оставлен для совместимости, но чужие ресурсы недоступны
+			if pathUserID := r.PathValue("userid"); pathUserID != "" && pathUserID != userID {
+				problem.Write(w, problem.Details{
+					Status: http.StatusForbidden, Detail: "access to another user's resources is denied", Instance: r.URL.Path,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
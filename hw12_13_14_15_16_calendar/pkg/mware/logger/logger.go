@@ -38,6 +38,11 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.responseData.status = statusCode // захватываем код статуса
 }
 
+// Unwrap gives http.ResponseController access to the original writer (Flush, deadlines).
+func (r *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // WithLogging wraps an http.HandlerFunc to add logging functionality.
 // It logs information about each HTTP request, including the URI, method, response status code,
 // response size, and the duration of the request.
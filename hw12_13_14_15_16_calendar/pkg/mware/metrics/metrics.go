@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// unmatchedRoute labels requests that no route matched, so unknown paths
+// do not create new label values.
+const unmatchedRoute = "unmatched"
+
+type routeKey struct{}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap gives http.ResponseController access to the original writer (Flush, deadlines).
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// SetRoute sets the route label of the current request. Handlers that route requests
+// themselves (like the REST gateway mounted at /v1/*) call it with their own pattern.
+func SetRoute(ctx context.Context, route string) {
+	if p, ok := ctx.Value(routeKey{}).(*string); ok {
+		*p = route
+	}
+}
+
+// WithMetrics is a chi middleware that counts requests and observes their latency
+// by method, route pattern and status.
+func WithMetrics(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := new(string)
+		sw := &statusResponseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), routeKey{}, route)))
+
+		if *route == "" {
+			*route = unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				*route = rctx.RoutePattern()
+			}
+		}
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		status := strconv.Itoa(sw.status)
+		metrics.HTTPRequests.WithLabelValues(r.Method, *route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, *route, status).Observe(time.Since(start).Seconds())
+	}
+	return http.HandlerFunc(fn)
+}
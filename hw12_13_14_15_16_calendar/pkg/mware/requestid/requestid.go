@@ -0,0 +1,19 @@
+package requestid
+
+import (
+	"net/http"
+
+	"github.com/adettelle/hw/hw12_13_14_15_calendar/internal/requestid"
+)
+
+// WithRequestID is a chi middleware that takes the request ID from the X-Request-ID header
+// (or generates a new one), returns it in the response and puts it into the request context.
+func WithRequestID(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := requestid.Ensure(r.Header.Get(requestid.Header))
+		r.Header.Set(requestid.Header, id)
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type routeKey struct{}
+
+// SetRoute sets the route the span of the current request is named after. Handlers that route
+// requests themselves (like the REST gateway mounted at /v1/*) call it with their own pattern.
+func SetRoute(ctx context.Context, route string) {
+	if p, ok := ctx.Value(routeKey{}).(*string); ok {
+		*p = route
+	}
+}
+
+// WithTracing is a chi middleware that starts a server span for every request, continuing
+// the trace from the traceparent header. The span is named after the method and route pattern.
+func WithTracing(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		route := new(string)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, route)))
+
+		if *route == "" {
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				*route = rctx.RoutePattern()
+			}
+		}
+		if *route != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + *route)
+			span.SetAttributes(semconv.HTTPRoute(*route))
+		}
+	}
+	return otelhttp.NewHandler(http.HandlerFunc(fn), "http request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method }))
+}